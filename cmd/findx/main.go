@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"Findx/internal/config"
+	"Findx/internal/logger"
+	"Findx/internal/output"
 	"Findx/internal/scanner"
 
 	"github.com/urfave/cli/v2"
@@ -40,6 +42,18 @@ func main() {
 				return fmt.Errorf("配置验证失败: %w", err)
 			}
 
+			// 按标签启用规则
+			if err := scanner.ApplyRuleTags(cfg); err != nil {
+				return fmt.Errorf("配置验证失败: %w", err)
+			}
+
+			// 设置日志级别
+			level, err := logger.ParseLevel(cfg.LogLevel)
+			if err != nil {
+				return fmt.Errorf("配置验证失败: %w", err)
+			}
+			logger.SetLevel(level)
+
 			// 打印配置信息
 			cfg.PrintConfig()
 
@@ -49,6 +63,10 @@ func main() {
 				return fmt.Errorf("扫描失败: %w", err)
 			}
 
+			if n := s.FailOnCount(); n > 0 {
+				return cli.Exit(fmt.Sprintf("发现 %d 个风险等级不低于 %s 的结果", n, cfg.FailOn), 1)
+			}
+
 			return nil
 		},
 		Commands: []*cli.Command{
@@ -61,6 +79,46 @@ func main() {
 					return nil
 				},
 			},
+			{
+				Name:      "diff",
+				Usage:     "对比两次扫描的JSON报告 / Compare two JSON scan reports",
+				ArgsUsage: "old.json new.json",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "html",
+						Usage: "生成HTML对比报告 / Write an HTML diff report",
+					},
+					&cli.BoolFlag{
+						Name:  "no-bom",
+						Usage: "HTML对比报告不写入UTF-8 BOM / Do not write a UTF-8 BOM to the HTML diff report",
+					},
+				},
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 2 {
+						return fmt.Errorf("用法: findx diff old.json new.json")
+					}
+
+					oldReport, err := output.LoadJSONReport(c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+					newReport, err := output.LoadJSONReport(c.Args().Get(1))
+					if err != nil {
+						return err
+					}
+
+					diff := output.DiffReports(oldReport, newReport)
+					fmt.Print(output.NewResultFormatter().FormatDiffSummary(diff))
+
+					if htmlPath := c.String("html"); htmlPath != "" {
+						if err := output.GenerateDiffHTML(htmlPath, diff, !c.Bool("no-bom")); err != nil {
+							return err
+						}
+						logger.Infof("HTML对比报告保存至: %s", htmlPath)
+					}
+					return nil
+				},
+			},
 		},
 	}
 
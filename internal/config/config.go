@@ -2,8 +2,14 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
+
+	"Findx/internal/logger"
 )
 
 const Banner = `
@@ -18,40 +24,215 @@ const Banner = `
    
 `
 
+// 结果去重粒度
+const (
+	DedupeByNone      = "none"       // 不去重
+	DedupeByValue     = "value"      // 相同敏感值全局只保留一次
+	DedupeByValueFile = "value+file" // 相同敏感值在每个文件中只保留一次
+	DedupeByValueRule = "value+rule" // 相同规则的相同敏感值只保留一次
+)
+
+// 报告中文件路径的分隔符
+const (
+	PathSeparatorSlash  = "slash"  // 统一使用 /（Windows 上遍历得到的 \ 转换为 /）
+	PathSeparatorNative = "native" // 使用操作系统的分隔符（Windows 上为 \）
+)
+
+// HTML报告文件排序方式
+const (
+	HTMLSortByPath  = "path"  // 按文件路径排序
+	HTMLSortByCount = "count" // 按结果数量降序排序
+	HTMLSortByScore = "score" // 按风险评分降序排序
+)
+
+// HTML报告主题
+const (
+	HTMLThemeLight = "light" // 浅色（默认）
+	HTMLThemeDark  = "dark"  // 深色
+	HTMLThemeAuto  = "auto"  // 按浏览器的 prefers-color-scheme 选择
+)
+
+// HTML报告编辑器链接方案
+const (
+	EditorLinksVSCode = "vscode" // vscode://file/<路径>:<行号>
+	EditorLinksIDEA   = "idea"   // idea://open?file=<路径>&line=<行号>
+	EditorLinksFile   = "file"   // file://<路径>
+)
+
+// 文本结果输出格式
+const (
+	OutputFormatText = "text" // 带分隔线的多行格式
+	OutputFormatFlat = "flat" // 每条结果一行: 路径:行号:风险:规则:匹配值
+)
+
+// 文本结果文件的编码
+const (
+	OutputEncodingUTF8    = "utf-8"     // UTF-8，不写入 BOM
+	OutputEncodingUTF8BOM = "utf-8-bom" // UTF-8，新文件写入 BOM
+	OutputEncodingGBK     = "gbk"       // GBK，供只能读取 GBK 的旧工具使用
+)
+
+// 不是有效PE的二进制文件（--binary-fallback）的处理方式
+const (
+	BinaryFallbackSkip = "skip" // 跳过（只输出调试日志）
+	BinaryFallbackRaw  = "raw"  // 按原始字节扫描（字符串提取、规则和Base64检查，不校验PE格式）
+)
+
+// MaxJSONRawContext JSON输出中原始字节上下文的最大长度（单侧）
+const MaxJSONRawContext = 1024
+
 // Config 扫描配置
 type Config struct {
 	// 基础配置
-	FileTypes   []string // 文件类型列表
-	Keywords    []string // 搜索关键词列表
-	OutputFile  string   // 输出文件路径
-	HTMLOutput  string   // HTML报告文件路径
-	Directory   string   // 扫描目录
-	Verbose     bool     // 是否实时输出
-	ThreadCount int      // 线程数
+	FileTypes    []string // 文件类型列表（已去除 ExcludeExts）
+	ExcludeExts  []string // 从文件类型列表中排除的扩展名
+	Keywords     []string // 搜索关键词列表
+	KeywordRisks map[string]string // 指定了风险等级的关键词（-k password:critical），其他关键词为 medium
+	KeywordCI    bool     // 关键字匹配忽略大小写和全角/半角差异
+	OutputFile   string   // 输出文件路径
+	OutputFormat string   // 文本结果输出格式（text/flat）
+	OutputEncoding string // 文本结果文件的编码（utf-8/utf-8-bom/gbk）
+	HTMLOutput   string   // HTML报告文件路径
+	HTMLSort     string   // HTML报告文件排序方式
+	HTMLTheme    string   // HTML报告主题（light/dark/auto）
+	EditorLinks  string   // HTML报告中结果位置的编辑器链接方案（为空则不生成）
+	JSONOutput   string   // JSON报告文件路径（为空则不生成）
+	CSVOutput    string   // CSV报告文件路径（为空则不生成）
+	CSVBOM       bool     // CSV报告写入UTF-8 BOM（便于Excel识别编码）
+	CSVMask      bool     // CSV报告中对匹配值脱敏
+	Syslog       string   // 实时发送结果的 syslog 服务器地址（为空则不发送）
+	Webhook      string   // 实时 POST 结果的 webhook 地址（为空则不发送）
+	DumpStrings  string   // 二进制文件字符串转储路径（为空则不转储）
+	CleanList    string   // 没有结果的文件列表路径（为空则不输出）
+	Manifest     string   // 扫描清单（JSON）路径（为空则不输出）
+	Directories  []string // 扫描目录列表
+	DockerImage  string   // 扫描的Docker镜像（镜像名或 docker save 导出包）
+	StdinContent bool     // 将标准输入的内容作为一个文件扫描
+	GitToken     string   // 克隆远程 Git 仓库时使用的访问令牌
+	Verbose      bool     // 是否实时输出
+	ShowClean    bool     // 扫描时为每个没有结果的文件输出一行 [ok] 路径
+	ThreadCount  int      // 线程数
 	
 	// 高级配置
 	MaxFileSize  int64    // 最大文件大小（字节）
+	MinFileSize  int64    // 最小文件大小（字节），小于该值的文件跳过
+	ArchiveFanout bool    // 压缩包条目在工作池中与其他文件并发扫描
+	ArchiveMemory int64   // 并发扫描时同时解压的条目总大小上限（字节）
 	ExcludeDirs  []string // 排除目录列表
+	AutoExclude  bool     // 按扫描目录的项目类型自动排除依赖和构建目录
+	AutoExcludes []string // 按项目类型自动排除的目录名（扫描时识别后填充，按目录名精确匹配）
 	ExcludeFiles []string // 排除文件模式列表
+	SkipHidden   bool     // 跳过以 . 开头的文件和目录
+	SkipGenerated bool    // 跳过按标记和内容识别的生成、压缩文件
+	IncludeGit   bool     // 扫描 .git 目录内部（默认跳过）
+	Owner        string   // 只扫描属于该用户的文件（用户名或UID）
+	Group        string   // 只扫描属于该组的文件（组名或GID）
 	
 	// 二进制扫描配置
 	BinaryMode    bool // 是否启用二进制扫描模式
+	DualScan      bool // 同时以文本和二进制方式扫描
+	RawScan       bool // 所有文件按原始字节扫描（内存转储等），不校验PE格式
+	BinaryFallback string // 不是有效PE的二进制文件的处理方式（skip/raw）
+	ScanImages    bool // 追加图片类型并扫描图片元数据
+	KeystorePassword string // 密钥库（JKS/JCEKS/PKCS#12）口令，为空时只尝试常见默认口令
+	MagicSignatures []MagicSignature // 自定义文件头签名，匹配时优先于扩展名选择解析方式
+	MaxPerRule    int  // 每个文件中单条规则的最大结果数（0表示不限制）
+	MaxStrings    int  // 每个文件最多检查的字符串数（0表示不限制）
+	MergeFragments bool // 合并相邻的字符串片段后再应用规则（检测被拆分的连接字符串）
+	IntraFileWorkers int // 单个文件内并发匹配字符串和Base64候选的协程数（不大于1时依次匹配）
+	BinaryMinRisk string // 二进制结果的最低风险等级，低于该等级的二进制结果不报告（为空则不过滤）
+	TextThreshold float64 // Base64解码内容视为文本的可打印字符最低比例
 	ContextLength int  // 上下文长度
+	
+	// JSON输出配置
+	JSONRawContext int // 二进制结果附带的原始字节上下文长度（单侧，0表示不附带）
+
+	// 运行模式
+	LogLevel    string // 日志级别
+	Pprof       string // pprof 诊断服务的监听地址（为空则不启动）
+	CountOnly   bool   // 仅统计待扫描文件，不解析内容
+	Interactive bool   // 开始解析前询问是否继续（仅标准输入为终端时）
+	AssumeYes   bool   // 跳过 --interactive 的确认
+	
+	// 结果处理配置
+	DedupeBy      string // 结果去重粒度
+	ValueMaxLen   int    // 所有输出中匹配值的最大长度（字符数，0表示不截断）
+	RelativePaths bool   // 报告中使用相对于扫描目录的路径
+	PathSeparator string // 报告中文件路径的分隔符（slash/native）
+	SummaryOnly   bool   // 仅输出汇总统计，不输出具体结果
+	DirSummary    bool   // 扫描结束后在控制台按目录汇总结果
+	Blame         bool   // 对 Git 仓库中的文本结果运行 git blame，记录引入的提交和时间
+	UnusedRules   bool   // 扫描结束后列出没有产生结果的关键词和规则
+	NoBOM         bool   // 文本和HTML输出不写入 UTF-8 BOM
+	Atomic        bool   // 文本结果先写入临时文件，扫描完成后再替换输出文件
+	FlushEach     bool   // 每个文件的结果写入后立即刷新输出文件
+	SplitOutput   bool   // 文本结果按风险等级写入单独的文件（res.critical.txt 等）
+	CacheFile string // 扫描缓存文件路径（为空则不使用缓存）
+	Incremental   bool   // 增量扫描：只解析上次扫描后变化的文件，状态默认保存在用户缓存目录
+	MaxFindings   int    // 累计结果达到该数量后提前结束扫描（0表示不限制）
+	Seed          int64  // 文件处理顺序的种子，非0时按种子确定的顺序依次提交各文件的结果（0表示按完成顺序）
+	FailOn        string // 存在不低于该风险等级的结果时以非零状态退出（为空则不检查）
+	
+	// 规则配置
+	OnlyRules         []string           // 仅启用的规则名称（包括 Tags 展开后的规则）
+	Tags              []string           // 仅启用带有这些标签的规则
+	AllMatches        bool               // 同一行命中多条规则时全部报告
+	RuleTimeout       time.Duration      // 规则匹配单个字符串的时间上限（0表示不限制）
+	WeakPasswords     bool               // 检查匹配值中的弱口令
+	SecretWords       []string           // 高熵赋值规则的敏感变量名关键词（为空时禁用该规则）
+	EntropyThreshold  float64            // 高熵赋值规则的信息熵阈值（每字符比特数）
+	SkipRules         []string           // 禁用的规则名称
+	NoValidateRules   []string           // 不校验匹配值的规则名称（直接报告正则匹配的结果）
+	SensitiveFiles    []string           // 无论文件类型都扫描并标记的敏感文件名
+	HashListFile      string             // 已知文件哈希列表路径
+	KnownHashes       map[string]string  // 已知文件的 SHA-256 -> 说明
+	RulesFiles        []string           // 规则配置文件路径（按指定顺序合并）
+	KeywordsFiles     []string           // 关键词文件路径
+	LoadedSources     []LoadedSource     // 从各关键词文件和规则文件加载的条目数
+	SeverityOverrides []SeverityOverride // 风险等级覆盖规则
+	SeverityWeights   map[string]int     // 风险评分中各等级的权重（未指定的等级使用默认权重）
+	TestPaths         []string           // 测试数据目录（其中文件的结果风险等级降为最低等级）
 }
 
 // Validate 验证配置有效性
 func (c *Config) Validate() error {
-	if c.Directory == "" {
+	if c.StdinContent && (len(c.Directories) > 0 || c.DockerImage != "") {
+		return fmt.Errorf("--stdin-content 不能与 -f 或 --docker-image 同时使用")
+	}
+	
+	if len(c.Directories) == 0 && c.DockerImage == "" && !c.StdinContent {
 		return fmt.Errorf("扫描目录不能为空")
 	}
 	
+	if c.DockerImage == "" {
+		for _, dir := range c.Directories {
+			if IsRemoteGitURL(dir) {
+				if len(c.Directories) > 1 {
+					return fmt.Errorf("远程 Git 仓库不能与其他扫描目录同时指定: %s", RedactRepoURL(dir))
+				}
+				continue
+			}
+			info, err := os.Stat(dir)
+			if err != nil {
+				return fmt.Errorf("扫描目录不存在: %s", dir)
+			}
+			if !info.IsDir() {
+				return fmt.Errorf("扫描目标不是目录: %s", dir)
+			}
+		}
+	}
+	
+	if len(c.FileTypes) == 0 && len(c.ExcludeExts) > 0 {
+		return fmt.Errorf("--exclude-ext 排除了全部文件类型: %s", strings.Join(c.ExcludeExts, ", "))
+	}
+	
 	if len(c.FileTypes) == 0 {
 		return fmt.Errorf("文件类型列表不能为空")
 	}
 	
 	// 二进制模式下，关键词可以为空（只使用规则匹配）
 	// 文本模式下，关键词不能为空
-	if len(c.Keywords) == 0 && !c.BinaryMode && !c.HasBinaryFileTypes() {
+	if len(c.Keywords) == 0 && !c.BinaryMode && !c.RawScan && !c.HasBinaryFileTypes() {
 		return fmt.Errorf("关键词列表不能为空（除非启用二进制扫描模式）")
 	}
 	
@@ -59,12 +240,171 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("线程数必须大于0")
 	}
 	
+	if c.TextThreshold <= 0 || c.TextThreshold >= 1 {
+		return fmt.Errorf("--text-threshold 必须在 0-1 之间（不含边界）")
+	}
+	
+	if c.EntropyThreshold <= 0 || c.EntropyThreshold > 8 {
+		return fmt.Errorf("--entropy-threshold 必须在 0-8 之间（不含0）")
+	}
+	
+	if c.RuleTimeout < 0 {
+		return fmt.Errorf("--rule-timeout 不能为负数")
+	}
+	
+	if c.MaxPerRule < 0 {
+		return fmt.Errorf("--max-per-rule 不能为负数")
+	}
+	
+	if c.MaxStrings < 0 {
+		return fmt.Errorf("--max-strings 不能为负数")
+	}
+
+	if c.IntraFileWorkers < 0 {
+		return fmt.Errorf("--intra-file-concurrency 不能为负数")
+	}
+	
+	if c.ValueMaxLen < 0 {
+		return fmt.Errorf("--value-max-len 不能为负数")
+	}
+	
+	if c.MaxFindings < 0 {
+		return fmt.Errorf("--max-findings 不能为负数")
+	}
+	
+	if c.BinaryFallback != BinaryFallbackSkip && c.BinaryFallback != BinaryFallbackRaw {
+		return fmt.Errorf("无效的 --binary-fallback: %s（可选: %s, %s）", c.BinaryFallback, BinaryFallbackSkip, BinaryFallbackRaw)
+	}
+	
+	if c.BinaryMinRisk != "" && !IsValidRiskLevel(c.BinaryMinRisk) {
+		return fmt.Errorf("无效的 --binary-min-risk 风险等级: %s（可选: %s）", c.BinaryMinRisk, RiskLevelNames())
+	}
+	if c.FailOn != "" && !IsValidRiskLevel(c.FailOn) {
+		return fmt.Errorf("无效的 --fail-on 风险等级: %s（可选: %s）", c.FailOn, RiskLevelNames())
+	}
+	
+	if c.JSONRawContext < 0 || c.JSONRawContext > MaxJSONRawContext {
+		return fmt.Errorf("原始字节上下文长度必须在 0-%d 之间", MaxJSONRawContext)
+	}
+	
+	if c.JSONRawContext > 0 && c.JSONOutput == "" {
+		return fmt.Errorf("--json-raw-context 需要同时指定 --json")
+	}
+	
+	if c.RawScan && c.DualScan {
+		return fmt.Errorf("--raw-scan 不能与 --dual-scan 同时使用")
+	}
+	
+	if c.ArchiveFanout && c.ArchiveMemory <= 0 {
+		return fmt.Errorf("--archive-memory 必须大于0")
+	}
+
+	if c.MinFileSize > 0 && c.MaxFileSize > 0 && c.MinFileSize >= c.MaxFileSize {
+		return fmt.Errorf("--min-size 必须小于 --max-size")
+	}
+	
+	if c.FlushEach && c.Atomic {
+		return fmt.Errorf("--flush-each 不能与 --atomic 同时使用")
+	}
+	
+	if c.SummaryOnly && c.SplitOutput {
+		return fmt.Errorf("--summary-only 不能与 --split-output 同时使用")
+	}
+	
+	if c.SummaryOnly && c.JSONOutput != "" {
+		return fmt.Errorf("--summary-only 不能与 --json 同时使用")
+	}
+	
+	if c.SummaryOnly && c.CSVOutput != "" {
+		return fmt.Errorf("--summary-only 不能与 --csv 同时使用")
+	}
+	
+	if c.SummaryOnly && (c.Syslog != "" || c.Webhook != "") {
+		return fmt.Errorf("--summary-only 不能与 --syslog 或 --webhook 同时使用")
+	}
+	
+	if (c.CSVBOM || c.CSVMask) && c.CSVOutput == "" {
+		return fmt.Errorf("--csv-bom 和 --csv-mask 需要同时指定 --csv")
+	}
+	
+	if c.Incremental && (c.StdinContent || c.DockerImage != "" || c.IsRemoteRepo()) {
+		return fmt.Errorf("--incremental 只能用于本地目录")
+	}
+	
+	if c.CacheFile != "" && c.DockerImage != "" {
+		return fmt.Errorf("--cache 不能与 --docker-image 同时使用")
+	}
+	
+	if c.CacheFile != "" && c.IsRemoteRepo() {
+		return fmt.Errorf("--cache 不能用于远程 Git 仓库")
+	}
+	
+	if c.GitToken != "" && !c.IsRemoteRepo() {
+		return fmt.Errorf("--git-token 需要 -f 指定远程 Git 仓库地址")
+	}
+	
+	switch c.OutputFormat {
+	case "", OutputFormatText, OutputFormatFlat:
+	default:
+		return fmt.Errorf("无效的输出格式: %s（可选: text, flat）", c.OutputFormat)
+	}
+	
+	switch c.OutputEncoding {
+	case OutputEncodingUTF8, OutputEncodingGBK:
+	case OutputEncodingUTF8BOM:
+		if c.NoBOM {
+			return fmt.Errorf("--no-bom 不能与 --output-encoding %s 同时使用", OutputEncodingUTF8BOM)
+		}
+	default:
+		return fmt.Errorf("无效的输出编码: %s（可选: %s, %s, %s）", c.OutputEncoding, OutputEncodingUTF8, OutputEncodingUTF8BOM, OutputEncodingGBK)
+	}
+	
+	switch c.PathSeparator {
+	case PathSeparatorSlash, PathSeparatorNative:
+	default:
+		return fmt.Errorf("无效的 --path-separator: %s（可选: %s, %s）", c.PathSeparator, PathSeparatorSlash, PathSeparatorNative)
+	}
+	
+	switch c.HTMLSort {
+	case "", HTMLSortByPath, HTMLSortByCount, HTMLSortByScore:
+	default:
+		return fmt.Errorf("无效的HTML排序方式: %s（可选: path, count, score）", c.HTMLSort)
+	}
+	
+	switch c.HTMLTheme {
+	case "", HTMLThemeLight, HTMLThemeDark, HTMLThemeAuto:
+	default:
+		return fmt.Errorf("无效的HTML报告主题: %s（可选: light, dark, auto）", c.HTMLTheme)
+	}
+	
+	switch c.EditorLinks {
+	case "", EditorLinksVSCode, EditorLinksIDEA, EditorLinksFile:
+	default:
+		return fmt.Errorf("无效的编辑器链接方案: %s（可选: vscode, idea, file）", c.EditorLinks)
+	}
+	
+	switch c.DedupeBy {
+	case "", DedupeByNone, DedupeByValue, DedupeByValueFile, DedupeByValueRule:
+	default:
+		return fmt.Errorf("无效的去重粒度: %s（可选: none, value, value+file, value+rule）", c.DedupeBy)
+	}
+	
+	for i := range c.SeverityOverrides {
+		if err := c.SeverityOverrides[i].compile(); err != nil {
+			return err
+		}
+	}
+	
+	if err := c.configureSeverityWeights(); err != nil {
+		return err
+	}
+	
 	return nil
 }
 
 // ShouldExcludeDir 判断是否应该排除该目录
 func (c *Config) ShouldExcludeDir(dirPath string) bool {
-	if len(c.ExcludeDirs) == 0 {
+	if len(c.ExcludeDirs) == 0 && len(c.AutoExcludes) == 0 {
 		return false
 	}
 	
@@ -74,10 +414,24 @@ func (c *Config) ShouldExcludeDir(dirPath string) bool {
 			return true
 		}
 	}
+	for _, exclude := range c.AutoExcludes {
+		if dirName == exclude {
+			return true
+		}
+	}
 	
 	return false
 }
 
+// ShouldSkipHidden 判断遍历到的文件或目录是否因隐藏或属于 .git 内部而跳过
+// 扫描根目录本身不受影响
+func (c *Config) ShouldSkipHidden(name string, isDir bool) bool {
+	if isDir && name == ".git" && !c.IncludeGit {
+		return true
+	}
+	return c.SkipHidden && strings.HasPrefix(name, ".") && name != "." && name != ".."
+}
+
 // ShouldExcludeFile 判断是否应该排除该文件
 func (c *Config) ShouldExcludeFile(filePath string) bool {
 	if len(c.ExcludeFiles) == 0 {
@@ -95,52 +449,283 @@ func (c *Config) ShouldExcludeFile(filePath string) bool {
 	return false
 }
 
-// ShouldSkipBySize 判断文件是否因大小超限而跳过
+// ShouldSkipBySize 判断文件大小是否超出 --min-size 和 --max-size 限定的范围
 func (c *Config) ShouldSkipBySize(fileSize int64) bool {
+	if c.MinFileSize > 0 && fileSize < c.MinFileSize {
+		return true
+	}
 	if c.MaxFileSize <= 0 {
 		return false
 	}
 	return fileSize > c.MaxFileSize
 }
 
+// RuleEnabled 判断规则是否按 --only-rules/--skip-rules 启用（Base64解码结果按原规则判断）
+func (c *Config) RuleEnabled(ruleName string) bool {
+	name := strings.TrimSuffix(ruleName, " (Base64编码)")
+	for _, skip := range c.SkipRules {
+		if name == skip {
+			return false
+		}
+	}
+	if len(c.OnlyRules) == 0 {
+		return true
+	}
+	for _, only := range c.OnlyRules {
+		if name == only {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveRiskLevel 根据覆盖规则计算结果的最终风险等级，首个匹配的规则生效
+func (c *Config) ResolveRiskLevel(filePath, ruleName, riskLevel string) string {
+	if len(c.SeverityOverrides) == 0 {
+		return riskLevel
+	}
+	
+	relPath := c.RelativePath(filePath)
+	for i := range c.SeverityOverrides {
+		if c.SeverityOverrides[i].Matches(relPath, ruleName) {
+			return c.SeverityOverrides[i].Risk
+		}
+	}
+	
+	return riskLevel
+}
+
+// IsTestPath 判断文件是否位于测试数据目录中（--test-paths）
+// 按扫描目录下的相对路径匹配，目录名不区分大小写，也可以指定多级目录（如 src/test）
+func (c *Config) IsTestPath(filePath string) bool {
+	if len(c.TestPaths) == 0 {
+		return false
+	}
+	
+	dir := "/" + strings.ToLower(filepath.ToSlash(filepath.Dir(c.RelativePath(filePath)))) + "/"
+	for _, testPath := range c.TestPaths {
+		testPath = strings.Trim(strings.ToLower(filepath.ToSlash(testPath)), "/")
+		if testPath != "" && strings.Contains(dir, "/"+testPath+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RootOf 获取文件所属的扫描目录，有多个匹配时取最深的一个，不属于任何扫描目录时返回空串
+func (c *Config) RootOf(filePath string) string {
+	root := ""
+	for _, dir := range c.Directories {
+		rel, err := filepath.Rel(dir, filePath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if len(dir) > len(root) {
+			root = dir
+		}
+	}
+	return root
+}
+
+// RelativePath 获取文件相对于扫描目录的路径，失败时返回原路径
+// 指定多个扫描目录时以所属目录名作为前缀，避免不同目录下的同名文件混淆
+func (c *Config) RelativePath(filePath string) string {
+	root := c.RootOf(filePath)
+	if root == "" {
+		return filePath
+	}
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil {
+		return filePath
+	}
+	if len(c.Directories) > 1 {
+		if abs, err := filepath.Abs(root); err == nil {
+			return filepath.Join(filepath.Base(abs), rel)
+		}
+	}
+	return rel
+}
+
 // PrintConfig 打印配置信息
 func (c *Config) PrintConfig() {
-	fmt.Println("[*] 扫描配置:")
-	fmt.Printf("    目录: %s\n", c.Directory)
-	fmt.Printf("    输出: %s\n", c.OutputFile)
-	fmt.Printf("    线程: %d\n", c.ThreadCount)
-	fmt.Printf("    文件类型: %s\n", strings.Join(c.FileTypes, ", "))
+	logger.Infof("扫描配置:")
+	if c.StdinContent {
+		logger.Detailf("    输入: 标准输入")
+	} else if c.DockerImage != "" {
+		logger.Detailf("    镜像: %s", c.DockerImage)
+	} else if c.IsRemoteRepo() {
+		logger.Detailf("    仓库: %s", RedactRepoURL(c.Directories[0]))
+	} else {
+		logger.Detailf("    目录: %s", strings.Join(c.Directories, ", "))
+	}
+	logger.Detailf("    输出: %s", c.OutputFile)
+	if c.JSONOutput != "" {
+		logger.Detailf("    JSON: %s", c.JSONOutput)
+	}
+	if c.CSVOutput != "" {
+		logger.Detailf("    CSV: %s", c.CSVOutput)
+	}
+	if c.Syslog != "" {
+		logger.Detailf("    Syslog: %s", c.Syslog)
+	}
+	if c.Webhook != "" {
+		logger.Detailf("    Webhook: %s", RedactRepoURL(c.Webhook))
+	}
+	if c.DumpStrings != "" {
+		logger.Detailf("    字符串转储: %s", c.DumpStrings)
+	}
+	if c.Manifest != "" {
+		logger.Detailf("    扫描清单: %s", c.Manifest)
+	}
+	if c.CleanList != "" {
+		logger.Detailf("    无结果文件列表: %s", c.CleanList)
+	}
+	logger.Detailf("    线程: %d", c.ThreadCount)
+	logger.Detailf("    文件类型: %s", strings.Join(c.FileTypes, ", "))
+	if len(c.ExcludeExts) > 0 {
+		logger.Detailf("    排除类型: %s", strings.Join(c.ExcludeExts, ", "))
+	}
 	
 	// 显示关键词信息
 	if len(c.Keywords) > 0 {
-		fmt.Printf("    关键词数: %d 个\n", len(c.Keywords))
+		if c.KeywordCI {
+			logger.Detailf("    关键词数: %d 个（忽略大小写）", len(c.Keywords))
+		} else {
+			logger.Detailf("    关键词数: %d 个", len(c.Keywords))
+		}
+		if len(c.KeywordRisks) > 0 {
+			names := make([]string, 0, len(c.KeywordRisks))
+			for keyword, level := range c.KeywordRisks {
+				names = append(names, keyword+"="+level)
+			}
+			sort.Strings(names)
+			logger.Detailf("    关键词风险: %s", strings.Join(names, ", "))
+		}
 	} else {
-		fmt.Println("    关键词: 无（仅使用规则匹配）")
+		logger.Detailf("    关键词: 无（仅使用规则匹配）")
 	}
 	
 	// 自动检测二进制文件类型
-	if c.BinaryMode || c.HasBinaryFileTypes() {
+	if c.RawScan {
+		logger.Detailf("    模式: 原始字节扫描（所有文件）")
+	} else if c.BinaryMode || c.HasBinaryFileTypes() {
 		binaryTypes := c.GetBinaryFileTypes()
 		if len(binaryTypes) > 0 {
-			fmt.Printf("    模式: 二进制扫描模式 (%s)\n", strings.Join(binaryTypes, ", "))
+			logger.Detailf("    模式: 二进制扫描模式 (%s)", strings.Join(binaryTypes, ", "))
 		} else {
-			fmt.Println("    模式: 二进制扫描模式 (DLL/EXE/SO)")
+			logger.Detailf("    模式: 二进制扫描模式 (DLL/EXE/SO)")
+		}
+		if c.BinaryFallback == BinaryFallbackRaw {
+			logger.Detailf("    非PE二进制文件: 按原始字节扫描")
 		}
 	}
+	if c.ScanImages {
+		logger.Detailf("    图片元数据: 启用 (EXIF/XMP/PNG文本块)")
+	}
+	if c.KeystorePassword != "" {
+		logger.Detailf("    密钥库口令: 已指定（另尝试常见默认口令）")
+	}
+	for _, signature := range c.MagicSignatures {
+		logger.Detailf("    文件头签名: %X -> %s", signature.Magic, signature.Type)
+	}
 	
 	if c.MaxFileSize > 0 {
-		fmt.Printf("    最大文件: %.2f MB\n", float64(c.MaxFileSize)/1024/1024)
+		logger.Detailf("    最大文件: %.2f MB", float64(c.MaxFileSize)/1024/1024)
+	}
+	if c.ArchiveFanout {
+		logger.Detailf("    压缩包条目: 并发扫描（同时解压上限 %.2f MB）", float64(c.ArchiveMemory)/1024/1024)
+	}
+	if c.MinFileSize > 0 {
+		logger.Detailf("    最小文件: %d 字节", c.MinFileSize)
 	}
 	
+	if c.AutoExclude {
+		logger.Detailf("    自动排除: 按项目类型")
+	}
 	if len(c.ExcludeDirs) > 0 {
-		fmt.Printf("    排除目录: %s\n", strings.Join(c.ExcludeDirs, ", "))
+		logger.Detailf("    排除目录: %s", strings.Join(c.ExcludeDirs, ", "))
 	}
 	
 	if len(c.ExcludeFiles) > 0 {
-		fmt.Printf("    排除文件: %s\n", strings.Join(c.ExcludeFiles, ", "))
+		logger.Detailf("    排除文件: %s", strings.Join(c.ExcludeFiles, ", "))
+	}
+	
+	switch {
+	case c.SkipHidden:
+		logger.Detailf("    隐藏文件: 跳过")
+	case c.IncludeGit:
+		logger.Detailf("    隐藏文件: 扫描（包括 .git）")
+	default:
+		logger.Detailf("    隐藏文件: 扫描（跳过 .git）")
 	}
 	
-	fmt.Println("[*] 🚀🚀🚀🚀🚀🚀开始扫描🚀🚀🚀🚀🚀🚀")
+	if c.SkipGenerated {
+		logger.Detailf("    生成文件: 跳过（生成标记、压缩内容、源码映射）")
+	}
+	
+	if c.Owner != "" {
+		logger.Detailf("    属主: %s", c.Owner)
+	}
+	if c.Group != "" {
+		logger.Detailf("    属组: %s", c.Group)
+	}
+	
+	if c.BinaryMinRisk != "" {
+		logger.Detailf("    二进制最低风险: %s", c.BinaryMinRisk)
+	}
+	
+	if len(c.Tags) > 0 {
+		logger.Detailf("    规则标签: %s（%d 条规则）", strings.Join(c.Tags, ", "), len(c.OnlyRules))
+	}
+	
+	if c.DedupeBy != "" && c.DedupeBy != DedupeByNone {
+		logger.Detailf("    去重粒度: %s", c.DedupeBy)
+	}
+	
+	for _, source := range c.LoadedSources {
+		if source.Duplicates > 0 {
+			logger.Detailf("    %s: %s（%d 条，忽略重复 %d 条）", source.Kind, source.Path, source.Loaded, source.Duplicates)
+		} else {
+			logger.Detailf("    %s: %s（%d 条）", source.Kind, source.Path, source.Loaded)
+		}
+	}
+	
+	if len(c.SeverityWeights) > 0 {
+		logger.Detailf("    风险评分权重: %s", c.SeverityWeightsText())
+	}
+	if len(c.SeverityOverrides) > 0 {
+		logger.Detailf("    风险覆盖: %d 条", len(c.SeverityOverrides))
+	}
+	
+	if len(c.TestPaths) > 0 {
+		logger.Detailf("    测试数据目录: %s", strings.Join(c.TestPaths, ", "))
+	}
+	
+	if c.HashListFile != "" {
+		logger.Detailf("    已知哈希: %d 条 (%s)", len(c.KnownHashes), c.HashListFile)
+	}
+	
+	if c.Incremental {
+		logger.Detailf("    增量扫描: %s", c.CacheFile)
+	}
+	
+	if c.MaxFindings > 0 {
+		logger.Detailf("    结果上限: %d", c.MaxFindings)
+	}
+	
+	if c.Seed != 0 {
+		logger.Detailf("    顺序种子: %d", c.Seed)
+	}
+	
+	if c.FailOn != "" {
+		logger.Detailf("    失败等级: %s", c.FailOn)
+	}
+	
+	if c.CountOnly {
+		logger.Detailf("    模式: 仅统计（不解析文件内容）")
+	}
+	
+	logger.Infof("🚀🚀🚀🚀🚀🚀开始扫描🚀🚀🚀🚀🚀🚀")
 }
 
 // GetFileTypeCount 获取文件类型数量
@@ -153,19 +738,41 @@ func (c *Config) GetKeywordCount() int {
 	return len(c.Keywords)
 }
 
-// IsFileTypeSupported 判断文件类型是否支持
+// IsSensitiveFile 判断文件是否为已知的敏感文件（凭据文件、私钥等）
+func (c *Config) IsSensitiveFile(filePath string) bool {
+	slashPath := filepath.ToSlash(filePath)
+	baseName := filepath.Base(filePath)
+	for _, name := range c.SensitiveFiles {
+		if strings.Contains(name, "/") {
+			if slashPath == name || strings.HasSuffix(slashPath, "/"+name) {
+				return true
+			}
+		} else if baseName == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rotatedLogPattern 轮转日志文件名（app.log.1、app.log-20240101、app.log.2.gz 等）
+var rotatedLogPattern = regexp.MustCompile(`\.log(?:[.-]\d[\d-]*)?(?:\.gz)?$`)
+
+// IsFileTypeSupported 判断文件类型是否支持，指定 .log 时同时包含轮转和压缩的日志
 func (c *Config) IsFileTypeSupported(filePath string) bool {
 	for _, ext := range c.FileTypes {
 		if strings.HasSuffix(filePath, ext) {
 			return true
 		}
+		if ext == ".log" && rotatedLogPattern.MatchString(filepath.Base(filePath)) {
+			return true
+		}
 	}
 	return false
 }
 
 // HasBinaryFileTypes 检查配置中是否包含二进制文件类型
 func (c *Config) HasBinaryFileTypes() bool {
-	binaryExts := []string{".dll", ".exe", ".so", ".dylib", ".bin", ".o", ".obj"}
+	binaryExts := []string{".dll", ".exe", ".so", ".dylib", ".bin", ".o", ".obj", ".class", ".jar", ".pcap", ".pcapng"}
 	
 	for _, fileType := range c.FileTypes {
 		fileTypeLower := strings.ToLower(fileType)
@@ -180,7 +787,7 @@ func (c *Config) HasBinaryFileTypes() bool {
 
 // GetBinaryFileTypes 获取配置中的二进制文件类型
 func (c *Config) GetBinaryFileTypes() []string {
-	binaryExts := []string{".dll", ".exe", ".so", ".dylib", ".bin", ".o", ".obj"}
+	binaryExts := []string{".dll", ".exe", ".so", ".dylib", ".bin", ".o", ".obj", ".class", ".jar", ".pcap", ".pcapng"}
 	var result []string
 	
 	for _, fileType := range c.FileTypes {
@@ -0,0 +1,34 @@
+package config
+
+import "testing"
+
+// TestIsFileTypeSupportedRotatedLogs 指定 .log 时包含轮转和压缩的日志，其他类型不受影响
+func TestIsFileTypeSupportedRotatedLogs(t *testing.T) {
+	tests := []struct {
+		types []string
+		path  string
+		want  bool
+	}{
+		{[]string{".log"}, "/var/log/app.log", true},
+		{[]string{".log"}, "/var/log/app.log.1", true},
+		{[]string{".log"}, "/var/log/app.log.12", true},
+		{[]string{".log"}, "/var/log/app.log.2.gz", true},
+		{[]string{".log"}, "/var/log/app.log.gz", true},
+		{[]string{".log"}, "/var/log/app.log-20240101", true},
+		{[]string{".log"}, "/var/log/app.log-2024-01-01.gz", true},
+		{[]string{".log"}, "/var/log/app.log.old", false},
+		{[]string{".log"}, "/var/log/app.logs", false},
+		{[]string{".log"}, "/var/log/dump.sql.gz", false},
+		{[]string{".log"}, "/var/log.1/app.txt", false},
+		{[]string{".txt"}, "/var/log/app.log.1", false},
+		{[]string{".txt", ".gz"}, "/var/log/app.log.1", false},
+		{[]string{".txt", ".gz"}, "/var/log/app.log.2.gz", true},
+	}
+
+	for _, tt := range tests {
+		c := &Config{FileTypes: tt.types}
+		if got := c.IsFileTypeSupported(tt.path); got != tt.want {
+			t.Errorf("IsFileTypeSupported(%q) with %v = %v, want %v", tt.path, tt.types, got, tt.want)
+		}
+	}
+}
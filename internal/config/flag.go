@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"github.com/urfave/cli/v2"
@@ -10,23 +13,48 @@ import (
 
 // 默认配置常量
 const (
-	DefaultFileTypes = ".txt,.log,.ini,.conf,.yaml,.yml,.xml,.json,.sql,.properties,.md,.java,.docx, .xlsx, .xls, .csv"
+	DefaultFileTypes = ".txt,.log,.ini,.conf,.yaml,.yml,.xml,.config,.json,.sql,.properties,.md,.java,.docx, .xlsx, .xls, .csv, .tfstate, .tfvars, .jks, .jceks, .keystore, .p12, .pfx, .pem, .crt, .cer"
 	DefaultKeywords  = "password=,username=,jdbc:,user=,ssh-,ldap:,mysqli_connect,sk-,账号,密码,username:,password:"
 	DefaultOutput    = "res.txt"
 
+	// 高熵赋值规则的敏感变量名关键词和信息熵阈值
+	DefaultSecretWords      = "secret,token,key,password,passwd,pwd,credential,auth,salt,signature"
+	DefaultEntropyThreshold = 3.5
+
+	// 压缩包条目并发扫描时同时解压的条目总大小上限
+	DefaultArchiveMemory = "256MB"
+
 	// 二进制文件类型
-	BinaryFileTypes = ".dll,.exe,.so,.dylib,.bin,.o,.obj"
+	BinaryFileTypes = ".dll,.exe,.so,.dylib,.bin,.o,.obj,.class,.jar"
+
+	// 内存转储文件类型（--raw-scan 时追加）
+	DumpFileTypes = ".dmp,.mdmp,.core,.mem,.vmem,.raw"
+
+	// 图片文件类型（--scan-images 时追加）
+	ImageFileTypes = ".jpg,.jpeg,.png,.tif,.tiff"
 )
 
 // GetFlags 返回所有命令行标志
 func GetFlags() []cli.Flag {
 	return []cli.Flag{
 		// 基础参数
+		&cli.StringSliceFlag{
+			Name:    "f",
+			Aliases: []string{"folder"},
+			Usage:   "扫描目录或远程Git仓库地址（必填，扫描镜像时除外；多个目录可重复指定或逗号分隔，合并为一份报告） / Scan directory or remote Git URL (required unless scanning an image; repeat or comma-separate for several directories in one report)",
+		},
 		&cli.StringFlag{
-			Name:     "f",
-			Aliases:  []string{"folder"},
-			Usage:    "扫描目录（必填） / Scan directory (required)",
-			Required: true,
+			Name:    "git-token",
+			Usage:   "克隆远程Git仓库时使用的访问令牌（也可通过环境变量 FINDX_GIT_TOKEN 设置） / Access token for cloning a remote Git repository (or set FINDX_GIT_TOKEN)",
+			EnvVars: []string{"FINDX_GIT_TOKEN"},
+		},
+		&cli.StringFlag{
+			Name:  "docker-image",
+			Usage: "扫描Docker镜像各层（镜像名或 docker save 导出的tar） / Scan Docker image layers (image ref or docker save tar)",
+		},
+		&cli.BoolFlag{
+			Name:  "stdin-content",
+			Usage: "将标准输入的内容作为一个文件扫描（报告中显示为 (stdin)） / Scan the content piped to stdin as a single file (reported as (stdin))",
 		},
 		&cli.StringFlag{
 			Name:    "o",
@@ -34,11 +62,87 @@ func GetFlags() []cli.Flag {
 			Usage:   "输出文件路径 / Output file path",
 			Value:   DefaultOutput,
 		},
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"output-format"},
+			Usage:   "文本结果格式（text/flat，flat为每条结果一行: 路径:行号:风险:规则:匹配值） / Text result format (text/flat; flat prints path:line:risk:rule:value per finding)",
+			Value:   OutputFormatText,
+		},
+		&cli.StringFlag{
+			Name:  "output-encoding",
+			Usage: "文本结果文件的编码（utf-8/utf-8-bom/gbk，默认 utf-8-bom，单行格式或 --no-bom 时为 utf-8；HTML报告始终为UTF-8） / Text output encoding (utf-8/utf-8-bom/gbk; default utf-8-bom, or utf-8 with flat format or --no-bom; the HTML report is always UTF-8)",
+		},
 		&cli.StringFlag{
 			Name:    "html",
 			Aliases: []string{"html-output"},
 			Usage:   "HTML报告文件路径（默认为输出文件名.html） / HTML report file path (default: output_file.html)",
 		},
+		&cli.StringFlag{
+			Name:  "html-sort",
+			Usage: "HTML报告文件排序方式（path/count/score） / HTML report file order (path/count/score)",
+			Value: HTMLSortByPath,
+		},
+		&cli.StringFlag{
+			Name:  "html-theme",
+			Usage: "HTML报告主题（light/dark/auto，auto 按浏览器的深色模式设置选择） / HTML report theme (light/dark/auto; auto follows the browser's color scheme)",
+			Value: HTMLThemeLight,
+		},
+		&cli.StringFlag{
+			Name:  "editor-links",
+			Usage: "HTML报告中将结果位置渲染为编辑器链接（vscode/idea/file） / Render finding locations as editor links in the HTML report (vscode/idea/file)",
+		},
+		&cli.BoolFlag{
+			Name:  "no-bom",
+			Usage: "文本和HTML输出不写入UTF-8 BOM / Do not write a UTF-8 BOM to text and HTML output",
+		},
+		&cli.BoolFlag{
+			Name:  "atomic",
+			Usage: "文本结果先写入临时文件，扫描完成后再替换输出文件（HTML/JSON报告始终如此） / Write text results to a temp file and rename it on completion (always done for HTML/JSON)",
+		},
+		&cli.BoolFlag{
+			Name:  "flush-each",
+			Usage: "每个文件的结果写入后立即刷新输出文件，便于 tail 查看进度 / Flush the output file after each file's results, for tailing long scans",
+		},
+		&cli.BoolFlag{
+			Name:  "split-output",
+			Usage: "文本结果按风险等级写入单独的文件（如 res.critical.txt、res.high.txt），HTML/JSON报告不拆分 / Write text results to one file per risk level (e.g. res.critical.txt); HTML/JSON stay combined",
+		},
+		&cli.StringFlag{
+			Name:  "json",
+			Usage: "JSON报告文件路径 / JSON report file path",
+		},
+		&cli.StringFlag{
+			Name:  "csv",
+			Usage: "CSV报告文件路径（每条结果一行） / CSV report file path (one row per finding)",
+		},
+		&cli.BoolFlag{
+			Name:  "csv-bom",
+			Usage: "CSV报告写入UTF-8 BOM（便于Excel打开） / Write a UTF-8 BOM to the CSV report (for Excel)",
+		},
+		&cli.BoolFlag{
+			Name:  "csv-mask",
+			Usage: "CSV报告中对匹配值脱敏 / Mask matched values in the CSV report",
+		},
+		&cli.StringFlag{
+			Name:  "clean-list",
+			Usage: "将扫描后没有任何结果的文件列表写入该文件（每行一个路径） / Write the list of scanned files with no findings to this file (one path per line)",
+		},
+		&cli.StringFlag{
+			Name:  "manifest",
+			Usage: "将扫描清单（时间、目标、生效的配置、扫描和跳过的文件数，不含匹配值）写入该JSON文件，用于审计 / Write a JSON scan manifest (time, target, effective config, scanned/skipped counts, no secret values) for audit trails",
+		},
+		&cli.StringFlag{
+			Name:  "syslog",
+			Usage: "将每条结果实时发送到syslog服务器（RFC 5424，[udp://|tcp://]主机:端口） / Stream each finding to a syslog server (RFC 5424, [udp://|tcp://]host:port)",
+		},
+		&cli.StringFlag{
+			Name:  "webhook",
+			Usage: "将结果按批以JSON POST到该地址 / POST findings in JSON batches to this URL",
+		},
+		&cli.IntFlag{
+			Name:  "json-raw-context",
+			Usage: "JSON中为二进制结果附带前后N字节原始数据（base64，最大1024） / Include N raw bytes around binary findings in JSON (base64, max 1024)",
+		},
 
 		// 文件类型参数
 		&cli.StringFlag{
@@ -52,12 +156,16 @@ func GetFlags() []cli.Flag {
 			Aliases: []string{"type-append"},
 			Usage:   "追加文件类型（逗号分隔） / Append file types (comma separated)",
 		},
+		&cli.StringFlag{
+			Name:  "exclude-ext",
+			Usage: "从文件类型中排除扩展名（逗号分隔，在 -t/-ta/-b 合并后生效） / Remove extensions from the file types (comma separated, applied after -t/-ta/-b)",
+		},
 
 		// 关键词参数
 		&cli.StringFlag{
 			Name:    "k",
 			Aliases: []string{"keyword"},
-			Usage:   "搜索关键词（逗号分隔，二进制模式下可为空） / Search keywords (comma separated, can be empty in binary mode)",
+			Usage:   "搜索关键词（逗号分隔，二进制模式下可为空；关键词:风险等级 指定命中时的等级，默认 medium） / Search keywords (comma separated, can be empty in binary mode; keyword:level sets the risk of its hits, default medium)",
 			Value:   DefaultKeywords,
 		},
 		&cli.StringFlag{
@@ -65,6 +173,14 @@ func GetFlags() []cli.Flag {
 			Aliases: []string{"keyword-append"},
 			Usage:   "追加关键词（逗号分隔） / Append keywords (comma separated)",
 		},
+		&cli.StringSliceFlag{
+			Name:  "keywords-file",
+			Usage: "从文件追加关键词（每行一个，# 开头为注释，可重复） / Append keywords from a file (one per line, # comments, repeatable)",
+		},
+		&cli.BoolFlag{
+			Name:  "keyword-ci",
+			Usage: "关键词匹配忽略大小写，全角字符按半角比较（报告保留原文） / Case-insensitive keyword matching, full-width characters compared as half-width (reports keep original text)",
+		},
 
 		// 性能参数
 		&cli.IntFlag{
@@ -73,12 +189,49 @@ func GetFlags() []cli.Flag {
 			Usage:   "线程数 / Number of threads",
 			Value:   runtime.NumCPU(),
 		},
+		&cli.BoolFlag{
+			Name:  "archive-fanout",
+			Usage: "JAR 中的类文件和 Office 文档中的嵌入对象分派到工作池中与其他文件并发扫描 / Scan JAR classes and Office embedded objects concurrently in the shared worker pool",
+		},
+		&cli.StringFlag{
+			Name:  "archive-memory",
+			Usage: "并发扫描时同时解压的条目总大小上限（可带 KB/MB/GB 单位） / Max total decompressed size of archive entries in flight with --archive-fanout (KB/MB/GB suffix allowed)",
+			Value: DefaultArchiveMemory,
+		},
 		&cli.BoolFlag{
 			Name:    "verbose",
 			Aliases: []string{"vb"},
 			Usage:   "实时输出扫描结果 / Real-time output scan results",
 			Value:   true,
 		},
+		&cli.BoolFlag{
+			Name:  "show-clean",
+			Usage: "扫描时为每个没有结果的文件输出一行 [ok] 路径，确认文件已被扫描 / Print an [ok] line for every file that was scanned without findings, to confirm it was reached",
+		},
+
+		&cli.StringFlag{
+			Name:  "log-level",
+			Usage: "日志级别（debug/info/warn/error），日志输出到标准错误 / Log level (debug/info/warn/error), logs go to stderr",
+			Value: "info",
+		},
+		&cli.StringFlag{
+			Name:   "pprof",
+			Usage:  "诊断用：在该地址（如 127.0.0.1:6060）启动 net/http/pprof 服务，扫描结束时关闭 / Diagnostics: serve net/http/pprof on this address (e.g. 127.0.0.1:6060) until the scan completes",
+			Hidden: true,
+		},
+		&cli.BoolFlag{
+			Name:  "interactive",
+			Usage: "遍历完成后显示待扫描文件数和总大小，确认后再开始扫描（仅标准输入为终端时询问） / Show the planned file count and size and ask before scanning (only when stdin is a TTY)",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"y"},
+			Usage:   "跳过 --interactive 的确认 / Skip the --interactive confirmation",
+		},
+		&cli.BoolFlag{
+			Name:  "count",
+			Usage: "仅统计待扫描文件数量、大小和扩展名分布，不解析内容 / Only report file counts, sizes and extensions without parsing",
+		},
 
 		// 高级参数
 		&cli.Int64Flag{
@@ -87,16 +240,44 @@ func GetFlags() []cli.Flag {
 			Usage:   "最大文件大小（MB，0表示不限制） / Max file size (MB, 0 means no limit)",
 			Value:   0,
 		},
+		&cli.StringFlag{
+			Name:  "min-size",
+			Usage: "最小文件大小，小于该值的文件跳过（字节，可带 KB/MB 单位，如 512、4KB） / Skip files smaller than this (bytes, KB/MB suffix allowed, e.g. 512, 4KB)",
+		},
 		&cli.StringFlag{
 			Name:    "ed",
 			Aliases: []string{"exclude-dir"},
 			Usage:   "排除目录（逗号分隔） / Exclude directories (comma separated)",
 		},
+		&cli.BoolFlag{
+			Name:  "auto-exclude",
+			Usage: "按扫描目录的项目类型（Node.js/Maven/Go/Python）自动排除依赖和构建目录并追加相关文件类型 / Detect the project type of each scan root and exclude its dependency/build directories automatically",
+		},
 		&cli.StringFlag{
 			Name:    "ef",
 			Aliases: []string{"exclude-file"},
 			Usage:   "排除文件模式（逗号分隔） / Exclude file patterns (comma separated)",
 		},
+		&cli.BoolFlag{
+			Name:  "skip-hidden",
+			Usage: "跳过以 . 开头的文件和目录 / Skip dot-prefixed files and directories",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-generated",
+			Usage: "跳过生成和压缩的文件：前几行带有生成标记（如 // Code generated ... DO NOT EDIT.）、压缩后的 JS/CSS（.min.js 或行长、空白少、符号密集的内容）和源码映射，计入跳过统计 / Skip generated and minified files: generated-file markers in the first lines, minified JS/CSS (.min.js or long, dense lines) and source maps; counted in skip statistics",
+		},
+		&cli.BoolFlag{
+			Name:  "include-git",
+			Usage: "扫描 .git 目录内部（默认跳过） / Scan inside .git directories (skipped by default)",
+		},
+		&cli.StringFlag{
+			Name:  "owner",
+			Usage: "只扫描属于该用户的文件（用户名或UID，仅Unix） / Only scan files owned by this user (name or UID, Unix only)",
+		},
+		&cli.StringFlag{
+			Name:  "group",
+			Usage: "只扫描属于该组的文件（组名或GID，仅Unix） / Only scan files owned by this group (name or GID, Unix only)",
+		},
 
 		// 二进制扫描参数
 		&cli.BoolFlag{
@@ -105,19 +286,189 @@ func GetFlags() []cli.Flag {
 			Usage:   "启用二进制文件扫描模式（DLL/EXE） / Enable binary file scan mode (DLL/EXE)",
 			Value:   false,
 		},
+		&cli.BoolFlag{
+			Name:  "raw-scan",
+			Usage: "所有文件按原始字节扫描（字符串提取、规则和Base64检查，不校验PE格式，报告偏移），并追加内存转储类型 " + DumpFileTypes + " / Scan every file as a raw byte blob (strings, rules and Base64, no PE check, offsets reported) and add dump types " + DumpFileTypes,
+		},
+		&cli.StringFlag{
+			Name:  "binary-fallback",
+			Usage: "不是有效PE的二进制文件（ELF、损坏的PE、原始数据等）的处理方式: skip 跳过，raw 按原始字节扫描（字符串提取、规则和Base64检查） / Handling of binary files that fail PE validation: skip, or raw to scan them as raw bytes (strings, rules and Base64)",
+			Value: "skip",
+		},
+		&cli.BoolFlag{
+			Name:  "scan-images",
+			Usage: "追加图片类型 " + ImageFileTypes + "，扫描 EXIF、XMP、JPEG 注释和 PNG 文本块中的元数据（不读取像素数据） / Add image types " + ImageFileTypes + " and scan EXIF, XMP, JPEG comments and PNG text chunks (pixel data is not read)",
+		},
+		&cli.StringFlag{
+			Name:  "keystore-password",
+			Usage: "Java 密钥库（.jks/.jceks/.keystore）和 PKCS#12（.p12/.pfx）的口令，用于列出其中的私钥和证书；未指定或不正确时尝试 changeit 等常见默认口令 / Password for Java keystores (.jks/.jceks/.keystore) and PKCS#12 files (.p12/.pfx) used to list stored keys and certificates; common defaults such as changeit are also tried",
+		},
+		&cli.StringSliceFlag{
+			Name:  "magic",
+			Usage: "自定义文件头签名（格式: 十六进制=raw|text，可重复），文件头匹配时优先于扩展名选择解析方式，用于字符串可提取的私有格式 / Custom file-header magic (format: hex=raw|text, repeatable); a matching header overrides extension-based routing, for proprietary formats with extractable strings",
+		},
+		&cli.BoolFlag{
+			Name:  "dual-scan",
+			Usage: "文本和二进制文件同时以两种方式扫描（32MB以内） / Scan text and binary files both ways (files up to 32MB)",
+		},
+		&cli.BoolFlag{
+			Name:  "dump-strings",
+			Usage: "将二进制文件中提取的全部字符串（偏移、编码、是否保留及原因）写入 输出文件名.strings.txt，用于排查规则未命中 / Write every string extracted from binary files (offset, encoding, kept or why not) to <output>.strings.txt for debugging missed rules",
+		},
+		&cli.Float64Flag{
+			Name:  "text-threshold",
+			Usage: "Base64解码内容视为文本的可打印字符最低比例（中文等多字节字符计为可打印） / Minimum printable ratio for decoded Base64 to count as text (multibyte characters count as printable)",
+			Value: 0.7,
+		},
+		&cli.IntFlag{
+			Name:  "max-per-rule",
+			Usage: "二进制文件中每条规则最多报告的结果数（0表示不限制） / Max findings per rule per binary file (0 = unlimited)",
+		},
+		&cli.IntFlag{
+			Name:  "max-strings",
+			Usage: "二进制扫描中每个文件最多检查的字符串数，达到上限时停止提取并警告（0表示不限制），用于防止异常文件耗时过长 / Max extracted strings checked per file in binary scans; extraction stops with a warning at the cap (0 = unlimited), a safety valve for pathological files",
+		},
+		&cli.IntFlag{
+			Name:  "intra-file-concurrency",
+			Usage: "二进制扫描中单个文件内并发匹配字符串和Base64候选的协程数，用于加速少量超大文件（如内存转储）的扫描，结果与依次匹配相同（0或1表示不并发） / Goroutines matching the strings and Base64 candidates of a single file in binary scans, to speed up a few huge files such as memory dumps; results are identical to serial matching (0 or 1 = serial)",
+		},
+		&cli.BoolFlag{
+			Name:  "merge-fragments",
+			Usage: "二进制扫描中将偏移相邻的字符串片段合并后再应用规则，检测被编译器拆分到多个字符串表项中的连接字符串（可能误拼接，结果标注合并的偏移范围） / In binary scans, concatenate strings at adjacent offsets before rule matching to catch connection strings split across string-table entries (may cause false joins; the merged offset range is reported)",
+		},
+		&cli.StringFlag{
+			Name:  "binary-min-risk",
+			Usage: "只报告不低于该风险等级的二进制结果（critical/high/medium/low），文本结果不受影响 / Only report binary findings at or above this risk level; text findings are unaffected",
+		},
 		&cli.IntFlag{
 			Name:    "ctx",
 			Aliases: []string{"context"},
-			Usage:   "上下文长度（字符数） / Context length (characters)",
+			Usage:   "上下文长度（字符数，匹配值以 ⟦ ⟧ 标出并完整保留） / Context length (characters; the match is kept whole and marked with ⟦ ⟧)",
 			Value:   150,
 		},
+
+		// 结果处理参数
+		&cli.StringFlag{
+			Name:  "dedupe-by",
+			Usage: "结果去重粒度（none/value/value+file/value+rule） / Dedup granularity (none/value/value+file/value+rule)",
+			Value: DedupeByNone,
+		},
+		&cli.IntFlag{
+			Name:  "value-max-len",
+			Usage: "所有输出中匹配值的最大长度（字符数，0表示不截断） / Max length of matched values in all outputs (characters, 0 = no truncation)",
+		},
+		&cli.BoolFlag{
+			Name:  "summary-only",
+			Usage: "仅输出风险统计摘要，不输出具体结果和HTML报告 / Write only the aggregate risk summary, no individual findings or HTML report",
+		},
+		&cli.BoolFlag{
+			Name:  "dir-summary",
+			Usage: "扫描结束后在控制台按目录汇总结果数和最高风险等级 / Print a per-directory rollup of finding counts and max severity after the scan",
+		},
+		&cli.BoolFlag{
+			Name:  "blame",
+			Usage: "对 Git 仓库中按行号定位的结果运行 git blame，记录最后修改该行的提交、作者和日期，扫描结束后列出存在时间最长的结果 / Run git blame on line-based findings in Git repositories to record the commit, author and date, and list the oldest findings after the scan",
+		},
+		&cli.BoolFlag{
+			Name:  "unused-rules",
+			Usage: "扫描结束后在控制台列出整个扫描中没有产生任何结果的关键词和规则，用于精简关键词和规则列表 / List keywords and rules that produced no findings across the whole scan, to help prune keyword and rule lists",
+		},
+		&cli.BoolFlag{
+			Name:  "relative-paths",
+			Usage: "报告中使用相对于扫描目录的路径 / Use paths relative to the scan root in reports",
+		},
+		&cli.StringFlag{
+			Name:  "path-separator",
+			Usage: "报告中文件路径的分隔符：slash 统一使用 /，native 使用操作系统的分隔符（Windows 上为 \\） / Path separator in reports: slash always uses /, native uses the OS separator (\\ on Windows)",
+			Value: PathSeparatorSlash,
+		},
+		&cli.StringFlag{
+			Name:  "cache",
+			Usage: "扫描缓存文件，未变化的文件直接使用上次结果 / Scan cache file, unchanged files reuse previous results",
+		},
+		&cli.BoolFlag{
+			Name:  "incremental",
+			Usage: "增量扫描：只解析上次扫描后变化的文件，未变化文件沿用上次结果，已删除文件的结果移除（状态按目录保存在用户缓存目录，指定 --cache 时使用该文件） / Incremental scan: only parse files changed since the last run, reuse results for the rest and drop deleted files (state is kept per directory in the user cache dir, or in --cache if given)",
+		},
+		&cli.IntFlag{
+			Name:  "max-findings",
+			Usage: "累计结果达到N条后停止扫描并输出已有结果（指定 --fail-on 时只计不低于该等级的结果） / Stop the scan and write a partial report once N findings accumulate (only findings at or above --fail-on when set)",
+		},
+		&cli.Int64Flag{
+			Name:  "seed",
+			Usage: "按该种子确定文件的处理顺序，结果序号、去重保留的文件和 --max-findings 截止的文件在多次运行和不同机器间保持一致（0表示按完成顺序） / Process files in an order derived from this seed so result numbering, dedupe winners and the --max-findings cutoff are reproducible across runs and machines (0 means completion order)",
+		},
+		&cli.StringFlag{
+			Name:  "fail-on",
+			Usage: "存在不低于该风险等级的结果时以退出码1结束（critical/high/medium/low） / Exit with status 1 when any finding is at or above this level (critical/high/medium/low)",
+		},
+
+		// 规则参数
+		&cli.StringFlag{
+			Name:  "only-rules",
+			Usage: "仅启用指定规则（规则名称，逗号分隔） / Enable only these rules (rule names, comma separated)",
+		},
+		&cli.StringFlag{
+			Name:  "skip-rules",
+			Usage: "禁用指定规则（规则名称，逗号分隔） / Disable these rules (rule names, comma separated)",
+		},
+		&cli.StringFlag{
+			Name:  "no-validate",
+			Usage: "不校验指定规则的匹配值，直接报告正则匹配的结果（规则名称，逗号分隔） / Report raw regex matches of these rules without value validation (rule names, comma separated)",
+		},
+		&cli.StringFlag{
+			Name:  "tags",
+			Usage: "仅启用带有指定标签的规则（如 cloud,db,pii,key，逗号分隔） / Enable only rules carrying these tags (e.g. cloud,db,pii,key, comma separated)",
+		},
+		&cli.BoolFlag{
+			Name:  "all-matches",
+			Usage: "同一行命中多条规则时全部报告（默认只保留优先级最高的） / Report every rule matching a line (default: highest priority only)",
+		},
+		&cli.DurationFlag{
+			Name:  "rule-timeout",
+			Usage: "规则匹配单个字符串的时间上限，超过后跳过该字符串上剩余的规则并记录警告（如 100ms，0表示不限制） / Per-string time budget for rule matching; remaining rules are skipped and logged once it is exceeded (e.g. 100ms, 0 = no limit)",
+		},
+		&cli.BoolFlag{
+			Name:  "weak-passwords",
+			Usage: "检查匹配到的口令是否为弱口令（识别 p@ssw0rd 等字符替换） / Flag weak/known passwords among matched values (handles leetspeak)",
+		},
+		&cli.StringFlag{
+			Name:  "secret-words",
+			Usage: "高熵赋值规则的敏感变量名关键词（逗号分隔，变量名包含任一关键词且值为高熵字符串时报告，为空时禁用该规则） / Variable-name words for the high-entropy assignment rule (comma separated; empty disables the rule)",
+			Value: DefaultSecretWords,
+		},
+		&cli.Float64Flag{
+			Name:  "entropy-threshold",
+			Usage: "高熵赋值规则的信息熵阈值（每字符比特数，0-8） / Shannon entropy threshold (bits per char, 0-8) for the high-entropy assignment rule",
+			Value: DefaultEntropyThreshold,
+		},
+		&cli.StringSliceFlag{
+			Name:  "rules",
+			Usage: "规则配置文件（JSON，可重复，合并所有文件中的规则） / Rules config file (JSON, repeatable; rules from all files are merged)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "severity-override",
+			Usage: "风险等级覆盖（格式: 路径通配符|规则名=等级，可重复） / Severity override (format: path-glob|rule=level, repeatable)",
+		},
+		&cli.StringFlag{
+			Name:  "severity-weights",
+			Usage: "风险评分中各风险等级的权重（格式: 等级=权重，逗号分隔），未指定的等级使用默认权重 critical=10,high=5,medium=2,low=1 / Per-level weights for the risk score (format: level=weight, comma separated); unspecified levels use the defaults critical=10,high=5,medium=2,low=1",
+		},
+		&cli.StringFlag{
+			Name:  "test-paths",
+			Usage: "测试数据目录（逗号分隔，按相对路径中的目录名匹配，不区分大小写，可指定多级目录如 src/test），其中文件的结果风险等级降为最低等级并单独统计 / Test fixture directories (comma separated, matched against directory names in the relative path, case-insensitive, multi-level like src/test allowed); findings in them are demoted to the lowest risk level and counted separately",
+		},
+		&cli.StringFlag{
+			Name:  "hash-list",
+			Usage: "已知文件SHA-256列表，命中的文件报告为严重（不受文件类型限制） / Known-bad file SHA-256 list; matching files are reported as critical regardless of type",
+		},
 	}
 }
 
 // ParseConfig 从 cli.Context 解析配置
 func ParseConfig(c *cli.Context) (*Config, error) {
 	// 获取基础参数
-	directory := c.String("f")
+	directories := parseDirectories(c.StringSlice("f"))
 	output := c.String("o")
 
 	// 合并文件类型
@@ -132,10 +483,36 @@ func ParseConfig(c *cli.Context) (*Config, error) {
 		fileTypes = append(fileTypes, binaryTypes...)
 	}
 
-	// 合并关键词
-	keywords := parseList(c.String("k"))
+	// 原始字节扫描模式，添加内存转储文件类型
+	if c.Bool("raw-scan") {
+		fileTypes = append(fileTypes, parseList(DumpFileTypes)...)
+	}
+
+	// 扫描图片元数据，添加图片文件类型
+	if c.Bool("scan-images") {
+		fileTypes = append(fileTypes, parseList(ImageFileTypes)...)
+	}
+
+	// 排除指定的扩展名
+	excludeExts := normalizeExts(parseList(c.String("exclude-ext")))
+	fileTypes = excludeFileTypes(fileTypes, excludeExts)
+
+	// 合并关键词，多个来源中相同的关键词只保留一个
+	keywordList := parseList(c.String("k"))
 	if appendKeywords := c.String("ka"); appendKeywords != "" {
-		keywords = append(keywords, parseList(appendKeywords)...)
+		keywordList = append(keywordList, parseList(appendKeywords)...)
+	}
+	keywordSeen := make(map[string]bool)
+	keywords, _, _ := appendUnique(nil, keywordSeen, keywordList)
+	var loadedSources []LoadedSource
+	for _, path := range c.StringSlice("keywords-file") {
+		fileKeywords, err := LoadKeywordsFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var added, duplicates int
+		keywords, added, duplicates = appendUnique(keywords, keywordSeen, fileKeywords)
+		loadedSources = append(loadedSources, LoadedSource{Kind: "关键词文件", Path: path, Loaded: added, Duplicates: duplicates})
 	}
 
 	// 解析排除规则
@@ -155,25 +532,231 @@ func ParseConfig(c *cli.Context) (*Config, error) {
 		htmlOutput = strings.TrimSuffix(output, ".txt") + ".html"
 	}
 
+	// 字符串转储路径与输出文件同名
+	dumpStrings := ""
+	if c.Bool("dump-strings") {
+		dumpStrings = strings.TrimSuffix(output, ".txt") + ".strings.txt"
+	}
+
+	// 解析最小文件大小
+	minFileSize, err := parseSize(c.String("min-size"))
+	if err != nil {
+		return nil, fmt.Errorf("--min-size 无效: %w", err)
+	}
+
+	archiveMemory, err := parseSize(c.String("archive-memory"))
+	if err != nil {
+		return nil, fmt.Errorf("--archive-memory 无效: %w", err)
+	}
+
 	// 创建配置对象
 	config := &Config{
-		FileTypes:     fileTypes,
-		Keywords:      keywords,
-		OutputFile:    output,
-		HTMLOutput:    htmlOutput,
-		Directory:     directory,
-		Verbose:       c.Bool("verbose"),
-		ThreadCount:   threadCount,
-		MaxFileSize:   c.Int64("s") * 1024 * 1024, // 转换为字节
-		ExcludeDirs:   excludeDirs,
-		ExcludeFiles:  excludeFiles,
-		BinaryMode:    c.Bool("b"),
-		ContextLength: c.Int("ctx"),
+		FileTypes:        fileTypes,
+		ExcludeExts:      excludeExts,
+		Keywords:         keywords,
+		KeywordCI:        c.Bool("keyword-ci"),
+		OutputFile:       output,
+		OutputFormat:     strings.ToLower(c.String("format")),
+		OutputEncoding:   strings.ToLower(c.String("output-encoding")),
+		HTMLOutput:       htmlOutput,
+		HTMLSort:         c.String("html-sort"),
+		HTMLTheme:        c.String("html-theme"),
+		EditorLinks:      c.String("editor-links"),
+		JSONOutput:       c.String("json"),
+		CSVOutput:        c.String("csv"),
+		CSVBOM:           c.Bool("csv-bom"),
+		CSVMask:          c.Bool("csv-mask"),
+		CleanList:        c.String("clean-list"),
+		Manifest:         c.String("manifest"),
+		Syslog:           c.String("syslog"),
+		Webhook:          c.String("webhook"),
+		DumpStrings:      dumpStrings,
+		Directories:      directories,
+		DockerImage:      c.String("docker-image"),
+		StdinContent:     c.Bool("stdin-content"),
+		GitToken:         c.String("git-token"),
+		Verbose:          c.Bool("verbose"),
+		ShowClean:        c.Bool("show-clean"),
+		ThreadCount:      threadCount,
+		ArchiveFanout:    c.Bool("archive-fanout"),
+		ArchiveMemory:    archiveMemory,
+		MaxFileSize:      c.Int64("s") * 1024 * 1024, // 转换为字节
+		MinFileSize:      minFileSize,
+		ExcludeDirs:      excludeDirs,
+		AutoExclude:      c.Bool("auto-exclude"),
+		SkipHidden:       c.Bool("skip-hidden"),
+		SkipGenerated:    c.Bool("skip-generated"),
+		IncludeGit:       c.Bool("include-git"),
+		Owner:            c.String("owner"),
+		Group:            c.String("group"),
+		ExcludeFiles:     excludeFiles,
+		BinaryMode:       c.Bool("b"),
+		DualScan:         c.Bool("dual-scan"),
+		RawScan:          c.Bool("raw-scan"),
+		BinaryFallback:   strings.ToLower(c.String("binary-fallback")),
+		ScanImages:       c.Bool("scan-images"),
+		KeystorePassword: c.String("keystore-password"),
+		MaxPerRule:       c.Int("max-per-rule"),
+		MaxStrings:       c.Int("max-strings"),
+		MergeFragments:   c.Bool("merge-fragments"),
+		IntraFileWorkers: c.Int("intra-file-concurrency"),
+		BinaryMinRisk:    strings.ToLower(c.String("binary-min-risk")),
+		TextThreshold:    c.Float64("text-threshold"),
+		ContextLength:    c.Int("ctx"),
+		JSONRawContext:   c.Int("json-raw-context"),
+		LogLevel:         c.String("log-level"),
+		Pprof:            c.String("pprof"),
+		CountOnly:        c.Bool("count"),
+		Interactive:      c.Bool("interactive"),
+		AssumeYes:        c.Bool("yes"),
+		DedupeBy:         c.String("dedupe-by"),
+		ValueMaxLen:      c.Int("value-max-len"),
+		CacheFile:        c.String("cache"),
+		Incremental:      c.Bool("incremental"),
+		MaxFindings:      c.Int("max-findings"),
+		Seed:             c.Int64("seed"),
+		FailOn:           strings.ToLower(c.String("fail-on")),
+		RelativePaths:    c.Bool("relative-paths"),
+		PathSeparator:    strings.ToLower(c.String("path-separator")),
+		SummaryOnly:      c.Bool("summary-only"),
+		DirSummary:       c.Bool("dir-summary"),
+		Blame:            c.Bool("blame"),
+		UnusedRules:      c.Bool("unused-rules"),
+		NoBOM:            c.Bool("no-bom"),
+		Atomic:           c.Bool("atomic"),
+		FlushEach:        c.Bool("flush-each"),
+		SplitOutput:      c.Bool("split-output"),
+		OnlyRules:        parseList(c.String("only-rules")),
+		Tags:             parseList(c.String("tags")),
+		SkipRules:        parseList(c.String("skip-rules")),
+		NoValidateRules:  parseList(c.String("no-validate")),
+		TestPaths:        parseList(c.String("test-paths")),
+		AllMatches:       c.Bool("all-matches"),
+		RuleTimeout:      c.Duration("rule-timeout"),
+		WeakPasswords:    c.Bool("weak-passwords"),
+		SecretWords:      parseList(c.String("secret-words")),
+		EntropyThreshold: c.Float64("entropy-threshold"),
+		KeywordsFiles:    c.StringSlice("keywords-file"),
+		RulesFiles:       c.StringSlice("rules"),
+		LoadedSources:    loadedSources,
+		HashListFile:     c.String("hash-list"),
+		SensitiveFiles:   append([]string{}, DefaultSensitiveFiles...),
+	}
+
+	// 未指定输出编码时按 --no-bom 和输出格式决定是否写入 BOM，单行格式用于 grep 等命令行工具，不写入 BOM
+	if config.OutputEncoding == "" {
+		config.OutputEncoding = OutputEncodingUTF8BOM
+		if config.NoBOM || config.OutputFormat == OutputFormatFlat {
+			config.OutputEncoding = OutputEncodingUTF8
+		}
+	}
+
+	// 增量扫描未指定缓存文件时，按扫描目录使用用户缓存目录中的状态文件
+	if config.Incremental && config.CacheFile == "" && !config.StdinContent && config.DockerImage == "" && !config.IsRemoteRepo() {
+		statePath, err := IncrementalStatePath(config.Directories)
+		if err != nil {
+			return nil, err
+		}
+		config.CacheFile = statePath
+	}
+
+	// 自定义文件头签名
+	for _, s := range c.StringSlice("magic") {
+		signature, err := ParseMagicSignature(s)
+		if err != nil {
+			return nil, fmt.Errorf("--magic 无效: %w", err)
+		}
+		config.MagicSignatures = append(config.MagicSignatures, signature)
+	}
+
+	// 命令行覆盖规则优先于规则文件
+	for _, s := range c.StringSlice("severity-override") {
+		override, err := ParseSeverityOverride(s)
+		if err != nil {
+			return nil, err
+		}
+		config.SeverityOverrides = append(config.SeverityOverrides, override)
+	}
+
+	// 风险评分权重（等级名称在规则文件加载后校验）
+	if s := c.String("severity-weights"); s != "" {
+		weights, err := ParseSeverityWeights(s)
+		if err != nil {
+			return nil, err
+		}
+		config.SeverityWeights = weights
+	}
+
+	// 加载规则配置文件
+	if err := config.loadRulesFiles(); err != nil {
+		return nil, err
+	}
+
+	// 拆分关键词指定的风险等级（规则文件可能自定义了风险等级体系，需在加载后拆分）
+	config.Keywords, config.KeywordRisks = splitKeywordRisks(config.Keywords)
+
+	// 加载已知文件哈希列表
+	if config.HashListFile != "" {
+		hashes, err := LoadHashList(config.HashListFile)
+		if err != nil {
+			return nil, err
+		}
+		config.KnownHashes = hashes
 	}
 
 	return config, nil
 }
 
+// normalizeExts 为扩展名补全前导的点（log -> .log）
+func normalizeExts(exts []string) []string {
+	for i, ext := range exts {
+		if !strings.HasPrefix(ext, ".") {
+			exts[i] = "." + ext
+		}
+	}
+	return exts
+}
+
+// excludeFileTypes 从文件类型列表中去除指定的扩展名（不区分大小写）
+func excludeFileTypes(fileTypes, exclude []string) []string {
+	if len(exclude) == 0 {
+		return fileTypes
+	}
+
+	result := make([]string, 0, len(fileTypes))
+	for _, fileType := range fileTypes {
+		excluded := false
+		for _, ext := range exclude {
+			if strings.EqualFold(fileType, ext) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			result = append(result, fileType)
+		}
+	}
+	return result
+}
+
+// parseDirectories 整理扫描目录列表，去除空项和重复的目录（远程仓库地址保持原样）
+func parseDirectories(values []string) []string {
+	var dirs []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, dir := range parseList(value) {
+			if !IsRemoteGitURL(dir) {
+				dir = filepath.Clean(dir)
+			}
+			if !seen[dir] {
+				seen[dir] = true
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	return dirs
+}
+
 // parseList 解析逗号分隔的列表
 func parseList(s string) []string {
 	if s == "" {
@@ -193,6 +776,32 @@ func parseList(s string) []string {
 	return result
 }
 
+// parseSize 解析文件大小，不带单位时为字节，支持 B/KB/MB/GB 后缀（不区分大小写，可省略 B）
+func parseSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		size   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.size
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("无法解析的大小: %s", value)
+	}
+	return n * multiplier, nil
+}
+
 // GetAppInfo 返回应用信息
 func GetAppInfo() (name, usage, version string) {
 	return "Findx",
@@ -225,12 +834,39 @@ func GetExamples() string {
   # 指定文件类型和关键词 / Specify file types and keywords
   findx -f /path/to/scan -t .txt,.log -k "password,token"
 
+  # 为关键词指定风险等级 / Set the risk level of individual keywords
+  findx -f /path/to/scan -k "password=,AKIA:critical,内部文件:low"
+
+  # 高熵赋值规则只检查指定变量名，提高阈值减少误报 / Entropy rule on selected names with a stricter threshold
+  findx -f /path/to/scan --secret-words "secret,token,apikey" --entropy-threshold 4
+
+  # 关键词匹配忽略大小写和全角/半角 / Case- and width-insensitive keyword matching
+  findx -f /path/to/scan --keyword-ci
+
   # 自定义输出文件和HTML报告名称 / Custom output and HTML report names
   findx -f /path/to/scan -o result.txt --html report.html
 
+  # 每条结果一行，便于 grep/awk 处理 / One line per finding for grep/awk
+  findx -f /path/to/scan --format flat | grep ':critical:'
+
+  # HTML报告按结果数量排序 / Order HTML report files by finding count
+  findx -f /path/to/scan --html-sort count
+
+  # 深色主题的HTML报告 / Dark-themed HTML report
+  findx -f /path/to/scan --html-theme dark
+
+  # HTML报告中点击行号直接在 VS Code 中打开 / Click line numbers in the HTML report to open VS Code
+  findx -f /path/to/scan --editor-links vscode
+
+  # 默认文件类型中排除日志 / Default file types except logs
+  findx -f /path/to/scan --exclude-ext .log
+
   # 扫描Java项目 / Scan Java project
   findx -f /path/to/java-project -t .java,.properties,.xml -k "password,jdbc"
 
+  # 一次扫描多个目录并合并报告 / Scan several directories into one report
+  findx -f /srv/app1 -f /srv/app2,/etc/nginx
+
   # 扫描Python项目，排除虚拟环境 / Scan Python project, exclude virtual environment
   findx -f /path/to/python-project -t .py,.ini,.yaml -ed "venv,.venv"
 
@@ -243,18 +879,181 @@ func GetExamples() string {
   # 扫描二进制文件（方式3：追加二进制类型）/ Scan binary files (method 3: append binary types)
   findx -ta .dll,.exe -f /path/to/binaries
 
+  # 扫描内存转储等任意二进制数据 / Scan memory dumps and other raw blobs
+  findx --raw-scan -k "" -f /path/to/dumps
+
+  # 扫描 .bin/.so 等二进制文件，不是有效PE的文件按原始字节扫描 / Scan non-PE binaries as raw bytes instead of skipping them
+  findx -b -k "" -f /path/to/firmware --binary-fallback raw
+
+  # 以 PK\x07\x08 开头的私有格式按原始字节扫描 / Scan a proprietary format starting with PK\x07\x08 as raw bytes
+  findx -ta .dat --magic 504b0708=raw -f /path/to/data
+
+  # 扫描图片 EXIF/XMP 元数据中的敏感信息 / Scan image EXIF/XMP metadata for secrets
+  findx --scan-images -f /path/to/photos
+
+  # 用指定口令列出密钥库中的私钥和证书 / List keys and certificates in keystores with a given password
+  findx -f /path/to/config --keystore-password "s3cret"
+
   # 扫描二进制文件，只使用规则匹配（不使用关键字）/ Scan binary files with rules only (no keywords)
   findx -b -k "" -f /path/to/binaries
 
   # 扫描二进制文件并自定义上下文长度 / Scan binary files with custom context length
   findx -b -f /path/to/binaries --ctx 200
 
+  # 转储二进制文件中提取的字符串，排查规则未命中 / Dump extracted strings to see why a rule missed
+  findx -b --dump-strings -f /path/to/binaries
+
+  # 每个二进制文件中每条规则最多报告20条 / At most 20 findings per rule per binary file
+  findx -b -f /path/to/binaries --max-per-rule 20
+
+  # 每个文件最多检查100万个字符串，防止异常的内存转储耗时过长 / Check at most 1M strings per file to bound pathological dumps
+  findx --raw-scan -f /path/to/dumps --max-strings 1000000
+
+  # 用8个协程并发匹配单个超大内存转储中的字符串 / Match the strings of a single huge memory dump with 8 goroutines
+  findx --raw-scan -f /path/to/core.dump --intra-file-concurrency 8
+
+  # 检测被拆分到多个字符串表项中的连接字符串 / Detect connection strings split across string-table entries
+  findx -b -f /path/to/binaries --merge-fragments
+
+  # 二进制结果只报告高危及以上，文本结果全部保留 / Only high+ binary findings, all text findings
+  findx -b -f /path/to/scan --binary-min-risk high
+
+  # 输出JSON报告，并为二进制结果附带原始字节 / JSON report with raw bytes for binary findings
+  findx -b -f /path/to/binaries --json result.json --json-raw-context 32
+
+  # 直接扫描远程Git仓库（浅克隆到临时目录，扫描后删除） / Scan a remote Git repository (shallow clone, removed afterwards)
+  findx -f https://github.com/org/repo.git
+  FINDX_GIT_TOKEN=ghp_xxx findx -f https://github.com/org/private.git
+
+  # 扫描Docker镜像各层 / Scan Docker image layers
+  findx --docker-image nginx:latest -ta .conf,.env
+  findx --docker-image image.tar
+
+  # 扫描管道输入的内容 / Scan piped content
+  cat app.conf | findx --stdin-content -t .txt
+  kubectl get secret db -o yaml | findx --stdin-content -t .yaml
+
+  # 只输出结果，日志仅保留错误 / Keep stdout for results, only errors on stderr
+  findx -f /path/to/scan --log-level error 2>/dev/null
+
+  # 正式扫描前评估扫描范围 / Estimate scan scope before a real run
+  findx -f /path/to/scan --count -ed "node_modules,.git"
+
+  # 按项目类型自动排除 node_modules、target/ 等目录 / Exclude node_modules, target/ etc. by project type
+  findx -f /path/to/node-app --auto-exclude
+
+  # 扫描大目录前确认文件数和总大小 / Confirm the workload before scanning a large tree
+  findx -f / --interactive
+
+  # 导出CSV结果清单（Excel打开，匹配值脱敏） / Export a CSV of all findings (for Excel, values masked)
+  findx -f /path/to/scan --csv findings.csv --csv-bom --csv-mask
+
+  # 记录已检查且没有结果的文件，用于合规证明 / Record files that were checked and found clean for attestation
+  findx -f /path/to/scan --clean-list clean-files.txt
+
+  # 扫描时逐个显示没有结果的文件 / Show every clean file as it is scanned
+  findx -f /path/to/scan --show-clean
+
+  # 记录扫描范围和配置，作为审计凭证 / Record what was scanned and with which settings for auditors
+  findx -f /path/to/scan --manifest manifest.json
+
+  # 定时扫描时将结果实时发送到SIEM / Stream findings to a SIEM during scheduled scans
+  findx -f /srv --syslog tcp://siem.example.com:6514
+  findx -f /srv --webhook https://collector.example.com/findx
+
+  # 长时间扫描时实时查看结果 / Follow results of a long scan with tail -f
+  findx -f / --flush-each -o res.txt & tail -f res.txt
+
+  # 按风险等级拆分文本结果，分别交给不同的人员处理 / One text file per risk level for separate reviewers
+  findx -f /path/to/scan -o res.txt --split-output
+
+  # 跳过所有隐藏文件和目录 / Skip all dotfiles and dot-directories
+  findx -f /path/to/scan --skip-hidden
+
+  # 跳过生成的代码和压缩的 JS/CSS / Skip generated code and minified JS/CSS
+  findx -f /path/to/webapp -ta .js,.css --skip-generated
+
+  # 只扫描某个用户的文件（多用户系统审计） / Only scan one user's files (multi-tenant audit)
+  findx -f /home --owner alice --group staff
+
   # 高性能扫描 / High performance scan
   findx -f /path/to/scan -n 16 -s 10 --verbose=false -ed "node_modules,.git"
 
+  # 大型JAR和Office文档的条目与其他文件并发扫描 / Fan out entries of large JARs and Office documents across all workers
+  findx -f /path/to/libs -t .jar,.docx --archive-fanout --archive-memory 512MB
+
   # 同时扫描文本和二进制文件 / Scan both text and binary files
   findx -t .txt,.log,.dll,.exe -f /path/to/scan
 
+  # 测试目录降级、生产配置升级 / Downgrade test dirs, upgrade production configs
+  findx -f /path/to/scan --severity-override "**/test/**|=low" --severity-override "prod/*.yml|密码字段=critical"
+
+  # 提高严重和高危结果在风险评分中的权重，HTML报告按评分排序 / Weight critical and high findings more in the risk score and rank files by it
+  findx -f /path/to/scan --severity-weights critical=20,high=8 --html-sort score
+
+  # 测试数据目录中的结果降为最低风险等级 / Demote findings under test fixture directories
+  findx -f /path/to/scan --test-paths testdata,fixtures,examples
+
+  # 每个文件内相同的敏感值只报告一次 / Report each secret once per file
+  findx -f /path/to/scan --dedupe-by value+file
+
+  # 匹配值最多显示64个字符（如很长的证书或私钥） / Show at most 64 characters of each matched value
+  findx -f /path/to/scan --value-max-len 64
+
+  # 只查找私钥和API密钥 / Hunt only for private keys and API keys
+  findx -f /path/to/scan --only-rules "私钥文件,API密钥"
+
+  # 只启用云服务和个人信息相关的规则 / Only enable cloud and PII rules
+  findx -f /path/to/scan --tags cloud,pii
+
+  # 跳过噪音较大的规则 / Skip noisy rules
+  findx -f /path/to/scan --skip-rules "邮箱地址,IP地址和端口"
+
+  # 仅输出风险统计，不暴露具体敏感值 / Aggregate counts only, no secret values
+  findx -f /path/to/scan --summary-only
+
+  # 按目录查看结果分布，优先处理问题最多的目录 / Per-directory rollup to triage the worst parts of a tree
+  findx -f /path/to/scan --dir-summary
+
+  # 标注结果的引入时间，优先处理长期暴露的敏感信息 / Record when each secret was committed and list the oldest
+  findx -f /path/to/repo --blame --json res.json
+
+  # 找出在代码库中从未命中的自定义关键词，精简关键词列表 / Find custom keywords that never matched, to prune the list
+  findx -f /path/to/scan --keywords-file keywords.txt --unused-rules
+
+  # 生成可分享的报告（不包含本机目录结构） / Shareable reports without local directory layout
+  findx -f /path/to/scan --relative-paths
+
+  # Windows 上报告中的路径保留反斜杠 / Keep backslash paths in reports on Windows
+  findx -f C:\scan --path-separator native
+
+  # 文本结果以GBK编码写入，供只能读取GBK的旧工具使用 / Write the text results in GBK for legacy tools
+  findx -f /path/to/scan --output-encoding gbk
+
+  # 反复扫描同一目录时跳过未变化的文件 / Skip unchanged files on repeated scans
+  findx -f /path/to/scan --cache .findx-cache.json
+
+  # 定时任务中只解析上次扫描后变化的文件，仍生成完整报告 / Cron job: only parse changed files but still write a full report
+  findx -f /path/to/scan --incremental
+
+  # CI中发现第一个严重结果即停止并返回失败 / Stop at the first critical finding and fail the CI job
+  findx -f /path/to/scan --max-findings 1 --fail-on critical
+
+  # 审计时可复现的部分扫描：相同种子下截止的文件和去重结果相同 / Reproducible partial scan for audits: same seed, same files and dedupe winners
+  findx -f /path/to/scan --max-findings 100 --dedupe-by value --seed 20261016
+
+  # 对比两次扫描的JSON报告 / Compare two JSON scan reports
+  findx diff --html diff.html old.json new.json
+
+  # 查找已泄露的密钥文件等已知文件 / Find known leaked key files and other known-bad blobs
+  findx -f /path/to/scan --hash-list known-bad.sha256
+
+  # 使用规则配置文件 / Use rules config file
+  findx -f /path/to/scan --rules rules.json
+
+  # 组合公共规则和项目规则，并从文件追加关键词 / Compose a shared base ruleset with project rules and keyword files
+  findx -f /path/to/scan --rules base-rules.json --rules project-rules.json --keywords-file keywords.txt
+
   # 使用所有简写参数 / Use all short flags
   findx -f /path/to/scan -t .txt,.log -k "password,token" -n 8 -s 10 -ed ".git" -ef "*.min.js"`
 }
@@ -262,40 +1061,132 @@ func GetExamples() string {
 // GetUsageText 返回使用说明
 func GetUsageText() string {
 	return `findx [全局选项] / findx [global options]
+   findx diff [--html 报告.html] old.json new.json / Compare two JSON scan reports
 
 参数说明 / Flag Description:
   简写和全称都可以使用 / Both short and long forms are available
   
   基础参数 / Basic Flags:
-    -f, --folder      扫描目录（必填）
+    -f, --folder      扫描目录或远程Git仓库地址（必填，可指定多个目录）
+    --docker-image    扫描Docker镜像（镜像名或导出的tar）
+    --stdin-content   扫描标准输入的内容
+    --git-token       克隆远程Git仓库的访问令牌
     -o, --output      输出文件路径
+    --format          文本结果格式（text/flat）
+    --html-sort       HTML报告文件排序方式（path/count/score）
+    --html-theme      HTML报告主题（light/dark/auto）
+    --editor-links    HTML报告中的编辑器链接（vscode/idea/file）
+    --no-bom          输出文件不写入UTF-8 BOM
+    --output-encoding 文本结果文件的编码（utf-8/utf-8-bom/gbk）
+    --atomic          文本结果扫描完成后再写入输出文件
+    --flush-each      每个文件的结果写入后立即刷新输出文件
+    --split-output    文本结果按风险等级拆分为多个文件
+    --json            JSON报告文件路径
+    --csv             CSV报告文件路径
+    --csv-bom         CSV报告写入UTF-8 BOM
+    --csv-mask        CSV报告中对匹配值脱敏
+    --clean-list      没有结果的文件列表路径
+    --manifest        扫描清单（JSON）路径
+    --syslog          实时发送结果到syslog服务器
+    --webhook         实时POST结果到webhook地址
   
   文件类型 / File Types:
     -t, --type        指定文件类型
     -ta, --type-append 追加文件类型
+    --exclude-ext     从文件类型中排除扩展名
   
   关键词 / Keywords:
     -k, --keyword     搜索关键词（二进制模式可为空）
     -ka, --keyword-append 追加关键词
+    --keywords-file   从文件追加关键词（可重复）
+    --keyword-ci      关键词匹配忽略大小写和全角/半角
   
   性能 / Performance:
     -n, --thread      线程数
+    --archive-fanout  压缩包条目在工作池中并发扫描
+    --archive-memory  并发扫描时同时解压的条目总大小上限
     --verbose, --vb   实时输出
+    --show-clean      为每个没有结果的文件输出 [ok] 路径
+    --log-level       日志级别（debug/info/warn/error）
+    --count           仅统计扫描范围，不解析文件
+    --interactive     扫描前确认待扫描文件数和大小
+    -y, --yes         跳过 --interactive 的确认
   
   高级 / Advanced:
     -s, --max-size    最大文件大小
+    --min-size        最小文件大小（可带 KB/MB 单位）
     -ed, --exclude-dir 排除目录
+    --auto-exclude    按项目类型自动排除依赖和构建目录
     -ef, --exclude-file 排除文件
+    --skip-hidden     跳过以 . 开头的文件和目录
+    --skip-generated  跳过生成和压缩的文件
+    --include-git     扫描 .git 目录内部
+    --owner           只扫描属于该用户的文件
+    --group           只扫描属于该组的文件
   
   二进制 / Binary:
     -b, --binary      二进制扫描模式
     --ctx, --context  上下文长度（字符数）
+    --dual-scan       同时以文本和二进制方式扫描
+    --raw-scan        所有文件按原始字节扫描（内存转储）
+    --binary-fallback 非PE二进制文件的处理方式（skip/raw）
+    --scan-images     扫描图片元数据（EXIF/XMP/PNG文本块）
+    --keystore-password 密钥库（JKS/PKCS#12）口令
+    --magic           自定义文件头签名（十六进制=raw|text）
+    --dump-strings    转储二进制文件中提取的字符串
+    --max-per-rule    每条规则最多报告的结果数
+    --max-strings     每个文件最多检查的字符串数
+    --merge-fragments 合并相邻的字符串片段后应用规则
+    --intra-file-concurrency 单个文件内并发匹配的协程数
+    --binary-min-risk 二进制结果的最低风险等级
+    --text-threshold  Base64解码内容视为文本的最低可打印比例
+    --json-raw-context JSON中附带的原始字节长度
+  
+  结果处理 / Results:
+    --dedupe-by       结果去重粒度（none/value/value+file/value+rule）
+    --value-max-len   匹配值的最大长度（0表示不截断）
+    --summary-only    仅输出风险统计摘要
+    --dir-summary     按目录汇总结果数和最高风险等级
+    --blame           用 git blame 标注结果的引入提交和时间
+    --unused-rules    列出没有产生结果的关键词和规则
+    --relative-paths  报告中使用相对路径
+    --path-separator  报告中路径的分隔符（slash/native）
+    --cache           扫描缓存文件（跳过未变化的文件）
+    --incremental     增量扫描（只解析上次扫描后变化的文件）
+    --max-findings    累计结果达到N条后提前结束扫描
+    --seed            按种子确定文件处理顺序（结果可复现）
+    --fail-on         存在不低于该等级的结果时返回退出码1
+  
+  规则 / Rules:
+    --only-rules      仅启用指定规则（规则名称）
+    --tags            仅启用带有指定标签的规则
+    --skip-rules      禁用指定规则（规则名称）
+    --no-validate     不校验指定规则的匹配值
+    --all-matches     同一行命中多条规则时全部报告
+    --rule-timeout    规则匹配单个字符串的时间上限
+    --weak-passwords  检查弱口令
+    --secret-words    高熵赋值规则的敏感变量名关键词
+    --entropy-threshold 高熵赋值规则的信息熵阈值
+    --rules           规则配置文件（JSON，可重复）
+    --hash-list       已知文件SHA-256列表
+    --severity-override 风险等级覆盖（路径通配符|规则名=等级）
+    --severity-weights 风险评分中各风险等级的权重
+    --test-paths      测试数据目录，其中的结果降为最低风险等级
 
 支持的文件类型 / Supported File Types:
-  文本 / Text: .txt, .log, .ini, .conf, .yaml, .yml, .xml, .json, .sql, .properties, .md
+  文本 / Text: .txt, .log, .ini, .conf, .yaml, .yml, .xml, .config, .json, .sql, .properties, .md
   文档 / Document: .docx, .xlsx, .xls, .csv
   代码 / Code: .java, .py, .js, .php, .go, .c, .cpp, .h, .sh, .bat, .ps1
   二进制 / Binary: .dll, .exe, .so, .dylib, .bin, .o, .obj (PE文件敏感信息扫描)
+  Java: .class, .jar (解析常量池字符串)
+  抓包 / Capture: .pcap, .pcapng (重组TCP流后扫描负载), .har (按请求扫描请求头、Cookie、参数和请求/响应体)
+  密钥库 / Keystores: .jks, .jceks, .keystore, .p12, .pfx (列出私钥和证书，见 --keystore-password)
+  证书 / Certificates: .pem, .crt, .cer (列出证书有效期，标记过期证书和附带的私钥)
+  凭据存储 / Credential stores: .keychain, Login Data, Cookies, cookies.sqlite, logins.json (按文件结构识别)
+  日志 / Logs: .log 包含轮转日志 (app.log.1, app.log.2.gz)
+  命令历史 / Shell history: .bash_history, .zsh_history 等 (总会扫描，匹配命令行凭据)
+  脚本 / Scripts: .ps1, .psm1, .bat, .cmd (解码 -EncodedCommand 后重新扫描)
+  YAML: .yaml, .yml (展开锚点/别名，报告文档序号和键路径)
   
 注意 / Note:
   - 如果在 -t 或 -ta 中指定了二进制文件类型，会自动启用二进制扫描模式
@@ -308,5 +1199,5 @@ func GetUsageText() string {
 
 // PrintBanner 打印Banner
 func PrintBanner() {
-	fmt.Println(Banner)
+	fmt.Fprint(os.Stderr, Banner)
 }
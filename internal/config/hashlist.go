@@ -0,0 +1,43 @@
+package config
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadHashList 加载已知文件哈希列表，返回 SHA-256（小写）到说明的映射
+// 每行一个哈希，可在其后以空白分隔附带说明（兼容 sha256sum 输出），# 开头的行为注释
+func LoadHashList(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取哈希列表失败: %w", err)
+	}
+	defer file.Close()
+
+	hashes := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		hash, label, _ := strings.Cut(line, " ")
+		hash = strings.ToLower(strings.TrimSpace(hash))
+		if decoded, err := hex.DecodeString(hash); err != nil || len(decoded) != 32 {
+			return nil, fmt.Errorf("哈希列表第 %d 行不是有效的SHA-256: %s", lineNum, hash)
+		}
+		// sha256sum 二进制模式在文件名前加 *
+		hashes[hash] = strings.TrimPrefix(strings.TrimSpace(label), "*")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取哈希列表失败: %w", err)
+	}
+
+	return hashes, nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IncrementalStatePath 获取增量扫描（--incremental）的状态文件路径
+// 状态文件保存在用户缓存目录下，按扫描目录的绝对路径区分，同一组目录的多次扫描共用一个状态文件
+func IncrementalStatePath(directories []string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("获取增量扫描状态目录失败: %w", err)
+	}
+
+	absDirs := make([]string, 0, len(directories))
+	for _, dir := range directories {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return "", fmt.Errorf("获取扫描目录的绝对路径失败: %w", err)
+		}
+		absDirs = append(absDirs, filepath.Clean(abs))
+	}
+	sort.Strings(absDirs)
+
+	sum := sha256.Sum256([]byte(strings.Join(absDirs, "\x00")))
+	name := "root"
+	if len(absDirs) > 0 {
+		if base := filepath.Base(absDirs[0]); base != string(filepath.Separator) && base != "." {
+			name = base
+		}
+	}
+	return filepath.Join(cacheDir, "findx", "incremental", name+"-"+hex.EncodeToString(sum[:8])+".json"), nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"Findx/internal/risk"
+)
+
+// LoadedSource 从一个文件中加载的关键词或规则数量
+type LoadedSource struct {
+	Kind       string // 来源类型（关键词文件、规则文件）
+	Path       string
+	Loaded     int // 加载的条目数（不含重复）
+	Duplicates int // 与其他来源重复而忽略的条目数
+}
+
+// LoadKeywordsFile 加载关键词文件，每行一个关键词，# 开头的行为注释
+func LoadKeywordsFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取关键词文件失败: %w", err)
+	}
+	defer file.Close()
+
+	var keywords []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keywords = append(keywords, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取关键词文件失败: %w", err)
+	}
+
+	return keywords, nil
+}
+
+// splitKeywordRisks 拆分关键词末尾的风险等级（如 password:critical），返回去掉等级后的关键词和各关键词的等级
+// 冒号后不是有效风险等级的关键词（如 password:、jdbc:）保持原样；拆分后重复的关键词只保留一个，后指定的等级生效
+func splitKeywordRisks(keywords []string) ([]string, map[string]string) {
+	risks := make(map[string]string)
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		if idx := strings.LastIndex(keyword, ":"); idx > 0 {
+			if level := strings.TrimSpace(keyword[idx+1:]); level != "" && risk.IsValid(level) {
+				keyword = keyword[:idx]
+				risks[keyword] = risk.Normalize(level)
+			}
+		}
+		if !seen[keyword] {
+			seen[keyword] = true
+			result = append(result, keyword)
+		}
+	}
+	return result, risks
+}
+
+// KeywordRisk 获取关键字匹配结果的风险等级，未指定时为 medium
+func (c *Config) KeywordRisk(keyword string) string {
+	if level, ok := c.KeywordRisks[keyword]; ok {
+		return level
+	}
+	return risk.Medium
+}
+
+// appendUnique 将 seen 中没有的条目追加到 list，返回追加后的列表以及追加和重复的条目数
+func appendUnique(list []string, seen map[string]bool, items []string) ([]string, int, int) {
+	added, duplicates := 0, 0
+	for _, item := range items {
+		if seen[item] {
+			duplicates++
+			continue
+		}
+		seen[item] = true
+		list = append(list, item)
+		added++
+	}
+	return list, added, duplicates
+}
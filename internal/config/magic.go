@@ -0,0 +1,48 @@
+package config
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// 自定义文件头可指定的解析方式
+const (
+	MagicTypeRaw  = "raw"  // 按原始字节扫描（字符串提取、规则和Base64检查，报告偏移）
+	MagicTypeText = "text" // 按文本逐行扫描
+)
+
+// maxMagicLength 文件头签名的最大字节数
+const maxMagicLength = 64
+
+// MagicSignature 自定义文件头签名，文件以 Magic 开头时使用 Type 指定的解析方式，优先于扩展名
+type MagicSignature struct {
+	Magic []byte
+	Type  string
+}
+
+// ParseMagicSignature 解析文件头签名，格式: <十六进制>=<解析方式>，如 4d5a=raw、0xCAFED00D=text
+// 十六进制中可包含空格，可带 0x 前缀
+func ParseMagicSignature(s string) (MagicSignature, error) {
+	value, kind, ok := strings.Cut(s, "=")
+	if !ok {
+		return MagicSignature{}, fmt.Errorf("文件头签名格式错误（应为 十六进制=解析方式）: %s", s)
+	}
+
+	value = strings.ReplaceAll(strings.TrimSpace(value), " ", "")
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X")
+	magic, err := hex.DecodeString(value)
+	if err != nil {
+		return MagicSignature{}, fmt.Errorf("文件头签名不是有效的十六进制: %s", s)
+	}
+	if len(magic) == 0 || len(magic) > maxMagicLength {
+		return MagicSignature{}, fmt.Errorf("文件头签名长度应为1-%d字节: %s", maxMagicLength, s)
+	}
+
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	if kind != MagicTypeRaw && kind != MagicTypeText {
+		return MagicSignature{}, fmt.Errorf("文件头签名的解析方式无效: %s（可选 %s/%s）", kind, MagicTypeRaw, MagicTypeText)
+	}
+
+	return MagicSignature{Magic: magic, Type: kind}, nil
+}
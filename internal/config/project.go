@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ProjectType 按扫描目录中的标志文件识别的项目类型
+type ProjectType struct {
+	Name        string   // 项目类型名称
+	Markers     []string // 扫描目录下存在其中任一文件时识别为该类型
+	ExcludeDirs []string // 自动排除的目录名（依赖、构建产物、虚拟环境）
+	FileTypes   []string // 自动追加的文件类型（该类项目中常见的配置和源代码）
+}
+
+// projectTypes 支持自动识别的项目类型
+var projectTypes = []ProjectType{
+	{
+		Name:        "Node.js",
+		Markers:     []string{"package.json"},
+		ExcludeDirs: []string{"node_modules", "bower_components"},
+		FileTypes:   []string{".js", ".ts", ".env"},
+	},
+	{
+		Name:        "Maven",
+		Markers:     []string{"pom.xml"},
+		ExcludeDirs: []string{"target"},
+		FileTypes:   []string{".properties", ".xml", ".yml"},
+	},
+	{
+		Name:        "Go",
+		Markers:     []string{"go.mod"},
+		ExcludeDirs: []string{"vendor"},
+		FileTypes:   []string{".go"},
+	},
+	{
+		Name:        "Python",
+		Markers:     []string{"requirements.txt", "pyproject.toml", "setup.py"},
+		ExcludeDirs: []string{"venv", ".venv", "__pycache__", ".tox", "site-packages"},
+		FileTypes:   []string{".py", ".cfg", ".toml", ".env"},
+	},
+}
+
+// DetectProjectTypes 识别扫描目录的项目类型，只检查目录本身（不递归），可能同时属于多种类型
+func DetectProjectTypes(root string) []ProjectType {
+	var detected []ProjectType
+	for _, project := range projectTypes {
+		for _, marker := range project.Markers {
+			if info, err := os.Stat(filepath.Join(root, marker)); err == nil && !info.IsDir() {
+				detected = append(detected, project)
+				break
+			}
+		}
+	}
+	return detected
+}
+
+// ApplyProjectType 按项目类型追加自动排除的目录和文件类型，返回新增的排除目录和文件类型
+// 自动排除的目录只按目录名精确匹配；--exclude-ext 排除的扩展名不会被追加
+func (c *Config) ApplyProjectType(project ProjectType) (dirs, types []string) {
+	for _, dir := range project.ExcludeDirs {
+		if !containsFold(c.AutoExcludes, dir) {
+			c.AutoExcludes = append(c.AutoExcludes, dir)
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, ext := range project.FileTypes {
+		if !containsFold(c.FileTypes, ext) && !containsFold(c.ExcludeExts, ext) {
+			c.FileTypes = append(c.FileTypes, ext)
+			types = append(types, ext)
+		}
+	}
+	return dirs, types
+}
+
+// containsFold 判断列表中是否包含该字符串（不区分大小写）
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"net/url"
+	"strings"
+)
+
+// remoteGitPrefixes 视为远程 Git 仓库的扫描目标前缀
+var remoteGitPrefixes = []string{"https://", "http://", "ssh://", "git://", "git@"}
+
+// IsRemoteGitURL 判断扫描目标是否为远程 Git 仓库地址
+func IsRemoteGitURL(target string) bool {
+	for _, prefix := range remoteGitPrefixes {
+		if strings.HasPrefix(target, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRemoteRepo 判断本次扫描的目录是否为远程 Git 仓库
+func (c *Config) IsRemoteRepo() bool {
+	return c.DockerImage == "" && len(c.Directories) == 1 && IsRemoteGitURL(c.Directories[0])
+}
+
+// RedactRepoURL 去除仓库地址中的用户名和口令，用于日志和报告
+func RedactRepoURL(target string) string {
+	u, err := url.Parse(target)
+	if err != nil || u.User == nil {
+		return target
+	}
+	u.User = nil
+	return u.String()
+}
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"Findx/internal/risk"
+)
+
+// RulesFile 规则配置文件结构（JSON）
+type RulesFile struct {
+	SeverityOverrides []SeverityOverride `json:"severity_overrides"` // 风险等级覆盖规则
+	SensitiveFiles    []string           `json:"sensitive_files"`    // 追加的敏感文件名
+	RiskLevels        []risk.Level       `json:"risk_levels"`        // 自定义风险等级，从高到低（为空则使用内置等级）
+}
+
+// DefaultSensitiveFiles 内置的敏感文件名列表
+// 不含 / 的条目按文件名匹配，含 / 的条目按路径后缀匹配
+var DefaultSensitiveFiles = []string{
+	".aws/credentials",
+	".aws/config",
+	".azure/accessTokens.json",
+	".config/gcloud/credentials.db",
+	".config/gcloud/application_default_credentials.json",
+	".docker/config.json",
+	".kube/config",
+	".npmrc",
+	".pypirc",
+	".dockercfg",
+	".netrc",
+	"_netrc",
+	".pgpass",
+	".git-credentials",
+	".htpasswd",
+	"id_rsa",
+	"id_dsa",
+	"id_ecdsa",
+	"id_ed25519",
+	"Login Data",
+	"Cookies",
+	"cookies.sqlite",
+	"logins.json",
+	"login.keychain",
+	"login.keychain-db",
+}
+
+// SeverityOverride 风险等级覆盖规则
+// Path 与 Rule 至少指定一个，同时指定时需要同时匹配
+type SeverityOverride struct {
+	Path string `json:"path"` // 路径通配符（支持 **）
+	Rule string `json:"rule"` // 规则名称
+	Risk string `json:"risk"` // 覆盖后的风险等级
+
+	pathPattern *regexp.Regexp
+}
+
+// LoadRulesFile 加载规则配置文件
+func LoadRulesFile(path string) (*RulesFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取规则文件失败: %w", err)
+	}
+
+	var rules RulesFile
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("解析规则文件失败: %w", err)
+	}
+
+	return &rules, nil
+}
+
+// loadRulesFiles 按顺序加载并合并所有规则配置文件，重复的覆盖规则和敏感文件名只保留一个
+// 多个文件都定义风险等级时必须完全相同
+func (c *Config) loadRulesFiles() error {
+	overrideSeen := make(map[string]bool)
+	for _, o := range c.SeverityOverrides {
+		overrideSeen[o.key()] = true
+	}
+	sensitiveSeen := make(map[string]bool)
+	for _, name := range c.SensitiveFiles {
+		sensitiveSeen[name] = true
+	}
+
+	var riskLevels []risk.Level
+	var riskLevelsFrom string
+	for _, path := range c.RulesFiles {
+		rules, err := LoadRulesFile(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+
+		source := LoadedSource{Kind: "规则文件", Path: path}
+		for _, o := range rules.SeverityOverrides {
+			if overrideSeen[o.key()] {
+				source.Duplicates++
+				continue
+			}
+			overrideSeen[o.key()] = true
+			c.SeverityOverrides = append(c.SeverityOverrides, o)
+			source.Loaded++
+		}
+		var added, duplicates int
+		c.SensitiveFiles, added, duplicates = appendUnique(c.SensitiveFiles, sensitiveSeen, rules.SensitiveFiles)
+		source.Loaded += added
+		source.Duplicates += duplicates
+
+		if len(rules.RiskLevels) > 0 {
+			if riskLevels != nil && !reflect.DeepEqual(riskLevels, rules.RiskLevels) {
+				return fmt.Errorf("规则文件 %s 与 %s 定义了不同的风险等级", path, riskLevelsFrom)
+			}
+			if riskLevels == nil {
+				riskLevels, riskLevelsFrom = rules.RiskLevels, path
+				source.Loaded += len(rules.RiskLevels)
+			} else {
+				source.Duplicates += len(rules.RiskLevels)
+			}
+		}
+		c.LoadedSources = append(c.LoadedSources, source)
+	}
+
+	if riskLevels != nil {
+		if err := risk.Configure(riskLevels); err != nil {
+			return fmt.Errorf("规则文件 %s 中的风险等级无效: %w", riskLevelsFrom, err)
+		}
+	}
+	return nil
+}
+
+// key 覆盖规则的唯一标识，用于合并多个来源时去重
+func (o *SeverityOverride) key() string {
+	return o.Path + "|" + o.Rule + "=" + strings.ToLower(o.Risk)
+}
+
+// ParseSeverityOverride 解析命令行风险覆盖规则，格式: <路径通配符>|<规则名>=<风险等级>
+func ParseSeverityOverride(s string) (SeverityOverride, error) {
+	target, risk, ok := strings.Cut(s, "=")
+	if !ok {
+		return SeverityOverride{}, fmt.Errorf("风险覆盖规则格式错误: %s", s)
+	}
+
+	path, rule, _ := strings.Cut(target, "|")
+	return SeverityOverride{
+		Path: strings.TrimSpace(path),
+		Rule: strings.TrimSpace(rule),
+		Risk: strings.TrimSpace(risk),
+	}, nil
+}
+
+// ParseSeverityWeights 解析风险评分权重，格式: 等级=权重,等级=权重（如 critical=20,high=8）
+func ParseSeverityWeights(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		level, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("风险评分权重格式错误: %s（格式: 等级=权重）", item)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("风险评分权重不是整数: %s", item)
+		}
+		weights[strings.ToLower(strings.TrimSpace(level))] = weight
+	}
+	return weights, nil
+}
+
+// configureSeverityWeights 校验并应用风险评分权重，需在规则文件自定义风险等级体系之后调用
+func (c *Config) configureSeverityWeights() error {
+	if len(c.SeverityWeights) == 0 {
+		return nil
+	}
+	if err := risk.ConfigureWeights(c.SeverityWeights); err != nil {
+		return fmt.Errorf("--severity-weights 无效: %w", err)
+	}
+	return nil
+}
+
+// SeverityWeightsText 按风险等级从高到低列出各等级的评分权重
+func (c *Config) SeverityWeightsText() string {
+	names := risk.Names()
+	items := make([]string, 0, len(names))
+	for _, name := range names {
+		items = append(items, fmt.Sprintf("%s=%d", name, risk.Weight(name)))
+	}
+	return strings.Join(items, ", ")
+}
+
+// compile 校验并编译覆盖规则
+func (o *SeverityOverride) compile() error {
+	if o.Path == "" && o.Rule == "" {
+		return fmt.Errorf("风险覆盖规则必须指定路径或规则名")
+	}
+
+	if !IsValidRiskLevel(o.Risk) {
+		return fmt.Errorf("无效的风险等级: %s（可选: %s）", o.Risk, RiskLevelNames())
+	}
+	o.Risk = risk.Normalize(o.Risk)
+
+	if o.Path != "" {
+		pattern, err := compileGlob(o.Path)
+		if err != nil {
+			return fmt.Errorf("无效的路径通配符 %s: %w", o.Path, err)
+		}
+		o.pathPattern = pattern
+	}
+
+	return nil
+}
+
+// Matches 判断覆盖规则是否适用于该结果
+func (o *SeverityOverride) Matches(relPath, ruleName string) bool {
+	if o.Rule != "" && o.Rule != ruleName {
+		return false
+	}
+	if o.pathPattern != nil && !matchPath(o.pathPattern, relPath) {
+		return false
+	}
+	return true
+}
+
+// IsValidRiskLevel 判断风险等级是否有效（按当前的风险等级体系）
+func IsValidRiskLevel(level string) bool {
+	return risk.IsValid(level)
+}
+
+// RiskLevelRank 风险等级的优先级，数值越大风险越高，无效等级返回 0
+func RiskLevelRank(level string) int {
+	return risk.Rank(level)
+}
+
+// RiskLevelNames 获取可选的风险等级名称，用于错误提示
+func RiskLevelNames() string {
+	return strings.Join(risk.Names(), ", ")
+}
+
+// compileGlob 将路径通配符编译为正则表达式
+// * 和 ? 不跨越目录分隔符，** 可匹配任意层级目录
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	glob = filepath.ToSlash(glob)
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch ch := glob[i]; ch {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				// "**/" 可以匹配零层目录
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					sb.WriteString("(?:.*/)?")
+				} else {
+					sb.WriteString(".*")
+				}
+			} else {
+				sb.WriteString("[^/]*")
+			}
+		case '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(ch)))
+		}
+	}
+	sb.WriteString("$")
+
+	return regexp.Compile(sb.String())
+}
+
+// matchPath 使用通配符匹配相对路径，同时尝试匹配文件名
+func matchPath(pattern *regexp.Regexp, relPath string) bool {
+	relPath = filepath.ToSlash(relPath)
+	return pattern.MatchString(relPath) || pattern.MatchString(filepath.Base(relPath))
+}
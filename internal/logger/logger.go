@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Level 日志级别
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// 各级别日志前缀，沿用原有的 [*]/[-] 风格
+var levelPrefixes = map[Level]string{
+	LevelDebug: "[D] ",
+	LevelInfo:  "[*] ",
+	LevelWarn:  "[!] ",
+	LevelError: "[-] ",
+}
+
+var (
+	mu     sync.Mutex
+	level            = LevelInfo
+	output io.Writer = os.Stderr
+)
+
+// ParseLevel 解析日志级别名称
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "info", "":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("无效的日志级别: %s（可选: debug, info, warn, error）", name)
+}
+
+// SetLevel 设置日志级别
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// SetOutput 设置日志输出目标（默认为标准错误）
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	output = w
+}
+
+// Enabled 判断指定级别的日志是否会输出
+func Enabled(l Level) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return l >= level
+}
+
+// Debugf 输出调试日志
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, levelPrefixes[LevelDebug], format, args...)
+}
+
+// Infof 输出信息日志
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, levelPrefixes[LevelInfo], format, args...)
+}
+
+// Warnf 输出警告日志
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, levelPrefixes[LevelWarn], format, args...)
+}
+
+// Errorf 输出错误日志
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, levelPrefixes[LevelError], format, args...)
+}
+
+// Detailf 输出信息级别的续行（不带前缀，用于多行信息的明细）
+func Detailf(format string, args ...interface{}) {
+	logf(LevelInfo, "", format, args...)
+}
+
+// logf 按级别格式化并输出一行日志
+func logf(l Level, prefix, format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if l < level {
+		return
+	}
+
+	msg := fmt.Sprintf(format, args...)
+	fmt.Fprint(output, prefix+strings.TrimSuffix(msg, "\n")+"\n")
+}
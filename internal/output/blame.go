@@ -0,0 +1,37 @@
+package output
+
+import (
+	"fmt"
+	"time"
+)
+
+// BlameInfo 结果所在行最后一次修改的提交（--blame），用于估计敏感信息已存在的时间
+type BlameInfo struct {
+	Commit   string    `json:"commit"`
+	Author   string    `json:"author"`
+	Date     time.Time `json:"date"`
+	AgeDays  int       `json:"age_days"`
+	Boundary bool      `json:"boundary,omitempty"` // 浅克隆的边界提交，实际引入时间可能更早
+}
+
+// NewBlameInfo 创建提交信息，按 now 计算已存在的天数
+func NewBlameInfo(commit, author string, date, now time.Time, boundary bool) *BlameInfo {
+	age := 0
+	if now.After(date) {
+		age = int(now.Sub(date).Hours() / 24)
+	}
+	return &BlameInfo{Commit: commit, Author: author, Date: date, AgeDays: age, Boundary: boundary}
+}
+
+// String 格式化为 日期 作者 (短提交号)，已存在 N 天
+func (b *BlameInfo) String() string {
+	commit := b.Commit
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	age := fmt.Sprintf("已存在 %d 天", b.AgeDays)
+	if b.Boundary {
+		age = fmt.Sprintf("已存在至少 %d 天（浅克隆）", b.AgeDays)
+	}
+	return fmt.Sprintf("%s %s (%s)，%s", b.Date.Format("2006-01-02"), b.Author, commit, age)
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strings"
+
+	"Findx/pkg/utils"
+)
+
+// csvHeader CSV报告的列名
+var csvHeader = []string{"file", "type", "rule", "risk", "confidence", "position", "value", "context"}
+
+// CSVReportGenerator CSV报告生成器，每条结果一行
+type CSVReportGenerator struct {
+	bom  bool
+	mask bool
+}
+
+// NewCSVReportGenerator 创建CSV报告生成器，bom 为真时写入 UTF-8 BOM，mask 为真时对匹配值脱敏
+func NewCSVReportGenerator(bom, mask bool) *CSVReportGenerator {
+	return &CSVReportGenerator{bom: bom, mask: mask}
+}
+
+// Generate 生成CSV报告，结果按文件路径排序
+func (g *CSVReportGenerator) Generate(outputPath string, fileResults map[string][]Finding) error {
+	file, err := CreateReportFile(outputPath, g.bom)
+	if err != nil {
+		return fmt.Errorf("创建CSV文件失败: %w", err)
+	}
+	defer file.Close()
+
+	paths := make([]string, 0, len(fileResults))
+	for filePath, results := range fileResults {
+		if len(results) > 0 {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	writer := csv.NewWriter(file)
+	writer.Write(csvHeader)
+	for _, filePath := range paths {
+		for i := range fileResults[filePath] {
+			writer.Write(g.record(filePath, &fileResults[filePath][i]))
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("生成CSV失败: %w", err)
+	}
+
+	return file.Commit()
+}
+
+// record 将扫描结果转换为CSV行，类型和位置与HTML报告一致
+func (g *CSVReportGenerator) record(filePath string, f *Finding) []string {
+	result := newHTMLResult(f)
+
+	position := result.LineNumber
+	if position == "" {
+		position = result.Offset
+	}
+	if result.Location != "" {
+		position = strings.TrimSpace(result.Location + " " + position)
+	}
+
+	value := f.MatchedValue
+	context := strings.NewReplacer(utils.HighlightStart, "", utils.HighlightEnd, "").Replace(f.Context)
+	if g.mask && value != "" {
+		masked := MaskValue(value)
+		context = strings.ReplaceAll(context, value, masked)
+		value = masked
+	}
+
+	return []string{filePath, result.Type, result.RuleName, strings.ToLower(f.RiskLevel), f.Confidence(), position, value, context}
+}
+
+// MaskValue 对敏感值脱敏，保留前2个和后2个字符，6个字符及以下全部替换
+func MaskValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= 6 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-2:])
+}
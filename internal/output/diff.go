@@ -0,0 +1,252 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"Findx/internal/risk"
+	"Findx/pkg/utils"
+)
+
+// ScanDiff 两次扫描结果的差异
+type ScanDiff struct {
+	OldReport *JSONReport
+	NewReport *JSONReport
+	Added     []JSONFinding // 新扫描中新增的结果
+	Removed   []JSONFinding // 新扫描中已消失的结果
+	Unchanged []JSONFinding // 两次扫描都存在的结果
+}
+
+// LoadJSONReport 读取 --json 生成的JSON报告
+func LoadJSONReport(path string) (*JSONReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取JSON报告失败: %w", err)
+	}
+
+	var report JSONReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("解析JSON报告失败 %s: %w", path, err)
+	}
+
+	// 旧版本报告没有指纹字段，按相同方式补算
+	for i := range report.Findings {
+		f := &report.Findings[i]
+		if f.Fingerprint == "" {
+			f.Fingerprint = findingFingerprint(f.File, &Finding{
+				Kind:         f.Kind,
+				RuleName:     f.RuleName,
+				Keyword:      f.Keyword,
+				MatchedValue: f.MatchedValue,
+				Context:      f.Context,
+			})
+		}
+	}
+
+	return &report, nil
+}
+
+// DiffReports 按指纹比较两次扫描结果，相同指纹出现多次时按次数比较
+func DiffReports(oldReport, newReport *JSONReport) *ScanDiff {
+	diff := &ScanDiff{
+		OldReport: oldReport,
+		NewReport: newReport,
+	}
+
+	remaining := make(map[string]int)
+	for _, f := range oldReport.Findings {
+		remaining[f.Fingerprint]++
+	}
+
+	for _, f := range newReport.Findings {
+		if remaining[f.Fingerprint] > 0 {
+			remaining[f.Fingerprint]--
+			diff.Unchanged = append(diff.Unchanged, f)
+		} else {
+			diff.Added = append(diff.Added, f)
+		}
+	}
+
+	// 旧报告中未被匹配的结果视为已移除，从后向前消耗以保留最早出现的结果
+	for i := len(oldReport.Findings) - 1; i >= 0; i-- {
+		f := oldReport.Findings[i]
+		if remaining[f.Fingerprint] > 0 {
+			remaining[f.Fingerprint]--
+			diff.Removed = append(diff.Removed, f)
+		}
+	}
+	for i, j := 0, len(diff.Removed)-1; i < j; i, j = i+1, j-1 {
+		diff.Removed[i], diff.Removed[j] = diff.Removed[j], diff.Removed[i]
+	}
+
+	return diff
+}
+
+// FormatDiffSummary 格式化差异摘要
+func (f *ResultFormatter) FormatDiffSummary(diff *ScanDiff) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n")
+	sb.WriteString(f.line("═"))
+	sb.WriteString(f.centerLine("📊 扫描结果对比"))
+	sb.WriteString(f.line("═"))
+	sb.WriteString(fmt.Sprintf("  旧扫描: %s (%s, %d 个结果)\n", diff.OldReport.ScanTarget, diff.OldReport.ScanTime, len(diff.OldReport.Findings)))
+	sb.WriteString(fmt.Sprintf("  新扫描: %s (%s, %d 个结果)\n", diff.NewReport.ScanTarget, diff.NewReport.ScanTime, len(diff.NewReport.Findings)))
+	sb.WriteString(fmt.Sprintf("\n  ➕ 新增: %d 个%s\n", len(diff.Added), formatRiskCounts(diff.Added)))
+	sb.WriteString(fmt.Sprintf("  ➖ 移除: %d 个%s\n", len(diff.Removed), formatRiskCounts(diff.Removed)))
+	sb.WriteString(fmt.Sprintf("  ＝ 未变: %d 个\n", len(diff.Unchanged)))
+
+	if len(diff.Added) > 0 {
+		sb.WriteString("\n  新增结果:\n")
+		for _, finding := range diff.Added {
+			sb.WriteString("    + " + formatDiffLine(finding) + "\n")
+		}
+	}
+	if len(diff.Removed) > 0 {
+		sb.WriteString("\n  移除结果:\n")
+		for _, finding := range diff.Removed {
+			sb.WriteString("    - " + formatDiffLine(finding) + "\n")
+		}
+	}
+
+	sb.WriteString(f.line("═"))
+
+	return sb.String()
+}
+
+// formatRiskCounts 格式化各风险等级的数量，如 "（严重 1, 高危 2）"
+func formatRiskCounts(findings []JSONFinding) string {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[risk.Normalize(f.RiskLevel)]++
+	}
+
+	var parts []string
+	for _, level := range risk.Levels() {
+		if counts[level.Name] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", level.Label, counts[level.Name]))
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "（" + strings.Join(parts, ", ") + "）"
+}
+
+// formatDiffLine 格式化差异中的单条结果
+func formatDiffLine(f JSONFinding) string {
+	location := f.File
+	if f.LineNumber > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.LineNumber)
+	} else if f.Offset != nil {
+		location = fmt.Sprintf("%s@0x%X", f.File, *f.Offset)
+	}
+	return fmt.Sprintf("[%s] %s %s: %s", f.RiskLevel, location, f.RuleName, utils.TruncateString(diffValue(f), 80))
+}
+
+// diffValue 获取差异中展示的值，关键字匹配展示命中的内容
+func diffValue(f JSONFinding) string {
+	if f.Kind == "TEXT" || f.Kind == "WORD" || f.Kind == "EXCEL" || f.Kind == "CSV" {
+		return strings.TrimSpace(f.Context)
+	}
+	return f.MatchedValue
+}
+
+// HTMLDiffReport HTML差异报告数据
+type HTMLDiffReport struct {
+	OldTarget      string
+	OldTime        string
+	NewTarget      string
+	NewTime        string
+	GenerateTime   string
+	AddedCount     int
+	RemovedCount   int
+	UnchangedCount int
+	Sections       []HTMLDiffSection
+}
+
+// HTMLDiffSection HTML差异报告中的一组结果
+type HTMLDiffSection struct {
+	Title   string
+	Status  string // added/removed/unchanged
+	Results []HTMLDiffResult
+}
+
+// HTMLDiffResult HTML差异报告中的单条结果
+type HTMLDiffResult struct {
+	File      string
+	Position  string
+	RuleName  string
+	RiskLevel string // 风险等级对应的内置等级，用于样式
+	RiskText  string
+	Value     string
+}
+
+// GenerateDiffHTML 生成HTML差异报告，bom 为真时写入 UTF-8 BOM
+func GenerateDiffHTML(outputPath string, diff *ScanDiff, bom bool) error {
+	tmplContent, err := templateFS.ReadFile("template/diff.html")
+	if err != nil {
+		return fmt.Errorf("读取模板失败: %w", err)
+	}
+	tmpl, err := template.New("diff").Parse(string(tmplContent))
+	if err != nil {
+		return fmt.Errorf("解析模板失败: %w", err)
+	}
+
+	report := &HTMLDiffReport{
+		OldTarget:      diff.OldReport.ScanTarget,
+		OldTime:        diff.OldReport.ScanTime,
+		NewTarget:      diff.NewReport.ScanTarget,
+		NewTime:        diff.NewReport.ScanTime,
+		GenerateTime:   time.Now().Format("2006-01-02 15:04:05"),
+		AddedCount:     len(diff.Added),
+		RemovedCount:   len(diff.Removed),
+		UnchangedCount: len(diff.Unchanged),
+		Sections: []HTMLDiffSection{
+			newHTMLDiffSection("新增", "added", diff.Added),
+			newHTMLDiffSection("移除", "removed", diff.Removed),
+			newHTMLDiffSection("未变", "unchanged", diff.Unchanged),
+		},
+	}
+
+	file, err := CreateReportFile(outputPath, bom)
+	if err != nil {
+		return fmt.Errorf("创建HTML文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if err := tmpl.Execute(file, report); err != nil {
+		return fmt.Errorf("生成HTML失败: %w", err)
+	}
+	return file.Commit()
+}
+
+// newHTMLDiffSection 构建差异报告分组，结果按文件路径排序
+func newHTMLDiffSection(title, status string, findings []JSONFinding) HTMLDiffSection {
+	section := HTMLDiffSection{Title: title, Status: status}
+	for _, f := range findings {
+		position := ""
+		if f.LineNumber > 0 {
+			position = fmt.Sprintf("行 %d", f.LineNumber)
+		} else if f.Offset != nil {
+			position = fmt.Sprintf("0x%X", *f.Offset)
+		}
+		section.Results = append(section.Results, HTMLDiffResult{
+			File:      f.File,
+			Position:  position,
+			RuleName:  f.RuleName,
+			RiskLevel: risk.Band(f.RiskLevel),
+			RiskText:  getRiskLevelText(f.RiskLevel),
+			Value:     diffValue(f),
+		})
+	}
+	sort.SliceStable(section.Results, func(i, j int) bool {
+		return section.Results[i].File < section.Results[j].File
+	})
+	return section
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"sort"
+	"strings"
+
+	"Findx/internal/risk"
+)
+
+// DirectoryCount 单个目录中结果的汇总
+type DirectoryCount struct {
+	Path    string         // 目录路径（没有目录部分的文件为 "."）
+	Files   []FileCount    // 目录中有结果的文件，按路径排序
+	Count   int            // 结果总数
+	ByLevel map[string]int // 各风险等级的结果数
+	MaxRisk string         // 最高风险等级
+}
+
+// FileCount 单个文件的结果数
+type FileCount struct {
+	Path  string
+	Count int
+}
+
+// parentDir 获取文件所在的目录，同时支持 / 和 \ 分隔的路径（Docker 镜像中的路径总是使用 /）
+func parentDir(path string) string {
+	idx := strings.LastIndexAny(path, `/\`)
+	switch {
+	case idx < 0:
+		return "."
+	case idx == 0:
+		return path[:1]
+	default:
+		return path[:idx]
+	}
+}
+
+// AggregateByDirectory 按文件所在的目录汇总结果数和最高风险等级
+// 目录按最高风险等级、结果数降序排列，相同时按路径排序
+func AggregateByDirectory(fileResults map[string][]Finding) []DirectoryCount {
+	index := make(map[string]int)
+	var dirs []DirectoryCount
+	for filePath, results := range fileResults {
+		if len(results) == 0 {
+			continue
+		}
+
+		path := parentDir(filePath)
+		i, ok := index[path]
+		if !ok {
+			i = len(dirs)
+			index[path] = i
+			dirs = append(dirs, DirectoryCount{Path: path, ByLevel: make(map[string]int)})
+		}
+		dir := &dirs[i]
+		dir.Files = append(dir.Files, FileCount{Path: filePath, Count: len(results)})
+		dir.Count += len(results)
+		for j := range results {
+			level := risk.Normalize(results[j].RiskLevel)
+			dir.ByLevel[level]++
+			if risk.Rank(level) > risk.Rank(dir.MaxRisk) {
+				dir.MaxRisk = level
+			}
+		}
+	}
+
+	for i := range dirs {
+		files := dirs[i].Files
+		sort.Slice(files, func(a, b int) bool {
+			return files[a].Path < files[b].Path
+		})
+	}
+	sort.Slice(dirs, func(i, j int) bool {
+		a, b := dirs[i], dirs[j]
+		if ra, rb := risk.Rank(a.MaxRisk), risk.Rank(b.MaxRisk); ra != rb {
+			return ra > rb
+		}
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Path < b.Path
+	})
+	return dirs
+}
@@ -0,0 +1,98 @@
+package output
+
+import (
+	"html/template"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EditorLinkFunc 根据文件绝对路径和行号生成编辑器链接，line 为0时只打开文件
+type EditorLinkFunc func(absPath string, line int) string
+
+// editorLinkSchemes 已注册的编辑器链接方案
+var editorLinkSchemes = map[string]EditorLinkFunc{
+	"vscode": vscodeLink,
+	"idea":   ideaLink,
+	"file":   fileLink,
+}
+
+// RegisterEditorLink 注册编辑器链接方案，同名方案会被覆盖
+func RegisterEditorLink(name string, fn EditorLinkFunc) {
+	editorLinkSchemes[name] = fn
+}
+
+// GetEditorLink 获取指定名称的编辑器链接方案
+func GetEditorLink(name string) (EditorLinkFunc, bool) {
+	fn, ok := editorLinkSchemes[name]
+	return fn, ok
+}
+
+// EditorLinkSchemes 返回已注册的方案名称（已排序）
+func EditorLinkSchemes() []string {
+	names := make([]string, 0, len(editorLinkSchemes))
+	for name := range editorLinkSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// slashPath 将路径转换为URL中使用的形式，Windows 盘符路径补上前导斜杠
+func slashPath(absPath string) string {
+	p := filepath.ToSlash(absPath)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	return (&url.URL{Path: p}).EscapedPath()
+}
+
+// vscodeLink 生成 vscode://file/<path>:<line> 链接
+func vscodeLink(absPath string, line int) string {
+	link := "vscode://file" + slashPath(absPath)
+	if line > 0 {
+		link += ":" + strconv.Itoa(line)
+	}
+	return link
+}
+
+// ideaLink 生成 idea://open?file=<path>&line=<line> 链接
+func ideaLink(absPath string, line int) string {
+	// 空格编码为 %20 而不是 +，避免被当作文件名中的字符
+	link := "idea://open?file=" + strings.ReplaceAll(url.QueryEscape(filepath.ToSlash(absPath)), "+", "%20")
+	if line > 0 {
+		link += "&line=" + strconv.Itoa(line)
+	}
+	return link
+}
+
+// fileLink 生成 file:// 链接（不支持定位到行）
+func fileLink(absPath string, line int) string {
+	return "file://" + slashPath(absPath)
+}
+
+// ApplyEditorLinks 为报告中的文件和结果生成编辑器链接
+// resolve 将报告中显示的路径转换为文件绝对路径，返回空字符串表示无法链接
+func (r *HTMLReport) ApplyEditorLinks(link EditorLinkFunc, resolve func(path string) string) {
+	for i := range r.Files {
+		file := &r.Files[i]
+		absPath := resolve(file.Path)
+		if absPath == "" {
+			continue
+		}
+		file.Link = template.URL(link(absPath, 0))
+
+		for j := range file.Results {
+			result := &file.Results[j]
+			// 文本类结果定位到行，二进制结果只打开文件（偏移量单独显示）
+			line, _ := strconv.Atoi(result.LineNumber)
+			if line > 0 {
+				result.Link = template.URL(link(absPath, line))
+			} else {
+				result.Link = file.Link
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// unsupportedRune 目标编码中无法表示的字符（如风险图标）的替换字符
+const unsupportedRune = '?'
+
+// nopWriteCloser 不需要转码时直接写入文件，Close 不关闭文件
+type nopWriteCloser struct {
+	io.Writer
+}
+
+// Close 不做任何操作
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// newEncodingWriter 将写入的 UTF-8 文本转码为 enc 后写入 w，enc 为 nil 时不转码
+// 无法表示的字符替换为 ?，Close 写出剩余内容但不关闭 w
+func newEncodingWriter(w io.Writer, enc encoding.Encoding) io.WriteCloser {
+	if enc == nil {
+		return nopWriteCloser{w}
+	}
+
+	replace := runes.Map(func(r rune) rune {
+		if !encodable(enc, r) {
+			return unsupportedRune
+		}
+		return r
+	})
+	return transform.NewWriter(w, transform.Chain(replace, enc.NewEncoder()))
+}
+
+// encodable 判断字符能否用 enc 编码
+func encodable(enc encoding.Encoding, r rune) bool {
+	if r < utf8.RuneSelf {
+		return true
+	}
+	_, err := enc.NewEncoder().String(string(r))
+	return err == nil
+}
@@ -0,0 +1,376 @@
+package output
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Findx/internal/risk"
+)
+
+// 关键字匹配结果的默认规则名称与风险等级
+const (
+	KeywordRuleName  = "关键字匹配"
+	KeywordRiskLevel = "medium"
+)
+
+// 相邻单元格凭据结果的规则名称与风险等级
+const (
+	CellPairRuleName  = "相邻单元格凭据"
+	CellPairRiskLevel = "high"
+)
+
+// 敏感文件结果的规则名称与风险等级
+const (
+	SensitiveFileRuleName  = "敏感文件"
+	SensitiveFileRiskLevel = "high"
+)
+
+// 弱口令结果的规则名称与风险等级
+const (
+	WeakPasswordRuleName  = "弱口令"
+	WeakPasswordRiskLevel = "high"
+)
+
+// 已知文件哈希结果的规则名称与风险等级
+const (
+	HashMatchRuleName  = "已知文件哈希"
+	HashMatchRiskLevel = "critical"
+)
+
+// 凭据存储中已保存密码的规则名称（风险等级随口令是否加密而不同）
+const CredentialStoreRuleName = "已保存密码"
+
+// 浏览器 Cookie 数据库中 Cookie 的规则名称（风险等级为高危）
+const BrowserCookieRuleName = "浏览器Cookie"
+
+// 高熵赋值规则的名称，结果中保留原始值，在文本、flat 和HTML报告中脱敏显示
+const EntropyRuleName = "高熵赋值"
+
+// Finding 解析后的单条扫描结果
+type Finding struct {
+	Kind         string       // 结果类别（TEXT/WORD/EXCEL/CSV/PAIR/JAVA/XML/YAML/K8S/PCAP/HAR/IMAGE/TERRAFORM/KEYSTORE/CERT/LINE/FILE/WEAK/HASH/CRED/COOKIE/BINARY），嵌入对象中的结果为原结果的类别
+	RuleName     string       // 规则名称
+	Keyword      string       // 匹配的关键字（关键字匹配）
+	MatchType    string       // 匹配方式（二进制文件）、弱口令的来源规则或文本规则结果的来源（如Shell历史）
+	Location     string       // 文档内位置（Word段落/表格、Excel格式、Java类名、XML元素路径、YAML键路径、Secret条目、网络流、HAR 请求及字段、Terraform 资源属性、凭据服务、Cookie 站点）
+	RiskLevel    string       // 风险等级
+	MatchedValue string       // 匹配值
+	LineNumber   int          // 行号（文本文件/表格行）
+	Offset       int          // 偏移量（二进制文件，-1表示无法定位）
+	ConstIndex   int          // 常量池索引（Java类文件）
+	Document     int          // 文档序号（YAML多文档文件，从1开始）
+	Context      string       // 内容或上下文
+	RawContext   []byte       // 匹配位置附近的原始字节（二进制文件，仅JSON输出）
+	Claims       *TokenClaims // JWT 解码后的声明（仅JWT令牌结果）
+	Root         string       // 结果所属的扫描目录（指定多个扫描目录时）
+	Tags         []string     // 规则的分类标签
+	Section      string       // 二进制结果所在的 PE 节区（不在节区内或非 PE 文件时为空）
+	Embedded     string       // 结果所在的嵌入对象在 Office 文档中的路径（多层嵌套以 ! 连接）
+	InComment    bool         // 匹配位于代码注释中（风险等级已降低一级）
+	InTestPath   bool         // 文件位于测试数据目录中（--test-paths，风险等级已降为最低等级）
+	Blame        *BlameInfo   // 最后修改结果所在行的提交（--blame，Git 仓库中按行号定位的结果）
+}
+
+// ParseFinding 解析解析器输出的原始结果字符串
+func ParseFinding(raw string) (*Finding, error) {
+	kind, rest, ok := strings.Cut(raw, "|")
+	if !ok {
+		return nil, fmt.Errorf("无法识别的结果: %s", raw)
+	}
+
+	finding := &Finding{
+		Kind:      kind,
+		RuleName:  KeywordRuleName,
+		RiskLevel: KeywordRiskLevel,
+		Offset:    -1,
+	}
+
+	switch kind {
+	case "EMBED":
+		// EMBED|对象路径|原结果
+		part, inner, ok := strings.Cut(rest, "|")
+		if !ok {
+			break
+		}
+		embedded, err := ParseFinding(inner)
+		if err != nil {
+			return nil, err
+		}
+		if embedded.Embedded != "" {
+			part += "!" + embedded.Embedded
+		}
+		embedded.Embedded = part
+		return embedded, nil
+
+	case "TEXT":
+		parts := strings.SplitN(rest, "|", 3)
+		if len(parts) < 3 {
+			break
+		}
+		finding.Keyword = parts[0]
+		finding.LineNumber, _ = strconv.Atoi(parts[1])
+		finding.Context = parts[2]
+		finding.MatchedValue = parts[0]
+		return finding, nil
+
+	case "WORD", "EXCEL":
+		parts := strings.SplitN(rest, "|", 3)
+		if len(parts) < 3 {
+			break
+		}
+		finding.Location = parts[0]
+		finding.Keyword = parts[1]
+		finding.Context = parts[2]
+		finding.MatchedValue = parts[1]
+		return finding, nil
+
+	case "CSV":
+		parts := strings.SplitN(rest, "|", 2)
+		if len(parts) < 2 {
+			break
+		}
+		finding.Keyword = parts[0]
+		finding.Context = parts[1]
+		finding.MatchedValue = parts[0]
+		return finding, nil
+
+	case "PAIR":
+		parts := strings.SplitN(rest, "|", 4)
+		if len(parts) < 4 {
+			break
+		}
+		finding.RuleName = CellPairRuleName
+		finding.RiskLevel = CellPairRiskLevel
+		finding.Location = parts[0]
+		finding.LineNumber, _ = strconv.Atoi(parts[1])
+		finding.Keyword = parts[2]
+		finding.MatchedValue = parts[3]
+		finding.Context = parts[2] + " → " + parts[3]
+		return finding, nil
+
+	case "JAVA":
+		parts := strings.SplitN(rest, "|", 6)
+		if len(parts) < 6 {
+			break
+		}
+		finding.Location = parts[0]
+		finding.ConstIndex, _ = strconv.Atoi(parts[1])
+		finding.RuleName = parts[2]
+		finding.RiskLevel = strings.ToLower(parts[3])
+		finding.MatchedValue = parts[4]
+		finding.Context = parts[5]
+		return finding, nil
+
+	case "XML":
+		parts := strings.SplitN(rest, "|", 7)
+		if len(parts) < 7 {
+			break
+		}
+		finding.Location = parts[0]
+		finding.LineNumber, _ = strconv.Atoi(parts[1])
+		finding.RuleName = parts[2]
+		finding.RiskLevel = strings.ToLower(parts[3])
+		finding.Keyword = parts[4]
+		finding.MatchedValue = parts[5]
+		finding.Context = parts[6]
+		return finding, nil
+
+	case "YAML":
+		// YAML|文档序号|键路径|行号|规则|风险|关键字|匹配值|内容
+		parts := strings.SplitN(rest, "|", 8)
+		if len(parts) < 8 {
+			break
+		}
+		finding.Document, _ = strconv.Atoi(parts[0])
+		finding.Location = parts[1]
+		finding.LineNumber, _ = strconv.Atoi(parts[2])
+		finding.RuleName = parts[3]
+		finding.RiskLevel = strings.ToLower(parts[4])
+		finding.Keyword = parts[5]
+		finding.MatchedValue = parts[6]
+		finding.Context = parts[7]
+		return finding, nil
+
+	case "LINE":
+		// LINE|来源|行号|规则|风险|匹配值|内容
+		parts := strings.SplitN(rest, "|", 6)
+		if len(parts) < 6 {
+			break
+		}
+		finding.MatchType = parts[0]
+		finding.LineNumber, _ = strconv.Atoi(parts[1])
+		finding.RuleName = parts[2]
+		finding.RiskLevel = strings.ToLower(parts[3])
+		finding.MatchedValue = parts[4]
+		finding.Context = parts[5]
+		return finding, nil
+
+	case "CERT":
+		// CERT|行号|位置|规则|风险|匹配值|内容
+		parts := strings.SplitN(rest, "|", 6)
+		if len(parts) < 6 {
+			break
+		}
+		finding.LineNumber, _ = strconv.Atoi(parts[0])
+		finding.Location = parts[1]
+		finding.RuleName = parts[2]
+		finding.RiskLevel = strings.ToLower(parts[3])
+		finding.MatchedValue = parts[4]
+		finding.Context = parts[5]
+		return finding, nil
+
+	case "K8S", "PCAP", "HAR", "IMAGE", "TERRAFORM", "KEYSTORE":
+		parts := strings.SplitN(rest, "|", 6)
+		if len(parts) < 6 {
+			break
+		}
+		finding.Location = parts[0]
+		finding.RuleName = parts[1]
+		finding.RiskLevel = strings.ToLower(parts[2])
+		finding.Keyword = parts[3]
+		finding.MatchedValue = parts[4]
+		finding.Context = parts[5]
+		return finding, nil
+
+	case "FILE":
+		finding.RuleName = SensitiveFileRuleName
+		finding.RiskLevel = SensitiveFileRiskLevel
+		finding.MatchedValue = rest
+		finding.Context = "已知的凭据/密钥文件"
+		return finding, nil
+
+	case "CRED", "COOKIE":
+		// CRED|存储格式|风险|服务|账号|口令，COOKIE|存储格式|风险|站点|名称|值
+		parts := strings.SplitN(rest, "|", 5)
+		if len(parts) < 5 {
+			break
+		}
+		finding.RuleName = CredentialStoreRuleName
+		if kind == "COOKIE" {
+			finding.RuleName = BrowserCookieRuleName
+		}
+		finding.MatchType = parts[0]
+		finding.RiskLevel = parts[1]
+		finding.Location = parts[2]
+		finding.Context = parts[3]
+		finding.MatchedValue = parts[4]
+		return finding, nil
+
+	case "HASH":
+		// HASH|sha256|说明
+		parts := strings.SplitN(rest, "|", 2)
+		if len(parts) < 2 {
+			break
+		}
+		finding.RuleName = HashMatchRuleName
+		finding.RiskLevel = HashMatchRiskLevel
+		finding.MatchedValue = parts[0]
+		finding.Context = parts[1]
+		return finding, nil
+
+	case "BINARY":
+		parts := strings.SplitN(rest, "|", 6)
+		if len(parts) < 6 {
+			break
+		}
+		finding.MatchType = parts[0]
+		finding.RuleName = parts[1]
+		finding.RiskLevel = strings.ToLower(parts[2])
+		finding.MatchedValue = parts[3]
+		if _, err := fmt.Sscanf(parts[4], "0x%X", &finding.Offset); err != nil {
+			finding.Offset = -1
+		}
+		finding.Context = parts[5]
+		return finding, nil
+	}
+
+	return nil, fmt.Errorf("无法识别的结果: %s", raw)
+}
+
+// FormatSensitiveFileRaw 生成敏感文件结果的原始结果字符串
+func FormatSensitiveFileRaw(fileName string) string {
+	return "FILE|" + fileName
+}
+
+// FormatHashMatchRaw 生成已知文件哈希命中的原始结果
+func FormatHashMatchRaw(hash, label string) string {
+	return "HASH|" + hash + "|" + label
+}
+
+// NormalizedValue 获取规范化后的敏感值，用于去重等需要比较敏感值的场景
+func (f *Finding) NormalizedValue() string {
+	return NormalizeValue(f.SecretValue())
+}
+
+// Confidence 获取结果的置信度：规则匹配为 high，相邻单元格和弱口令等启发式结果为 medium，仅命中关键字为 low
+func (f *Finding) Confidence() string {
+	if f.InComment || f.InTestPath {
+		return "low"
+	}
+	switch f.RuleName {
+	case KeywordRuleName:
+		return "low"
+	case CellPairRuleName, WeakPasswordRuleName:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// MarkInComment 标记结果位于代码注释中，风险等级降低一级（已是最低等级时不变）
+func (f *Finding) MarkInComment() {
+	f.InComment = true
+	f.RiskLevel = risk.Lower(f.RiskLevel)
+}
+
+// MarkInTestPath 标记结果位于测试数据目录中，风险等级降为最低等级
+func (f *Finding) MarkInTestPath() {
+	f.InTestPath = true
+	f.RiskLevel = risk.Lowest()
+}
+
+// valueUnescaper 常见转义序列的还原
+var valueUnescaper = strings.NewReplacer(`\"`, `"`, `\'`, `'`, `\\`, `\`, `\/`, `/`)
+
+// NormalizeValue 规范化敏感值，规则依次为：
+//  1. 去除首尾空白
+//  2. 去除末尾的 , 和 ;
+//  3. 去除成对包裹的引号（" ' `），可嵌套
+//  4. 还原 \" \' \\ \/ 转义
+//  5. 再次去除首尾空白
+func NormalizeValue(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimRight(value, ",;")
+	for len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if first != last || (first != '"' && first != '\'' && first != '`') {
+			break
+		}
+		value = value[1 : len(value)-1]
+	}
+	value = valueUnescaper.Replace(value)
+	return strings.TrimSpace(value)
+}
+
+// DisplayValue 获取报告中显示的匹配值，高熵赋值规则的值脱敏显示
+func (f *Finding) DisplayValue() string {
+	if f.RuleName == EntropyRuleName {
+		return MaskValue(f.MatchedValue)
+	}
+	return f.MatchedValue
+}
+
+// SecretValue 获取结果中代表敏感信息本身的值
+// 关键字匹配的匹配值是关键字本身，因此使用命中的内容作为敏感值
+func (f *Finding) SecretValue() string {
+	switch f.Kind {
+	case "TEXT", "WORD", "EXCEL", "CSV":
+		return strings.TrimSpace(f.Context)
+	case "CRED", "COOKIE":
+		// 加密的口令都是同一个占位值，需要连同服务和账号一起区分
+		return f.Location + " " + f.Context + " " + f.MatchedValue
+	default:
+		return f.MatchedValue
+	}
+}
@@ -2,7 +2,10 @@ package output
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
+	"Findx/internal/risk"
 )
 
 // ResultFormatter 结果格式化器
@@ -32,7 +35,8 @@ func (f *ResultFormatter) FormatFileHeader(filePath string, count int) string {
 }
 
 // FormatBinaryResult 格式化二进制扫描结果
-func (f *ResultFormatter) FormatBinaryResult(index int, matchType, ruleName, riskLevel, matchedValue string, offset int, context string) string {
+// section 为结果所在的 PE 节区，为空时不显示
+func (f *ResultFormatter) FormatBinaryResult(index int, matchType, ruleName, riskLevel, matchedValue string, offset int, section, context string) string {
 	var sb strings.Builder
 	
 	riskIcon := getRiskIcon(riskLevel)
@@ -47,6 +51,9 @@ func (f *ResultFormatter) FormatBinaryResult(index int, matchType, ruleName, ris
 	if offset >= 0 {
 		sb.WriteString(fmt.Sprintf("  偏移: 0x%X\n", offset))
 	}
+	if section != "" {
+		sb.WriteString(fmt.Sprintf("  节区: %s\n", section))
+	}
 	
 	sb.WriteString(fmt.Sprintf("  上下文:\n"))
 	sb.WriteString(f.wrapText(context, "    "))
@@ -56,12 +63,13 @@ func (f *ResultFormatter) FormatBinaryResult(index int, matchType, ruleName, ris
 }
 
 // FormatTextResult 格式化文本扫描结果
-func (f *ResultFormatter) FormatTextResult(index int, keyword string, lineNum int, content string) string {
+func (f *ResultFormatter) FormatTextResult(index int, keyword, riskLevel string, lineNum int, content string) string {
 	var sb strings.Builder
 	
 	sb.WriteString(fmt.Sprintf("\n[%d] 🔑 关键字匹配: %s\n", index, keyword))
 	sb.WriteString(f.line("─"))
 	sb.WriteString(fmt.Sprintf("  类型: 文本文件\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", getRiskIcon(riskLevel), riskLevel))
 	sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
 	sb.WriteString(fmt.Sprintf("  内容:\n"))
 	sb.WriteString(f.wrapText(content, "    "))
@@ -71,13 +79,293 @@ func (f *ResultFormatter) FormatTextResult(index int, keyword string, lineNum in
 }
 
 // FormatDocumentResult 格式化文档扫描结果
-func (f *ResultFormatter) FormatDocumentResult(index int, docType, location, keyword, content string) string {
+func (f *ResultFormatter) FormatDocumentResult(index int, docType, location, keyword, riskLevel, content string) string {
 	var sb strings.Builder
 	
 	sb.WriteString(fmt.Sprintf("\n[%d] 📋 关键字匹配: %s\n", index, keyword))
 	sb.WriteString(f.line("─"))
 	sb.WriteString(fmt.Sprintf("  类型: %s\n", docType))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", getRiskIcon(riskLevel), riskLevel))
+	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatCellPairResult 格式化相邻单元格凭据结果
+func (f *ResultFormatter) FormatCellPairResult(index int, docType string, rowNum int, label, value, riskLevel string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s 相邻单元格凭据: %s\n", index, riskIcon, label))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", docType))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  行号: %d\n", rowNum))
+	sb.WriteString(fmt.Sprintf("  标签: %s\n", label))
+	sb.WriteString(fmt.Sprintf("  疑似值:\n"))
+	sb.WriteString(f.wrapText(value, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatJavaResult 格式化Java常量池扫描结果
+func (f *ResultFormatter) FormatJavaResult(index int, className string, constIndex int, ruleName, riskLevel, matchedValue, constant string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: Java类文件\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  类名: %s\n", className))
+	sb.WriteString(fmt.Sprintf("  常量: #%d\n", constIndex))
+	sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(constant, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatXMLResult 格式化XML元素/属性扫描结果
+func (f *ResultFormatter) FormatXMLResult(index int, elementPath string, lineNum int, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: XML文件\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  路径: %s\n", elementPath))
+	sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatYAMLResult 格式化YAML键值扫描结果，包含文档序号和完整键路径
+func (f *ResultFormatter) FormatYAMLResult(index, document int, keyPath string, lineNum int, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: YAML文件\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  文档: #%d\n", document))
+	sb.WriteString(fmt.Sprintf("  路径: %s\n", keyPath))
+	sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatFlatResult 格式化单行结果: <路径>:<行号或偏移>:<风险>:<规则>:<匹配值>
+// 二进制结果以 0x 开头的偏移代替行号，无法定位时为 0；匹配值中的换行替换为空格，便于 grep/awk 处理
+// 关键字结果（文本、Word、Excel、CSV）的匹配值是关键字本身，输出命中的行内容
+func (f *ResultFormatter) FormatFlatResult(filePath string, finding *Finding) string {
+	position := strconv.Itoa(finding.LineNumber)
+	if finding.LineNumber == 0 && finding.Offset >= 0 {
+		position = fmt.Sprintf("0x%X", finding.Offset)
+	}
+
+	value := finding.SecretValue()
+	if finding.RuleName == EntropyRuleName {
+		value = finding.DisplayValue()
+	}
+	if value == "" {
+		value = finding.Keyword
+	}
+	value = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(value)
+
+	if finding.Embedded != "" {
+		filePath += "!" + finding.Embedded
+	}
+
+	return fmt.Sprintf("%s:%s:%s:%s:%s\n", filePath, position, finding.RiskLevel, finding.RuleName, value)
+}
+
+// FormatEmbedded 在格式化后的结果中标注所在的嵌入对象（插入到标题分隔线之后）
+func (f *ResultFormatter) FormatEmbedded(result, part string) string {
+	return f.insertAfterSeparator(result, fmt.Sprintf("  嵌入对象: %s\n", part))
+}
+
+// FormatInComment 在格式化后的结果中标注匹配位于代码注释中（插入到标题分隔线之后）
+func (f *ResultFormatter) FormatInComment(result string) string {
+	return f.insertAfterSeparator(result, "  注释中: 匹配位于代码注释中，风险已降低一级\n")
+}
+
+// FormatInTestPath 在格式化后的结果中标注文件位于测试数据目录中（插入到标题分隔线之后）
+func (f *ResultFormatter) FormatInTestPath(result string) string {
+	return f.insertAfterSeparator(result, "  测试数据: 文件位于测试数据目录中，风险已降为最低等级\n")
+}
+
+// FormatBlame 在格式化后的结果中标注最后修改该行的提交（插入到标题分隔线之后）
+func (f *ResultFormatter) FormatBlame(result, blame string) string {
+	return f.insertAfterSeparator(result, fmt.Sprintf("  引入: %s\n", blame))
+}
+
+// insertAfterSeparator 在格式化后的结果的标题分隔线之后插入一行
+func (f *ResultFormatter) insertAfterSeparator(result, line string) string {
+	separator := f.line("─")
+	idx := strings.Index(result, separator)
+	if idx < 0 {
+		return result
+	}
+	idx += len(separator)
+	return result[:idx] + line + result[idx:]
+}
+
+// FormatLineResult 格式化文本行的规则匹配结果
+func (f *ResultFormatter) FormatLineResult(index int, source string, lineNum int, ruleName, riskLevel, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", source))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatJWTResult 格式化附带解码声明的JWT结果，lineNum 为 0 时显示偏移
+func (f *ResultFormatter) FormatJWTResult(index int, source, riskLevel, matchedValue string, lineNum, offset int, claims, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, JWTRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", source))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	if lineNum > 0 {
+		sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	} else if offset >= 0 {
+		sb.WriteString(fmt.Sprintf("  偏移: 0x%X\n", offset))
+	}
+	sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	sb.WriteString(fmt.Sprintf("  声明: %s\n", claims))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatK8sSecretResult 格式化 Kubernetes Secret 扫描结果
+func (f *ResultFormatter) FormatK8sSecretResult(index int, location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: Kubernetes Secret\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  条目: %s\n", location))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  解码内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatPcapResult 格式化网络抓包扫描结果
+func (f *ResultFormatter) FormatPcapResult(index int, flow, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: 网络抓包\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  流: %s\n", flow))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatHarResult 格式化 HTTP 存档（HAR）扫描结果，位置为请求序号、方法、URL 和字段
+func (f *ResultFormatter) FormatHarResult(index int, location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: HTTP存档 (HAR)\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatTerraformResult 格式化 Terraform 状态和变量文件扫描结果，位置为资源地址和属性路径、输出或变量名
+func (f *ResultFormatter) FormatTerraformResult(index int, location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: Terraform\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
 	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
 	sb.WriteString(fmt.Sprintf("  内容:\n"))
 	sb.WriteString(f.wrapText(content, "    "))
 	sb.WriteString("\n")
@@ -85,8 +373,173 @@ func (f *ResultFormatter) FormatDocumentResult(index int, docType, location, key
 	return sb.String()
 }
 
+// FormatKeystoreResult 格式化密钥库扫描结果，位置为条目别名，内容中的各项说明分行显示
+func (f *ResultFormatter) FormatKeystoreResult(index int, location, ruleName, riskLevel, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: 密钥库\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	for _, detail := range strings.Split(content, "; ") {
+		sb.WriteString(f.wrapText(detail, "    "))
+		sb.WriteString("\n")
+	}
+	
+	return sb.String()
+}
+
+// FormatCertResult 格式化证书文件扫描结果，位置为 PEM 块序号和类型，内容中的各项说明分行显示
+func (f *ResultFormatter) FormatCertResult(index int, lineNum int, location, ruleName, riskLevel, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: 证书文件\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	if lineNum > 0 {
+		sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	}
+	sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	for _, detail := range strings.Split(content, "; ") {
+		sb.WriteString(f.wrapText(detail, "    "))
+		sb.WriteString("\n")
+	}
+	
+	return sb.String()
+}
+
+// FormatImageResult 格式化图片元数据扫描结果，位置为 EXIF 标签、XMP 属性或 PNG 文本块
+func (f *ResultFormatter) FormatImageResult(index int, location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, ruleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: 图片元数据\n"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  位置: %s\n", location))
+	if keyword != "" {
+		sb.WriteString(fmt.Sprintf("  关键字: %s\n", keyword))
+	} else {
+		sb.WriteString(fmt.Sprintf("  匹配: %s\n", matchedValue))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatWeakPasswordResult 格式化弱口令结果
+func (f *ResultFormatter) FormatWeakPasswordResult(index int, source, riskLevel, value string, lineNum int, content string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, WeakPasswordRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  来源: %s\n", source))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  口令: %s\n", value))
+	if lineNum > 0 {
+		sb.WriteString(fmt.Sprintf("  行号: %d\n", lineNum))
+	}
+	sb.WriteString(fmt.Sprintf("  内容:\n"))
+	sb.WriteString(f.wrapText(content, "    "))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatSensitiveFileResult 格式化敏感文件结果
+func (f *ResultFormatter) FormatSensitiveFileResult(index int, fileName, riskLevel, description string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, SensitiveFileRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", description))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  文件名: %s\n", fileName))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatCredentialResult 格式化凭据存储中的已保存密码
+func (f *ResultFormatter) FormatCredentialResult(index int, store, riskLevel, service, account, password string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, CredentialStoreRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", store))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  服务: %s\n", service))
+	sb.WriteString(fmt.Sprintf("  账号: %s\n", account))
+	sb.WriteString(fmt.Sprintf("  密码: %s\n", password))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatCookieResult 格式化浏览器 Cookie 数据库中的 Cookie
+func (f *ResultFormatter) FormatCookieResult(index int, store, riskLevel, host, name, value string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, BrowserCookieRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  类型: %s\n", store))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  站点: %s\n", host))
+	sb.WriteString(fmt.Sprintf("  名称: %s\n", name))
+	sb.WriteString(fmt.Sprintf("  值: %s\n", value))
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// FormatHashMatchResult 格式化已知文件哈希命中结果
+func (f *ResultFormatter) FormatHashMatchResult(index int, hash, riskLevel, label string) string {
+	var sb strings.Builder
+	
+	riskIcon := getRiskIcon(riskLevel)
+	
+	sb.WriteString(fmt.Sprintf("\n[%d] %s %s\n", index, riskIcon, HashMatchRuleName))
+	sb.WriteString(f.line("─"))
+	sb.WriteString(fmt.Sprintf("  风险: %s %s\n", riskIcon, riskLevel))
+	sb.WriteString(fmt.Sprintf("  SHA-256: %s\n", hash))
+	if label != "" {
+		sb.WriteString(fmt.Sprintf("  说明: %s\n", label))
+	}
+	sb.WriteString("\n")
+	
+	return sb.String()
+}
+
+// RuleCount 规则命中次数
+type RuleCount struct {
+	Name  string
+	Count int
+}
+
 // FormatSummary 格式化扫描摘要
-func (f *ResultFormatter) FormatSummary(totalFiles, totalFindings int, elapsed string, stats map[string]int) string {
+func (f *ResultFormatter) FormatSummary(totalFiles, totalFindings int, elapsed string, stats map[string]int, topRules []RuleCount) string {
 	var sb strings.Builder
 	
 	sb.WriteString("\n")
@@ -95,21 +548,22 @@ func (f *ResultFormatter) FormatSummary(totalFiles, totalFindings int, elapsed s
 	sb.WriteString(f.line("═"))
 	sb.WriteString(fmt.Sprintf("  扫描文件: %d 个\n", totalFiles))
 	sb.WriteString(fmt.Sprintf("  发现问题: %d 个\n", totalFindings))
+	sb.WriteString(fmt.Sprintf("  风险评分: %d\n", risk.Score(stats)))
 	sb.WriteString(fmt.Sprintf("  耗时: %s\n", elapsed))
 	
 	if len(stats) > 0 {
 		sb.WriteString(fmt.Sprintf("\n  风险分布:\n"))
-		if stats["critical"] > 0 {
-			sb.WriteString(fmt.Sprintf("    🔴 严重: %d\n", stats["critical"]))
-		}
-		if stats["high"] > 0 {
-			sb.WriteString(fmt.Sprintf("    🟠 高危: %d\n", stats["high"]))
+		for _, level := range risk.Levels() {
+			if stats[level.Name] > 0 {
+				sb.WriteString(fmt.Sprintf("    %s %s: %d\n", level.Icon, level.Label, stats[level.Name]))
+			}
 		}
-		if stats["medium"] > 0 {
-			sb.WriteString(fmt.Sprintf("    🟡 中危: %d\n", stats["medium"]))
-		}
-		if stats["low"] > 0 {
-			sb.WriteString(fmt.Sprintf("    🟢 低危: %d\n", stats["low"]))
+	}
+	
+	if len(topRules) > 0 {
+		sb.WriteString(fmt.Sprintf("\n  命中最多的规则:\n"))
+		for _, rule := range topRules {
+			sb.WriteString(fmt.Sprintf("    %s: %d\n", rule.Name, rule.Count))
 		}
 	}
 	
@@ -153,18 +607,7 @@ func (f *ResultFormatter) wrapText(text, prefix string) string {
 
 // getRiskIcon 获取风险图标
 func getRiskIcon(riskLevel string) string {
-	switch strings.ToLower(riskLevel) {
-	case "critical":
-		return "🔴"
-	case "high":
-		return "🟠"
-	case "medium":
-		return "🟡"
-	case "low":
-		return "🟢"
-	default:
-		return "⚪"
-	}
+	return risk.Icon(riskLevel)
 }
 
 // truncatePath 截断路径
@@ -0,0 +1,39 @@
+package output
+
+import "testing"
+
+func TestFormatFlatResult(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "keyword hit prints the matched line",
+			raw:  "TEXT|password=|12|  db.password=Tr0ub4dor  ",
+			want: "conf/app.properties:12:medium:关键字匹配:db.password=Tr0ub4dor\n",
+		},
+		{
+			name: "multi-line context is flattened",
+			raw:  "TEXT|密码|3|账号: admin\n密码: Tr0ub4dor",
+			want: "conf/app.properties:3:medium:关键字匹配:账号: admin 密码: Tr0ub4dor\n",
+		},
+		{
+			name: "rule hit prints the matched value",
+			raw:  "BINARY|规则匹配|密码字段|critical|Tr0ub4dor|0x1F40|password=Tr0ub4dor;timeout=30",
+			want: "conf/app.properties:0x1F40:critical:密码字段:Tr0ub4dor\n",
+		},
+	}
+	formatter := NewResultFormatter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding, err := ParseFinding(tt.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := formatter.FormatFlatResult("conf/app.properties", finding); got != tt.want {
+				t.Errorf("FormatFlatResult() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,91 +4,147 @@ import (
 	"embed"
 	"fmt"
 	"html/template"
-	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"Findx/internal/risk"
+	"Findx/pkg/utils"
 )
 
-//go:embed template/report.html
+//go:embed template/report.html template/diff.html
 var templateFS embed.FS
 
 // HTMLReport HTML报告数据结构
 type HTMLReport struct {
 	TotalFiles    int
 	TotalFindings int
+	Score         int // 总风险评分
 	Duration      string
 	ScanTime      string
 	GenerateTime  string
 	ScanDirectory string
-	CriticalCount int
-	HighCount     int
-	MediumCount   int
-	LowCount      int
+	Theme         string          // 报告主题（light/dark/auto），为空时使用浅色主题
+	Levels        []HTMLRiskLevel // 各风险等级的结果数，从高到低
+	Rules         []HTMLRuleCount // 各规则的结果数，用于报告中的规则筛选
+	Directories   []HTMLDirectory // 按目录汇总的结果数，用于报告顶部的目录汇总
 	Files         []HTMLFileSection
 }
 
+// HTMLDirectory 目录汇总，Levels 只包含目录中出现的风险等级（从高到低）
+type HTMLDirectory struct {
+	Path    string
+	Count   int
+	MaxRisk HTMLRiskLevel
+	Levels  []HTMLRiskLevel
+	Files   []FileCount
+}
+
+// HTMLRiskLevel 单个风险等级的显示信息和结果数
+type HTMLRiskLevel struct {
+	Name  string
+	Band  string // 对应的内置等级，决定默认样式
+	Icon  string
+	Label string
+	Color string
+	Count int
+}
+
+// HTMLRuleCount 单条规则的结果数
+type HTMLRuleCount struct {
+	Name  string
+	Count int
+}
+
 // HTMLFileSection 文件区域
 type HTMLFileSection struct {
 	Path    string
+	Link    template.URL // 编辑器链接（未启用时为空）
 	Count   int
+	Score   int // 文件的风险评分
 	Results []HTMLResult
+	Groups  []HTMLResultGroup // 按 PE 节区分组的结果（文件中没有节区信息时为空）
+}
+
+// HTMLResultGroup 同一 PE 节区中的结果，Results 指向所属文件区域的 Results
+type HTMLResultGroup struct {
+	Name    string
+	Results []*HTMLResult
 }
 
+// otherSectionGroup 不在任何节区内的结果所在的分组
+const otherSectionGroup = "其他"
+
 // HTMLResult HTML结果项
 type HTMLResult struct {
 	Icon           string
 	RuleName       string
+	Rule           string // 规则名称（不含关键字），用于报告中的规则筛选
 	Type           string
 	RiskLevel      string
+	RiskBand       string // 风险等级对应的内置等级，决定默认样式
+	RiskColor      string // 风险等级的颜色
 	RiskLevelText  string
 	MatchedValue   string
 	LineNumber     string
 	Offset         string
+	Location       string
 	Context        string
+	Claims         string       // JWT 声明摘要
+	Blame          string       // 最后修改该行的提交（--blame）
+	Link           template.URL // 编辑器链接（未启用时为空）
 }
 
 // HTMLReportGenerator HTML报告生成器
 type HTMLReportGenerator struct {
 	template *template.Template
+	bom      bool
 }
 
-// NewHTMLReportGenerator 创建HTML报告生成器
-func NewHTMLReportGenerator() (*HTMLReportGenerator, error) {
+// highlightContext 转义上下文并将匹配内容的标记渲染为 <mark>
+func highlightContext(context string) template.HTML {
+	escaped := template.HTMLEscapeString(context)
+	escaped = strings.ReplaceAll(escaped, utils.HighlightStart, `<mark class="match">`)
+	escaped = strings.ReplaceAll(escaped, utils.HighlightEnd, "</mark>")
+	return template.HTML(escaped)
+}
+
+// NewHTMLReportGenerator 创建HTML报告生成器，bom 为真时报告文件写入 UTF-8 BOM
+func NewHTMLReportGenerator(bom bool) (*HTMLReportGenerator, error) {
 	tmplContent, err := templateFS.ReadFile("template/report.html")
 	if err != nil {
 		return nil, fmt.Errorf("读取模板失败: %w", err)
 	}
 
-	tmpl, err := template.New("report").Parse(string(tmplContent))
+	tmpl, err := template.New("report").Funcs(template.FuncMap{"highlight": highlightContext}).Parse(string(tmplContent))
 	if err != nil {
 		return nil, fmt.Errorf("解析模板失败: %w", err)
 	}
 
 	return &HTMLReportGenerator{
 		template: tmpl,
+		bom:      bom,
 	}, nil
 }
 
 // Generate 生成HTML报告
 func (g *HTMLReportGenerator) Generate(outputPath string, report *HTMLReport) error {
-	file, err := os.Create(outputPath)
+	file, err := CreateReportFile(outputPath, g.bom)
 	if err != nil {
 		return fmt.Errorf("创建HTML文件失败: %w", err)
 	}
 	defer file.Close()
 
-	// 写入 UTF-8 BOM
-	file.Write([]byte{0xEF, 0xBB, 0xBF})
-
 	if err := g.template.Execute(file, report); err != nil {
 		return fmt.Errorf("生成HTML失败: %w", err)
 	}
 
-	return nil
+	return file.Commit()
 }
 
-// BuildHTMLReport 构建HTML报告数据
-func BuildHTMLReport(scanDir string, duration time.Duration, fileResults map[string][]string) *HTMLReport {
+// BuildHTMLReport 构建HTML报告数据，文件按路径排序（sortBy 为 count 时按结果数量、为 score 时按风险评分降序）
+func BuildHTMLReport(scanDir string, duration time.Duration, fileResults map[string][]Finding, sortBy string) *HTMLReport {
 	report := &HTMLReport{
 		ScanDirectory: scanDir,
 		Duration:      duration.String(),
@@ -97,6 +153,9 @@ func BuildHTMLReport(scanDir string, duration time.Duration, fileResults map[str
 		Files:         make([]HTMLFileSection, 0),
 	}
 
+	ruleCounts := make(map[string]int)
+	riskCounts := make(map[string]int)
+
 	// 处理每个文件的结果
 	for filePath, results := range fileResults {
 		if len(results) == 0 {
@@ -106,135 +165,308 @@ func BuildHTMLReport(scanDir string, duration time.Duration, fileResults map[str
 		fileSection := HTMLFileSection{
 			Path:    filePath,
 			Count:   len(results),
+			Score:   scoreResults(results),
 			Results: make([]HTMLResult, 0),
 		}
 
-		for _, raw := range results {
-			htmlResult := parseRawResult(raw)
-			if htmlResult != nil {
-				fileSection.Results = append(fileSection.Results, *htmlResult)
-				
-				// 统计风险等级
-				switch strings.ToLower(htmlResult.RiskLevel) {
-				case "critical":
-					report.CriticalCount++
-				case "high":
-					report.HighCount++
-				case "medium":
-					report.MediumCount++
-				case "low":
-					report.LowCount++
-				}
-			}
+		for i := range results {
+			htmlResult := newHTMLResult(&results[i])
+			fileSection.Results = append(fileSection.Results, *htmlResult)
+			ruleCounts[htmlResult.Rule]++
+			riskCounts[htmlResult.RiskLevel]++
 		}
 
+		fileSection.Groups = groupBySection(results, fileSection.Results)
 		report.Files = append(report.Files, fileSection)
 		report.TotalFiles++
 		report.TotalFindings += len(fileSection.Results)
+		report.Score += fileSection.Score
 	}
 
+	// map 遍历顺序随机，排序后保证报告稳定、便于比对
+	sort.Slice(report.Files, func(i, j int) bool {
+		a, b := report.Files[i], report.Files[j]
+		if sortBy == "count" && a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if sortBy == "score" && a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Path < b.Path
+	})
+
+	for _, level := range risk.Levels() {
+		report.Levels = append(report.Levels, HTMLRiskLevel{
+			Name:  level.Name,
+			Band:  level.Band(),
+			Icon:  level.Icon,
+			Label: level.Label,
+			Color: level.Color,
+			Count: riskCounts[level.Name],
+		})
+	}
+
+	for _, dir := range AggregateByDirectory(fileResults) {
+		htmlDir := HTMLDirectory{Path: dir.Path, Count: dir.Count, Files: dir.Files}
+		for _, level := range report.Levels {
+			count := dir.ByLevel[level.Name]
+			if count == 0 {
+				continue
+			}
+			level.Count = count
+			if level.Name == dir.MaxRisk {
+				htmlDir.MaxRisk = level
+			}
+			htmlDir.Levels = append(htmlDir.Levels, level)
+		}
+		report.Directories = append(report.Directories, htmlDir)
+	}
+
+	for name, count := range ruleCounts {
+		report.Rules = append(report.Rules, HTMLRuleCount{Name: name, Count: count})
+	}
+	sort.Slice(report.Rules, func(i, j int) bool {
+		a, b := report.Rules[i], report.Rules[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Name < b.Name
+	})
+
 	return report
 }
 
-// parseRawResult 解析原始结果字符串
-func parseRawResult(raw string) *HTMLResult {
-	parts := strings.Split(raw, "|")
-	if len(parts) < 2 {
+// groupBySection 按 PE 节区对文件的结果分组，分组按首次出现的顺序排列，"其他" 分组排在最后
+// 没有任何结果带有节区信息时返回 nil
+func groupBySection(findings []Finding, results []HTMLResult) []HTMLResultGroup {
+	hasSection := false
+	for i := range findings {
+		if findings[i].Section != "" {
+			hasSection = true
+			break
+		}
+	}
+	if !hasSection {
 		return nil
 	}
 
-	result := &HTMLResult{}
+	var groups []HTMLResultGroup
+	index := make(map[string]int)
+	var other []*HTMLResult
+	for i := range findings {
+		name := findings[i].Section
+		if name == "" {
+			other = append(other, &results[i])
+			continue
+		}
+		if _, ok := index[name]; !ok {
+			index[name] = len(groups)
+			groups = append(groups, HTMLResultGroup{Name: name})
+		}
+		groups[index[name]].Results = append(groups[index[name]].Results, &results[i])
+	}
+	if len(other) > 0 {
+		groups = append(groups, HTMLResultGroup{Name: otherSectionGroup, Results: other})
+	}
+	return groups
+}
+
+// newHTMLResult 将扫描结果转换为HTML结果项
+func newHTMLResult(f *Finding) *HTMLResult {
+	result := &HTMLResult{
+		RuleName:      KeywordRuleName + ": " + f.Keyword,
+		Rule:          f.RuleName,
+		RiskLevel:     risk.Normalize(f.RiskLevel),
+		RiskBand:      risk.Band(f.RiskLevel),
+		RiskColor:     risk.Color(f.RiskLevel),
+		RiskLevelText: getRiskLevelText(f.RiskLevel),
+		MatchedValue:  f.DisplayValue(),
+		Context:       f.Context,
+	}
+	if f.Claims != nil {
+		result.Claims = f.Claims.String()
+	}
+	if f.Blame != nil {
+		result.Blame = f.Blame.String()
+	}
 
-	switch parts[0] {
+	switch f.Kind {
 	case "TEXT":
-		if len(parts) >= 4 {
-			result.Icon = "🔑"
-			result.RuleName = "关键字匹配: " + parts[1]
-			result.Type = "文本文件"
-			result.RiskLevel = "medium"
-			result.RiskLevelText = "中危"
-			result.LineNumber = parts[2]
-			result.Context = parts[3]
-			result.MatchedValue = parts[1]
+		result.Icon = "🔑"
+		result.Type = "文本文件"
+		if f.LineNumber > 0 {
+			result.LineNumber = strconv.Itoa(f.LineNumber)
 		}
 
 	case "WORD":
-		if len(parts) >= 4 {
-			result.Icon = "📄"
-			result.RuleName = "关键字匹配: " + parts[2]
-			result.Type = "Word文档 - " + parts[1]
-			result.RiskLevel = "medium"
-			result.RiskLevelText = "中危"
-			result.Context = parts[3]
-			result.MatchedValue = parts[2]
-		}
+		result.Icon = "📄"
+		result.Type = "Word文档 - " + f.Location
 
 	case "EXCEL":
-		if len(parts) >= 4 {
-			result.Icon = "📊"
-			result.RuleName = "关键字匹配: " + parts[2]
-			result.Type = "Excel文档 (" + parts[1] + ")"
-			result.RiskLevel = "medium"
-			result.RiskLevelText = "中危"
-			result.Context = parts[3]
-			result.MatchedValue = parts[2]
-		}
+		result.Icon = "📊"
+		result.Type = "Excel文档 (" + f.Location + ")"
 
 	case "CSV":
-		if len(parts) >= 3 {
-			result.Icon = "📋"
-			result.RuleName = "关键字匹配: " + parts[1]
-			result.Type = "CSV文件"
-			result.RiskLevel = "medium"
-			result.RiskLevelText = "中危"
-			result.Context = parts[2]
-			result.MatchedValue = parts[1]
+		result.Icon = "📋"
+		result.Type = "CSV文件"
+
+	case "PAIR":
+		result.Icon = "🔗"
+		result.RuleName = CellPairRuleName + ": " + f.Keyword
+		result.Type = f.Location + " 相邻单元格"
+		result.LineNumber = strconv.Itoa(f.LineNumber)
+
+	case "JAVA":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = "Java类文件"
+		result.Location = fmt.Sprintf("%s 常量池 #%d", f.Location, f.ConstIndex)
+
+	case "XML":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "XML文件"
+		result.Location = f.Location
+		result.LineNumber = strconv.Itoa(f.LineNumber)
+
+	case "YAML":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
 		}
+		result.Type = "YAML文件"
+		result.Location = fmt.Sprintf("文档 #%d %s", f.Document, f.Location)
+		result.LineNumber = strconv.Itoa(f.LineNumber)
+
+	case "LINE":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = f.MatchType
+		result.LineNumber = strconv.Itoa(f.LineNumber)
+
+	case "K8S":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "Kubernetes Secret"
+		result.Location = f.Location
+
+	case "PCAP":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "网络抓包"
+		result.Location = f.Location
+
+	case "HAR":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "HTTP存档 (HAR)"
+		result.Location = f.Location
+
+	case "TERRAFORM":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "Terraform"
+		result.Location = f.Location
+
+	case "KEYSTORE":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = "密钥库"
+		result.Location = f.Location
+
+	case "CERT":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = "证书文件"
+		result.Location = f.Location
+		if f.LineNumber > 0 {
+			result.LineNumber = strconv.Itoa(f.LineNumber)
+		}
+
+	case "IMAGE":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		if f.Keyword != "" {
+			result.RuleName = f.RuleName + ": " + f.Keyword
+		}
+		result.Type = "图片元数据"
+		result.Location = f.Location
+
+	case "WEAK":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = "弱口令 (" + f.MatchType + ")"
+		result.Location = f.Location
+		if f.LineNumber > 0 {
+			result.LineNumber = strconv.Itoa(f.LineNumber)
+		}
+		if f.Offset >= 0 {
+			result.Offset = fmt.Sprintf("0x%X", f.Offset)
+		}
+
+	case "FILE":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = f.Context
+
+	case "CRED":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = f.MatchType
+		result.Location = f.Location
+		result.Context = "账号: " + f.Context
+
+	case "COOKIE":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = f.MatchType
+		result.Location = f.Location
+		result.Context = "Cookie: " + f.Context
+
+	case "HASH":
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = "已知文件 SHA-256"
 
 	case "BINARY":
-		if len(parts) >= 7 {
-			result.Icon = getRiskIconText(parts[3])
-			result.RuleName = parts[2]
-			result.Type = parts[1]
-			result.RiskLevel = strings.ToLower(parts[3])
-			result.RiskLevelText = getRiskLevelText(parts[3])
-			result.MatchedValue = parts[4]
-			result.Offset = parts[5]
-			result.Context = parts[6]
+		result.Icon = getRiskIcon(f.RiskLevel)
+		result.RuleName = f.RuleName
+		result.Type = f.MatchType
+		if f.Offset >= 0 {
+			result.Offset = fmt.Sprintf("0x%X", f.Offset)
 		}
 	}
 
-	return result
-}
-
-// getRiskIconText 获取风险图标文本
-func getRiskIconText(riskLevel string) string {
-	switch strings.ToLower(riskLevel) {
-	case "critical":
-		return "🔴"
-	case "high":
-		return "🟠"
-	case "medium":
-		return "🟡"
-	case "low":
-		return "🟢"
-	default:
-		return "⚪"
+	if f.Embedded != "" {
+		result.Location = strings.TrimSpace("嵌入对象 " + f.Embedded + " " + result.Location)
+	}
+	if f.InComment {
+		result.RiskLevelText += "（注释中）"
 	}
+	if f.InTestPath {
+		result.RiskLevelText += "（测试数据）"
+	}
+
+	return result
 }
 
 // getRiskLevelText 获取风险等级文本
 func getRiskLevelText(riskLevel string) string {
-	switch strings.ToLower(riskLevel) {
-	case "critical":
-		return "严重"
-	case "high":
-		return "高危"
-	case "medium":
-		return "中危"
-	case "low":
-		return "低危"
-	default:
-		return "未知"
-	}
+	return risk.Label(riskLevel)
 }
@@ -0,0 +1,140 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// JSONReport JSON报告数据结构
+type JSONReport struct {
+	Tool          string        `json:"tool"`
+	Version       string        `json:"version"`
+	ScanTarget    string        `json:"target"`
+	ScanTime      string        `json:"scan_time"`
+	Duration      string        `json:"duration"`
+	TotalFiles    int           `json:"total_files"`
+	TotalFindings int           `json:"total_findings"`
+	RiskScore     int           `json:"risk_score"` // 按风险等级权重（--severity-weights）计算的总风险评分
+	Findings      []JSONFinding `json:"findings"`
+}
+
+// JSONFinding JSON报告中的单条结果
+type JSONFinding struct {
+	Fingerprint  string       `json:"fingerprint"` // 结果指纹（文件、规则、关键字和规范化后的敏感值），不受行号和偏移变化影响
+	File         string       `json:"file"`
+	Root         string       `json:"root,omitempty"` // 所属扫描目录（指定多个扫描目录时）
+	Kind         string       `json:"kind"`
+	RuleName     string       `json:"rule"`
+	RiskLevel    string       `json:"risk"`
+	Keyword      string       `json:"keyword,omitempty"`
+	MatchType    string       `json:"match_type,omitempty"`
+	Location     string       `json:"location,omitempty"`
+	MatchedValue string       `json:"value"`
+	LineNumber   int          `json:"line,omitempty"`
+	Offset       *int         `json:"offset,omitempty"`
+	ConstIndex   int          `json:"const_index,omitempty"`
+	Document     int          `json:"document,omitempty"` // YAML 文档序号
+	Context      string       `json:"context,omitempty"`
+	RawContext   []byte       `json:"raw_context,omitempty"`  // 原始字节上下文（base64编码）
+	Claims       *TokenClaims `json:"claims,omitempty"`       // JWT 解码后的声明
+	Tags         []string     `json:"tags,omitempty"`         // 规则的分类标签
+	Section      string       `json:"section,omitempty"`      // PE 节区
+	Embedded     string       `json:"embedded,omitempty"`     // Office 文档中的嵌入对象路径
+	InComment    bool         `json:"in_comment,omitempty"`   // 位于代码注释中
+	InTestPath   bool         `json:"in_test_path,omitempty"` // 位于测试数据目录中
+	Blame        *BlameInfo   `json:"blame,omitempty"`        // 最后修改该行的提交（--blame）
+}
+
+// BuildJSONReport 构建JSON报告数据，结果按文件路径排序
+func BuildJSONReport(tool, version, scanTarget string, duration time.Duration, fileResults map[string][]Finding) *JSONReport {
+	report := &JSONReport{
+		Tool:       tool,
+		Version:    version,
+		ScanTarget: scanTarget,
+		ScanTime:   time.Now().Format(time.RFC3339),
+		Duration:   duration.String(),
+		Findings:   make([]JSONFinding, 0),
+	}
+
+	paths := make([]string, 0, len(fileResults))
+	for filePath, results := range fileResults {
+		if len(results) > 0 {
+			paths = append(paths, filePath)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		for i := range fileResults[filePath] {
+			report.Findings = append(report.Findings, newJSONFinding(filePath, &fileResults[filePath][i]))
+		}
+		report.TotalFiles++
+	}
+	report.TotalFindings = len(report.Findings)
+	report.RiskScore = ScoreFindings(fileResults)
+
+	return report
+}
+
+// newJSONFinding 将扫描结果转换为JSON结果项
+func newJSONFinding(filePath string, f *Finding) JSONFinding {
+	result := JSONFinding{
+		File:         filePath,
+		Root:         f.Root,
+		Kind:         f.Kind,
+		RuleName:     f.RuleName,
+		RiskLevel:    f.RiskLevel,
+		Keyword:      f.Keyword,
+		MatchType:    f.MatchType,
+		Location:     f.Location,
+		MatchedValue: f.MatchedValue,
+		LineNumber:   f.LineNumber,
+		Claims:       f.Claims,
+		Tags:         f.Tags,
+		Section:      f.Section,
+		Embedded:     f.Embedded,
+		InComment:    f.InComment,
+		InTestPath:   f.InTestPath,
+		Blame:        f.Blame,
+		ConstIndex:   f.ConstIndex,
+		Document:     f.Document,
+		Context:      f.Context,
+		RawContext:   f.RawContext,
+	}
+
+	if f.Kind == "BINARY" && f.Offset >= 0 {
+		offset := f.Offset
+		result.Offset = &offset
+	}
+	result.Fingerprint = findingFingerprint(filePath, f)
+
+	return result
+}
+
+// findingFingerprint 计算结果指纹，用于比较不同时间的扫描结果
+func findingFingerprint(filePath string, f *Finding) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", filePath, f.RuleName, f.Keyword, f.NormalizedValue())
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// WriteJSONReport 写入JSON报告（不写入BOM）
+func WriteJSONReport(outputPath string, report *JSONReport) error {
+	file, err := CreateReportFile(outputPath, false)
+	if err != nil {
+		return fmt.Errorf("创建JSON文件失败: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("生成JSON失败: %w", err)
+	}
+
+	return file.Commit()
+}
@@ -0,0 +1,114 @@
+package output
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// JWT 结果的规则名称与风险等级（未过期的令牌升为严重，已过期的降为中危）
+const (
+	JWTRuleName         = "JWT令牌"
+	JWTRiskLevel        = "high"
+	JWTActiveRiskLevel  = "critical"
+	JWTExpiredRiskLevel = "medium"
+)
+
+// TokenClaims JWT 头部和载荷中的关键声明
+type TokenClaims struct {
+	Algorithm string     `json:"alg,omitempty"`
+	Issuer    string     `json:"iss,omitempty"`
+	Subject   string     `json:"sub,omitempty"`
+	ExpiresAt *time.Time `json:"exp,omitempty"`
+	Expired   bool       `json:"expired"`
+}
+
+// DecodeJWT 解码 JWT 的头部和载荷（不校验签名），now 用于判断是否过期
+func DecodeJWT(token string, now time.Time) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("JWT 应由三段组成")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("解码JWT头部失败: %w", err)
+	}
+
+	var payload struct {
+		Iss string          `json:"iss"`
+		Sub json.RawMessage `json:"sub"`
+		Exp json.Number     `json:"exp"`
+	}
+	if err := decodeJWTSegment(parts[1], &payload); err != nil {
+		return nil, fmt.Errorf("解码JWT载荷失败: %w", err)
+	}
+
+	claims := &TokenClaims{
+		Algorithm: header.Alg,
+		Issuer:    payload.Iss,
+		Subject:   strings.Trim(string(payload.Sub), `"`),
+	}
+	if exp, err := payload.Exp.Float64(); err == nil && exp > 0 {
+		t := time.Unix(int64(exp), 0)
+		claims.ExpiresAt = &t
+		claims.Expired = t.Before(now)
+	}
+	return claims, nil
+}
+
+// decodeJWTSegment 解码 base64url 编码的 JSON 段（兼容带填充的写法）
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// String 返回声明摘要，用于文本和HTML报告
+func (c *TokenClaims) String() string {
+	var parts []string
+	if c.Algorithm != "" {
+		parts = append(parts, "alg="+c.Algorithm)
+	}
+	if c.Issuer != "" {
+		parts = append(parts, "iss="+c.Issuer)
+	}
+	if c.Subject != "" {
+		parts = append(parts, "sub="+c.Subject)
+	}
+	switch {
+	case c.ExpiresAt == nil:
+		parts = append(parts, "exp=无（永不过期）")
+	case c.Expired:
+		parts = append(parts, "exp="+c.ExpiresAt.Format("2006-01-02 15:04:05")+"（已过期）")
+	default:
+		parts = append(parts, "exp="+c.ExpiresAt.Format("2006-01-02 15:04:05")+"（未过期）")
+	}
+	return strings.Join(parts, "  ")
+}
+
+// AnnotateJWT 为 JWT 规则的结果附加解码后的声明并按是否过期调整风险等级
+// 无法解码的令牌保持原样
+func AnnotateJWT(f *Finding, now time.Time) {
+	if f.RuleName != JWTRuleName {
+		return
+	}
+	claims, err := DecodeJWT(f.MatchedValue, now)
+	if err != nil {
+		return
+	}
+	f.Claims = claims
+	if claims.Expired {
+		f.RiskLevel = JWTExpiredRiskLevel
+	} else {
+		f.RiskLevel = JWTActiveRiskLevel
+	}
+}
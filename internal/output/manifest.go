@@ -0,0 +1,97 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Manifest 扫描清单（--manifest）：记录扫描的时间、范围、生效的配置和文件统计，用于审计，不包含任何匹配值
+type Manifest struct {
+	Tool       string           `json:"tool"`
+	Version    string           `json:"version"`
+	StartTime  string           `json:"start_time"`
+	EndTime    string           `json:"end_time"`
+	Duration   string           `json:"duration"`
+	Target     string           `json:"target"`
+	Config     ManifestConfig   `json:"config"`
+	Files      ManifestFiles    `json:"files"`
+	Skipped    ManifestSkipped  `json:"skipped"`
+	Findings   ManifestFindings `json:"findings"`
+	Incomplete bool             `json:"incomplete"` // 达到 --max-findings 上限提前结束、或有文件解析失败
+}
+
+// ManifestConfig 扫描时生效的配置
+type ManifestConfig struct {
+	FileTypes      []string `json:"file_types"`
+	ExcludeExts    []string `json:"exclude_exts,omitempty"`
+	ExcludeDirs    []string `json:"exclude_dirs,omitempty"`
+	AutoExcludes   []string `json:"auto_excludes,omitempty"` // 按项目类型自动排除的目录
+	ExcludeFiles   []string `json:"exclude_files,omitempty"`
+	Keywords       []string `json:"keywords"`
+	KeywordCI      bool     `json:"keyword_ci,omitempty"`
+	Rules          []string `json:"rules"` // 启用的规则
+	OnlyRules      []string `json:"only_rules,omitempty"`
+	SkipRules      []string `json:"skip_rules,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	TestPaths      []string `json:"test_paths,omitempty"`
+	MaxFileSize    int64    `json:"max_file_size"`
+	MinFileSize    int64    `json:"min_file_size,omitempty"`
+	SkipHidden     bool     `json:"skip_hidden,omitempty"`
+	SkipGenerated  bool     `json:"skip_generated,omitempty"`
+	IncludeGit     bool     `json:"include_git,omitempty"`
+	Owner          string   `json:"owner,omitempty"`
+	Group          string   `json:"group,omitempty"`
+	BinaryMode     bool     `json:"binary_mode,omitempty"`
+	RawScan        bool     `json:"raw_scan,omitempty"`
+	DualScan       bool     `json:"dual_scan,omitempty"`
+	BinaryFallback string   `json:"binary_fallback"`
+	MaxFindings    int      `json:"max_findings,omitempty"`
+	Seed           int64    `json:"seed,omitempty"`
+	Incremental    bool     `json:"incremental,omitempty"`
+	Threads        int      `json:"threads"`
+}
+
+// ManifestFiles 扫描的文件统计
+type ManifestFiles struct {
+	Scanned    int            `json:"scanned"`          // 扫描的文件数（包括使用缓存结果的文件）
+	Cached     int            `json:"cached,omitempty"` // 未变化、使用缓存结果的文件数
+	TotalBytes int64          `json:"total_bytes"`
+	ByExt      map[string]int `json:"by_ext"`
+}
+
+// ManifestSkipped 跳过的文件和目录数及原因
+type ManifestSkipped struct {
+	ExcludedDirs  int      `json:"excluded_dirs"`       // 排除、隐藏和自动排除的目录
+	ExcludedFiles int      `json:"excluded_files"`      // 排除、隐藏和类型不匹配的文件
+	TooLarge      int      `json:"too_large"`           // 超过 --max-size
+	TooSmall      int      `json:"too_small"`           // 小于 --min-size
+	OwnerMismatch int      `json:"owner_mismatch"`      // 属主/属组不符
+	Generated     int      `json:"generated,omitempty"` // --skip-generated 识别的生成和压缩文件
+	MaxFindings   int      `json:"max_findings"`        // 达到 --max-findings 上限后未扫描
+	ParseErrors   []string `json:"parse_errors,omitempty"`
+}
+
+// ManifestFindings 结果统计（只有数量）
+type ManifestFindings struct {
+	Total    int            `json:"total"`
+	ByRisk   map[string]int `json:"by_risk"`
+	Score    int            `json:"risk_score"`          // 按风险等级权重计算的风险评分
+	TestData int            `json:"test_data,omitempty"` // 位于测试数据目录中、风险等级已降为最低等级的结果数
+}
+
+// WriteManifest 写入扫描清单
+func WriteManifest(outputPath string, manifest *Manifest) error {
+	file, err := CreateReportFile(outputPath, false)
+	if err != nil {
+		return fmt.Errorf("创建扫描清单失败: %w", err)
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return fmt.Errorf("生成扫描清单失败: %w", err)
+	}
+
+	return file.Commit()
+}
@@ -0,0 +1,252 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"Findx/internal/risk"
+)
+
+// 远程结果发送的批量、重试和超时参数
+const (
+	remoteBatchSize     = 100              // 每批最多发送的结果数
+	remoteFlushInterval = 2 * time.Second  // 未满一批时的最长等待时间
+	remoteQueueSize     = 1000             // 待发送队列长度，队列满时写入方等待
+	remoteMaxAttempts   = 3                // 每批最多尝试次数
+	remoteRetryDelay    = 1 * time.Second  // 首次重试的等待时间，之后逐次加倍
+	remoteTimeout       = 10 * time.Second // 连接和请求超时
+)
+
+// remoteTransport 远程结果的发送方式
+type remoteTransport interface {
+	send(batch []JSONFinding) error
+}
+
+// RemoteSink 将结果实时发送到远程收集端（syslog 或 webhook），按批发送并有限次重试
+type RemoteSink struct {
+	name      string
+	transport remoteTransport
+	events    chan JSONFinding
+	done      chan struct{}
+
+	mu      sync.Mutex
+	sent    int
+	dropped int
+	lastErr error
+}
+
+// NewSyslogSink 创建 syslog 发送端，地址格式为 [udp://|tcp://]主机:端口（默认 UDP）
+// 每条结果为一条 RFC 5424 消息，消息内容为 JSON 报告中的结果项
+func NewSyslogSink(addr string) (*RemoteSink, error) {
+	network, host := "udp", addr
+	if i := strings.Index(addr, "://"); i >= 0 {
+		network, host = strings.ToLower(addr[:i]), addr[i+3:]
+	}
+	if network != "udp" && network != "tcp" {
+		return nil, fmt.Errorf("不支持的syslog协议: %s（可选: udp, tcp）", network)
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		return nil, fmt.Errorf("无效的syslog地址: %s", addr)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	return newRemoteSink("syslog", &syslogTransport{
+		network:  network,
+		addr:     host,
+		hostname: hostname,
+		pid:      os.Getpid(),
+	}), nil
+}
+
+// NewWebhookSink 创建 webhook 发送端，每批结果以 JSON 数组 POST 到该地址
+func NewWebhookSink(rawURL string) (*RemoteSink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("无效的webhook地址: %s", rawURL)
+	}
+	return newRemoteSink("webhook", &webhookTransport{
+		url:    rawURL,
+		client: &http.Client{Timeout: remoteTimeout},
+	}), nil
+}
+
+// newRemoteSink 创建发送端并启动发送协程
+func newRemoteSink(name string, transport remoteTransport) *RemoteSink {
+	sink := &RemoteSink{
+		name:      name,
+		transport: transport,
+		events:    make(chan JSONFinding, remoteQueueSize),
+		done:      make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+// Name 获取发送端名称
+func (s *RemoteSink) Name() string {
+	return s.name
+}
+
+// Send 将一个文件的结果加入发送队列
+func (s *RemoteSink) Send(filePath string, findings []Finding) {
+	for i := range findings {
+		s.events <- newJSONFinding(filePath, &findings[i])
+	}
+}
+
+// Close 发送队列中剩余的结果并等待发送协程结束
+// 返回已发送和丢弃的结果数，以及最后一次发送失败的错误
+func (s *RemoteSink) Close() (sent, dropped int, err error) {
+	close(s.events)
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sent, s.dropped, s.lastErr
+}
+
+// run 发送协程：攒满一批或等待超时后发送
+func (s *RemoteSink) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(remoteFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]JSONFinding, 0, remoteBatchSize)
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= remoteBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush 发送一批结果，失败时等待后重试，超过重试次数后丢弃
+func (s *RemoteSink) flush(batch []JSONFinding) {
+	if len(batch) == 0 {
+		return
+	}
+
+	var err error
+	delay := remoteRetryDelay
+	for attempt := 1; attempt <= remoteMaxAttempts; attempt++ {
+		if err = s.transport.send(batch); err == nil {
+			break
+		}
+		if attempt < remoteMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.dropped += len(batch)
+		s.lastErr = err
+		return
+	}
+	s.sent += len(batch)
+}
+
+// syslogTransport 以 RFC 5424 格式发送到 syslog 服务器，TCP 使用 RFC 6587 的长度前缀分帧
+type syslogTransport struct {
+	network  string
+	addr     string
+	hostname string
+	pid      int
+}
+
+// send 建立连接并发送一批消息
+func (t *syslogTransport) send(batch []JSONFinding) error {
+	conn, err := net.DialTimeout(t.network, t.addr, remoteTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(remoteTimeout))
+
+	for i := range batch {
+		msg, err := t.message(&batch[i])
+		if err != nil {
+			return err
+		}
+		if t.network == "tcp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		if _, err := conn.Write([]byte(msg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// message 生成一条 RFC 5424 消息: <PRI>1 时间 主机 应用 进程号 消息ID - 内容
+func (t *syslogTransport) message(f *JSONFinding) (string, error) {
+	body, err := json.Marshal(f)
+	if err != nil {
+		return "", err
+	}
+	// facility 为 user (1)
+	pri := 1*8 + syslogSeverity(f.RiskLevel)
+	timestamp := time.Now().Format(time.RFC3339)
+	return fmt.Sprintf("<%d>1 %s %s findx %d finding - %s", pri, timestamp, t.hostname, t.pid, body), nil
+}
+
+// syslogSeverity 按风险等级对应的内置等级映射为 syslog 严重程度
+func syslogSeverity(riskLevel string) int {
+	switch risk.Band(riskLevel) {
+	case risk.Critical:
+		return 2 // crit
+	case risk.High:
+		return 3 // err
+	case risk.Medium:
+		return 4 // warning
+	default:
+		return 5 // notice
+	}
+}
+
+// webhookTransport 以 JSON 数组 POST 到 HTTP 地址
+type webhookTransport struct {
+	url    string
+	client *http.Client
+}
+
+// send 发送一批结果，非 2xx 状态码视为失败
+func (t *webhookTransport) send(batch []JSONFinding) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Post(t.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook返回状态码: %d", resp.StatusCode)
+	}
+	return nil
+}
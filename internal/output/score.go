@@ -0,0 +1,21 @@
+package output
+
+import "Findx/internal/risk"
+
+// scoreResults 计算单个文件结果的风险评分（各结果风险等级的权重之和）
+func scoreResults(results []Finding) int {
+	score := 0
+	for i := range results {
+		score += risk.Weight(results[i].RiskLevel)
+	}
+	return score
+}
+
+// ScoreFindings 计算所有文件结果的总风险评分
+func ScoreFindings(fileResults map[string][]Finding) int {
+	score := 0
+	for _, results := range fileResults {
+		score += scoreResults(results)
+	}
+	return score
+}
@@ -0,0 +1,92 @@
+package output
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SplitOutputPath 获取按风险等级拆分的输出文件路径，如 res.txt -> res.critical.txt
+func SplitOutputPath(outputFile, level string) string {
+	ext := filepath.Ext(outputFile)
+	return strings.TrimSuffix(outputFile, ext) + "." + level + ext
+}
+
+// SplitWriter 按风险等级拆分的输出写入器（--split-output），每个等级写入单独的文件，首次写入该等级时创建写入器
+type SplitWriter struct {
+	outputFile string
+	create     func(path string) *Writer // 创建单个文件的写入器（BOM、编码和原子写入与合并输出一致）
+	writers    map[string]*Writer
+}
+
+// NewSplitWriter 创建按风险等级拆分的输出写入器
+func NewSplitWriter(outputFile string, create func(path string) *Writer) *SplitWriter {
+	return &SplitWriter{
+		outputFile: outputFile,
+		create:     create,
+		writers:    make(map[string]*Writer),
+	}
+}
+
+// WriteBlock 将一个文件中某个风险等级的格式化结果写入该等级的输出文件；只应由单个输出协程调用
+func (w *SplitWriter) WriteBlock(level string, parts []string, flush bool) error {
+	writer, ok := w.writers[level]
+	if !ok {
+		writer = w.create(SplitOutputPath(w.outputFile, level))
+		w.writers[level] = writer
+	}
+	return writer.WriteBlock(parts, flush)
+}
+
+// Levels 获取已写入结果的风险等级，按 order 中的顺序排列，不在 order 中的等级排在最后
+func (w *SplitWriter) Levels(order []string) []string {
+	var levels []string
+	seen := make(map[string]bool)
+	for _, level := range order {
+		if _, ok := w.writers[level]; ok {
+			levels = append(levels, level)
+			seen[level] = true
+		}
+	}
+	var rest []string
+	for level := range w.writers {
+		if !seen[level] {
+			rest = append(rest, level)
+		}
+	}
+	sort.Strings(rest)
+	return append(levels, rest...)
+}
+
+// WriteFormattedResults 向所有已创建的输出文件追加相同的内容（如扫描汇总）
+func (w *SplitWriter) WriteFormattedResults(results []string) error {
+	var firstErr error
+	for _, writer := range w.writers {
+		if err := writer.WriteFormattedResults(results); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close 刷新并关闭所有输出文件
+func (w *SplitWriter) Close() error {
+	var firstErr error
+	for _, writer := range w.writers {
+		if err := writer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Commit 提交所有输出文件（原子写入模式下替换各个输出文件）
+func (w *SplitWriter) Commit() error {
+	var firstErr error
+	for _, writer := range w.writers {
+		if err := writer.Commit(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
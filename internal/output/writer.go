@@ -3,77 +3,230 @@ package output
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"golang.org/x/text/encoding"
 )
 
+// utf8BOM UTF-8 字节顺序标记
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// writeBufferSize 保持打开的输出文件的写缓冲区大小
+const writeBufferSize = 64 * 1024
+
 // Writer 输出写入器
 type Writer struct {
 	outputFile string
+	bom        bool   // 新文件是否写入 UTF-8 BOM
+	encoding   encoding.Encoding // 输出文件的编码（nil 表示 UTF-8）
+	atomic     bool   // 先写入临时文件，Commit 时再替换输出文件
+	tempFile   string // 原子写入模式下的临时文件路径（首次写入时创建）
 	file       *os.File
+	encoder    io.WriteCloser // 转码写入器（不转码时直接写入文件）
 	writer     *bufio.Writer
 }
 
 // NewWriter 创建输出写入器
-func NewWriter(outputFile string) *Writer {
+func NewWriter(outputFile string, bom bool) *Writer {
 	return &Writer{
 		outputFile: outputFile,
+		bom:        bom,
 	}
 }
 
-// Open 打开输出文件
-func (w *Writer) Open() error {
-	file, err := os.OpenFile(w.outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+// NewAtomicWriter 创建原子写入的输出写入器：结果追加到临时文件（包含输出文件原有内容），
+// 调用 Commit 后才替换输出文件，扫描中断时输出文件保持不变
+func NewAtomicWriter(outputFile string, bom bool) *Writer {
+	return &Writer{
+		outputFile: outputFile,
+		bom:        bom,
+		atomic:     true,
+	}
+}
+
+// SetEncoding 设置输出文件的编码（如 GBK），nil 表示 UTF-8；需在首次写入前调用
+func (w *Writer) SetEncoding(enc encoding.Encoding) {
+	w.encoding = enc
+}
+
+// targetFile 获取实际写入的文件路径，原子写入模式下首次调用时创建临时文件
+func (w *Writer) targetFile() (string, error) {
+	if !w.atomic {
+		return w.outputFile, nil
+	}
+	if w.tempFile != "" {
+		return w.tempFile, nil
+	}
+
+	temp, err := createTempFile(w.outputFile)
 	if err != nil {
-		return fmt.Errorf("打开输出文件失败: %w", err)
+		return "", fmt.Errorf("创建临时文件失败: %w", err)
 	}
-	
+	defer temp.Close()
+
+	// 保留输出文件原有内容，与直接追加的结果一致
+	if existing, err := os.Open(w.outputFile); err == nil {
+		_, err = io.Copy(temp, existing)
+		existing.Close()
+		if err != nil {
+			os.Remove(temp.Name())
+			return "", fmt.Errorf("复制输出文件失败: %w", err)
+		}
+	}
+
+	w.tempFile = temp.Name()
+	return w.tempFile, nil
+}
+
+// Commit 原子写入模式下将临时文件重命名为输出文件，其他模式下不做任何操作
+func (w *Writer) Commit() error {
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("写入输出文件失败: %w", err)
+	}
+	if w.tempFile == "" {
+		return nil
+	}
+	temp := w.tempFile
+	w.tempFile = ""
+	if err := os.Rename(temp, w.outputFile); err != nil {
+		os.Remove(temp)
+		return fmt.Errorf("替换输出文件失败: %w", err)
+	}
+	return nil
+}
+
+// openFile 以追加方式打开输出文件，所有写入路径统一在此处理BOM
+func (w *Writer) openFile() (*os.File, error) {
+	path, err := w.targetFile()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("打开输出文件失败: %w", err)
+	}
+
 	// 如果是新文件，写入 UTF-8 BOM
-	fileInfo, _ := file.Stat()
-	if fileInfo.Size() == 0 {
-		// UTF-8 BOM: EF BB BF
-		file.Write([]byte{0xEF, 0xBB, 0xBF})
+	if w.bom {
+		if fileInfo, err := file.Stat(); err == nil && fileInfo.Size() == 0 {
+			file.Write(utf8BOM)
+		}
+	}
+
+	return file, nil
+}
+
+// createTempFile 在目标文件所在目录创建临时文件，重命名即可原子替换目标文件
+func createTempFile(outputPath string) (*os.File, error) {
+	file, err := os.CreateTemp(filepath.Dir(outputPath), "."+filepath.Base(outputPath)+".tmp-*")
+	if err != nil {
+		return nil, err
+	}
+	file.Chmod(0644)
+	return file, nil
+}
+
+// ReportFile 报告文件：内容先写入同目录下的临时文件，Commit 后重命名为报告文件，
+// 未提交就关闭时删除临时文件，生成失败或中断时不会留下不完整的报告
+type ReportFile struct {
+	*os.File
+	target string
+	done   bool
+}
+
+// CreateReportFile 创建（覆盖）报告文件，bom 为真时写入 UTF-8 BOM
+func CreateReportFile(outputPath string, bom bool) (*ReportFile, error) {
+	file, err := createTempFile(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	if bom {
+		file.Write(utf8BOM)
+	}
+	return &ReportFile{File: file, target: outputPath}, nil
+}
+
+// Commit 关闭临时文件并替换报告文件
+func (f *ReportFile) Commit() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.target); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// Close 放弃未提交的内容并删除临时文件，已提交时不做任何操作
+func (f *ReportFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	f.File.Close()
+	return os.Remove(f.Name())
+}
+
+// Open 打开输出文件
+func (w *Writer) Open() error {
+	file, err := w.openFile()
+	if err != nil {
+		return err
 	}
 	
 	w.file = file
-	w.writer = bufio.NewWriter(file)
+	w.encoder = newEncodingWriter(file, w.encoding)
+	w.writer = bufio.NewWriterSize(w.encoder, writeBufferSize)
 	return nil
 }
 
-// Close 关闭输出文件
+// Close 刷新缓冲区并关闭输出文件，未打开时不做任何操作
 func (w *Writer) Close() error {
-	if w.writer != nil {
-		w.writer.Flush()
+	if w.file == nil {
+		return nil
 	}
-	if w.file != nil {
-		return w.file.Close()
+	flushErr := w.writer.Flush()
+	encodeErr := w.encoder.Close()
+	closeErr := w.file.Close()
+	w.file, w.encoder, w.writer = nil, nil, nil
+	if flushErr != nil {
+		return flushErr
 	}
-	return nil
+	if encodeErr != nil {
+		return encodeErr
+	}
+	return closeErr
 }
 
 // WriteResults 将匹配结果写入文件（旧格式，保持兼容）
 func (w *Writer) WriteResults(filePath string, matchingLines []string) error {
-	file, err := os.OpenFile(w.outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := w.openFile()
 	if err != nil {
-		return fmt.Errorf("打开输出文件失败: %w", err)
+		return err
 	}
 	defer file.Close()
 
-	// 如果是新文件，写入 UTF-8 BOM
-	fileInfo, _ := file.Stat()
-	if fileInfo.Size() == 0 {
-		// UTF-8 BOM: EF BB BF
-		file.Write([]byte{0xEF, 0xBB, 0xBF})
-	}
-
-	writer := bufio.NewWriter(file)
+	encoder := newEncodingWriter(file, w.encoding)
+	writer := bufio.NewWriter(encoder)
 	fmt.Fprintf(writer, "[!] 文件地址: %s\n", filePath)
 	for _, line := range matchingLines {
 		fmt.Fprintln(writer, line)
 	}
 	fmt.Fprintln(writer)
 	
-	return writer.Flush()
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return encoder.Close()
 }
 
 // WriteResult 写入单个结果（新格式）
@@ -99,25 +252,41 @@ func (w *Writer) Flush() error {
 	return nil
 }
 
+// WriteBlock 通过保持打开的缓冲写入器写入一个文件的格式化结果，首次写入时打开输出文件
+// flush 为真时写入后立即刷新，便于在扫描过程中 tail 输出文件；只应由单个输出协程调用
+func (w *Writer) WriteBlock(parts []string, flush bool) error {
+	if w.writer == nil {
+		if err := w.Open(); err != nil {
+			return err
+		}
+	}
+	for _, part := range parts {
+		if _, err := w.writer.WriteString(part); err != nil {
+			return err
+		}
+	}
+	if flush {
+		return w.writer.Flush()
+	}
+	return nil
+}
+
 // WriteFormattedResults 写入格式化的结果
 func (w *Writer) WriteFormattedResults(results []string) error {
-	file, err := os.OpenFile(w.outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	file, err := w.openFile()
 	if err != nil {
-		return fmt.Errorf("打开输出文件失败: %w", err)
+		return err
 	}
 	defer file.Close()
 
-	// 如果是新文件，写入 UTF-8 BOM
-	fileInfo, _ := file.Stat()
-	if fileInfo.Size() == 0 {
-		// UTF-8 BOM: EF BB BF
-		file.Write([]byte{0xEF, 0xBB, 0xBF})
-	}
-
-	writer := bufio.NewWriter(file)
+	encoder := newEncodingWriter(file, w.encoding)
+	writer := bufio.NewWriter(encoder)
 	for _, result := range results {
 		fmt.Fprint(writer, result)
 	}
 
-	return writer.Flush()
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return encoder.Close()
 }
@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxLabelLength 标签单元格的最大长度，超过则视为普通内容而非字段名
+const maxLabelLength = 30
+
+// secretLabels 表格中常见的敏感字段标签
+var secretLabels = []string{
+	"密码", "口令", "密钥", "秘钥", "令牌",
+	"password", "passwd", "pwd", "secret", "token", "apikey", "api key", "api_key", "access key", "accesskey",
+}
+
+// isSecretLabel 判断单元格内容是否为敏感字段标签（如 "密码" 或 "Password:"）
+func isSecretLabel(text string) bool {
+	label := strings.ToLower(strings.TrimSpace(text))
+	label = strings.TrimRight(label, ":：= ")
+	if label == "" || len(label) > maxLabelLength {
+		return false
+	}
+
+	for _, secretLabel := range secretLabels {
+		if strings.Contains(label, secretLabel) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkAdjacentCells 检查同一行中敏感标签右侧相邻的单元格，将其作为疑似凭据值
+func checkAdjacentCells(docType string, rowNum int, row []string) []string {
+	var results []string
+
+	for i := 0; i < len(row)-1; i++ {
+		if !isSecretLabel(row[i]) {
+			continue
+		}
+
+		value := strings.TrimSpace(row[i+1])
+		if value == "" || isSecretLabel(value) {
+			continue
+		}
+
+		results = append(results, formatCellPairResult(docType, rowNum, strings.TrimSpace(row[i]), value))
+	}
+
+	return results
+}
+
+// formatCellPairResult 格式化相邻单元格凭据结果
+func formatCellPairResult(docType string, rowNum int, label, value string) string {
+	return fmt.Sprintf("PAIR|%s|%d|%s|%s", docType, rowNum, label, value)
+}
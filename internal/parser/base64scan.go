@@ -0,0 +1,74 @@
+package parser
+
+import (
+	"context"
+	"encoding/base64"
+)
+
+const (
+	// minBase64RunLength 参与解码的Base64片段最小长度
+	minBase64RunLength = 40
+	// maxBase64RunLength 参与解码的Base64片段最大长度，更长的片段通常是嵌入的资源数据
+	maxBase64RunLength = 64 * 1024
+	// maxBase64Candidates 单个文件最多解码的Base64片段数
+	maxBase64Candidates = 10000
+	// base64SampleLength 完整解码前先试解码的长度，用于尽早排除非文本内容
+	base64SampleLength = 64
+)
+
+// isBase64Char 判断是否为标准Base64字符（不含填充）
+func isBase64Char(b byte) bool {
+	return b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '+' || b == '/'
+}
+
+// forEachBase64Candidate 逐字节查找Base64片段，对解码后为文本的片段调用 fn（decoded 为 UTF-8 文本）
+// 可打印字符比例不超过 threshold 的片段视为非文本；片段数量和长度都有上限，ctx 取消时立即停止
+func forEachBase64Candidate(ctx context.Context, data []byte, threshold float64, fn func(start int, base64Str string, decoded []byte)) {
+	candidates := 0
+
+	for i := 0; i < len(data); {
+		if !isBase64Char(data[i]) {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(data) && isBase64Char(data[i]) {
+			i++
+		}
+		for pad := 0; pad < 2 && i < len(data) && data[i] == '='; pad++ {
+			i++
+		}
+		end := i
+
+		length := end - start
+		if length < minBase64RunLength || length > maxBase64RunLength || length%4 != 0 {
+			continue
+		}
+
+		if candidates >= maxBase64Candidates || ctx.Err() != nil {
+			return
+		}
+		candidates++
+
+		// 先解码开头一小段，不是文本则跳过整个片段
+		if length > base64SampleLength {
+			sample, err := base64.StdEncoding.DecodeString(string(data[start : start+base64SampleLength]))
+			if _, ok := textContent(sample, threshold); err != nil || !ok {
+				continue
+			}
+		}
+
+		base64Str := string(data[start:end])
+		decoded, err := base64.StdEncoding.DecodeString(base64Str)
+		if err != nil {
+			continue
+		}
+		decoded, ok := textContent(decoded, threshold)
+		if !ok {
+			continue
+		}
+
+		fn(start, base64Str, decoded)
+	}
+}
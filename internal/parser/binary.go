@@ -1,13 +1,16 @@
 package parser
 
 import (
-	"encoding/base64"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 	"unicode/utf16"
+	"unicode/utf8"
 
+	"Findx/internal/logger"
 	"Findx/pkg/utils"
 )
 
@@ -16,24 +19,181 @@ const (
 	DOS_SIGNATURE = 0x5A4D
 )
 
+// jwtPattern JWT（头部和载荷均为 base64url 编码的 JSON 对象，签名可为空）
+var jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]*`)
+
 // DetectionRule 检测规则定义
 type DetectionRule struct {
 	Name        string
 	Pattern     *regexp.Regexp
 	Description string
 	RiskLevel   string
+	Tags        []string // 分类标签（cloud/db/pii/key/token/password/network/shell 等），用于 --tags 筛选
+
+	timeout    time.Duration     // 单次匹配的超时时间（0表示不限制）
+	validate   func(string) bool // 没有捕获组的规则的匹配值校验（nil 表示由正则本身限定格式）
+	noValidate bool              // 不校验匹配值（--no-validate）
+}
+
+// matchValue 从匹配结果中取出敏感值并校验
+// 有两个捕获组时取第2组（第1组为字段名），一个捕获组时取第1组，均需符合凭据格式；
+// 没有捕获组的规则（私钥头、SSH公钥等）由正则本身限定格式，直接取整个匹配，邮箱、IP地址等再校验格式
+// 通过 --no-validate 指定的规则不校验
+func (r *DetectionRule) matchValue(match []string) (string, bool) {
+	switch {
+	case r.noValidate:
+		return match[len(match)-1], true
+	case len(match) > 2:
+		return match[2], isValidCredential(match[2])
+	case len(match) == 2:
+		return match[1], isValidCredential(match[1])
+	case r.validate != nil:
+		return match[0], r.validate(match[0])
+	default:
+		return match[0], true
+	}
 }
 
 // BinaryParser 二进制文件解析器（DLL/EXE）
 type BinaryParser struct {
-	rules []DetectionRule
+	rules          []DetectionRule
+	allMatches     bool // 为 false 时同一字符串只保留优先级最高的规则结果
+	maxPerRule     int  // 每个文件中单条规则的最大结果数（0表示不限制）
+	maxStrings     int  // 每个文件最多检查的字符串数（0表示不限制）
+	mergeFragments bool // 合并相邻的字符串片段后再应用规则
+	concurrency    int  // 单个文件内并发匹配的协程数（不大于1时依次匹配）
+	valueMaxLen    int  // 实时输出中匹配值的最大长度（0表示不截断）
+
+	textThreshold float64           // Base64解码内容视为文本的可打印字符最低比例
+	matcher       *keywordMatcher   // 关键字匹配方式（nil 表示区分大小写）
+	keywordRisks  map[string]string // 指定了风险等级的关键字（-k password:critical）
 }
 
 // NewBinaryParser 创建二进制解析器
 func NewBinaryParser() *BinaryParser {
 	return &BinaryParser{
-		rules: initDetectionRules(),
+		rules:         initDetectionRules(),
+		textThreshold: DefaultTextThreshold,
+	}
+}
+
+// keywordRisk 获取关键字匹配结果的风险等级，未指定时为 medium
+func (p *BinaryParser) keywordRisk(keyword string) string {
+	if level, ok := p.keywordRisks[keyword]; ok {
+		return level
+	}
+	return "medium"
+}
+
+// FilterRules 按规则名称筛选检测规则，only 为空时保留全部规则
+func (p *BinaryParser) FilterRules(only, skip []string) {
+	p.rules = filterRules(p.rules, only, skip)
+}
+
+// filterRules 按规则名称筛选规则列表，only 和 skip 均为空时原样返回
+func filterRules(rules []DetectionRule, only, skip []string) []DetectionRule {
+	if len(only) == 0 && len(skip) == 0 {
+		return rules
+	}
+
+	onlySet := make(map[string]bool)
+	for _, name := range only {
+		onlySet[name] = true
+	}
+	skipSet := make(map[string]bool)
+	for _, name := range skip {
+		skipSet[name] = true
+	}
+
+	var filtered []DetectionRule
+	for _, rule := range rules {
+		if (len(onlySet) > 0 && !onlySet[rule.Name]) || skipSet[rule.Name] {
+			continue
+		}
+		filtered = append(filtered, rule)
+	}
+	return filtered
+}
+
+// riskRanks 风险等级的优先级
+var riskRanks = map[string]int{
+	"critical": 4,
+	"high":     3,
+	"medium":   2,
+	"low":      1,
+}
+
+// selectMatches 同一字符串（行）命中多条规则时，只保留优先级最高的规则的结果
+// 优先级先比较风险等级，相同时按规则定义顺序（越靠前越具体）
+func (p *BinaryParser) selectMatches(results []BinaryMatchResult) []BinaryMatchResult {
+	if p.allMatches || len(results) < 2 {
+		return results
+	}
+
+	// results 按规则定义顺序排列，取第一个风险等级最高的规则
+	best := results[0]
+	for _, result := range results[1:] {
+		if riskRanks[strings.ToLower(result.RiskLevel)] > riskRanks[strings.ToLower(best.RiskLevel)] {
+			best = result
+		}
+	}
+
+	selected := results[:0]
+	for _, result := range results {
+		if result.RuleName == best.RuleName {
+			selected = append(selected, result)
+		}
+	}
+	return selected
+}
+
+// BuiltinRuleNames 获取所有内置规则名称（包括各解析器专用的规则）
+func BuiltinRuleNames() []string {
+	var names []string
+	for _, rule := range initDetectionRules() {
+		names = append(names, rule.Name)
 	}
+	return append(names, "关键字匹配", "相邻单元格凭据", "敏感文件", "HTTP Basic认证", "弱口令", "已知文件哈希", "已保存密码",
+		"命令行密码参数", "MySQL命令行密码", "sshpass密码", "redis-cli密码", "curl认证", "URL内嵌凭据", "环境变量凭据",
+		"SecureString明文", "SecureString密文", "net use凭据", "PowerShell编码命令", "硬编码密钥", "硬编码密码", "账号口令组合", "HTTP认证头", "浏览器Cookie", "高熵赋值", "Terraform敏感值",
+		"密钥库私钥", "密钥库证书", "加密密钥库", "X.509证书", "证书已过期", "证书即将过期")
+}
+
+// extraRuleTags 不由 DetectionRule 定义的内置规则的标签
+var extraRuleTags = map[string][]string{
+	"关键字匹配":          {"keyword"},
+	"相邻单元格凭据":        {"password"},
+	"敏感文件":           {"file"},
+	"HTTP Basic认证":   {"network", "password"},
+	"弱口令":            {"password"},
+	"已知文件哈希":         {"file"},
+	"已保存密码":          {"password"},
+	"PowerShell编码命令": {"shell"},
+	"账号口令组合":         {"password"},
+	"HTTP认证头":         {"network", "token"},
+	"浏览器Cookie":       {"token"},
+	"高熵赋值":           {"token"},
+	"Terraform敏感值":     {"cloud", "password"},
+	"密钥库私钥":          {"key"},
+	"密钥库证书":          {"key", "file"},
+	"加密密钥库":          {"key", "file"},
+	"X.509证书":        {"key", "file"},
+	"证书已过期":          {"key", "file"},
+	"证书即将过期":         {"key", "file"},
+}
+
+// RuleTags 返回内置规则名称到标签的映射
+func RuleTags() map[string][]string {
+	tags := make(map[string][]string)
+	for _, rules := range [][]DetectionRule{initDetectionRules(), initHistoryRules(), initScriptRules(), initCodeRules()} {
+		for _, rule := range rules {
+			tags[rule.Name] = rule.Tags
+		}
+	}
+	for name, ruleTags := range extraRuleTags {
+		tags[name] = ruleTags
+	}
+	return tags
 }
 
 // initDetectionRules 初始化检测规则
@@ -44,78 +204,100 @@ func initDetectionRules() []DetectionRule {
 			Pattern:     regexp.MustCompile(`(?i)(ConnectionString|connstr?)\s*=\s*["']([^"']{10,200})["']`),
 			Description: "数据库连接字符串包含认证信息",
 			RiskLevel:   "high",
+			Tags:        []string{"db", "password"},
 		},
 		{
 			Name:        "JDBC连接URL",
 			Pattern:     regexp.MustCompile(`jdbc:\w+://[^\s"']+`),
 			Description: "JDBC数据库连接URL",
 			RiskLevel:   "high",
+			Tags:        []string{"db"},
 		},
 		{
 			Name:        "密码字段",
 			Pattern:     regexp.MustCompile(`(?i)(password|pwd)\s*=\s*["']?([^"'\s;&]{4,50})`),
 			Description: "密码字段赋值",
 			RiskLevel:   "critical",
+			Tags:        []string{"password"},
 		},
 		{
 			Name:        "用户名字段",
 			Pattern:     regexp.MustCompile(`(?i)(username|user|uid)\s*=\s*["']?([^"'\s;&]{3,50})`),
 			Description: "用户名字段赋值",
 			RiskLevel:   "high",
+			Tags:        []string{"pii"},
 		},
 		{
 			Name:        "API密钥",
 			Pattern:     regexp.MustCompile(`(?i)(api[_-]?key|apisecret|sk-)\s*=\s*["']?([a-zA-Z0-9]{20,60})`),
 			Description: "API密钥或访问令牌",
 			RiskLevel:   "critical",
+			Tags:        []string{"cloud", "token"},
 		},
 		{
 			Name:        "SSH密钥",
 			Pattern:     regexp.MustCompile(`ssh-\w+\s+[A-Za-z0-9+/]{100,}`),
 			Description: "SSH公钥或私钥",
 			RiskLevel:   "critical",
+			Tags:        []string{"key"},
 		},
 		{
 			Name:        "LDAP连接",
 			Pattern:     regexp.MustCompile(`ldap[s]?://[^\s"']+`),
 			Description: "LDAP连接字符串",
 			RiskLevel:   "high",
+			Tags:        []string{"network"},
 		},
 		{
 			Name:        "MySQL连接",
 			Pattern:     regexp.MustCompile(`mysqli_connect\([^)]+\)`),
 			Description: "MySQL数据库连接",
 			RiskLevel:   "high",
+			Tags:        []string{"db"},
 		},
 		{
 			Name:        "中文凭据",
 			Pattern:     regexp.MustCompile(`(账号|密码|用户名)\s*[=:]\s*["']?([^"'\s]{3,50})`),
 			Description: "中文账号密码信息",
 			RiskLevel:   "high",
+			Tags:        []string{"password", "pii"},
+		},
+		{
+			Name:        "JWT令牌",
+			Pattern:     jwtPattern,
+			Description: "JSON Web Token（报告中附带解码后的声明）",
+			RiskLevel:   "high",
+			Tags:        []string{"token"},
 		},
 		{
 			Name:        "Bearer令牌",
 			Pattern:     regexp.MustCompile(`Bearer\s+[\w\-._~+/]{20,100}`),
 			Description: "Bearer认证令牌",
 			RiskLevel:   "high",
+			Tags:        []string{"token"},
 		},
 		{
 			Name:        "私钥文件",
 			Pattern:     regexp.MustCompile(`-----BEGIN (?:RSA|DSA|EC) PRIVATE KEY-----`),
 			Description: "加密私钥文件",
 			RiskLevel:   "critical",
+			Tags:        []string{"key"},
 		},
 		{
 			Name:        "邮箱地址",
 			Pattern:     regexp.MustCompile(`[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}`),
 			Description: "邮箱地址（可能用于认证）",
 			RiskLevel:   "medium",
+			Tags:        []string{"pii"},
+			validate:    isValidEmail,
 		},
 		{
 			Name:        "IP地址和端口",
 			Pattern:     regexp.MustCompile(`\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}(?::\d+)?\b`),
 			Description: "IP地址和端口信息",
 			RiskLevel:   "low",
+			Tags:        []string{"network"},
+			validate:    isValidIPAddress,
 		},
 	}
 }
@@ -126,35 +308,39 @@ func (p *BinaryParser) Parse(filePath string, data []byte, verbose bool) []strin
 
 	// 验证PE文件
 	if len(data) < 64 || !isValidPEFile(data) {
-		if verbose {
-			fmt.Printf("[-] 不是有效的PE文件: %s\n", filePath)
-		}
+		logger.Debugf("不是有效的PE文件: %s", filePath)
 		return matchingLines
 	}
 
-	if verbose {
-		fmt.Printf("[*] 分析二进制文件: %s (%.2f MB)\n", filePath, float64(len(data))/1024/1024)
-	}
+	logger.Debugf("分析二进制文件: %s (%.2f MB)", filePath, float64(len(data))/1024/1024)
 
-	// 提取字符串
-	allStrings := extractMeaningfulStrings(data)
+	limit := newRuleLimiter(p.maxPerRule)
 
-	// 检查字符串
-	for _, str := range allStrings {
-		results := p.checkStringWithRules(str, data)
+	// 逐个提取并检查字符串
+	p.forEachMeaningfulString(filePath, data, func(str string) {
+		results := p.checkStringWithRules(str, data, limit)
 		for _, result := range results {
-			lineOutput := fmt.Sprintf("[+] %s: %s", result.RuleName, utils.TruncateString(result.MatchedValue, 100))
+			lineOutput := fmt.Sprintf("[+] %s: %s", result.RuleName, utils.TruncateString(result.MatchedValue, p.valueMaxLen))
 			matchingLines = append(matchingLines, lineOutput)
 			if verbose {
 				fmt.Println(lineOutput)
 			}
 		}
-	}
+	})
 
 	// 检查Base64编码
-	base64Results := p.checkBase64Encoded(data)
+	base64Results := p.checkBase64Encoded(context.Background(), data, limit)
 	for _, result := range base64Results {
-		lineOutput := fmt.Sprintf("[+] %s (Base64): %s", result.RuleName, utils.TruncateString(result.MatchedValue, 100))
+		lineOutput := fmt.Sprintf("[+] %s (Base64): %s", result.RuleName, utils.TruncateString(result.MatchedValue, p.valueMaxLen))
+		matchingLines = append(matchingLines, lineOutput)
+		if verbose {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	// 标记达到上限的规则
+	for _, result := range limit.cappedResults() {
+		lineOutput := fmt.Sprintf("[!] %s: %s", result.RuleName, result.MatchedValue)
 		matchingLines = append(matchingLines, lineOutput)
 		if verbose {
 			fmt.Println(lineOutput)
@@ -166,87 +352,104 @@ func (p *BinaryParser) Parse(filePath string, data []byte, verbose bool) []strin
 
 // ParseWithKeywords 使用关键字解析二进制文件内容
 func (p *BinaryParser) ParseWithKeywords(filePath string, data []byte, keywords []string, verbose bool, contextLen int) []string {
-	var matchingLines []string
-	seenOffsets := make(map[int]bool) // 用于去重
+	return p.ParseWithKeywordsContext(context.Background(), filePath, data, keywords, verbose, contextLen)
+}
 
+// ParseWithKeywordsContext 使用关键字解析二进制文件内容，ctx 取消时中止Base64扫描
+func (p *BinaryParser) ParseWithKeywordsContext(ctx context.Context, filePath string, data []byte, keywords []string, verbose bool, contextLen int) []string {
 	// 验证PE文件
 	if len(data) < 64 || !isValidPEFile(data) {
-		if verbose {
-			fmt.Printf("[-] 不是有效的PE文件: %s\n", filePath)
-		}
-		return matchingLines
+		logger.Debugf("不是有效的PE文件，已跳过（可使用 --binary-fallback raw 按原始字节扫描）: %s", filePath)
+		return nil
 	}
 
-	if verbose {
-		fmt.Printf("[*] 分析二进制文件: %s (%.2f MB)\n", filePath, float64(len(data))/1024/1024)
-	}
+	logger.Debugf("分析二进制文件: %s (%.2f MB)", filePath, float64(len(data))/1024/1024)
 
-	// 提取字符串
-	allStrings := extractMeaningfulStrings(data)
+	return p.scanBytes(ctx, filePath, data, keywords, verbose, contextLen)
+}
 
-	// 1. 使用规则检查
-	for _, str := range allStrings {
-		results := p.checkStringWithRulesEx(str, data, contextLen)
-		for _, result := range results {
-			// 去重：检查偏移是否已存在
-			if seenOffsets[result.Offset] {
-				continue
-			}
-			seenOffsets[result.Offset] = true
-			
-			lineOutput := formatBinaryResult(result, "规则匹配", contextLen)
-			matchingLines = append(matchingLines, lineOutput)
-			if verbose {
-				fmt.Println(lineOutput)
-			}
+// scanBytes 对任意字节内容执行字符串提取、规则、关键字和Base64检查（不校验PE格式）
+// 字符串逐个提取后立即检查，不在内存中保存文件的全部字符串；name 只用于日志
+// 指定 --intra-file-concurrency 时字符串和Base64候选分批在多个协程中匹配，按提取顺序合并，结果与依次扫描相同
+func (p *BinaryParser) scanBytes(ctx context.Context, name string, data []byte, keywords []string, verbose bool, contextLen int) []string {
+	var matchingLines []string
+	seenOffsets := make(map[int]bool) // 用于去重
+	limit := newRuleLimiter(p.maxPerRule)
+	pool := newOrderedPool(p.concurrency)
+	defer pool.stop()
+
+	emit := func(result BinaryMatchResult, matchType string) {
+		// 去重：检查偏移是否已存在
+		if seenOffsets[result.Offset] {
+			return
+		}
+		seenOffsets[result.Offset] = true
+		
+		lineOutput := formatBinaryResult(result, matchType, contextLen)
+		matchingLines = append(matchingLines, lineOutput)
+		if verbose {
+			fmt.Println(lineOutput)
 		}
 	}
 
-	// 2. 使用关键字检查
-	if len(keywords) > 0 {
-		for _, str := range allStrings {
-			for _, keyword := range keywords {
-				if strings.Contains(str, keyword) {
-					offset := findStringOffset(data, str)
-					
-					// 去重：检查偏移是否已存在
-					if seenOffsets[offset] {
-						continue
-					}
-					seenOffsets[offset] = true
-					
-					context := getStringContext(data, offset, contextLen)
-					
-					result := BinaryMatchResult{
-						RuleName:     "关键字匹配",
-						RuleDesc:     fmt.Sprintf("匹配关键字: %s", keyword),
-						RiskLevel:    "medium",
-						MatchedValue: str,
-						Offset:       offset,
-						Context:      context,
-					}
-					
-					lineOutput := formatBinaryResult(result, "关键字", contextLen)
-					matchingLines = append(matchingLines, lineOutput)
-					if verbose {
-						fmt.Println(lineOutput)
-					}
-					break // 找到一个匹配即可
+	// 1. 使用规则和关键字检查提取的字符串
+	var batch []string
+	flush := func() {
+		strs := batch
+		batch = nil
+		matches := make([]stringMatch, len(strs))
+		pool.submit(func() {
+			for i, str := range strs {
+				matches[i] = p.matchString(str, data, keywords, contextLen)
+			}
+		}, func() {
+			for _, match := range matches {
+				dropped := limit.droppedCount()
+				for _, result := range limit.filter(match.rules) {
+					emit(result, "规则匹配")
 				}
+				// 规则结果因达到上限被省略的字符串，不再以关键字形式报告
+				if limit.droppedCount() > dropped || match.keyword == nil {
+					continue
+				}
+				emit(*match.keyword, "关键字")
 			}
+		})
+	}
+	p.forEachMeaningfulString(name, data, func(str string) {
+		batch = append(batch, str)
+		if len(batch) >= intraFileBatchSize {
+			flush()
 		}
+	})
+	if len(batch) > 0 {
+		flush()
+	}
+	pool.wait()
+
+	// 2. 合并相邻的字符串片段后应用规则
+	if p.mergeFragments {
+		p.checkMergedFragments(data, contextLen, limit, func(result BinaryMatchResult) {
+			emit(result, "片段合并")
+		})
 	}
 
 	// 3. 检查Base64编码
-	base64Results := p.checkBase64EncodedEx(data, contextLen)
-	for _, result := range base64Results {
-		// 去重：检查偏移是否已存在
-		if seenOffsets[result.Offset] {
-			continue
-		}
-		seenOffsets[result.Offset] = true
-		
-		lineOutput := formatBinaryResult(result, "Base64编码", contextLen)
+	forEachBase64Candidate(ctx, data, p.textThreshold, func(start int, base64Str string, decoded []byte) {
+		var results []BinaryMatchResult
+		pool.submit(func() {
+			results = p.matchBase64(start, base64Str, decoded, data, contextLen)
+		}, func() {
+			for _, result := range limit.filter(results) {
+				emit(result, "Base64编码")
+			}
+		})
+	})
+	pool.wait()
+
+	// 4. 标记达到上限的规则
+	for _, result := range limit.cappedResults() {
+		lineOutput := formatBinaryResult(result, "规则上限", contextLen)
 		matchingLines = append(matchingLines, lineOutput)
 		if verbose {
 			fmt.Println(lineOutput)
@@ -256,13 +459,36 @@ func (p *BinaryParser) ParseWithKeywords(filePath string, data []byte, keywords
 	return matchingLines
 }
 
+// stringMatch 单个字符串的规则和关键字匹配结果，尚未经过结果数限制和偏移去重
+type stringMatch struct {
+	rules   []BinaryMatchResult
+	keyword *BinaryMatchResult // 未命中关键字时为 nil
+}
+
+// matchString 对提取的字符串应用规则和关键字，只读取 data，可以在多个协程中并发调用
+func (p *BinaryParser) matchString(str string, data []byte, keywords []string, contextLen int) stringMatch {
+	match := stringMatch{rules: p.matchRules(str, data, contextLen)}
+	if len(keywords) == 0 {
+		return match
+	}
+	if keyword, ok := p.matcher.find(str, keywords); ok {
+		offset := findStringOffset(data, str)
+		match.keyword = &BinaryMatchResult{
+			RuleName:     "关键字匹配",
+			RuleDesc:     fmt.Sprintf("匹配关键字: %s", keyword),
+			RiskLevel:    p.keywordRisk(keyword),
+			MatchedValue: str,
+			Offset:       offset,
+			Context:      getStringContext(data, offset, len(str), contextLen),
+		}
+	}
+	return match
+}
+
 // formatBinaryResult 格式化二进制扫描结果
+// 上下文在提取时已按 contextLen 截取并完整保留匹配内容，这里不再截断
 func formatBinaryResult(result BinaryMatchResult, matchType string, contextLen int) string {
-	// 根据上下文长度动态调整显示
 	contextDisplay := result.Context
-	if len(contextDisplay) > contextLen {
-		contextDisplay = contextDisplay[:contextLen] + "..."
-	}
 	
 	return fmt.Sprintf("BINARY|%s|%s|%s|%s|0x%X|%s",
 		matchType,
@@ -275,56 +501,45 @@ func formatBinaryResult(result BinaryMatchResult, matchType string, contextLen i
 
 
 
-// checkStringWithRulesEx 使用规则检查字符串（支持自定义上下文长度）
-func (p *BinaryParser) checkStringWithRulesEx(str string, data []byte, contextLen int) []BinaryMatchResult {
+// matchRules 使用规则检查字符串并定位偏移（支持自定义上下文长度），不计入结果数限制，可以在多个协程中并发调用
+func (p *BinaryParser) matchRules(str string, data []byte, contextLen int) []BinaryMatchResult {
 	var results []BinaryMatchResult
 
+	deadline := startDeadline(p.rules)
 	for _, rule := range p.rules {
-		matches := rule.Pattern.FindAllStringSubmatch(str, -1)
+		if deadline.exceeded(&rule, str) {
+			break
+		}
+		matches := rule.findAll(str)
 		for _, match := range matches {
-			if len(match) > 1 {
-				matchedValue := match[1]
-				if len(match) > 2 {
-					matchedValue = match[2]
-				}
+			matchedValue, ok := rule.matchValue(match)
+			if !ok {
+				continue
+			}
 
-				if isValidCredential(matchedValue) {
-					// 尝试多种方式查找偏移
-					offset := findStringOffset(data, match[0])
-					if offset == -1 {
-						offset = findStringOffset(data, matchedValue)
-					}
-					if offset == -1 {
-						offset = findStringOffset(data, str)
-					}
-					
-					// 如果还是找不到，尝试查找部分字符串
-					if offset == -1 && len(matchedValue) > 10 {
-						offset = findStringOffset(data, matchedValue[:10])
-					}
-
-					context := getStringContext(data, offset, contextLen)
-					
-					// 如果找不到偏移，使用原始字符串作为上下文
-					if offset == -1 && context == "无法定位" {
-						context = truncateForContext(str, contextLen)
-					}
-
-					result := BinaryMatchResult{
-						RuleName:     rule.Name,
-						RuleDesc:     rule.Description,
-						RiskLevel:    rule.RiskLevel,
-						MatchedValue: matchedValue,
-						Offset:       offset,
-						Context:      context,
-					}
-					results = append(results, result)
-				}
+			// 尝试多种方式查找偏移
+			offset, valueOffset, valueLen := locateMatch(data, match[0], matchedValue, str)
+
+			context := getStringContext(data, valueOffset, valueLen, contextLen)
+			
+			// 如果找不到偏移，使用原始字符串作为上下文
+			if offset == -1 && context == "无法定位" {
+				context = highlightInString(str, matchedValue, contextLen)
+			}
+
+			result := BinaryMatchResult{
+				RuleName:     rule.Name,
+				RuleDesc:     rule.Description,
+				RiskLevel:    rule.RiskLevel,
+				MatchedValue: matchedValue,
+				Offset:       offset,
+				Context:      context,
 			}
+			results = append(results, result)
 		}
 	}
 
-	return results
+	return p.selectMatches(results)
 }
 
 // truncateForContext 截断字符串用作上下文
@@ -336,68 +551,43 @@ func truncateForContext(s string, maxLen int) string {
 	return s[:half] + "..." + s[len(s)-half:]
 }
 
-// checkBase64EncodedEx 检查Base64编码的内容（支持自定义上下文长度）
-func (p *BinaryParser) checkBase64EncodedEx(data []byte, contextLen int) []BinaryMatchResult {
-	var results []BinaryMatchResult
-	base64Pattern := regexp.MustCompile(`[A-Za-z0-9+/]{40,}[=]{0,2}`)
-
-	base64Matches := base64Pattern.FindAllIndex(data, -1)
-	for _, match := range base64Matches {
-		start, end := match[0], match[1]
-		base64Str := string(data[start:end])
+// matchBase64 对Base64解码后的文本应用所有检测规则，不计入结果数限制，可以在多个协程中并发调用
+func (p *BinaryParser) matchBase64(start int, base64Str string, decoded []byte, data []byte, contextLen int) []BinaryMatchResult {
+	decodedStr := string(decoded)
 
-		// 尝试解码
-		decoded, err := base64.StdEncoding.DecodeString(base64Str)
-		if err != nil {
-			continue
-		}
-
-		// 检查解码后是否为文本
-		if !isText(decoded) {
-			continue
+	var candidateResults []BinaryMatchResult
+	deadline := startDeadline(p.rules)
+	for _, rule := range p.rules {
+		if deadline.exceeded(&rule, decodedStr) {
+			break
 		}
+		ruleMatches := rule.findAll(decodedStr)
+		for _, ruleMatch := range ruleMatches {
+			matchedValue, ok := rule.matchValue(ruleMatch)
+			if !ok {
+				continue
+			}
 
-		decodedStr := string(decoded)
-
-		// 对解码后的文本应用所有检测规则
-		for _, rule := range p.rules {
-			ruleMatches := rule.Pattern.FindAllStringSubmatch(decodedStr, -1)
-			for _, ruleMatch := range ruleMatches {
-				if len(ruleMatch) < 2 {
-					continue
-				}
-
-				matchedValue := ruleMatch[1]
-				if len(ruleMatch) > 2 {
-					matchedValue = ruleMatch[2]
-				}
-
-				if !isValidCredential(matchedValue) {
-					continue
-				}
-
-				context := getStringContext(data, start, contextLen)
-				
-				// 如果上下文无法定位，使用解码后的字符串
-				if context == "无法定位" {
-					context = fmt.Sprintf("Base64: %s -> %s", 
-						truncateForContext(base64Str, contextLen/2),
-						truncateForContext(decodedStr, contextLen/2))
-				}
-
-				results = append(results, BinaryMatchResult{
-					RuleName:     rule.Name + " (Base64编码)",
-					RuleDesc:     rule.Description + " - Base64编码版本",
-					RiskLevel:    rule.RiskLevel,
-					MatchedValue: matchedValue,
-					Offset:       start,
-					Context:      context,
-				})
+			context := getStringContext(data, start, len(base64Str), contextLen)
+			
+			// 如果上下文无法定位，使用解码后的字符串
+			if context == "无法定位" {
+				context = fmt.Sprintf("Base64: %s -> %s", 
+					truncateForContext(base64Str, contextLen/2),
+					highlightInString(decodedStr, matchedValue, contextLen/2))
 			}
+
+			candidateResults = append(candidateResults, BinaryMatchResult{
+				RuleName:     rule.Name + " (Base64编码)",
+				RuleDesc:     rule.Description + " - Base64编码版本",
+				RiskLevel:    rule.RiskLevel,
+				MatchedValue: matchedValue,
+				Offset:       start,
+				Context:      context,
+			})
 		}
 	}
-
-	return results
+	return p.selectMatches(candidateResults)
 }
 
 // BinaryMatchResult 二进制匹配结果
@@ -410,87 +600,95 @@ type BinaryMatchResult struct {
 	Context      string
 }
 
-// checkStringWithRules 使用规则检查字符串
-func (p *BinaryParser) checkStringWithRules(str string, data []byte) []BinaryMatchResult {
+// MatchString 使用检测规则匹配单个字符串（不定位偏移）
+func (p *BinaryParser) MatchString(str string) []BinaryMatchResult {
 	var results []BinaryMatchResult
 
+	deadline := startDeadline(p.rules)
 	for _, rule := range p.rules {
-		matches := rule.Pattern.FindAllStringSubmatch(str, -1)
+		if deadline.exceeded(&rule, str) {
+			break
+		}
+		matches := rule.findAll(str)
 		for _, match := range matches {
-			if len(match) > 1 {
-				matchedValue := match[1]
-				if len(match) > 2 {
-					matchedValue = match[2]
-				}
-
-				if isValidCredential(matchedValue) {
-					offset := findStringOffset(data, match[0])
-					if offset == -1 {
-						offset = findStringOffset(data, matchedValue)
-					}
-
-					context := getStringContext(data, offset, 50)
-
-					result := BinaryMatchResult{
-						RuleName:     rule.Name,
-						RuleDesc:     rule.Description,
-						RiskLevel:    rule.RiskLevel,
-						MatchedValue: matchedValue,
-						Offset:       offset,
-						Context:      context,
-					}
-					results = append(results, result)
-				}
+			matchedValue, ok := rule.matchValue(match)
+			if !ok {
+				continue
 			}
+
+			results = append(results, BinaryMatchResult{
+				RuleName:     rule.Name,
+				RuleDesc:     rule.Description,
+				RiskLevel:    rule.RiskLevel,
+				MatchedValue: matchedValue,
+				Offset:       -1,
+				Context:      str,
+			})
 		}
 	}
 
-	return results
+	return p.selectMatches(results)
 }
 
-// checkBase64Encoded 检查Base64编码的内容
-func (p *BinaryParser) checkBase64Encoded(data []byte) []BinaryMatchResult {
+// checkStringWithRules 使用规则检查字符串
+func (p *BinaryParser) checkStringWithRules(str string, data []byte, limit *ruleLimiter) []BinaryMatchResult {
 	var results []BinaryMatchResult
-	base64Pattern := regexp.MustCompile(`[A-Za-z0-9+/]{40,}[=]{0,2}`)
 
-	base64Matches := base64Pattern.FindAllIndex(data, -1)
-	for _, match := range base64Matches {
-		start, end := match[0], match[1]
-		base64Str := string(data[start:end])
-
-		// 尝试解码
-		decoded, err := base64.StdEncoding.DecodeString(base64Str)
-		if err != nil {
-			continue
+	deadline := startDeadline(p.rules)
+	for _, rule := range p.rules {
+		if deadline.exceeded(&rule, str) {
+			break
 		}
+		matches := rule.findAll(str)
+		for _, match := range matches {
+			matchedValue, ok := rule.matchValue(match)
+			if !ok {
+				continue
+			}
 
-		// 检查解码后是否为文本
-		if !isText(decoded) {
-			continue
+			offset, valueOffset, valueLen := locateMatch(data, match[0], matchedValue, "")
+
+			context := getStringContext(data, valueOffset, valueLen, 50)
+
+			result := BinaryMatchResult{
+				RuleName:     rule.Name,
+				RuleDesc:     rule.Description,
+				RiskLevel:    rule.RiskLevel,
+				MatchedValue: matchedValue,
+				Offset:       offset,
+				Context:      context,
+			}
+			results = append(results, result)
 		}
+	}
 
+	return limit.filter(p.selectMatches(results))
+}
+
+// checkBase64Encoded 检查Base64编码的内容
+func (p *BinaryParser) checkBase64Encoded(ctx context.Context, data []byte, limit *ruleLimiter) []BinaryMatchResult {
+	var results []BinaryMatchResult
+
+	forEachBase64Candidate(ctx, data, p.textThreshold, func(start int, base64Str string, decoded []byte) {
 		decodedStr := string(decoded)
 
 		// 对解码后的文本应用所有检测规则
+		var candidateResults []BinaryMatchResult
+		deadline := startDeadline(p.rules)
 		for _, rule := range p.rules {
-			ruleMatches := rule.Pattern.FindAllStringSubmatch(decodedStr, -1)
+			if deadline.exceeded(&rule, decodedStr) {
+				break
+			}
+			ruleMatches := rule.findAll(decodedStr)
 			for _, ruleMatch := range ruleMatches {
-				if len(ruleMatch) < 2 {
-					continue
-				}
-
-				matchedValue := ruleMatch[1]
-				if len(ruleMatch) > 2 {
-					matchedValue = ruleMatch[2]
-				}
-
-				if !isValidCredential(matchedValue) {
+				matchedValue, ok := rule.matchValue(ruleMatch)
+				if !ok {
 					continue
 				}
 
-				context := getStringContext(data, start, 50)
+				context := getStringContext(data, start, len(base64Str), 50)
 
-				results = append(results, BinaryMatchResult{
+				candidateResults = append(candidateResults, BinaryMatchResult{
 					RuleName:     rule.Name + " (Base64编码)",
 					RuleDesc:     rule.Description + " - Base64编码版本",
 					RiskLevel:    rule.RiskLevel,
@@ -500,90 +698,128 @@ func (p *BinaryParser) checkBase64Encoded(data []byte) []BinaryMatchResult {
 				})
 			}
 		}
-	}
+		results = append(results, limit.filter(p.selectMatches(candidateResults))...)
+	})
 
 	return results
 }
 
-// extractMeaningfulStrings 提取有意义的字符串
-func extractMeaningfulStrings(data []byte) []string {
-	var results []string
-	stringSet := make(map[string]bool)
+// minStringLength 提取的可打印字符串的最小长度
+const minStringLength = 8
 
-	// 提取UTF-8字符串
-	var current strings.Builder
-	for i := 0; i < len(data); i++ {
-		if data[i] >= 32 && data[i] <= 126 {
-			current.WriteByte(data[i])
-		} else {
-			if current.Len() >= 8 {
-				str := current.String()
-				if !stringSet[str] && isMeaningfulString(str) {
-					stringSet[str] = true
-					results = append(results, str)
-				}
-			}
-			current.Reset()
-		}
-	}
+// stringDedupWindow 字符串去重窗口的大小：最近提取的这么多个不同字符串中重复出现的只检查一次
+const stringDedupWindow = 1 << 16
 
-	if current.Len() >= 8 {
-		str := current.String()
-		if !stringSet[str] && isMeaningfulString(str) {
-			results = append(results, str)
-		}
-	}
+// stringWindow 有界的字符串去重集合，分两代保存最近见过的字符串，当前一代写满时丢弃上一代，
+// 内存占用不随文件大小增长；超出窗口后再次出现的字符串会被重新检查，其结果按偏移去重
+type stringWindow struct {
+	size     int
+	current  map[string]bool
+	previous map[string]bool
+}
 
-	// 提取UTF-16字符串
-	utf16Strings := extractUTF16Strings(data)
-	for _, str := range utf16Strings {
-		if !stringSet[str] && isMeaningfulString(str) {
-			stringSet[str] = true
-			results = append(results, str)
-		}
+// newStringWindow 创建字符串去重窗口
+func newStringWindow(size int) *stringWindow {
+	return &stringWindow{size: size, current: make(map[string]bool)}
+}
+
+// seen 判断字符串是否在窗口中
+func (w *stringWindow) seen(str string) bool {
+	return w.current[str] || w.previous[str]
+}
+
+// add 将字符串加入窗口
+func (w *stringWindow) add(str string) {
+	if len(w.current) >= w.size {
+		w.previous = w.current
+		w.current = make(map[string]bool)
 	}
+	w.current[str] = true
+}
 
-	return results
+// forEachMeaningfulString 依次回调数据中有意义的字符串（先 ASCII 后 UTF-16），去重窗口内重复的字符串只回调一次
+// 回调的字符串数达到 --max-strings 上限时停止提取并输出警告，name 只用于日志
+func (p *BinaryParser) forEachMeaningfulString(name string, data []byte, fn func(str string)) {
+	window := newStringWindow(stringDedupWindow)
+	count := 0
+	forEachString(data, func(offset int, encoding, str string) bool {
+		if window.seen(str) || !isMeaningfulString(str) {
+			return true
+		}
+		if p.maxStrings > 0 && count >= p.maxStrings {
+			logger.Warnf("字符串数达到 --max-strings 上限 (%d)，偏移 0x%X 之后的字符串未检查（Base64编码检查不受影响）: %s", p.maxStrings, offset, name)
+			return false
+		}
+		window.add(str)
+		count++
+		fn(str)
+		return true
+	})
 }
 
-// extractUTF16Strings 提取UTF-16字符串
-func extractUTF16Strings(data []byte) []string {
-	var results []string
-	var currentString []uint16
+// forEachString 依次回调数据中所有长度不小于 minStringLength 的可打印 ASCII 串和 UTF-16LE 串，回调返回 false 时停止
+// offset 为字符串在数据中的字节偏移，encoding 为 "ascii" 或 "utf16"
+func forEachString(data []byte, fn func(offset int, encoding, str string) bool) {
+	// 提取UTF-8字符串
+	start := 0
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && data[i] >= 32 && data[i] <= 126 {
+			continue
+		}
+		if i-start >= minStringLength {
+			if !fn(start, "ascii", string(data[start:i])) {
+				return
+			}
+		}
+		start = i + 1
+	}
 
+	// 提取UTF-16字符串
+	var current []uint16
+	start = 0
 	for i := 0; i < len(data)-1; i += 2 {
 		char := binary.LittleEndian.Uint16(data[i:])
 		if char >= 32 && char <= 126 {
-			currentString = append(currentString, char)
-		} else {
-			if len(currentString) >= 8 {
-				str := string(utf16.Decode(currentString))
-				if isMeaningfulString(str) {
-					results = append(results, str)
-				}
+			if len(current) == 0 {
+				start = i
 			}
-			currentString = nil
+			current = append(current, char)
+			continue
 		}
-	}
-
-	if len(currentString) >= 8 {
-		str := string(utf16.Decode(currentString))
-		if isMeaningfulString(str) {
-			results = append(results, str)
+		if len(current) >= minStringLength {
+			if !fn(start, "utf16", string(utf16.Decode(current))) {
+				return
+			}
 		}
+		current = current[:0]
+	}
+	if len(current) >= minStringLength {
+		fn(start, "utf16", string(utf16.Decode(current)))
 	}
-
-	return results
 }
 
 // isMeaningfulString 判断字符串是否有意义
 func isMeaningfulString(str string) bool {
-	if len(str) > 500 || len(str) < 8 {
-		return false
+	return stringRejectReason(str) == ""
+}
+
+// stringRejectReason 获取字符串未被提取的原因，返回空字符串表示保留
+func stringRejectReason(str string) string {
+	if len(str) > 500 {
+		return "过长"
+	}
+	if len(str) < 8 {
+		return "过短"
+	}
+
+	// PEM 头的连续短横线和 SSH 公钥开头的 AAAA 会被判定为重复模式，需要先行放过；JWT 不含关键字
+	if strings.Contains(str, "-----BEGIN ") || jwtPattern.MatchString(str) || strings.Contains(str, "ssh-rsa AAAA") ||
+		strings.Contains(str, "ssh-ed25519 AAAA") || strings.Contains(str, "ssh-dss AAAA") {
+		return ""
 	}
 
 	if isLikelyGarbage(str) {
-		return false
+		return "乱码"
 	}
 
 	keywords := []string{
@@ -595,16 +831,16 @@ func isMeaningfulString(str string) bool {
 
 	for _, keyword := range keywords {
 		if strings.Contains(strings.ToLower(str), strings.ToLower(keyword)) {
-			return true
+			return ""
 		}
 	}
 
 	if strings.Contains(str, "://") || strings.Contains(str, "Data Source") ||
 		strings.Contains(str, "Initial Catalog") || strings.Contains(str, "User ID") {
-		return true
+		return ""
 	}
 
-	return false
+	return "无关键字"
 }
 
 // isLikelyGarbage 判断是否为垃圾字符串
@@ -689,17 +925,6 @@ func hasCredentialLikePattern(str string) bool {
 	return false
 }
 
-// isText 判断是否为文本
-func isText(data []byte) bool {
-	printable := 0
-	for _, b := range data {
-		if b >= 32 && b <= 126 || b == 10 || b == 13 || b == 9 {
-			printable++
-		}
-	}
-	return float64(printable)/float64(len(data)) > 0.7
-}
-
 // isValidPEFile 验证是否为有效的PE文件
 func isValidPEFile(data []byte) bool {
 	if len(data) < 2 || binary.LittleEndian.Uint16(data[0:2]) != DOS_SIGNATURE {
@@ -718,25 +943,99 @@ func findStringOffset(data []byte, str string) int {
 	return strings.Index(string(data), str)
 }
 
-// getStringContext 获取字符串上下文
-func getStringContext(data []byte, offset, contextLen int) string {
+// locateMatch 查找规则匹配在数据中的偏移，依次尝试完整匹配、匹配值、所在字符串和匹配值前10个字符
+// 返回报告的偏移，以及上下文中需要高亮的匹配值的偏移和长度（无法定位时偏移均为 -1）
+func locateMatch(data []byte, match, value, str string) (offset, valueOffset, valueLen int) {
+	for _, candidate := range []string{match, value, str} {
+		if candidate == "" {
+			continue
+		}
+		if offset = findStringOffset(data, candidate); offset == -1 {
+			continue
+		}
+		if idx := strings.Index(candidate, value); idx >= 0 {
+			return offset, offset + idx, len(value)
+		}
+		return offset, offset, len(candidate)
+	}
+
+	// 如果还是找不到，尝试查找部分字符串
+	if len(value) > 10 {
+		if offset = findStringOffset(data, value[:10]); offset != -1 {
+			return offset, offset, min(len(value), len(data)-offset)
+		}
+	}
+	return -1, -1, 0
+}
+
+// getStringContext 获取匹配位置的上下文，完整包含 [offset, offset+length) 的匹配内容并用标记包围
+// 两侧对称扩展至总长度 contextLen，一侧到达数据边界时剩余长度留给另一侧；不可打印字节显示为 '.'
+func getStringContext(data []byte, offset, length, contextLen int) string {
 	if offset == -1 {
 		return "无法定位"
 	}
+	length = min(length, len(data)-offset)
+
+	// 匹配内容本身超过上下文长度时只显示匹配内容
+	if length >= contextLen {
+		return utils.Highlight(truncateForContext(printableBytes(data[offset:offset+length]), contextLen))
+	}
+
+	start, end := contextWindow(len(data), offset, length, contextLen)
+	return printableBytes(data[start:offset]) +
+		utils.Highlight(printableBytes(data[offset:offset+length])) +
+		printableBytes(data[offset+length:end])
+}
+
+// highlightInString 截取字符串中匹配值附近的内容作为上下文，匹配值不在字符串中时退化为截断
+func highlightInString(s, value string, contextLen int) string {
+	idx := strings.Index(s, value)
+	if value == "" || idx < 0 {
+		return truncateForContext(s, contextLen)
+	}
+	if len(value) >= contextLen {
+		return utils.Highlight(truncateForContext(value, contextLen))
+	}
+
+	start, end := contextWindow(len(s), idx, len(value), contextLen)
+	// 避免截断多字节字符
+	for start > 0 && !utf8.RuneStart(s[start]) {
+		start--
+	}
+	for end < len(s) && !utf8.RuneStart(s[end]) {
+		end++
+	}
+	return s[start:idx] + utils.Highlight(value) + s[idx+len(value):end]
+}
 
-	start := max(0, offset-contextLen)
-	end := min(len(data), offset+contextLen)
+// contextWindow 计算包含 [offset, offset+length) 的上下文范围
+func contextWindow(total, offset, length, contextLen int) (int, int) {
+	budget := max(0, contextLen-length)
+	left := budget / 2
+	right := budget - left
+
+	if offset < left {
+		right += left - offset
+		left = offset
+	}
+	if end := offset + length; end+right > total {
+		left = min(offset, left+end+right-total)
+		right = total - end
+	}
+	return offset - left, offset + length + right
+}
 
-	contextBytes := data[start:end]
+// printableBytes 将字节转换为可显示的字符串，不可打印字节显示为 '.'
+func printableBytes(data []byte) string {
 	var result strings.Builder
-	for _, b := range contextBytes {
+	result.Grow(len(data))
+	for _, b := range data {
 		if b >= 32 && b <= 126 {
 			result.WriteByte(b)
 		} else {
 			result.WriteByte('.')
 		}
 	}
-
 	return result.String()
 }
 
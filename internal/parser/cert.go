@@ -0,0 +1,304 @@
+package parser
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"Findx/internal/logger"
+)
+
+// 证书文件结果的规则名称
+const (
+	CertificateRuleName         = "X.509证书"
+	CertificateExpiredRuleName  = "证书已过期"
+	CertificateExpiringRuleName = "证书即将过期"
+	PrivateKeyRuleName          = "私钥文件" // 与文本扫描的私钥规则相同
+)
+
+// certExpiryWarningDays 剩余有效期不超过该天数的证书报告为即将过期
+const certExpiryWarningDays = 30
+
+// maxCertNames 证书结果中列出的备用名称数量上限
+const maxCertNames = 5
+
+// certExtensions 证书文件的扩展名
+var certExtensions = []string{".pem", ".crt", ".cer"}
+
+// pemBegin PEM 块的起始标记
+var pemBegin = []byte("-----BEGIN ")
+
+// IsCertificateFile 按扩展名判断是否为证书文件
+func IsCertificateFile(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	for _, ext := range certExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// certBlock 证书文件中的一个证书或私钥
+type certBlock struct {
+	Line      int    // PEM 块起始行号（DER 文件为 0）
+	Location  string // 块序号和类型
+	Type      string // PEM 块类型
+	Cert      *x509.Certificate
+	PublicKey crypto.PublicKey // 私钥对应的公钥（无法解析时为 nil）
+	Algorithm string           // 私钥算法
+	Encrypted bool             // 私钥是否加密保存
+}
+
+// CertParser X.509 证书文件（.pem/.crt/.cer）解析器，列出证书的主体、颁发者和有效期，标记过期和即将过期的证书以及文件中附带的私钥
+type CertParser struct{}
+
+// NewCertParser 创建证书文件解析器
+func NewCertParser() *CertParser {
+	return &CertParser{}
+}
+
+// Parse 解析证书文件，PEM 文件依次解析所有块，否则按 DER 编码的单个证书解析
+// 没有可识别的证书和私钥时返回 nil 由调用方按普通文件处理
+func (p *CertParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取文件%s错误", filePath)
+		return nil
+	}
+
+	var blocks []certBlock
+	if bytes.Contains(data, pemBegin) {
+		blocks = parsePEMBlocks(data)
+	} else if cert, err := parseCertificateDER(data); err == nil {
+		blocks = append(blocks, certBlock{Location: "DER证书", Type: "CERTIFICATE", Cert: cert})
+	}
+	if len(blocks) == 0 {
+		logger.Debugf("没有可识别的证书或私钥，按普通文件扫描: %s", filePath)
+		return nil
+	}
+
+	matchingLines := p.results(blocks, time.Now())
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+	return matchingLines
+}
+
+// parsePEMBlocks 解析 PEM 文件（包括证书链和附带私钥的证书包）中的所有证书和私钥块，其他类型的块忽略
+func parsePEMBlocks(data []byte) []certBlock {
+	var blocks []certBlock
+	index := 0
+	for pos := 0; ; {
+		start := bytes.Index(data[pos:], pemBegin)
+		if start < 0 {
+			break
+		}
+		start += pos
+		block, rest := pem.Decode(data[start:])
+		if block == nil {
+			pos = start + len(pemBegin)
+			continue
+		}
+		pos = len(data) - len(rest)
+		index++
+
+		b := certBlock{
+			Line:     1 + bytes.Count(data[:start], []byte("\n")),
+			Location: fmt.Sprintf("PEM块 #%d (%s)", index, block.Type),
+			Type:     block.Type,
+		}
+		switch {
+		case strings.HasSuffix(block.Type, "CERTIFICATE"):
+			cert, err := parseCertificateDER(block.Bytes)
+			if err != nil {
+				logger.Debugf("%s 无法解析: %v", b.Location, err)
+				continue
+			}
+			b.Cert = cert
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			b.Encrypted = block.Type == "ENCRYPTED PRIVATE KEY" || strings.Contains(block.Headers["Proc-Type"], "ENCRYPTED")
+			if !b.Encrypted {
+				b.Algorithm, b.PublicKey, b.Encrypted = describePrivateKey(block)
+			}
+		default:
+			continue
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks
+}
+
+// parseCertificateDER 解析 DER 编码的证书，忽略证书之后的附加数据（如 OpenSSL TRUSTED CERTIFICATE 的信任设置）
+func parseCertificateDER(der []byte) (*x509.Certificate, error) {
+	var raw asn1.RawValue
+	if _, err := asn1.Unmarshal(der, &raw); err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(raw.FullBytes)
+}
+
+// describePrivateKey 获取未加密私钥的算法和公钥，OpenSSH 私钥只判断是否加密
+func describePrivateKey(block *pem.Block) (algorithm string, public crypto.PublicKey, encrypted bool) {
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "OPENSSH PRIVATE KEY":
+		cipher := opensshCipher(block.Bytes)
+		return "OpenSSH", nil, cipher != "" && cipher != "none"
+	default:
+		return strings.TrimSuffix(block.Type, " PRIVATE KEY"), nil, false
+	}
+	if err != nil {
+		return "无法解析", nil, false
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return fmt.Sprintf("RSA %d 位", k.N.BitLen()), k.Public(), false
+	case *ecdsa.PrivateKey:
+		return "ECDSA " + k.Curve.Params().Name, k.Public(), false
+	case ed25519.PrivateKey:
+		return "Ed25519", k.Public(), false
+	}
+	return fmt.Sprintf("%T", key), nil, false
+}
+
+// opensshCipher 读取 OpenSSH 私钥的加密算法（none 表示未加密），格式无法识别时返回空
+func opensshCipher(data []byte) string {
+	const magic = "openssh-key-v1\x00"
+	if !bytes.HasPrefix(data, []byte(magic)) || len(data) < len(magic)+4 {
+		return ""
+	}
+	data = data[len(magic):]
+	n := binary.BigEndian.Uint32(data)
+	if uint64(n) > uint64(len(data)-4) {
+		return ""
+	}
+	return string(data[4 : 4+n])
+}
+
+// results 生成证书文件的扫描结果：私钥为严重，过期和即将过期的证书为中危，其他证书为低危
+func (p *CertParser) results(blocks []certBlock, now time.Time) []string {
+	var results []string
+	for i := range blocks {
+		b := &blocks[i]
+		if b.Cert != nil {
+			results = append(results, certResult(b, now))
+			continue
+		}
+
+		details := []string{"类型: " + b.Type}
+		if b.Algorithm != "" {
+			details = append(details, "算法: "+b.Algorithm)
+		}
+		if b.Encrypted {
+			details = append(details, "私钥已加密")
+		} else {
+			details = append(details, "私钥未加密")
+		}
+		if owner := matchingCertificate(blocks, b.PublicKey); owner != nil {
+			details = append(details, fmt.Sprintf("对应证书: %s（%s）", owner.Location, owner.Cert.Subject))
+		}
+		results = append(results, formatCertResult(b.Line, b.Location, PrivateKeyRuleName, "critical", b.Type, strings.Join(details, "; ")))
+	}
+	return results
+}
+
+// certResult 生成一张证书的结果，包括主体、颁发者、有效期、剩余天数、备用名称和指纹
+func certResult(b *certBlock, now time.Time) string {
+	cert := b.Cert
+	ruleName, riskLevel := CertificateRuleName, "low"
+	var status string
+	days := int(cert.NotAfter.Sub(now).Hours() / 24)
+	switch {
+	case now.After(cert.NotAfter):
+		ruleName, riskLevel = CertificateExpiredRuleName, "medium"
+		status = fmt.Sprintf("已过期 %d 天", -days)
+	case days <= certExpiryWarningDays:
+		ruleName, riskLevel = CertificateExpiringRuleName, "medium"
+		status = fmt.Sprintf("%d 天后过期", days)
+	case now.Before(cert.NotBefore):
+		status = "尚未生效"
+	default:
+		status = fmt.Sprintf("剩余 %d 天", days)
+	}
+
+	details := []string{
+		"主体: " + cert.Subject.String(),
+		"颁发者: " + cert.Issuer.String(),
+		fmt.Sprintf("有效期: %s 至 %s（%s）", cert.NotBefore.Format("2006-01-02"), cert.NotAfter.Format("2006-01-02"), status),
+	}
+	if names := certificateNames(cert); len(names) > 0 {
+		details = append(details, "备用名称: "+strings.Join(names, ", "))
+	}
+	var flags []string
+	if cert.IsCA {
+		flags = append(flags, "CA证书")
+	}
+	if bytes.Equal(cert.RawSubject, cert.RawIssuer) {
+		flags = append(flags, "自签名")
+	}
+	if len(flags) > 0 {
+		details = append(details, "属性: "+strings.Join(flags, ", "))
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	details = append(details, "序列号: "+cert.SerialNumber.Text(16), "SHA-256: "+hex.EncodeToString(fingerprint[:]))
+
+	value := cert.Subject.CommonName
+	if value == "" {
+		value = cert.Subject.String()
+	}
+	return formatCertResult(b.Line, b.Location, ruleName, riskLevel, value, strings.Join(details, "; "))
+}
+
+// certificateNames 获取证书的 DNS 名称和 IP 备用名称，超过上限时注明总数
+func certificateNames(cert *x509.Certificate) []string {
+	names := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	if len(names) > maxCertNames {
+		names = append(names[:maxCertNames], fmt.Sprintf("等 %d 个", len(names)))
+	}
+	return names
+}
+
+// matchingCertificate 查找公钥与私钥对应的证书，找不到时返回 nil
+func matchingCertificate(blocks []certBlock, public crypto.PublicKey) *certBlock {
+	key, ok := public.(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return nil
+	}
+	for i := range blocks {
+		if blocks[i].Cert != nil && key.Equal(blocks[i].Cert.PublicKey) {
+			return &blocks[i]
+		}
+	}
+	return nil
+}
+
+// formatCertResult 格式化证书文件扫描结果
+func formatCertResult(lineNum int, location, ruleName, riskLevel, matchedValue, content string) string {
+	return fmt.Sprintf("CERT|%d|%s|%s|%s|%s|%s", lineNum, location, ruleName, riskLevel, strings.ReplaceAll(matchedValue, "|", "/"), content)
+}
@@ -0,0 +1,208 @@
+package parser
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCertKey 生成证书用例的 ECDSA 密钥
+func testCertKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// signCertificate 用 parent 的密钥签发证书，parent 为 nil 时自签名
+func signCertificate(t *testing.T, template *x509.Certificate, key *ecdsa.PrivateKey, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) *x509.Certificate {
+	t.Helper()
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// certFingerprint 证书的 SHA-256 指纹
+func certFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// opensshKeyBlock 构造只包含文件头和加密算法的 OpenSSH 私钥块
+func opensshKeyBlock(cipher string) *pem.Block {
+	data := append([]byte("openssh-key-v1\x00"), binary.BigEndian.AppendUint32(nil, uint32(len(cipher)))...)
+	data = append(data, cipher...)
+	return &pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: append(data, make([]byte, 32)...)}
+}
+
+// writePEM 追加一个 PEM 块，返回块起始行号（ECDSA 签名长度不固定，块的行数随之变化）
+func writePEM(b *strings.Builder, block *pem.Block) string {
+	line := strings.Count(b.String(), "\n") + 1
+	b.Write(pem.EncodeToMemory(block))
+	return strconv.Itoa(line)
+}
+
+func TestCertParserResults(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	caKey := testCertKey(t)
+	ca := signCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(0x1001),
+		Subject:               pkix.Name{CommonName: "Corp Root CA", Organization: []string{"Corp"}},
+		NotBefore:             time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:              time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, caKey, nil, nil)
+
+	serverKey := testCertKey(t)
+	server := signCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(0x2002),
+		Subject:      pkix.Name{CommonName: "server.corp.local"},
+		NotBefore:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC),
+		DNSNames:     []string{"server.corp.local", "api.corp.local"},
+	}, serverKey, ca, caKey)
+
+	expired := signCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(0x3003),
+		Subject:      pkix.Name{CommonName: "old.corp.local"},
+		NotBefore:    time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2025, 5, 22, 0, 0, 0, 0, time.UTC),
+	}, testCertKey(t), nil, nil)
+
+	serverKeyDER, err := x509.MarshalPKCS8PrivateKey(serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var bundle strings.Builder
+	bundle.WriteString("# server.corp.local 证书链和私钥\n")
+	serverLine := writePEM(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: server.Raw})
+	caLine := writePEM(&bundle, &pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	keyLine := writePEM(&bundle, &pem.Block{Type: "PRIVATE KEY", Bytes: serverKeyDER})
+
+	var encrypted strings.Builder
+	expiredLine := writePEM(&encrypted, &pem.Block{Type: "CERTIFICATE", Bytes: expired.Raw})
+	// PKCS#8 加密私钥、带 Proc-Type 头的传统加密私钥、加密和未加密的 OpenSSH 私钥
+	pkcs8Line := writePEM(&encrypted, &pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: make([]byte, 64)})
+	rsaLine := writePEM(&encrypted, &pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00112233445566778899AABBCCDDEEFF"},
+		Bytes:   make([]byte, 64),
+	})
+	opensshLine := writePEM(&encrypted, opensshKeyBlock("aes256-ctr"))
+	plainOpensshLine := writePEM(&encrypted, opensshKeyBlock("none"))
+	encrypted.WriteString("-----BEGIN CERTIFICATE-----\nnot base64\n-----END CERTIFICATE-----\n")
+
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{
+			name: "chain with key",
+			data: bundle.String(),
+			want: []string{
+				"CERT|" + serverLine + "|PEM块 #1 (CERTIFICATE)|证书即将过期|medium|server.corp.local|主体: CN=server.corp.local; 颁发者: CN=Corp Root CA,O=Corp; " +
+					"有效期: 2025-01-01 至 2025-06-21（20 天后过期）; 备用名称: server.corp.local, api.corp.local; 序列号: 2002; SHA-256: " + certFingerprint(server),
+				"CERT|" + caLine + "|PEM块 #2 (CERTIFICATE)|X.509证书|low|Corp Root CA|主体: CN=Corp Root CA,O=Corp; 颁发者: CN=Corp Root CA,O=Corp; " +
+					"有效期: 2020-01-01 至 2030-01-01（剩余 1675 天）; 属性: CA证书, 自签名; 序列号: 1001; SHA-256: " + certFingerprint(ca),
+				"CERT|" + keyLine + "|PEM块 #3 (PRIVATE KEY)|私钥文件|critical|PRIVATE KEY|类型: PRIVATE KEY; 算法: ECDSA P-256; 私钥未加密; 对应证书: PEM块 #1 (CERTIFICATE)（CN=server.corp.local）",
+			},
+		},
+		{
+			name: "encrypted keys",
+			data: encrypted.String(),
+			want: []string{
+				"CERT|" + expiredLine + "|PEM块 #1 (CERTIFICATE)|证书已过期|medium|old.corp.local|主体: CN=old.corp.local; 颁发者: CN=old.corp.local; " +
+					"有效期: 2023-01-01 至 2025-05-22（已过期 10 天）; 属性: 自签名; 序列号: 3003; SHA-256: " + certFingerprint(expired),
+				"CERT|" + pkcs8Line + "|PEM块 #2 (ENCRYPTED PRIVATE KEY)|私钥文件|critical|ENCRYPTED PRIVATE KEY|类型: ENCRYPTED PRIVATE KEY; 私钥已加密",
+				"CERT|" + rsaLine + "|PEM块 #3 (RSA PRIVATE KEY)|私钥文件|critical|RSA PRIVATE KEY|类型: RSA PRIVATE KEY; 私钥已加密",
+				"CERT|" + opensshLine + "|PEM块 #4 (OPENSSH PRIVATE KEY)|私钥文件|critical|OPENSSH PRIVATE KEY|类型: OPENSSH PRIVATE KEY; 算法: OpenSSH; 私钥已加密",
+				"CERT|" + plainOpensshLine + "|PEM块 #5 (OPENSSH PRIVATE KEY)|私钥文件|critical|OPENSSH PRIVATE KEY|类型: OPENSSH PRIVATE KEY; 算法: OpenSSH; 私钥未加密",
+			},
+		},
+	}
+
+	parser := NewCertParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parser.results(parsePEMBlocks([]byte(tt.data)), now)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCertParser(t *testing.T) {
+	cert := signCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(0x4004),
+		Subject:      pkix.Name{CommonName: "der.corp.local"},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, testCertKey(t), nil, nil)
+
+	tests := []struct {
+		file   string
+		data   []byte
+		prefix string // 结果中不随当前时间变化的部分，为空表示按普通文件处理
+	}{
+		{"server.cer", cert.Raw, "CERT|0|DER证书|X.509证书|low|der.corp.local|主体: CN=der.corp.local; 颁发者: CN=der.corp.local; 有效期: 2024-01-01 至 2099-01-01（剩余 "},
+		{"server.crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), "CERT|1|PEM块 #1 (CERTIFICATE)|X.509证书|low|der.corp.local|"},
+		{"truncated.cer", cert.Raw[:len(cert.Raw)/2], ""},
+		{"notes.pem", []byte("password=Hunter2024\n"), ""},
+		{"csr.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: make([]byte, 16)}), ""},
+	}
+
+	parser := NewCertParser()
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if !IsCertificateFile(path) {
+				t.Fatalf("IsCertificateFile(%q) = false", tt.file)
+			}
+			got := parser.Parse(path, nil, false)
+			if tt.prefix == "" {
+				if got != nil {
+					t.Errorf("results = %q, want nil", got)
+				}
+				return
+			}
+			if len(got) != 1 || !strings.HasPrefix(got[0], tt.prefix) {
+				t.Errorf("results = %q, want one result starting with %q", got, tt.prefix)
+			}
+		})
+	}
+}
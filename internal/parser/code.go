@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// codeSource 源代码中硬编码凭据结果的来源名称
+const codeSource = "源代码"
+
+// codeExtensions 应用变量名赋值规则的源代码扩展名
+var codeExtensions = []string{".go", ".py", ".js", ".jsx", ".ts", ".tsx", ".java", ".kt", ".php", ".rb", ".cs"}
+
+// codeAssignment 赋值部分: 可选的引号（字典/对象键）和类型标注，赋值运算符及字符串字面量
+// 字面量的三个捕获组分别对应双引号、单引号和反引号
+const codeAssignment = `[\w$]*["']?(?:\s*:\s*[\w.\[\]<>]+\s*=|\s*(?::=|=|:))\s*` +
+	`(?:"([^"\s]{4,200})"|'([^'\s]{4,200})'|` + "`" + `([^` + "`" + `\s]{4,200})` + "`" + `)`
+
+// codePlaceholders 字面量中出现时视为模板或占位符的内容
+var codePlaceholders = []string{"${", "{{", "%s", "%(", "<", ">", "***", "xxxx", "changeme", "your_", "your-"}
+
+// IsCodeFile 判断是否为应用硬编码凭据规则的源代码文件
+func IsCodeFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, codeExt := range codeExtensions {
+		if ext == codeExt {
+			return true
+		}
+	}
+	return false
+}
+
+// initCodeRules 初始化源代码硬编码凭据规则，按变量名判断，不要求值符合特定格式
+// 密钥规则在前，变量名同时符合两条规则（如 secret_key）时按密钥报告
+func initCodeRules() []DetectionRule {
+	return []DetectionRule{
+		{
+			Name:        "硬编码密钥",
+			Pattern:     regexp.MustCompile(`(?i)\b[\w$]*(?:token|api_?key|access_?key|secret_?key|client_?secret|auth_?key|private_?key)` + codeAssignment),
+			Description: "赋值给令牌、API密钥类变量的字符串字面量",
+			RiskLevel:   "high",
+			Tags:        []string{"token"},
+		},
+		{
+			Name:        "硬编码密码",
+			Pattern:     regexp.MustCompile(`(?i)\b[\w$]*(?:password|passwd|pwd|secret|credential)` + codeAssignment),
+			Description: "赋值给密码类变量的字符串字面量",
+			RiskLevel:   "high",
+			Tags:        []string{"password"},
+		},
+	}
+}
+
+// matchCodeRules 对一行源代码应用硬编码凭据规则，每行只报告第一条命中的规则
+func matchCodeRules(rules []DetectionRule, lineNum int, line string) []string {
+	deadline := startDeadline(rules)
+	for _, rule := range rules {
+		if deadline.exceeded(&rule, line) {
+			break
+		}
+		var results []string
+		for _, match := range rule.findAll(line) {
+			value := match[1] + match[2] + match[3]
+			if isPlaceholderValue(value) {
+				continue
+			}
+			results = append(results, formatLineResult(codeSource, lineNum, rule.Name, rule.RiskLevel, value, line))
+		}
+		if len(results) > 0 {
+			return results
+		}
+	}
+	return nil
+}
+
+// isPlaceholderValue 判断字面量是否为模板、占位符或字段名本身（如 "password"）
+func isPlaceholderValue(value string) bool {
+	lower := strings.ToLower(value)
+	for _, placeholder := range codePlaceholders {
+		if strings.Contains(lower, placeholder) {
+			return true
+		}
+	}
+	switch strings.Trim(lower, "_-:") {
+	case "password", "passwd", "pwd", "secret", "token", "apikey", "api_key", "api-key", "credential", "credentials":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,198 @@
+package parser
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxCommentScanSize 检测注释的文件大小上限，超出时不标注
+const maxCommentScanSize = 8 * 1024 * 1024
+
+// commentSyntax 一种语言的注释语法
+type commentSyntax struct {
+	line   []string  // 行注释前缀（如 //、#）
+	block  [2]string // 块注释起止（如 /* */、<!-- -->），为空表示不支持
+	quotes string    // 字符串字面量的引号，引号内的注释符号不生效
+	hash   bool      // # 仅在行首或空白之后开始注释（Shell、YAML 等）
+}
+
+var (
+	cStyleComments = commentSyntax{line: []string{"//"}, block: [2]string{"/*", "*/"}, quotes: "\"'`"}
+	hashComments   = commentSyntax{line: []string{"#"}, quotes: "\"'", hash: true}
+	phpComments    = commentSyntax{line: []string{"//", "#"}, block: [2]string{"/*", "*/"}, quotes: "\"'"}
+	markupComments = commentSyntax{block: [2]string{"<!--", "-->"}}
+)
+
+// commentSyntaxes 各注释语法适用的扩展名
+var commentSyntaxes = []struct {
+	syntax commentSyntax
+	exts   []string
+}{
+	{cStyleComments, []string{".go", ".js", ".jsx", ".ts", ".tsx", ".java", ".kt", ".cs", ".c", ".cpp", ".h", ".swift", ".scala", ".rs", ".groovy"}},
+	{phpComments, []string{".php"}},
+	{hashComments, []string{".py", ".rb", ".sh", ".bash", ".zsh", ".ps1", ".yaml", ".yml", ".toml", ".properties", ".conf", ".env", ".tf"}},
+	{markupComments, []string{".xml", ".html", ".htm", ".config"}},
+}
+
+// commentSyntaxFor 获取文件类型的注释语法
+func commentSyntaxFor(filePath string) (commentSyntax, bool) {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, s := range commentSyntaxes {
+		for _, e := range s.exts {
+			if ext == e {
+				return s.syntax, true
+			}
+		}
+	}
+	return commentSyntax{}, false
+}
+
+// commentSpan 一行中注释所占的字节范围 [start, end)
+type commentSpan struct {
+	start, end int
+}
+
+// CommentMap 文件中各行的注释范围
+type CommentMap struct {
+	lines map[int]string
+	spans map[int][]commentSpan
+}
+
+// LoadCommentMap 读取文件并识别其中的行注释和块注释，不支持的文件类型或读取失败时返回 nil
+func LoadCommentMap(filePath string) *CommentMap {
+	syntax, ok := commentSyntaxFor(filePath)
+	if !ok {
+		return nil
+	}
+	info, err := os.Stat(filePath)
+	if err != nil || info.Size() > maxCommentScanSize {
+		return nil
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	cm := &CommentMap{lines: make(map[int]string), spans: make(map[int][]commentSpan)}
+	lexer := commentLexer{syntax: syntax}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxCommentScanSize)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		if spans := lexer.scanLine(line); len(spans) > 0 {
+			cm.lines[lineNum] = line
+			cm.spans[lineNum] = spans
+		}
+	}
+	return cm
+}
+
+// InComment 判断第 lineNum 行中的匹配是否位于注释中
+// 依次以匹配值、关键字在行中的位置判断，都找不到时以该行第一个非空白字符判断
+func (cm *CommentMap) InComment(lineNum int, value, keyword string) bool {
+	if cm == nil || lineNum <= 0 {
+		return false
+	}
+	spans := cm.spans[lineNum]
+	if len(spans) == 0 {
+		return false
+	}
+	line := cm.lines[lineNum]
+
+	pos := -1
+	if value != "" {
+		pos = strings.Index(line, value)
+	}
+	if pos < 0 && keyword != "" {
+		pos = strings.Index(strings.ToLower(line), strings.ToLower(keyword))
+	}
+	if pos < 0 {
+		pos = len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+	for _, span := range spans {
+		if pos >= span.start && pos < span.end {
+			return true
+		}
+	}
+	return false
+}
+
+// commentLexer 逐行识别注释，跨行的块注释和反引号字符串在行之间保持状态
+type commentLexer struct {
+	syntax  commentSyntax
+	inBlock bool // 处于块注释中
+	quote   byte // 处于跨行字符串中（仅反引号）
+}
+
+// scanLine 识别一行中的注释范围
+func (l *commentLexer) scanLine(line string) []commentSpan {
+	var spans []commentSpan
+	start := 0
+
+	for i := 0; i < len(line); {
+		if l.inBlock {
+			end := strings.Index(line[i:], l.syntax.block[1])
+			if end < 0 {
+				return append(spans, commentSpan{start, len(line)})
+			}
+			i += end + len(l.syntax.block[1])
+			spans = append(spans, commentSpan{start, i})
+			l.inBlock = false
+			continue
+		}
+
+		if l.quote != 0 {
+			if line[i] == '\\' && l.quote != '`' {
+				i += 2
+				continue
+			}
+			if line[i] == l.quote {
+				l.quote = 0
+			}
+			i++
+			continue
+		}
+
+		c := line[i]
+		if strings.IndexByte(l.syntax.quotes, c) >= 0 && l.opensQuote(line, i) {
+			l.quote = c
+			i++
+			continue
+		}
+		if l.syntax.block[0] != "" && strings.HasPrefix(line[i:], l.syntax.block[0]) {
+			l.inBlock = true
+			start = i
+			i += len(l.syntax.block[0])
+			continue
+		}
+		for _, prefix := range l.syntax.line {
+			if !strings.HasPrefix(line[i:], prefix) {
+				continue
+			}
+			if prefix == "#" && l.syntax.hash && i > 0 && line[i-1] != ' ' && line[i-1] != '\t' {
+				continue
+			}
+			// 行注释到行尾结束，行内未闭合的普通字符串也随行结束
+			return append(spans, commentSpan{i, len(line)})
+		}
+		i++
+	}
+
+	// 单引号、双引号字符串不跨行
+	if l.quote != '`' {
+		l.quote = 0
+	}
+	return spans
+}
+
+// opensQuote 判断位置 i 的引号是否开始字符串
+// Shell、YAML 等语言中未加引号的值可能包含撇号（如 don't），引号只在行首或分隔符之后开始字符串
+func (l *commentLexer) opensQuote(line string, i int) bool {
+	if !l.syntax.hash || i == 0 {
+		return true
+	}
+	return strings.IndexByte(" \t=:([{,", line[i-1]) >= 0
+}
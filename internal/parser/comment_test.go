@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommentMap(t *testing.T) {
+	type check struct {
+		line    int
+		value   string
+		keyword string
+		want    bool
+	}
+	tests := []struct {
+		file    string
+		content string
+		checks  []check
+	}{
+		{
+			file: "main.go",
+			content: "package main\n" +
+				"// password = \"old-pass\"\n" +
+				"var password = \"Live-Pass\" // TODO rotate token\n" +
+				"/* db_password = \"B1ock-1\"\n" +
+				"   db_password = \"B1ock-2\" */ var url = \"http://host//x?pw=Str1ng\"\n" +
+				"var tpl = `\n" +
+				"// Raw-Str1ng\n" +
+				"`\n",
+			checks: []check{
+				{2, "old-pass", "", true},
+				{3, "Live-Pass", "", false},
+				{3, "", "token", true},
+				{4, "B1ock-1", "", true},
+				{5, "B1ock-2", "", true},
+				{5, "Str1ng", "", false}, // 字符串中的 // 不是注释
+				{7, "Raw-Str1ng", "", false},
+				{1, "main", "", false},
+			},
+		},
+		{
+			file: "app.yaml",
+			content: "db:\n" +
+				"  # password: Comm3nted\n" +
+				"  password: pass#word # inline token\n" +
+				"  url: 'http://host/#frag'\n",
+			checks: []check{
+				{2, "Comm3nted", "", true},
+				{3, "pass#word", "", false}, // 值中的 # 前没有空白，不是注释
+				{3, "", "token", true},
+				{4, "http://host/#frag", "", false},
+				// 找不到匹配值和关键字时按行首判断
+				{2, "missing", "", true},
+				{3, "missing", "", false},
+			},
+		},
+		{
+			file:    "web.config",
+			content: "<configuration>\n  <!-- <add key=\"ApiKey\" value=\"Old-Key\"/>\n  -->\n  <add key=\"ApiKey\" value=\"New-Key\"/>\n</configuration>\n",
+			checks: []check{
+				{2, "Old-Key", "", true},
+				{4, "New-Key", "", false},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			cm := LoadCommentMap(path)
+			if cm == nil {
+				t.Fatalf("LoadCommentMap(%q) = nil", tt.file)
+			}
+			for _, c := range tt.checks {
+				if got := cm.InComment(c.line, c.value, c.keyword); got != c.want {
+					t.Errorf("InComment(%d, %q, %q) = %v, want %v", c.line, c.value, c.keyword, got, c.want)
+				}
+			}
+		})
+	}
+
+	// 不支持的文件类型不识别注释
+	path := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(path, []byte("# password=x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if cm := LoadCommentMap(path); cm != nil || cm.InComment(1, "x", "") {
+		t.Errorf("LoadCommentMap(notes.txt) = %v, want nil", cm)
+	}
+}
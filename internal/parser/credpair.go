@@ -0,0 +1,183 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// 账号口令组合结果的规则名称与风险等级
+const (
+	credentialPairRule = "账号口令组合"
+	credentialPairRisk = "critical"
+)
+
+// 同一行中的账号、口令赋值（key=value、key: value），值可以带引号
+var (
+	pairUserPattern     = regexp.MustCompile(`(?i)(?:^|[^\w])(?:[\w.-]*(?:user(?:_?name)?|login|account)|用户名|账号|帐号)\s*[:=：]\s*["']?([^\s"'&;,|]+)`)
+	pairPasswordPattern = regexp.MustCompile(`(?i)(?:^|[^\w])(?:[\w.-]*(?:password|passwd|pwd)|(?:[\w.-]*[_.-])?pass|密码|口令)\s*[:=：]\s*["']?([^\s"'&;,|]+)`)
+)
+
+// 表格中的账号、口令字段标签
+var (
+	userLabels     = []string{"用户名", "账号", "帐号", "用户", "username", "user", "login", "account"}
+	passwordLabels = []string{"密码", "口令", "password", "passwd", "pwd", "pass"}
+)
+
+// matchLabel 判断单元格内容是否为列表中的字段标签（如 "用户名" 或 "Password:"）
+func matchLabel(text string, labels []string) bool {
+	label := strings.ToLower(strings.TrimSpace(text))
+	label = strings.TrimRight(label, ":：= ")
+	if label == "" || len(label) > maxLabelLength {
+		return false
+	}
+	for _, l := range labels {
+		if strings.Contains(label, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUserLabel 判断是否为账号字段标签，同时包含口令字样的（如 "user_password"）按口令处理
+func isUserLabel(text string) bool {
+	return matchLabel(text, userLabels) && !matchLabel(text, passwordLabels)
+}
+
+// isPasswordLabel 判断是否为口令字段标签
+func isPasswordLabel(text string) bool {
+	return matchLabel(text, passwordLabels)
+}
+
+// findUserValue 查找一行文本中的账号赋值
+func findUserValue(line string) string {
+	if match := pairUserPattern.FindStringSubmatch(line); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// findPasswordValue 查找一行文本中第一个不是占位符的口令赋值
+func findPasswordValue(line string) string {
+	for _, match := range pairPasswordPattern.FindAllStringSubmatch(line, -1) {
+		if !isPlaceholderValue(match[1]) {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// findCredentialPair 在一行文本中查找同时出现的账号和口令赋值
+func findCredentialPair(line string) (user, password string, ok bool) {
+	if user = findUserValue(line); user == "" {
+		return "", "", false
+	}
+	if password = findPasswordValue(line); password == "" {
+		return "", "", false
+	}
+	return user, password, true
+}
+
+// checkLinePair 检查文本行中的账号口令组合，返回 LINE 结果
+func checkLinePair(source string, lineNum int, line string) (string, bool) {
+	user, password, ok := findCredentialPair(line)
+	if !ok {
+		return "", false
+	}
+	return formatCredentialPairResult(source, lineNum, user, password, line), true
+}
+
+// linePairs 跟踪上一行中单独出现的账号或口令赋值，与下一行中的另一半组成账号口令组合
+// （如 db.username=app 之后紧跟 db.password=secret）
+type linePairs struct {
+	user     string
+	password string
+	line     string
+}
+
+// check 检查文本行中的账号口令组合：同一行同时出现时直接报告，否则与上一行单独出现的另一半组合
+// 跨行的组合按上一行的行号报告，内容为两行以 ⏎ 连接
+func (lp *linePairs) check(source string, lineNum int, line string) (string, bool) {
+	prev := *lp
+	*lp = linePairs{}
+	if result, ok := checkLinePair(source, lineNum, line); ok {
+		return result, true
+	}
+
+	// 本行的口令字段为占位符时，不与上一行组合
+	user, password := findUserValue(line), findPasswordValue(line)
+	if password == "" && pairPasswordPattern.MatchString(line) {
+		return "", false
+	}
+	content := prev.line + " ⏎ " + line
+	switch {
+	case user != "" && prev.password != "":
+		return formatCredentialPairResult(source, lineNum-1, user, prev.password, content), true
+	case password != "" && prev.user != "":
+		return formatCredentialPairResult(source, lineNum-1, prev.user, password, content), true
+	case user != "" || password != "":
+		*lp = linePairs{user: user, password: password, line: line}
+	}
+	return "", false
+}
+
+// credentialColumns 跟踪表格表头中的账号列和口令列，用于检查之后的数据行
+type credentialColumns struct {
+	user     int
+	password int
+	found    bool
+}
+
+// check 检查表格中的一行，依次尝试: 标签与值相邻的单元格、单元格内的赋值、表头确定的账号列和口令列
+func (c *credentialColumns) check(source string, rowNum int, row []string) (string, bool) {
+	content := strings.Join(row, " | ")
+
+	var user, password string
+	userCol, passwordCol := -1, -1
+	for i, cell := range row {
+		switch {
+		case isPasswordLabel(cell):
+			passwordCol = i
+			if i+1 < len(row) && password == "" && !isLabelCell(row[i+1]) {
+				password = strings.TrimSpace(row[i+1])
+			}
+		case isUserLabel(cell):
+			userCol = i
+			if i+1 < len(row) && user == "" && !isLabelCell(row[i+1]) {
+				user = strings.TrimSpace(row[i+1])
+			}
+		}
+	}
+	if user != "" && password != "" && !isPlaceholderValue(password) {
+		return formatCredentialPairResult(source, rowNum, user, password, content), true
+	}
+
+	if u, p, ok := findCredentialPair(content); ok {
+		return formatCredentialPairResult(source, rowNum, u, p, content), true
+	}
+
+	// 同一行中同时出现两种标签而没有对应的值，视为表头
+	if userCol >= 0 && passwordCol >= 0 {
+		c.user, c.password, c.found = userCol, passwordCol, true
+		return "", false
+	}
+
+	if !c.found || c.user >= len(row) || c.password >= len(row) {
+		return "", false
+	}
+	user = strings.TrimSpace(row[c.user])
+	password = strings.TrimSpace(row[c.password])
+	if user == "" || password == "" || isLabelCell(password) || isPlaceholderValue(password) {
+		return "", false
+	}
+	return formatCredentialPairResult(source, rowNum, user, password, content), true
+}
+
+// isLabelCell 判断单元格是否为账号或口令字段标签
+func isLabelCell(text string) bool {
+	return isUserLabel(text) || isPasswordLabel(text)
+}
+
+// formatCredentialPairResult 格式化账号口令组合结果，匹配值为 "账号:口令"
+func formatCredentialPairResult(source string, lineNum int, user, password, content string) string {
+	return formatLineResult(source, lineNum, credentialPairRule, credentialPairRisk, user+":"+password, content)
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCredentialPairs(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			file: "notes.txt",
+			content: "user=admin password=S4me-Line\n" +
+				"db.username=app\n" +
+				"db.password=Next-L1ne\n" +
+				"\n" +
+				"密码：Rev3rsed\n" +
+				"账号：ops\n" +
+				"login: guest\n" +
+				"\n" +
+				"password: Too-Far\n" +
+				"\n" +
+				"api.password=Pr3v-Line\n" +
+				"api.user=svc password=${SVC_PASSWORD}\n",
+			want: []string{
+				"LINE|文本文件|1|账号口令组合|critical|admin:S4me-Line|user=admin password=S4me-Line",
+				// 相邻两行的组合按第一行报告，顺序不限；空行隔开的和本行口令为占位符的不组合
+				"LINE|文本文件|2|账号口令组合|critical|app:Next-L1ne|db.username=app ⏎ db.password=Next-L1ne",
+				"LINE|文本文件|5|账号口令组合|critical|ops:Rev3rsed|密码：Rev3rsed ⏎ 账号：ops",
+			},
+		},
+		{
+			file: "accounts.csv",
+			content: "备注,系统,用户名,密码\n" +
+				",OA,zhangsan,Oa-2024!x\n" +
+				"未设置,VPN,lisi,\n" +
+				",Mail,wangwu,changeme\n" +
+				"账号,root,密码,Csv-R00t\n",
+			want: []string{
+				// 表头确定账号列和口令列，空口令和占位符不报告
+				"LINE|CSV|2|账号口令组合|critical|zhangsan:Oa-2024!x| | OA | zhangsan | Oa-2024!x",
+				"PAIR|CSV|5|密码|Csv-R00t",
+				"LINE|CSV|5|账号口令组合|critical|root:Csv-R00t|账号 | root | 密码 | Csv-R00t",
+			},
+		},
+	}
+
+	parser := NewFileParser(ParserConfig{})
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, nil, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
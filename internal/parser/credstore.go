@@ -0,0 +1,404 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"Findx/internal/logger"
+)
+
+// 凭据存储格式
+const (
+	credStoreKeychain       = "macOS钥匙串"
+	credStoreLoginData      = "浏览器Login Data"
+	credStoreCookies        = "浏览器Cookies"
+	credStoreFirefoxCookies = "Firefox cookies.sqlite"
+	credStoreFirefoxLogins  = "Firefox logins.json"
+)
+
+// firefoxLoginsFile Firefox 保存登录信息的文件名
+const firefoxLoginsFile = "logins.json"
+
+// encryptedPassword 已加密口令的占位值
+const encryptedPassword = "[已加密]"
+
+// keychainMagic macOS 钥匙串（.keychain / .keychain-db）文件头
+const keychainMagic = "kych"
+
+// 钥匙串中的记录表类型
+const (
+	keychainGenericPassword  = 0x80000000
+	keychainInternetPassword = 0x80000001
+)
+
+// dpapiHeader Windows DPAPI 加密数据的固定前缀
+var dpapiHeader = []byte{0x01, 0x00, 0x00, 0x00, 0xD0, 0x8C, 0x9D, 0xDF}
+
+// CredentialStoreParser 导出的凭据存储解析器（macOS 钥匙串、Chromium 系浏览器 Login Data 和 Cookies、Firefox 配置文件）
+type CredentialStoreParser struct{}
+
+// NewCredentialStoreParser 创建凭据存储解析器
+func NewCredentialStoreParser() *CredentialStoreParser {
+	return &CredentialStoreParser{}
+}
+
+// credential 凭据存储中的一条记录，Cookie 记录的服务为站点、账号为 Cookie 名称、口令为 Cookie 值
+type credential struct {
+	Service  string
+	Account  string
+	Password string // 已加密时为 encryptedPassword
+	Cookie   bool
+}
+
+// IsCredentialStore 按文件头判断是否为支持的凭据存储
+func IsCredentialStore(filePath string) bool {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	head := make([]byte, len(sqliteMagic))
+	if _, err := io.ReadFull(file, head); err != nil {
+		return bytes.HasPrefix(head, []byte(keychainMagic))
+	}
+	return bytes.HasPrefix(head, []byte(keychainMagic)) || string(head) == sqliteMagic
+}
+
+// IsFirefoxLogins 按文件名判断是否为 Firefox 的 logins.json
+func IsFirefoxLogins(filePath string) bool {
+	return filepath.Base(filePath) == firefoxLoginsFile
+}
+
+// Parse 解析凭据存储，不是支持的格式时返回 nil 由调用方按普通文件处理
+func (p *CredentialStoreParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	var store string
+	var creds []credential
+	var parseErr error
+	_, err := scanMappedFile(filePath, func(data []byte) []string {
+		switch {
+		case bytes.HasPrefix(data, []byte(keychainMagic)):
+			store = credStoreKeychain
+			creds, parseErr = parseKeychain(data)
+		case bytes.HasPrefix(data, []byte(sqliteMagic)):
+			store, creds, parseErr = parseBrowserDB(data)
+		case IsFirefoxLogins(filePath):
+			store = credStoreFirefoxLogins
+			creds, parseErr = parseFirefoxLogins(data)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Warnf("读取凭据存储失败: %s: %v", filePath, err)
+		return nil
+	}
+	if parseErr != nil {
+		logger.Debugf("解析凭据存储失败: %s: %v", filePath, parseErr)
+		return nil
+	}
+	if store == "" {
+		return nil
+	}
+
+	var matchingLines []string
+	for _, cred := range creds {
+		// 明文保存的登录口令为严重，Cookie 和加密的口令为高危
+		risk := "critical"
+		if cred.Cookie || cred.Password == encryptedPassword {
+			risk = "high"
+		}
+		lineOutput := formatCredentialResult(store, risk, cred)
+		matchingLines = append(matchingLines, lineOutput)
+		if verbose {
+			fmt.Println(lineOutput)
+		}
+	}
+	return matchingLines
+}
+
+// formatCredentialResult 生成凭据存储结果: CRED|存储格式|风险|服务|账号|口令
+// Cookie 记录为 COOKIE|存储格式|风险|站点|名称|值
+func formatCredentialResult(store, risk string, cred credential) string {
+	clean := strings.NewReplacer("|", "/", "\n", " ", "\r", " ").Replace
+	kind := "CRED"
+	if cred.Cookie {
+		kind = "COOKIE"
+	}
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", kind, store, risk, clean(cred.Service), clean(cred.Account), clean(cred.Password))
+}
+
+// parseBrowserDB 按表结构识别浏览器的 SQLite 数据库：Login Data（logins）、Chromium Cookies（cookies）和 Firefox cookies.sqlite（moz_cookies）
+func parseBrowserDB(data []byte) (string, []credential, error) {
+	db, err := openSQLite(data)
+	if err != nil {
+		return "", nil, err
+	}
+	tables, err := db.tables()
+	if err != nil {
+		return "", nil, err
+	}
+
+	for _, table := range tables {
+		switch table.Name {
+		case "logins":
+			creds, err := parseLoginData(db, table)
+			return credStoreLoginData, creds, err
+		case "cookies":
+			creds, err := parseCookies(db, table, "host_key", "encrypted_value")
+			return credStoreCookies, creds, err
+		case "moz_cookies":
+			creds, err := parseCookies(db, table, "host", "")
+			return credStoreFirefoxCookies, creds, err
+		}
+	}
+
+	return "", nil, fmt.Errorf("未找到 logins、cookies 或 moz_cookies 表")
+}
+
+// sqliteColumns 获取表中各列的位置，缺少任意一列时返回 false
+func sqliteColumns(table sqliteTable, names ...string) ([]int, bool) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = -1
+		for j, column := range table.Columns {
+			if column == name {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, false
+		}
+	}
+	return indexes, true
+}
+
+// parseLoginData 读取 Chromium 系浏览器 Login Data 中的 logins 表
+// 口令通常经 DPAPI 或 v10/v11 方式加密，只在明文保存时报告原值
+func parseLoginData(db *sqliteDB, table sqliteTable) ([]credential, error) {
+	columns, ok := sqliteColumns(table, "origin_url", "username_value", "password_value")
+	if !ok {
+		return nil, fmt.Errorf("logins 表缺少必要的列")
+	}
+	origin, user, pass := columns[0], columns[1], columns[2]
+
+	var creds []credential
+	err := db.forEachRow(table.RootPage, func(values []interface{}) {
+		if len(values) <= origin || len(values) <= user || len(values) <= pass {
+			return
+		}
+		cred := credential{
+			Service:  sqliteText(values[origin]),
+			Account:  sqliteText(values[user]),
+			Password: loginPassword(values[pass]),
+		}
+		if cred.Account == "" && cred.Password == "" {
+			return
+		}
+		creds = append(creds, cred)
+	})
+	return creds, err
+}
+
+// parseCookies 读取浏览器 Cookie 表中的站点、名称和值
+// Chromium 的 value 列为空时值保存在 encrypted_value 列中（v10/v11 或 DPAPI 加密），只报告 Cookie 存在并标记为已加密
+func parseCookies(db *sqliteDB, table sqliteTable, hostColumn, encryptedColumn string) ([]credential, error) {
+	names := []string{hostColumn, "name", "value"}
+	if encryptedColumn != "" {
+		names = append(names, encryptedColumn)
+	}
+	columns, ok := sqliteColumns(table, names...)
+	if !ok {
+		return nil, fmt.Errorf("%s 表缺少必要的列", table.Name)
+	}
+
+	var creds []credential
+	err := db.forEachRow(table.RootPage, func(values []interface{}) {
+		for _, column := range columns {
+			if len(values) <= column {
+				return
+			}
+		}
+		cred := credential{
+			Service:  sqliteText(values[columns[0]]),
+			Account:  sqliteText(values[columns[1]]),
+			Password: sqliteText(values[columns[2]]),
+			Cookie:   true,
+		}
+		if cred.Password == "" && encryptedColumn != "" {
+			cred.Password = loginPassword(values[columns[3]])
+		}
+		if cred.Account == "" || cred.Password == "" {
+			return
+		}
+		creds = append(creds, cred)
+	})
+	return creds, err
+}
+
+// parseFirefoxLogins 读取 Firefox logins.json 中保存的登录信息
+// 用户名和口令都经 NSS 加密（密钥在 key4.db 中），只报告站点和存在已保存的凭据
+func parseFirefoxLogins(data []byte) ([]credential, error) {
+	var logins struct {
+		Logins []struct {
+			Hostname          string `json:"hostname"`
+			HTTPRealm         string `json:"httpRealm"`
+			EncryptedUsername string `json:"encryptedUsername"`
+			EncryptedPassword string `json:"encryptedPassword"`
+		} `json:"logins"`
+	}
+	if err := json.Unmarshal(data, &logins); err != nil {
+		return nil, err
+	}
+	if logins.Logins == nil {
+		return nil, fmt.Errorf("未找到 logins 列表")
+	}
+
+	var creds []credential
+	for _, login := range logins.Logins {
+		if login.EncryptedPassword == "" {
+			continue
+		}
+		cred := credential{
+			Service:  login.Hostname,
+			Password: encryptedPassword,
+		}
+		if login.HTTPRealm != "" {
+			cred.Service += " (" + login.HTTPRealm + ")"
+		}
+		if login.EncryptedUsername != "" {
+			cred.Account = encryptedPassword
+		}
+		creds = append(creds, cred)
+	}
+	return creds, nil
+}
+
+// sqliteText 将字段值转换为字符串
+func sqliteText(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case []byte:
+		return string(value)
+	default:
+		return ""
+	}
+}
+
+// loginPassword 获取 Login Data 中的口令，加密或无法识别的数据返回占位值
+func loginPassword(v interface{}) string {
+	var raw []byte
+	switch value := v.(type) {
+	case []byte:
+		raw = value
+	case string:
+		raw = []byte(value)
+	default:
+		return ""
+	}
+	if len(raw) == 0 {
+		return ""
+	}
+
+	if bytes.HasPrefix(raw, []byte("v10")) || bytes.HasPrefix(raw, []byte("v11")) ||
+		bytes.HasPrefix(raw, []byte("v20")) || bytes.HasPrefix(raw, dpapiHeader) {
+		return encryptedPassword
+	}
+	if !utf8.Valid(raw) || textRatio(raw) < 1 {
+		return encryptedPassword
+	}
+	return string(raw)
+}
+
+// parseKeychain 读取 macOS 钥匙串中通用密码和互联网密码记录的服务与账号
+// 钥匙串中的口令始终加密保存，只报告存在已保存的凭据
+func parseKeychain(data []byte) ([]credential, error) {
+	be := binary.BigEndian
+	// 文件头: 魔数、版本、头长度、模式偏移、认证偏移（均为大端 uint32）
+	const headerSize = 20
+	if len(data) < headerSize+8 {
+		return nil, fmt.Errorf("钥匙串文件过短")
+	}
+
+	schema := headerSize
+	tableCount := int(be.Uint32(data[schema+4 : schema+8]))
+	if tableCount <= 0 || schema+8+tableCount*4 > len(data) {
+		return nil, fmt.Errorf("钥匙串表数量无效")
+	}
+
+	var creds []credential
+	for i := 0; i < tableCount; i++ {
+		off := schema + 8 + i*4
+		base := headerSize + int(be.Uint32(data[off:off+4]))
+		if base+28 > len(data) {
+			continue
+		}
+
+		// 表头: 大小、类型、记录数、记录偏移、索引偏移、空闲链表、记录号数量
+		tableID := be.Uint32(data[base+4 : base+8])
+		if tableID != keychainGenericPassword && tableID != keychainInternetPassword {
+			continue
+		}
+		recordCount := int(be.Uint32(data[base+8 : base+12]))
+
+		// 记录偏移数组中 0 表示已删除的记录，跳过直到读满记录数
+		found := 0
+		for slot := base + 28; found < recordCount && slot+4 <= len(data); slot += 4 {
+			recordOffset := int(be.Uint32(data[slot : slot+4]))
+			if recordOffset == 0 || recordOffset%4 != 0 {
+				continue
+			}
+			found++
+			if cred, ok := keychainRecord(data, base+recordOffset, tableID); ok {
+				creds = append(creds, cred)
+			}
+		}
+	}
+
+	return creds, nil
+}
+
+// keychainRecord 读取钥匙串记录中的服务和账号属性
+// 记录头为6个 uint32，其后是各属性的偏移（相对记录起点，最低位为标志位）
+func keychainRecord(data []byte, record int, tableID uint32) (credential, bool) {
+	// 通用密码: 账号为第13个属性、服务为第14个；互联网密码: 账号为第9个、服务器为第11个
+	accountIndex, serviceIndex := 13, 14
+	if tableID == keychainInternetPassword {
+		accountIndex, serviceIndex = 9, 11
+	}
+
+	attr := func(index int) string {
+		pos := record + (6+index)*4
+		if pos+4 > len(data) {
+			return ""
+		}
+		offset := int(binary.BigEndian.Uint32(data[pos:pos+4]) &^ 1)
+		if offset == 0 || record+offset+4 > len(data) {
+			return ""
+		}
+		start := record + offset
+		length := int(binary.BigEndian.Uint32(data[start : start+4]))
+		if length <= 0 || start+4+length > len(data) {
+			return ""
+		}
+		return strings.TrimRight(string(data[start+4:start+4+length]), "\x00")
+	}
+
+	cred := credential{
+		Service:  attr(serviceIndex),
+		Account:  attr(accountIndex),
+		Password: encryptedPassword,
+	}
+	if cred.Service == "" && cred.Account == "" {
+		return cred, false
+	}
+	return cred, true
+}
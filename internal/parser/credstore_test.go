@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testSQLitePageSize 用例数据库的页大小
+const testSQLitePageSize = 1024
+
+// putSQLiteVarint 追加 SQLite 变长整数（用例中的值都小于 2^56）
+func putSQLiteVarint(b []byte, v uint64) []byte {
+	var groups []byte
+	for {
+		groups = append([]byte{byte(v & 0x7F)}, groups...)
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	for i := 0; i < len(groups)-1; i++ {
+		groups[i] |= 0x80
+	}
+	return append(b, groups...)
+}
+
+// sqliteRecord 编码记录，支持 nil、int64、string 和 []byte
+func sqliteRecord(values ...interface{}) []byte {
+	var types, body []byte
+	for _, v := range values {
+		switch value := v.(type) {
+		case nil:
+			types = putSQLiteVarint(types, 0)
+		case int64:
+			types = putSQLiteVarint(types, 6)
+			body = binary.BigEndian.AppendUint64(body, uint64(value))
+		case string:
+			types = putSQLiteVarint(types, uint64(13+2*len(value)))
+			body = append(body, value...)
+		case []byte:
+			types = putSQLiteVarint(types, uint64(12+2*len(value)))
+			body = append(body, value...)
+		}
+	}
+	// 记录头长度包含自身，用例中的记录头不超过127字节
+	record := putSQLiteVarint(nil, uint64(len(types)+1))
+	return append(append(record, types...), body...)
+}
+
+// sqliteLeafPage 构造表叶子页，每条记录的 rowid 从1开始；第1页的页头位于文件头之后
+func sqliteLeafPage(pageNum int, records ...[]byte) []byte {
+	page := make([]byte, testSQLitePageSize)
+	hdr := 0
+	if pageNum == 1 {
+		hdr = 100
+	}
+	page[hdr] = 0x0D
+	binary.BigEndian.PutUint16(page[hdr+3:], uint16(len(records)))
+
+	end := len(page)
+	for i, record := range records {
+		cell := putSQLiteVarint(nil, uint64(len(record)))
+		cell = putSQLiteVarint(cell, uint64(i+1))
+		cell = append(cell, record...)
+		end -= len(cell)
+		copy(page[end:], cell)
+		binary.BigEndian.PutUint16(page[hdr+8+i*2:], uint16(end))
+	}
+	binary.BigEndian.PutUint16(page[hdr+5:], uint16(end))
+	return page
+}
+
+// buildSQLite 构造只包含一张表的数据库：第1页为 sqlite_master，第2页起为表的页
+func buildSQLite(sql, table string, pages ...[]byte) []byte {
+	master := sqliteLeafPage(1, sqliteRecord("table", table, table, int64(2), sql))
+	copy(master, sqliteMagic)
+	binary.BigEndian.PutUint16(master[16:], testSQLitePageSize)
+	master[18], master[19] = 1, 1 // 读写版本
+	master[21], master[22], master[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(master[28:], uint32(1+len(pages))) // 页数
+	binary.BigEndian.PutUint32(master[40:], 1)                    // schema cookie
+	binary.BigEndian.PutUint32(master[44:], 4)                    // schema 格式
+	binary.BigEndian.PutUint32(master[56:], 1)                    // UTF-8
+
+	data := master
+	for _, page := range pages {
+		data = append(data, page...)
+	}
+	return data
+}
+
+const (
+	testLoginsSQL  = "CREATE TABLE logins (origin_url VARCHAR NOT NULL, action_url VARCHAR, username_value VARCHAR, password_value BLOB)"
+	testCookiesSQL = "CREATE TABLE cookies(creation_utc INTEGER NOT NULL, host_key TEXT NOT NULL, name TEXT NOT NULL, value TEXT NOT NULL, encrypted_value BLOB NOT NULL DEFAULT '')"
+)
+
+// testLoginData 包含一条明文口令和一条 v10 加密口令的 Login Data
+func testLoginData() []byte {
+	return buildSQLite(testLoginsSQL, "logins", sqliteLeafPage(2,
+		sqliteRecord("https://intranet.corp/login", "", "admin", []byte("Hunter2024")),
+		sqliteRecord("https://mail.corp/", "", "ops", append([]byte("v10"), 0x9A, 0x01, 0xF3, 0x7C)),
+	))
+}
+
+func TestCredentialStoreParser(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		data []byte
+		want []string // nil 表示按普通文件处理
+	}{
+		{
+			name: "login data",
+			file: "Login Data",
+			data: testLoginData(),
+			want: []string{
+				"CRED|浏览器Login Data|critical|https://intranet.corp/login|admin|Hunter2024",
+				"CRED|浏览器Login Data|high|https://mail.corp/|ops|[已加密]",
+			},
+		},
+		{
+			name: "chromium cookies",
+			file: "Cookies",
+			data: buildSQLite(testCookiesSQL, "cookies", sqliteLeafPage(2,
+				sqliteRecord(int64(13350000000000000), ".corp.local", "session", "s3ss10n-t0ken", []byte{}),
+				sqliteRecord(int64(13350000000000000), ".corp.local", "sid", "", append([]byte("v11"), 0x01, 0x02)),
+				sqliteRecord(int64(13350000000000000), ".corp.local", "empty", "", []byte{}),
+			)),
+			want: []string{
+				"COOKIE|浏览器Cookies|high|.corp.local|session|s3ss10n-t0ken",
+				"COOKIE|浏览器Cookies|high|.corp.local|sid|[已加密]",
+			},
+		},
+		{
+			name: "unrelated sqlite database",
+			file: "app.db",
+			data: buildSQLite("CREATE TABLE notes (id INTEGER, body TEXT)", "notes", sqliteLeafPage(2,
+				sqliteRecord(int64(1), "password=Hunter2024"),
+			)),
+			want: nil,
+		},
+		{
+			name: "table page truncated",
+			file: "truncated",
+			data: testLoginData()[:testSQLitePageSize+100],
+			want: nil,
+		},
+		{
+			name: "table page type invalid",
+			file: "badtype",
+			data: func() []byte {
+				data := testLoginData()
+				data[testSQLitePageSize] = 0x07
+				return data
+			}(),
+			want: nil,
+		},
+		{
+			name: "cell pointer out of page",
+			file: "badcell",
+			data: func() []byte {
+				data := testLoginData()
+				binary.BigEndian.PutUint16(data[testSQLitePageSize+3:], 0xFFFF) // 单元格数
+				return data
+			}(),
+			want: nil,
+		},
+		{
+			// 内部页的最右子页指向自身
+			name: "interior page cycle",
+			file: "cycle",
+			data: func() []byte {
+				page := make([]byte, testSQLitePageSize)
+				page[0] = 0x05
+				binary.BigEndian.PutUint16(page[5:], testSQLitePageSize)
+				binary.BigEndian.PutUint32(page[8:], 2)
+				return buildSQLite(testLoginsSQL, "logins", page)
+			}(),
+			want: nil,
+		},
+		{
+			// 第一条记录的负载长度超出数据库，跳过该记录
+			name: "record length out of range",
+			file: "badrecord",
+			data: func() []byte {
+				data := testLoginData()
+				page := data[testSQLitePageSize:]
+				cell := binary.BigEndian.Uint16(page[8:])
+				page[cell], page[cell+1], page[cell+2] = 0xFF, 0xFF, 0x7F
+				return data
+			}(),
+			want: []string{
+				"CRED|浏览器Login Data|high|https://mail.corp/|ops|[已加密]",
+			},
+		},
+	}
+
+	parser := NewCredentialStoreParser()
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if !IsCredentialStore(path) {
+				t.Fatalf("IsCredentialStore(%q) = false", tt.file)
+			}
+			if got := parser.Parse(path, nil, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
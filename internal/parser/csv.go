@@ -4,11 +4,14 @@ import (
 	"encoding/csv"
 	"fmt"
 	"os"
-	"strings"
+
+	"Findx/internal/logger"
 )
 
 // CSVParser CSV文件解析器
-type CSVParser struct{}
+type CSVParser struct {
+	matcher *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+}
 
 // NewCSVParser 创建CSV解析器
 func NewCSVParser() *CSVParser {
@@ -20,7 +23,7 @@ func (p *CSVParser) Parse(filePath string, keywords []string, verbose bool) []st
 	var matchingLines []string
 	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Printf("[-] 打开CSV文件%s错误\n", filePath)
+		logger.Warnf("打开CSV文件%s错误", filePath)
 		return matchingLines
 	}
 	defer file.Close()
@@ -28,23 +31,37 @@ func (p *CSVParser) Parse(filePath string, keywords []string, verbose bool) []st
 	reader := csv.NewReader(file)
 	records, err := reader.ReadAll()
 	if err != nil {
-		fmt.Printf("[-] 读取CSV文件%s错误\n", filePath)
+		logger.Warnf("读取CSV文件%s错误", filePath)
 		return matchingLines
 	}
 
-	for _, record := range records {
+	var columns credentialColumns
+	for rowIndex, record := range records {
 		for _, text := range record {
-			for _, keyword := range keywords {
-				if strings.Contains(text, keyword) {
-					lineOutput := formatCSVResult(keyword, text)
-					matchingLines = append(matchingLines, lineOutput)
-					if verbose {
-						fmt.Println(lineOutput)
-					}
-					break
+			if keyword, ok := p.matcher.find(text, keywords); ok {
+				lineOutput := formatCSVResult(keyword, text)
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
 				}
 			}
 		}
+
+		// 检查标签与值分列存放的凭据
+		for _, lineOutput := range checkAdjacentCells("CSV", rowIndex+1, record) {
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+
+		// 检查同一行中的账号和口令
+		if lineOutput, ok := columns.check("CSV", rowIndex+1, record); ok {
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
 	}
 	return matchingLines
 }
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// StringDumper 将二进制文件中提取的全部字符串及其偏移写入转储文件（--dump-strings），
+// 用于排查某条规则为何没有命中: 每个字符串标注是否被保留，未保留时给出原因
+type StringDumper struct {
+	mu      sync.Mutex
+	file    *os.File
+	writer  *bufio.Writer
+	display func(string) string
+	err     error
+}
+
+// NewStringDumper 创建字符串转储文件，display 用于将文件路径转换为报告中显示的路径（可为 nil）
+func NewStringDumper(path string, display func(string) string) (*StringDumper, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("创建字符串转储文件失败: %w", err)
+	}
+	if display == nil {
+		display = func(p string) string { return p }
+	}
+	return &StringDumper{
+		file:    file,
+		writer:  bufio.NewWriter(file),
+		display: display,
+	}, nil
+}
+
+// Dump 写入一个文件的字符串转储，note 非空时附在文件头之后（如文件未被二进制扫描的原因）
+// 每行格式: 偏移 编码 判定 字符串，判定为 "保留" 或未保留的原因
+func (d *StringDumper) Dump(filePath string, data []byte, note string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return
+	}
+
+	w := d.writer
+	fmt.Fprintf(w, "==== %s (%d 字节)\n", d.display(filePath), len(data))
+	if note != "" {
+		fmt.Fprintf(w, "# %s\n", note)
+	}
+
+	kept, total := 0, 0
+	window := newStringWindow(stringDedupWindow)
+	forEachString(data, func(offset int, encoding, str string) bool {
+		verdict := stringRejectReason(str)
+		switch {
+		case window.seen(str):
+			verdict = "重复"
+		case verdict == "":
+			verdict = "保留"
+			window.add(str)
+			kept++
+		}
+		total++
+		fmt.Fprintf(w, "0x%08X  %-5s  %-4s  %q\n", offset, encoding, verdict, str)
+		return true
+	})
+	fmt.Fprintf(w, "# 共 %d 个字符串，保留 %d 个\n\n", total, kept)
+
+	if err := w.Flush(); err != nil {
+		d.err = err
+	}
+}
+
+// Close 写入剩余内容并关闭转储文件
+func (d *StringDumper) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.writer.Flush(); err != nil && d.err == nil {
+		d.err = err
+	}
+	if err := d.file.Close(); err != nil && d.err == nil {
+		d.err = err
+	}
+	return d.err
+}
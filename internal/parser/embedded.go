@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// maxEmbeddedSize Office 文档中单个嵌入对象的最大读取大小
+const maxEmbeddedSize = 16 * 1024 * 1024
+
+// maxEmbedDepth 嵌入对象的最大嵌套层数（文档中嵌入的文档中再嵌入的对象）
+const maxEmbedDepth = 3
+
+// embeddedDocumentExts 嵌入对象中按文件类型解析的扩展名，其余（OLE 对象、图片等）按原始字节扫描
+var embeddedDocumentExts = []string{".docx", ".docm", ".xlsx", ".xlsm", ".xls", ".pptx", ".csv", ".txt", ".xml", ".json", ".yaml", ".yml"}
+
+// embedDepthKey 上下文中记录当前嵌套层数的键
+type embedDepthKey struct{}
+
+// isEmbeddedPart 判断 OOXML 压缩包中的条目是否为嵌入对象或媒体文件
+func isEmbeddedPart(name string) bool {
+	for _, dir := range []string{"word/", "xl/", "ppt/"} {
+		if strings.HasPrefix(name, dir+"embeddings/") || strings.HasPrefix(name, dir+"media/") {
+			return !strings.HasSuffix(name, "/")
+		}
+	}
+	return false
+}
+
+// parseEmbedded 解析 OOXML 文档（.docx/.xlsx/.pptx）中的嵌入对象和媒体文件
+// 嵌入的文档按类型递归解析，OLE 对象和图片按原始字节扫描，结果标注嵌入对象在压缩包中的路径
+func (fp *FileParser) parseEmbedded(ctx context.Context, filePath string, keywords []string, verbose bool) []string {
+	depth, _ := ctx.Value(embedDepthKey{}).(int)
+	if depth >= maxEmbedDepth {
+		return nil
+	}
+	ctx = context.WithValue(ctx, embedDepthKey{}, depth+1)
+
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		logger.Debugf("打开文档压缩包%s失败: %v", filePath, err)
+		return nil
+	}
+	defer reader.Close()
+
+	var entries []*zip.File
+	index := make(map[*zip.File]int)
+	hasDocument := false
+	for i, entry := range reader.File {
+		if !isEmbeddedPart(entry.Name) || entry.UncompressedSize64 > maxEmbeddedSize {
+			continue
+		}
+		entries = append(entries, entry)
+		index[entry] = i
+		hasDocument = hasDocument || isEmbeddedDocument(entry.Name)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var tempDir string
+	if hasDocument {
+		if tempDir, err = os.MkdirTemp("", "findx-embed-"); err != nil {
+			logger.Warnf("创建临时目录失败: %v", err)
+			return nil
+		}
+		defer os.RemoveAll(tempDir)
+	}
+
+	// 只有最外层文档的嵌入对象进入工作池，嵌套文档中的对象在所属条目的协程中依次扫描
+	var pool EntryPool
+	if depth == 0 {
+		pool = fp.entryPool
+	}
+	return scanEntries(pool, filePath, entries, func(entry *zip.File) []string {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		data, err := readZipEntry(entry)
+		if err != nil {
+			logger.Debugf("读取嵌入对象%s失败: %v", entry.Name, err)
+			return nil
+		}
+
+		var results []string
+		if isEmbeddedDocument(entry.Name) {
+			// 保留扩展名以选择解析器，文件名加序号避免不同目录下的同名对象冲突
+			tempFile := filepath.Join(tempDir, fmt.Sprintf("%d-%s", index[entry], path.Base(entry.Name)))
+			if err := os.WriteFile(tempFile, data, 0600); err != nil {
+				logger.Debugf("写入嵌入对象%s失败: %v", entry.Name, err)
+				return nil
+			}
+			results = fp.parse(ctx, tempFile, keywords, false)
+			os.Remove(tempFile)
+		} else {
+			results = fp.binaryParser.scanBytes(ctx, filePath+"!"+entry.Name, data, keywords, false, fp.contextLength)
+		}
+
+		lines := make([]string, 0, len(results))
+		for _, result := range results {
+			lineOutput := formatEmbeddedResult(entry.Name, result)
+			lines = append(lines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+		return lines
+	})
+}
+
+// isEmbeddedDocument 判断嵌入对象是否按文件类型解析
+func isEmbeddedDocument(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	for _, docExt := range embeddedDocumentExts {
+		if ext == docExt {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEmbeddedResult 为嵌入对象中的结果标注对象路径: EMBED|对象路径|原结果
+func formatEmbeddedResult(part, result string) string {
+	return fmt.Sprintf("EMBED|%s|%s", part, result)
+}
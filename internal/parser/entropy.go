@@ -0,0 +1,116 @@
+package parser
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// EntropyAssignmentRuleName 高熵赋值规则的名称
+const EntropyAssignmentRuleName = "高熵赋值"
+
+// DefaultEntropyThreshold 高熵赋值规则默认的信息熵阈值（每字符比特数）
+const DefaultEntropyThreshold = 3.5
+
+// minEntropyValueLength 高熵赋值规则检查的最短值长度
+const minEntropyValueLength = 12
+
+// newEntropyRules 根据敏感变量名关键词创建高熵赋值规则，变量名包含任一关键词（不区分大小写）时检查所赋的值
+// 值可带引号，不含空白、括号和分隔符；关键词为空时不创建规则
+func newEntropyRules(words []string) []DetectionRule {
+	var quoted []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			quoted = append(quoted, regexp.QuoteMeta(word))
+		}
+	}
+	if len(quoted) == 0 {
+		return nil
+	}
+
+	pattern := `(?i)(?:^|[^\w$.-])([\w$.-]*(?:` + strings.Join(quoted, "|") + `)[\w$.-]*)["']?\s*(?::=|=>|=|:)\s*["'` + "`" + `]?` +
+		`([^\s"'` + "`" + `,;(){}\[\]<>]{` + strconv.Itoa(minEntropyValueLength) + `,200})`
+	return []DetectionRule{{
+		Name:        EntropyAssignmentRuleName,
+		Pattern:     regexp.MustCompile(pattern),
+		Description: "赋值给敏感变量名的高熵值",
+		RiskLevel:   "high",
+		Tags:        []string{"token"},
+	}}
+}
+
+// entropyWords 将敏感变量名关键词转为小写，用于正则匹配前的快速筛选
+func entropyWords(words []string) []string {
+	var lower []string
+	for _, word := range words {
+		if word = strings.TrimSpace(word); word != "" {
+			lower = append(lower, strings.ToLower(word))
+		}
+	}
+	return lower
+}
+
+// mayHaveSecretAssignment 快速判断一行是否可能命中高熵赋值规则：包含赋值符号且包含任一敏感变量名关键词（不区分大小写）
+// 大部分行在这里就被排除，不再执行正则匹配
+func mayHaveSecretAssignment(line string, words []string) bool {
+	if !strings.ContainsAny(line, "=:") {
+		return false
+	}
+	lower := strings.ToLower(line)
+	for _, word := range words {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchEntropyRule 对一行文本应用高熵赋值规则，值的信息熵不低于 threshold 且同时包含字母和数字时报告
+// 匹配值为原始值，去重、规范化和弱口令检查都基于原始值，报告中再脱敏显示
+func matchEntropyRule(rule *DetectionRule, threshold float64, source string, lineNum int, line string) []string {
+	var results []string
+	for _, match := range rule.findAll(line) {
+		value := match[2]
+		if !hasLetterAndDigit(value) || isPlaceholderValue(value) || shannonEntropy(value) < threshold {
+			continue
+		}
+		results = append(results, formatLineResult(source, lineNum, rule.Name, rule.RiskLevel, value, line))
+	}
+	return results
+}
+
+// shannonEntropy 计算字符串的香农信息熵（每字符比特数）
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	if total == 0 {
+		return 0
+	}
+
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// hasLetterAndDigit 判断字符串是否同时包含字母和数字，排除普通单词和标识符
+func hasLetterAndDigit(s string) bool {
+	letter, digit := false, false
+	for _, r := range s {
+		switch {
+		case unicode.IsLetter(r):
+			letter = true
+		case unicode.IsDigit(r):
+			digit = true
+		}
+	}
+	return letter && digit
+}
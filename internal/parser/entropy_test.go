@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestMayHaveSecretAssignment 快速筛选只放行包含赋值符号和敏感变量名关键词的行
+func TestMayHaveSecretAssignment(t *testing.T) {
+	words := entropyWords([]string{"secret", " Token ", ""})
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"clientSecret = a8Fk2LmQ9zXw4RtB", true},
+		{"API_TOKEN: a8Fk2LmQ9zXw4RtB", true},
+		{"x := mySECRETvalue", true},
+		{"secret a8Fk2LmQ9zXw4RtB", false},
+		{"password=a8Fk2LmQ9zXw4RtB", false},
+		{"2024-01-02 12:00:01 request served in 12ms", false},
+	}
+	for _, tt := range tests {
+		if got := mayHaveSecretAssignment(tt.line, words); got != tt.want {
+			t.Errorf("mayHaveSecretAssignment(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+// TestMatchEntropyRuleRawValue 高熵赋值结果的匹配值为未脱敏的原始值
+func TestMatchEntropyRuleRawValue(t *testing.T) {
+	rules := newEntropyRules([]string{"token"})
+	got := matchEntropyRule(&rules[0], DefaultEntropyThreshold, textSource, 3, `api_token = "a8Fk2LmQ9zXw4RtB"`)
+	want := `LINE|文本文件|3|高熵赋值|high|a8Fk2LmQ9zXw4RtB|api_token = "a8Fk2LmQ9zXw4RtB"`
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("got %q, want [%q]", got, want)
+	}
+}
+
+// BenchmarkParseReaderEntropy 日志类文本的逐行扫描，大部分行不含敏感变量名，由快速筛选排除
+func BenchmarkParseReaderEntropy(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 10000; i++ {
+		if i%50 == 0 {
+			fmt.Fprintf(&sb, "auth_token=a8Fk2LmQ9zXw%04d\n", i)
+			continue
+		}
+		fmt.Fprintf(&sb, "2024-01-02 12:00:%02d INFO request id=%d path=/api/v1/items/%d status=200 took=%dms\n", i%60, i, i, i%97)
+	}
+	content := sb.String()
+	p := NewTextParser()
+	p.entropyRules = newEntropyRules([]string{"secret", "token", "key", "password", "auth"})
+	p.entropyWords = entropyWords([]string{"secret", "token", "key", "password", "auth"})
+	p.entropyThreshold = DefaultEntropyThreshold
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.parseReader("app.log", strings.NewReader(content), nil, false)
+	}
+}
@@ -0,0 +1,36 @@
+package parser
+
+import "archive/zip"
+
+// EntryPool 扫描器的工作池，压缩包中的条目通过它与普通文件一起并发扫描
+type EntryPool interface {
+	// Scan 在工作池中并发执行 n 个条目的扫描，archive 为压缩包路径，names[i] 和 sizes[i] 为第 i 个条目的名称和解压后的大小，返回时所有条目均已完成
+	Scan(archive string, names []string, sizes []int64, scan func(i int))
+}
+
+// scanEntries 扫描压缩包中的条目，结果按条目顺序合并
+// 设置了工作池时条目在工作池中并发解压和扫描，否则在当前协程中依次处理
+func scanEntries(pool EntryPool, archive string, entries []*zip.File, scan func(entry *zip.File) []string) []string {
+	results := make([][]string, len(entries))
+	if pool == nil || len(entries) < 2 {
+		for i, entry := range entries {
+			results[i] = scan(entry)
+		}
+	} else {
+		names := make([]string, len(entries))
+		sizes := make([]int64, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name
+			sizes[i] = int64(entry.UncompressedSize64)
+		}
+		pool.Scan(archive, names, sizes, func(i int) {
+			results[i] = scan(entries[i])
+		})
+	}
+
+	var matchingLines []string
+	for _, r := range results {
+		matchingLines = append(matchingLines, r...)
+	}
+	return matchingLines
+}
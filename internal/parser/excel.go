@@ -2,14 +2,17 @@ package parser
 
 import (
 	"fmt"
-	"strings"
+
+	"Findx/internal/logger"
 
 	"github.com/extrame/xls"
 	"github.com/tealeg/xlsx"
 )
 
 // ExcelParser Excel文档解析器
-type ExcelParser struct{}
+type ExcelParser struct {
+	matcher *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+}
 
 // NewExcelParser 创建Excel解析器
 func NewExcelParser() *ExcelParser {
@@ -21,25 +24,41 @@ func (p *ExcelParser) ParseXLSX(filePath string, keywords []string, verbose bool
 	var matchingLines []string
 	xlFile, err := xlsx.OpenFile(filePath)
 	if err != nil {
-		fmt.Printf("[-] 打开Excel文件%s错误\n", filePath)
+		logger.Warnf("打开Excel文件%s错误", filePath)
 		return matchingLines
 	}
 
 	for _, sheet := range xlFile.Sheets {
-		for _, row := range sheet.Rows {
+		var columns credentialColumns
+		for rowIndex, row := range sheet.Rows {
+			cells := make([]string, 0, len(row.Cells))
 			for _, cell := range row.Cells {
 				text := cell.String()
-				for _, keyword := range keywords {
-					if strings.Contains(text, keyword) {
-						lineOutput := formatExcelResult(keyword, "XLSX", text)
-						matchingLines = append(matchingLines, lineOutput)
-						if verbose {
-							fmt.Println(lineOutput)
-						}
-						break
+				cells = append(cells, text)
+				if keyword, ok := p.matcher.find(text, keywords); ok {
+					lineOutput := formatExcelResult(keyword, "XLSX", text)
+					matchingLines = append(matchingLines, lineOutput)
+					if verbose {
+						fmt.Println(lineOutput)
 					}
 				}
 			}
+
+			// 检查标签与值分列存放的凭据
+			for _, lineOutput := range checkAdjacentCells("XLSX", rowIndex+1, cells) {
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
+				}
+			}
+
+			// 检查同一行中的账号和口令
+			if lineOutput, ok := columns.check("XLSX", rowIndex+1, cells); ok {
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
+				}
+			}
 		}
 	}
 	return matchingLines
@@ -50,27 +69,43 @@ func (p *ExcelParser) ParseXLS(filePath string, keywords []string, verbose bool)
 	var matchingLines []string
 	xlFile, err := xls.Open(filePath, "utf-8")
 	if err != nil {
-		fmt.Printf("[-] 打开XLS文件%s错误\n", filePath)
+		logger.Warnf("打开XLS文件%s错误", filePath)
 		return matchingLines
 	}
 
 	for i := 0; i < xlFile.NumSheets(); i++ {
 		sheet := xlFile.GetSheet(i)
+		var columns credentialColumns
 		for j := 0; j <= int(sheet.MaxRow); j++ {
 			row := sheet.Row(j)
+			cells := make([]string, 0, row.LastCol())
 			for k := 0; k < row.LastCol(); k++ {
 				text := row.Col(k)
-				for _, keyword := range keywords {
-					if strings.Contains(text, keyword) {
-						lineOutput := formatExcelResult(keyword, "XLS", text)
-						matchingLines = append(matchingLines, lineOutput)
-						if verbose {
-							fmt.Println(lineOutput)
-						}
-						break
+				cells = append(cells, text)
+				if keyword, ok := p.matcher.find(text, keywords); ok {
+					lineOutput := formatExcelResult(keyword, "XLS", text)
+					matchingLines = append(matchingLines, lineOutput)
+					if verbose {
+						fmt.Println(lineOutput)
 					}
 				}
 			}
+
+			// 检查标签与值分列存放的凭据
+			for _, lineOutput := range checkAdjacentCells("XLS", j+1, cells) {
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
+				}
+			}
+
+			// 检查同一行中的账号和口令
+			if lineOutput, ok := columns.check("XLS", j+1, cells); ok {
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
+				}
+			}
 		}
 	}
 	return matchingLines
@@ -0,0 +1,130 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 片段合并的限制：相邻片段之间最多间隔 maxFragmentGap 字节，每次最多合并 maxMergedFragments 个片段、maxMergedLength 个字符
+const (
+	maxFragmentGap     = 16
+	maxMergedFragments = 8
+	maxMergedLength    = 1024
+)
+
+// stringFragment 提取的字符串片段，width 为每个字符占用的字节数（ASCII 为 1，UTF-16 为 2）
+type stringFragment struct {
+	offset int
+	width  int
+	str    string
+}
+
+// end 片段结束的字节偏移（不含）
+func (f stringFragment) end() int {
+	return f.offset + len(f.str)*f.width
+}
+
+// forEachFragmentRun 依次回调数据中相邻的字符串片段序列：编码相同、间隔不超过 maxFragmentGap 字节，至少两个片段
+// 片段不经过有意义字符串的筛选，被拆开的连接字符串的每一段单独看往往像无意义的字符串
+func forEachFragmentRun(data []byte, fn func(run []stringFragment)) {
+	var run []stringFragment
+	length := 0
+	flush := func() {
+		if len(run) >= 2 {
+			fn(run)
+		}
+		run = run[:0]
+		length = 0
+	}
+
+	forEachString(data, func(offset int, encoding, str string) bool {
+		fragment := stringFragment{offset: offset, width: 1, str: str}
+		if encoding == "utf16" {
+			fragment.width = 2
+		}
+		if len(run) > 0 {
+			last := run[len(run)-1]
+			if last.width != fragment.width || offset-last.end() > maxFragmentGap ||
+				len(run) >= maxMergedFragments || length+len(str) > maxMergedLength {
+				flush()
+			}
+		}
+		run = append(run, fragment)
+		length += len(str)
+		return true
+	})
+	flush()
+}
+
+// fragmentAt 获取合并后的字符串中位置 pos 所在的片段及其在合并字符串中的起始位置
+func fragmentAt(run []stringFragment, pos int) (stringFragment, int) {
+	start := 0
+	for _, fragment := range run {
+		if pos < start+len(fragment.str) {
+			return fragment, start
+		}
+		start += len(fragment.str)
+	}
+	last := run[len(run)-1]
+	return last, start - len(last.str)
+}
+
+// checkMergedFragments 将相邻的字符串片段合并后应用规则（--merge-fragments），用于发现被编译器拆分到多个字符串表项中的连接字符串
+// 只报告跨越多个片段、且起始片段单独不满足同一规则的匹配；结果的偏移为匹配起始处，上下文中标注合并的偏移范围
+func (p *BinaryParser) checkMergedFragments(data []byte, contextLen int, limit *ruleLimiter, fn func(result BinaryMatchResult)) {
+	forEachFragmentRun(data, func(run []stringFragment) {
+		var sb strings.Builder
+		for _, fragment := range run {
+			sb.WriteString(fragment.str)
+		}
+		merged := sb.String()
+
+		var results []BinaryMatchResult
+		deadline := startDeadline(p.rules)
+		for _, rule := range p.rules {
+			if deadline.exceeded(&rule, merged) {
+				break
+			}
+			for _, match := range rule.findAll(merged) {
+				idx := strings.Index(merged, match[0])
+				if idx < 0 {
+					continue
+				}
+				first, firstStart := fragmentAt(run, idx)
+				last, lastStart := fragmentAt(run, idx+len(match[0])-1)
+				if first.offset == last.offset || matchesAlone(&rule, first.str) {
+					continue
+				}
+				matchedValue, ok := rule.matchValue(match)
+				if !ok {
+					continue
+				}
+
+				start := first.offset + (idx-firstStart)*first.width
+				end := last.offset + (idx+len(match[0])-lastStart)*last.width
+				results = append(results, BinaryMatchResult{
+					RuleName:     rule.Name,
+					RuleDesc:     rule.Description,
+					RiskLevel:    rule.RiskLevel,
+					MatchedValue: matchedValue,
+					Offset:       start,
+					Context:      fmt.Sprintf("合并片段 0x%X-0x%X: %s", start, end, highlightInString(merged, matchedValue, contextLen)),
+				})
+			}
+		}
+
+		for _, result := range limit.filter(p.selectMatches(results)) {
+			fn(result)
+		}
+	})
+}
+
+// matchesAlone 判断单个片段是否已满足规则（此时合并后的匹配只是把后续片段误接到了值上）
+func matchesAlone(rule *DetectionRule, str string) bool {
+	for _, match := range rule.findAll(str) {
+		if _, ok := rule.matchValue(match); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// HTTP存档结果中的认证头规则
+const (
+	httpAuthHeaderRule = "HTTP认证头"
+	httpAuthHeaderRisk = "high"
+)
+
+// maxHarBodySize HAR 中单个请求体或响应体的最大扫描长度
+const maxHarBodySize = 1024 * 1024
+
+// harAuthHeaders 值本身即为凭据的请求头
+var harAuthHeaders = []string{"Authorization", "Proxy-Authorization", "X-API-Key", "X-Auth-Token", "X-Access-Token"}
+
+// harNameValue HAR 中的名称/值对（请求头、Cookie、查询参数、表单参数）
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// harFile HAR 文件中用到的字段（HTTP Archive 1.2）
+type harFile struct {
+	Log struct {
+		Entries []struct {
+			Request struct {
+				Method      string         `json:"method"`
+				URL         string         `json:"url"`
+				Headers     []harNameValue `json:"headers"`
+				QueryString []harNameValue `json:"queryString"`
+				Cookies     []harNameValue `json:"cookies"`
+				PostData    *struct {
+					MimeType string         `json:"mimeType"`
+					Text     string         `json:"text"`
+					Params   []harNameValue `json:"params"`
+				} `json:"postData"`
+			} `json:"request"`
+			Response struct {
+				Headers []harNameValue `json:"headers"`
+				Cookies []harNameValue `json:"cookies"`
+				Content struct {
+					MimeType string `json:"mimeType"`
+					Text     string `json:"text"`
+					Encoding string `json:"encoding"`
+				} `json:"content"`
+			} `json:"response"`
+		} `json:"entries"`
+	} `json:"log"`
+}
+
+// HarParser HTTP 存档（.har）解析器，按请求逐项扫描请求头、Cookie、查询参数和请求/响应体
+type HarParser struct {
+	binaryParser *BinaryParser
+	textParser   *TextParser
+}
+
+// NewHarParser 创建 HAR 文件解析器
+func NewHarParser(binaryParser *BinaryParser, textParser *TextParser) *HarParser {
+	return &HarParser{
+		binaryParser: binaryParser,
+		textParser:   textParser,
+	}
+}
+
+// Parse 解析 .har 文件，格式错误时按文本扫描
+func (p *HarParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取HAR文件%s错误", filePath)
+		return nil
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		logger.Debugf("解析HAR文件%s失败，按文本扫描: %v", filePath, err)
+		return p.textParser.Parse(filePath, keywords, verbose)
+	}
+
+	var matchingLines []string
+	for i, entry := range har.Log.Entries {
+		req, resp := entry.Request, entry.Response
+		// 位置中的 URL 去掉查询参数，参数值单独报告
+		target := fmt.Sprintf("#%d %s %s", i+1, req.Method, strings.SplitN(req.URL, "?", 2)[0])
+
+		for _, h := range req.Headers {
+			matchingLines = append(matchingLines, p.checkHeader(target+" 请求头 "+h.Name, h, keywords)...)
+		}
+		for _, c := range req.Cookies {
+			matchingLines = append(matchingLines, p.checkLine(target+" 请求Cookie "+c.Name, c.Name+"="+c.Value, keywords)...)
+		}
+		for _, q := range req.QueryString {
+			matchingLines = append(matchingLines, p.checkLine(target+" 查询参数 "+q.Name, q.Name+"="+q.Value, keywords)...)
+		}
+		if req.PostData != nil {
+			for _, param := range req.PostData.Params {
+				matchingLines = append(matchingLines, p.checkLine(target+" 表单参数 "+param.Name, param.Name+"="+param.Value, keywords)...)
+			}
+			matchingLines = append(matchingLines, p.checkBody(target+" 请求体", req.PostData.Text, keywords)...)
+		}
+
+		for _, h := range resp.Headers {
+			matchingLines = append(matchingLines, p.checkHeader(target+" 响应头 "+h.Name, h, keywords)...)
+		}
+		for _, c := range resp.Cookies {
+			matchingLines = append(matchingLines, p.checkLine(target+" 响应Cookie "+c.Name, c.Name+"="+c.Value, keywords)...)
+		}
+		body := resp.Content.Text
+		if resp.Content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				continue
+			}
+			text, ok := textContent(decoded, p.binaryParser.textThreshold)
+			if !ok {
+				continue
+			}
+			body = string(text)
+		}
+		matchingLines = append(matchingLines, p.checkBody(target+" 响应体", body, keywords)...)
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// checkHeader 检查一个请求头或响应头：Basic 认证解码后报告，其他认证头报告其值，其余按规则和关键字检查
+func (p *HarParser) checkHeader(location string, h harNameValue, keywords []string) []string {
+	line := h.Name + ": " + h.Value
+	if credentials, ok := decodeBasicAuth(line); ok {
+		return []string{formatHarResult(location, "HTTP Basic认证", "critical", "", credentials, line)}
+	}
+	if results := p.checkLine(location, line, keywords); len(results) > 0 {
+		return results
+	}
+	for _, name := range harAuthHeaders {
+		if strings.EqualFold(h.Name, name) && strings.TrimSpace(h.Value) != "" {
+			return []string{formatHarResult(location, httpAuthHeaderRule, httpAuthHeaderRisk, "", strings.TrimSpace(h.Value), line)}
+		}
+	}
+	return nil
+}
+
+// checkBody 检查请求体或响应体，JSON 体按字段检查，其他逐行检查，超过上限的部分不扫描
+func (p *HarParser) checkBody(location, body string, keywords []string) []string {
+	if len(body) > maxHarBodySize {
+		body = body[:maxHarBodySize]
+	}
+
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		decoder := json.NewDecoder(strings.NewReader(trimmed))
+		decoder.UseNumber()
+		var value interface{}
+		if err := decoder.Decode(&value); err == nil {
+			return p.walkJSON(location, "", "", value, keywords)
+		}
+	}
+
+	var results []string
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(line) < 4 {
+			continue
+		}
+		results = append(results, p.checkLine(location, line, keywords)...)
+	}
+	return results
+}
+
+// walkJSON 递归检查 JSON 体中的字段，以 "字段名=值" 的形式匹配规则，位置中附加字段路径（如 user.password、items[0].token）
+func (p *HarParser) walkJSON(location, path, name string, value interface{}, keywords []string) []string {
+	var results []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for _, key := range sortedJSONKeys(v) {
+			child := key
+			if path != "" {
+				child = path + "." + key
+			}
+			results = append(results, p.walkJSON(location, child, key, v[key], keywords)...)
+		}
+	case []interface{}:
+		for i, item := range v {
+			results = append(results, p.walkJSON(location, path+"["+strconv.Itoa(i)+"]", name, item, keywords)...)
+		}
+	case string, json.Number:
+		line := fmt.Sprint(v)
+		if name != "" {
+			line = name + "=" + line
+		}
+		results = p.checkLine(strings.TrimSpace(location+" "+path), line, keywords)
+	}
+	return results
+}
+
+// checkLine 对一行内容应用检测规则，未命中时检查关键字
+func (p *HarParser) checkLine(location, line string, keywords []string) []string {
+	if matches := p.binaryParser.MatchString(line); len(matches) > 0 {
+		results := make([]string, 0, len(matches))
+		for _, result := range matches {
+			results = append(results, formatHarResult(location, result.RuleName, result.RiskLevel, "", result.MatchedValue, line))
+		}
+		return results
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(line, keywords); ok {
+		return []string{formatHarResult(location, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, strings.TrimSpace(line), line)}
+	}
+	return nil
+}
+
+// formatHarResult 格式化 HAR 文件扫描结果，位置中的 | 会破坏结果格式，替换为 URL 编码
+func formatHarResult(location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	location = strings.ReplaceAll(location, "|", "%7C")
+	return fmt.Sprintf("HAR|%s|%s|%s|%s|%s|%s", location, ruleName, riskLevel, keyword, matchedValue, content)
+}
@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// historySource Shell 历史结果的来源名称
+const historySource = "Shell历史"
+
+// historyFileNames 总会被扫描的命令历史文件名
+var historyFileNames = map[string]bool{
+	".bash_history":           true,
+	".zsh_history":            true,
+	".sh_history":             true,
+	".history":                true,
+	"fish_history":            true,
+	".mysql_history":          true,
+	".psql_history":           true,
+	".rediscli_history":       true,
+	"ConsoleHost_history.txt": true, // PowerShell PSReadLine
+}
+
+// zshExtendedPrefix zsh 扩展历史格式的时间戳前缀（": 1700000000:0;"）
+var zshExtendedPrefix = regexp.MustCompile(`^: \d+:\d+;`)
+
+// IsHistoryFile 判断是否为命令历史文件
+func IsHistoryFile(filePath string) bool {
+	return historyFileNames[filepath.Base(filePath)]
+}
+
+// HistoryParser 命令历史解析器，识别命令行参数中的凭据
+type HistoryParser struct {
+	rules   []DetectionRule
+	matcher *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+}
+
+// NewHistoryParser 创建命令历史解析器
+func NewHistoryParser() *HistoryParser {
+	return &HistoryParser{rules: initHistoryRules()}
+}
+
+// FilterRules 按规则名称筛选命令行凭据规则
+func (p *HistoryParser) FilterRules(only, skip []string) {
+	p.rules = filterRules(p.rules, only, skip)
+}
+
+// initHistoryRules 初始化命令行凭据规则，第一个捕获组为凭据值
+func initHistoryRules() []DetectionRule {
+	return []DetectionRule{
+		{
+			Name:        "命令行密码参数",
+			Pattern:     regexp.MustCompile(`(?:^|\s)--(?:password|passwd|pass|pwd)(?:=|\s+)("[^"]+"|'[^']+'|[^\s"']+)`),
+			Description: "命令行中以 --password 传递的密码",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "MySQL命令行密码",
+			Pattern:     regexp.MustCompile(`\bmysql(?:dump|admin|import)?\b.*?\s-p([^\s-]\S*)`),
+			Description: "mysql -p<密码> 形式传递的密码",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "db", "password"},
+		},
+		{
+			Name:        "sshpass密码",
+			Pattern:     regexp.MustCompile(`\bsshpass\s+-p\s*("[^"]+"|'[^']+'|\S+)`),
+			Description: "sshpass -p 传递的SSH密码",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "redis-cli密码",
+			Pattern:     regexp.MustCompile(`\bredis-cli\b.*?\s-a\s+("[^"]+"|'[^']+'|\S+)`),
+			Description: "redis-cli -a 传递的密码",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "db", "password"},
+		},
+		{
+			Name:        "curl认证",
+			Pattern:     regexp.MustCompile(`\bcurl\b.*?\s(?:-u|--user)\s*["']?[^:\s"']+:([^\s"']+)`),
+			Description: "curl -u 用户名:密码",
+			RiskLevel:   "high",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "URL内嵌凭据",
+			Pattern:     regexp.MustCompile(`\b[a-zA-Z][a-zA-Z0-9+.-]*://[^:/\s@]+:([^@\s/]+)@`),
+			Description: "URL中的 用户名:密码@主机",
+			RiskLevel:   "high",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "环境变量凭据",
+			Pattern:     regexp.MustCompile(`\b[A-Z0-9_]*(?:PASSWORD|PASSWD|SECRET|TOKEN|API_KEY|APIKEY)[A-Z0-9_]*=("[^"]+"|'[^']+'|[^\s"';]+)`),
+			Description: "命令行中设置的凭据类环境变量",
+			RiskLevel:   "high",
+			Tags:        []string{"shell", "cloud", "token"},
+		},
+	}
+}
+
+// Parse 解析命令历史文件，规则未命中的行再按关键字匹配
+func (p *HistoryParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	var matchingLines []string
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("打开文件%s错误", filePath)
+		return matchingLines
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		command := zshExtendedPrefix.ReplaceAllString(line, "")
+
+		results := matchCommandRules(p.rules, historySource, lineNum, command)
+		if len(results) == 0 {
+			if keyword, ok := p.matcher.find(line, keywords); ok {
+				results = append(results, formatTextResult(keyword, lineNum, line))
+			}
+		}
+
+		for _, lineOutput := range results {
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logger.Warnf("读取文件错误%s: %v", filePath, err)
+	}
+
+	return matchingLines
+}
+
+// matchCommandRules 对单条命令应用命令行凭据规则，取第一个非空的捕获组为凭据值
+func matchCommandRules(rules []DetectionRule, source string, lineNum int, command string) []string {
+	var results []string
+	deadline := startDeadline(rules)
+	for _, rule := range rules {
+		if deadline.exceeded(&rule, command) {
+			break
+		}
+		for _, match := range rule.findAll(command) {
+			value := ""
+			for _, group := range match[1:] {
+				if value = strings.Trim(group, `"'`); value != "" {
+					break
+				}
+			}
+			if value == "" {
+				continue
+			}
+			results = append(results, formatLineResult(source, lineNum, rule.Name, rule.RiskLevel, value, command))
+		}
+	}
+	return results
+}
+
+// formatLineResult 格式化文本行的规则匹配结果: LINE|来源|行号|规则|风险|匹配值|内容
+func formatLineResult(source string, lineNum int, ruleName, riskLevel, value, content string) string {
+	return fmt.Sprintf("LINE|%s|%d|%s|%s|%s|%s", source, lineNum, ruleName, riskLevel, strings.ReplaceAll(value, "|", "/"), content)
+}
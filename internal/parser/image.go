@@ -0,0 +1,538 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"Findx/internal/logger"
+)
+
+// imageExts 按元数据扫描的图片扩展名
+var imageExts = []string{".jpg", ".jpeg", ".png", ".tif", ".tiff"}
+
+// maxImageFieldSize 单个元数据字段（EXIF 字符串、PNG 文本块、XMP 包）的最大读取长度
+const maxImageFieldSize = 1024 * 1024
+
+// maxTIFFDirectories TIFF 中最多解析的目录数，防止损坏文件中的循环引用
+const maxTIFFDirectories = 16
+
+// pngSignature PNG 文件头
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// JPEG APP1 段中 EXIF 和 XMP 数据的标识
+var (
+	exifHeader = []byte("Exif\x00\x00")
+	xmpHeader  = []byte("http://ns.adobe.com/xap/1.0/\x00")
+)
+
+// tiffTagNames EXIF 中按字符串扫描的标签
+var tiffTagNames = map[uint16]string{
+	0x010D: "DocumentName",
+	0x010E: "ImageDescription",
+	0x010F: "Make",
+	0x0110: "Model",
+	0x0131: "Software",
+	0x013B: "Artist",
+	0x013C: "HostComputer",
+	0x8298: "Copyright",
+	0x9286: "UserComment",
+	0x9C9B: "XPTitle",
+	0x9C9C: "XPComment",
+	0x9C9D: "XPAuthor",
+	0x9C9E: "XPKeywords",
+	0x9C9F: "XPSubject",
+	0xA420: "ImageUniqueID",
+	0xA430: "CameraOwnerName",
+	0xA431: "BodySerialNumber",
+}
+
+// gpsTagNames GPS 目录中按字符串扫描的标签（标签号与主目录重叠，单独命名）
+var gpsTagNames = map[uint16]string{
+	0x001B: "GPSProcessingMethod",
+	0x001C: "GPSAreaInformation",
+}
+
+// EXIF 中指向子目录的标签
+const (
+	tiffExifIFD = 0x8769
+	tiffGPSIFD  = 0x8825
+)
+
+// imageField 图片中的一个元数据字段
+type imageField struct {
+	Name  string // 标签位置，如 EXIF UserComment、PNG tEXt Comment、XMP dc:creator
+	Value string
+}
+
+// IsImageFile 判断文件是否为按元数据扫描的图片
+func IsImageFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, imageExt := range imageExts {
+		if ext == imageExt {
+			return true
+		}
+	}
+	return false
+}
+
+// ImageMetadataParser 图片元数据解析器，扫描 EXIF、XMP、JPEG 注释和 PNG 文本块，不读取像素数据
+type ImageMetadataParser struct {
+	binaryParser *BinaryParser
+}
+
+// NewImageMetadataParser 创建图片元数据解析器
+func NewImageMetadataParser(binaryParser *BinaryParser) *ImageMetadataParser {
+	return &ImageMetadataParser{
+		binaryParser: binaryParser,
+	}
+}
+
+// Parse 读取图片的元数据字段，逐行应用检测规则和关键字
+func (p *ImageMetadataParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("读取图片%s错误", filePath)
+		return nil
+	}
+	defer file.Close()
+
+	var fields []imageField
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".jpg", ".jpeg":
+		fields, err = readJPEGMetadata(file)
+	case ".png":
+		fields, err = readPNGMetadata(file)
+	default:
+		var info os.FileInfo
+		if info, err = file.Stat(); err == nil {
+			fields, err = readTIFFFields(file, info.Size())
+		}
+	}
+	if err != nil {
+		// 格式不完整时保留已读取的字段
+		logger.Debugf("解析图片元数据%s失败: %v", filePath, err)
+	}
+
+	var matchingLines []string
+	for _, field := range fields {
+		for _, line := range strings.Split(field.Value, "\n") {
+			line = strings.TrimSpace(line)
+			if len(line) < 4 {
+				continue
+			}
+			matchingLines = append(matchingLines, p.checkLine(field.Name, line, keywords)...)
+		}
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// checkLine 对元数据中的一行应用检测规则，未命中时检查关键字
+func (p *ImageMetadataParser) checkLine(location, line string, keywords []string) []string {
+	if matches := p.binaryParser.MatchString(line); len(matches) > 0 {
+		results := make([]string, 0, len(matches))
+		for _, result := range matches {
+			results = append(results, formatImageResult(location, result.RuleName, result.RiskLevel, "", result.MatchedValue, line))
+		}
+		return results
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(line, keywords); ok {
+		return []string{formatImageResult(location, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, line, line)}
+	}
+	return nil
+}
+
+// formatImageResult 格式化图片元数据扫描结果: IMAGE|标签|规则|风险|关键字|匹配值|内容
+func formatImageResult(location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	clean := strings.NewReplacer("|", "/", "\r", " ").Replace
+	return fmt.Sprintf("IMAGE|%s|%s|%s|%s|%s|%s", clean(location), ruleName, riskLevel, keyword, matchedValue, content)
+}
+
+// readJPEGMetadata 读取 JPEG 文件中扫描数据之前的段：APP1 中的 EXIF 和 XMP、COM 注释
+func readJPEGMetadata(file io.Reader) ([]imageField, error) {
+	r := bufio.NewReader(file)
+	var soi [2]byte
+	if _, err := io.ReadFull(r, soi[:]); err != nil || soi != [2]byte{0xFF, 0xD8} {
+		return nil, fmt.Errorf("不是JPEG文件")
+	}
+
+	var fields []imageField
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return fields, err
+		}
+		if b != 0xFF {
+			continue
+		}
+		marker, err := r.ReadByte()
+		if err != nil {
+			return fields, err
+		}
+		switch {
+		case marker == 0xFF || marker == 0x00:
+			// 填充字节
+			continue
+		case marker == 0xD9 || marker == 0xDA:
+			// 图像结束或扫描数据开始，之后是像素数据
+			return fields, nil
+		case marker == 0x01 || marker >= 0xD0 && marker <= 0xD7:
+			// 没有长度字段的标记
+			continue
+		}
+
+		var length [2]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return fields, err
+		}
+		size := int(binary.BigEndian.Uint16(length[:])) - 2
+		if size < 0 {
+			return fields, fmt.Errorf("JPEG段长度无效")
+		}
+		if marker != 0xE1 && marker != 0xFE {
+			if _, err := r.Discard(size); err != nil {
+				return fields, err
+			}
+			continue
+		}
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fields, err
+		}
+		switch {
+		case marker == 0xFE:
+			fields = append(fields, imageField{Name: "JPEG注释", Value: string(data)})
+		case bytes.HasPrefix(data, exifHeader):
+			tiff := data[len(exifHeader):]
+			exif, _ := readTIFFFields(bytes.NewReader(tiff), int64(len(tiff)))
+			fields = append(fields, exif...)
+		case bytes.HasPrefix(data, xmpHeader):
+			fields = append(fields, readXMPFields(data[len(xmpHeader):])...)
+		}
+	}
+}
+
+// readPNGMetadata 读取 PNG 文件中的文本块（tEXt、zTXt、iTXt）和 eXIf 块，图像数据块直接跳过
+func readPNGMetadata(file io.ReadSeeker) ([]imageField, error) {
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(file, signature); err != nil || !bytes.Equal(signature, pngSignature) {
+		return nil, fmt.Errorf("不是PNG文件")
+	}
+
+	var fields []imageField
+	var header [8]byte
+	for {
+		if _, err := io.ReadFull(file, header[:]); err != nil {
+			return fields, err
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		chunkType := string(header[4:])
+		if chunkType == "IEND" {
+			return fields, nil
+		}
+
+		switch {
+		case chunkType != "tEXt" && chunkType != "zTXt" && chunkType != "iTXt" && chunkType != "eXIf",
+			length > maxImageFieldSize:
+			// 跳过数据和 CRC
+			if _, err := file.Seek(length+4, io.SeekCurrent); err != nil {
+				return fields, err
+			}
+			continue
+		}
+
+		data := make([]byte, length+4)
+		if _, err := io.ReadFull(file, data); err != nil {
+			return fields, err
+		}
+		data = data[:length]
+
+		if chunkType == "eXIf" {
+			exif, _ := readTIFFFields(bytes.NewReader(data), int64(len(data)))
+			fields = append(fields, exif...)
+			continue
+		}
+		if field, ok := pngTextField(chunkType, data); ok {
+			fields = append(fields, field)
+		}
+	}
+}
+
+// pngTextField 解析 PNG 文本块: 关键字\0文本（zTXt 的文本经 zlib 压缩，iTXt 另有压缩标志、语言和翻译后的关键字）
+func pngTextField(chunkType string, data []byte) (imageField, bool) {
+	keyword, rest, ok := bytes.Cut(data, []byte{0})
+	if !ok {
+		return imageField{}, false
+	}
+
+	compressed := false
+	switch chunkType {
+	case "zTXt":
+		if len(rest) < 1 {
+			return imageField{}, false
+		}
+		rest, compressed = rest[1:], true
+	case "iTXt":
+		if len(rest) < 2 {
+			return imageField{}, false
+		}
+		compressed = rest[0] == 1
+		// 跳过压缩标志、压缩方法、语言标签和翻译后的关键字
+		rest = rest[2:]
+		for i := 0; i < 2; i++ {
+			var found bool
+			if _, rest, found = bytes.Cut(rest, []byte{0}); !found {
+				return imageField{}, false
+			}
+		}
+	}
+
+	if compressed {
+		reader, err := zlib.NewReader(bytes.NewReader(rest))
+		if err != nil {
+			return imageField{}, false
+		}
+		defer reader.Close()
+		if rest, err = io.ReadAll(io.LimitReader(reader, maxImageFieldSize)); err != nil {
+			return imageField{}, false
+		}
+	}
+
+	// XMP 以 iTXt 块保存
+	if string(keyword) == "XML:com.adobe.xmp" {
+		return imageField{Name: "XMP", Value: strings.Join(xmpValues(rest), "\n")}, true
+	}
+	return imageField{Name: "PNG " + chunkType + " " + string(keyword), Value: string(rest)}, true
+}
+
+// readTIFFFields 读取 TIFF 结构（EXIF 数据或 .tif 文件）中的字符串标签，只读取目录项，不读取图像条带
+func readTIFFFields(r io.ReaderAt, size int64) ([]imageField, error) {
+	var header [8]byte
+	if _, err := r.ReadAt(header[:], 0); err != nil {
+		return nil, fmt.Errorf("TIFF文件头不完整")
+	}
+
+	var order binary.ByteOrder
+	switch string(header[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("不是TIFF数据")
+	}
+	if order.Uint16(header[2:4]) != 42 {
+		return nil, fmt.Errorf("不是TIFF数据")
+	}
+
+	t := &tiffReader{r: r, size: size, order: order, visited: make(map[uint32]bool)}
+	err := t.readDirectory(order.Uint32(header[4:8]), tiffTagNames, true)
+	return t.fields, err
+}
+
+// tiffReader 解析 TIFF 目录
+type tiffReader struct {
+	r       io.ReaderAt
+	size    int64
+	order   binary.ByteOrder
+	visited map[uint32]bool
+	fields  []imageField
+}
+
+// readDirectory 读取一个目录中的字符串标签，并跟随 EXIF、GPS 子目录（主目录链上的后续目录同样读取）
+func (t *tiffReader) readDirectory(offset uint32, names map[uint16]string, chain bool) error {
+	for offset != 0 {
+		if t.visited[offset] || len(t.visited) >= maxTIFFDirectories {
+			return nil
+		}
+		t.visited[offset] = true
+
+		var countBuf [2]byte
+		if _, err := t.r.ReadAt(countBuf[:], int64(offset)); err != nil {
+			return fmt.Errorf("TIFF目录越界")
+		}
+		count := int(t.order.Uint16(countBuf[:]))
+		entries := make([]byte, count*12+4)
+		if _, err := t.r.ReadAt(entries, int64(offset)+2); err != nil && err != io.EOF {
+			return fmt.Errorf("TIFF目录项越界")
+		}
+
+		for i := 0; i < count; i++ {
+			entry := entries[i*12 : i*12+12]
+			tag := t.order.Uint16(entry[0:2])
+			switch {
+			case tag == tiffExifIFD && chain:
+				t.readDirectory(t.order.Uint32(entry[8:12]), tiffTagNames, false)
+				continue
+			case tag == tiffGPSIFD && chain:
+				t.readDirectory(t.order.Uint32(entry[8:12]), gpsTagNames, false)
+				continue
+			}
+			if field, ok := t.readField(entry, names); ok {
+				t.fields = append(t.fields, field)
+			}
+		}
+
+		if !chain {
+			return nil
+		}
+		offset = t.order.Uint32(entries[count*12:])
+	}
+	return nil
+}
+
+// readField 读取目录项中的字符串值：ASCII 类型的标签、UserComment 等 UNDEFINED 类型的注释和 Windows XP 的 UTF-16 标签
+func (t *tiffReader) readField(entry []byte, names map[uint16]string) (imageField, bool) {
+	tag := t.order.Uint16(entry[0:2])
+	fieldType := t.order.Uint16(entry[2:4])
+	count := t.order.Uint32(entry[4:8])
+
+	name, known := names[tag]
+	switch {
+	case fieldType == 2:
+		// ASCII，未命名的标签以标签号表示
+		if !known {
+			name = fmt.Sprintf("0x%04X", tag)
+		}
+	case known && (fieldType == 1 || fieldType == 7):
+	default:
+		return imageField{}, false
+	}
+	if count == 0 || count > maxImageFieldSize {
+		return imageField{}, false
+	}
+
+	data := make([]byte, count)
+	if count <= 4 {
+		copy(data, entry[8:8+count])
+	} else {
+		offset := int64(t.order.Uint32(entry[8:12]))
+		if offset+int64(count) > t.size {
+			return imageField{}, false
+		}
+		if _, err := t.r.ReadAt(data, offset); err != nil {
+			return imageField{}, false
+		}
+	}
+
+	var value string
+	switch {
+	case strings.HasPrefix(name, "XP"):
+		value = decodeUTF16LE(data)
+	case fieldType == 7:
+		value = decodeExifComment(data)
+	default:
+		value = string(data)
+	}
+	value = strings.TrimRight(value, "\x00 ")
+	if value == "" {
+		return imageField{}, false
+	}
+	return imageField{Name: "EXIF " + name, Value: value}, true
+}
+
+// decodeExifComment 解码 UserComment 等注释：前8字节为字符集标识（ASCII、UNICODE 等）
+func decodeExifComment(data []byte) string {
+	if len(data) < 8 {
+		return string(data)
+	}
+	if bytes.HasPrefix(data, []byte("UNICODE\x00")) {
+		return decodeUTF16LE(data[8:])
+	}
+	return string(data[8:])
+}
+
+// decodeUTF16LE 解码 UTF-16LE 字符串
+func decodeUTF16LE(data []byte) string {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// readXMPFields 读取 XMP 包中各元素的文本和属性值，位置为 XMP 加元素或属性名
+func readXMPFields(data []byte) []imageField {
+	var fields []imageField
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+
+	var stack []string
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return fields
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, xmpName(t.Name))
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "about" || strings.TrimSpace(attr.Value) == "" {
+					continue
+				}
+				fields = append(fields, imageField{Name: "XMP " + xmpName(attr.Name), Value: attr.Value})
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" || len(stack) == 0 {
+				continue
+			}
+			// rdf:li 等容器元素以所属的属性命名
+			name := stack[len(stack)-1]
+			for i := len(stack) - 1; i >= 0 && strings.HasPrefix(stack[i], "rdf:"); i-- {
+				name = stack[i]
+				if i > 0 {
+					name = stack[i-1]
+				}
+			}
+			fields = append(fields, imageField{Name: "XMP " + name, Value: text})
+		}
+	}
+}
+
+// xmpValues 获取 XMP 包中所有元素的文本和属性值
+func xmpValues(data []byte) []string {
+	var values []string
+	for _, field := range readXMPFields(data) {
+		values = append(values, field.Value)
+	}
+	return values
+}
+
+// xmpName 获取 XMP 元素或属性的显示名称，常见命名空间使用通用前缀
+func xmpName(name xml.Name) string {
+	prefixes := map[string]string{
+		"http://purl.org/dc/elements/1.1/":            "dc",
+		"http://ns.adobe.com/xap/1.0/":                "xmp",
+		"http://www.w3.org/1999/02/22-rdf-syntax-ns#": "rdf",
+		"http://ns.adobe.com/photoshop/1.0/":          "photoshop",
+		"http://ns.adobe.com/exif/1.0/":               "exif",
+		"http://ns.adobe.com/tiff/1.0/":               "tiff",
+		"http://ns.adobe.com/xap/1.0/rights/":         "xmpRights",
+	}
+	if prefix, ok := prefixes[name.Space]; ok {
+		return prefix + ":" + name.Local
+	}
+	return name.Local
+}
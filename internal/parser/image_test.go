@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// tiffEntry 构造 TIFF 目录项用的标签、类型和值
+type tiffEntry struct {
+	tag       uint16
+	fieldType uint16
+	value     []byte
+}
+
+// buildTIFF 构造小端序 TIFF 数据：主目录和可选的 EXIF 子目录，超过4字节的值放在目录之后
+func buildTIFF(ifd0, exif []tiffEntry) []byte {
+	order := binary.LittleEndian
+	if len(exif) > 0 {
+		ifd0 = append(ifd0, tiffEntry{tag: tiffExifIFD, fieldType: 4})
+	}
+	exifOffset := 8 + 2 + 12*len(ifd0) + 4
+	dataOffset := exifOffset
+	if len(exif) > 0 {
+		dataOffset += 2 + 12*len(exif) + 4
+	}
+
+	var data []byte
+	directory := func(entries []tiffEntry) []byte {
+		dir := order.AppendUint16(nil, uint16(len(entries)))
+		for _, e := range entries {
+			dir = order.AppendUint16(dir, e.tag)
+			dir = order.AppendUint16(dir, e.fieldType)
+			switch {
+			case e.tag == tiffExifIFD:
+				dir = order.AppendUint32(dir, 1)
+				dir = order.AppendUint32(dir, uint32(exifOffset))
+			case len(e.value) <= 4:
+				dir = order.AppendUint32(dir, uint32(len(e.value)))
+				dir = append(dir, append(e.value, make([]byte, 4-len(e.value))...)...)
+			default:
+				dir = order.AppendUint32(dir, uint32(len(e.value)))
+				dir = order.AppendUint32(dir, uint32(dataOffset+len(data)))
+				data = append(data, e.value...)
+			}
+		}
+		return order.AppendUint32(dir, 0)
+	}
+
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = append(tiff, directory(ifd0)...)
+	if len(exif) > 0 {
+		tiff = append(tiff, directory(exif)...)
+	}
+	return append(tiff, data...)
+}
+
+// asciiTag 构造以 NUL 结尾的 ASCII 值
+func asciiTag(s string) []byte {
+	return append([]byte(s), 0)
+}
+
+// utf16Tag 构造 Windows XP 标签使用的 UTF-16LE 值
+func utf16Tag(s string) []byte {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(s)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return append(b, 0, 0)
+}
+
+// testEXIF 主目录中的 ImageDescription、Artist 和 XPComment，EXIF 子目录中的 UserComment
+func testEXIF() []byte {
+	return buildTIFF(
+		[]tiffEntry{
+			{0x010E, 2, asciiTag("deploy password=Hunter2024")},
+			{0x013B, 2, asciiTag("Ops")},
+			{0x9C9C, 1, utf16Tag("备份账号 api_key=AKIA1234567890ABCDEFGH")},
+		},
+		[]tiffEntry{
+			{0x9286, 7, append([]byte("ASCII\x00\x00\x00"), "token for build server"...)},
+		},
+	)
+}
+
+// testJPEG 在编码的 JPEG 图像中插入 EXIF、XMP 和注释段
+func testJPEG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8)), nil); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+
+	segment := func(marker byte, data []byte) []byte {
+		seg := []byte{0xFF, marker}
+		seg = binary.BigEndian.AppendUint16(seg, uint16(len(data)+2))
+		return append(seg, data...)
+	}
+	xmp := `<x:xmpmeta xmlns:x="adobe:ns:meta/"><rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#">` +
+		`<rdf:Description rdf:about="" xmlns:dc="http://purl.org/dc/elements/1.1/">` +
+		`<dc:description><rdf:Alt><rdf:li xml:lang="x-default">wifi password=Guest-W1fi-24</rdf:li></rdf:Alt></dc:description>` +
+		`</rdf:Description></rdf:RDF></x:xmpmeta>`
+
+	out := append([]byte(nil), encoded[:2]...)
+	out = append(out, segment(0xE1, append(append([]byte(nil), exifHeader...), testEXIF()...))...)
+	out = append(out, segment(0xE1, append(append([]byte(nil), xmpHeader...), xmp...))...)
+	out = append(out, segment(0xFE, []byte("jdbc:mysql://db.internal:3306/app"))...)
+	return append(out, encoded[2:]...)
+}
+
+// pngChunk 构造 PNG 块（长度、类型、数据和 CRC）
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+// testPNG 在编码的 PNG 图像的 IEND 之前插入 tEXt、zTXt 和 iTXt 文本块
+func testPNG(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	encoded := buf.Bytes()
+	iend := len(encoded) - 12
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write([]byte("line one\nsmtp password=Mail-Pass-99"))
+	zw.Close()
+
+	out := append([]byte(nil), encoded[:iend]...)
+	out = append(out, pngChunk("tEXt", []byte("Comment\x00username=svc_render"))...)
+	out = append(out, pngChunk("zTXt", append([]byte("Description\x00\x00"), compressed.Bytes()...))...)
+	out = append(out, pngChunk("iTXt", []byte("Author\x00\x00\x00zh\x00作者\x00secret token holder"))...)
+	return append(out, encoded[iend:]...)
+}
+
+func TestImageMetadataParser(t *testing.T) {
+	jpegData := testJPEG(t)
+	pngData := testPNG(t)
+	if _, err := jpeg.Decode(bytes.NewReader(jpegData)); err != nil {
+		t.Fatalf("test JPEG is invalid: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(pngData)); err != nil {
+		t.Fatalf("test PNG is invalid: %v", err)
+	}
+
+	exifResults := []string{
+		"IMAGE|EXIF ImageDescription|密码字段|critical||Hunter2024|deploy password=Hunter2024",
+		"IMAGE|EXIF XPComment|API密钥|critical||AKIA1234567890ABCDEFGH|备份账号 api_key=AKIA1234567890ABCDEFGH",
+		"IMAGE|EXIF UserComment|关键字匹配|medium|token|token for build server|token for build server",
+	}
+	jpegResults := append(append([]string(nil), exifResults...),
+		"IMAGE|XMP dc:description|密码字段|critical||Guest-W1fi-24|wifi password=Guest-W1fi-24",
+		"IMAGE|JPEG注释|JDBC连接URL|high||jdbc:mysql://db.internal:3306/app|jdbc:mysql://db.internal:3306/app",
+	)
+	pngResults := []string{
+		"IMAGE|PNG tEXt Comment|用户名字段|high||svc_render|username=svc_render",
+		"IMAGE|PNG zTXt Description|密码字段|critical||Mail-Pass-99|smtp password=Mail-Pass-99",
+		"IMAGE|PNG iTXt Author|关键字匹配|medium|token|secret token holder|secret token holder",
+	}
+
+	// 截断后保留已读取的字段，不能 panic
+	truncatedJPEG := jpegData[:bytes.Index(jpegData, xmpHeader)+40]
+	truncatedPNG := pngData[:bytes.Index(pngData, []byte("iTXt"))+10]
+	truncatedTIFF := testEXIF()
+	truncatedTIFF = truncatedTIFF[:bytes.Index(truncatedTIFF, []byte("Hunter2024"))+16] // 截断在 XPComment 的值中
+	cyclicTIFF := testEXIF()
+	binary.LittleEndian.PutUint32(cyclicTIFF[8+2+12*4:], 8) // 主目录的下一个目录指向自身
+	badOffsetTIFF := testEXIF()
+	binary.LittleEndian.PutUint32(badOffsetTIFF[4:], 0xFFFFFF00)
+
+	tests := []struct {
+		file string
+		data []byte
+		want []string
+	}{
+		{"photo.jpg", jpegData, jpegResults},
+		{"screenshot.png", pngData, pngResults},
+		{"scan.tif", testEXIF(), exifResults},
+		{"truncated.jpeg", truncatedJPEG, exifResults},
+		{"truncated.png", truncatedPNG, pngResults[:2]},
+		{"truncated.tiff", truncatedTIFF, exifResults[:1]},
+		{"cyclic.tif", cyclicTIFF, exifResults},
+		{"bad-offset.tif", badOffsetTIFF, nil},
+		{"not-an-image.jpg", []byte("password=Hunter2024"), nil},
+	}
+
+	parser := NewImageMetadataParser(NewBinaryParser())
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, []string{"token"}, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestImageMetadataEveryTruncation 在每个长度截断图片，读取元数据时都不能 panic
+func TestImageMetadataEveryTruncation(t *testing.T) {
+	jpegData, pngData, tiffData := testJPEG(t), testPNG(t), testEXIF()
+	for n := 0; n < len(jpegData); n++ {
+		readJPEGMetadata(bytes.NewReader(jpegData[:n]))
+	}
+	for n := 0; n < len(pngData); n++ {
+		readPNGMetadata(bytes.NewReader(pngData[:n]))
+	}
+	for n := 0; n < len(tiffData); n++ {
+		readTIFFFields(bytes.NewReader(tiffData[:n]), int64(n))
+	}
+}
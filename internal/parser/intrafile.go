@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// intraFileBatchSize 文件内并发匹配时每个任务包含的字符串数
+const intraFileBatchSize = 256
+
+// maxPendingBatches 每个工作协程最多积压的未合并任务数，限制并发时的内存占用
+const maxPendingBatches = 4
+
+// orderedPool 文件内并发匹配的工作池（--intra-file-concurrency）
+// 任务的匹配部分在工作协程中并发执行，合并部分（结果数限制、偏移去重和输出）按提交顺序在调用方协程中执行，结果与依次扫描完全相同
+// 为 nil 时任务在调用方协程中依次执行
+type orderedPool struct {
+	jobs       chan *orderedJob
+	pending    []*orderedJob // 已提交、尚未合并的任务（按提交顺序）
+	maxPending int
+	workers    sync.WaitGroup
+	closed     bool
+}
+
+// orderedJob 工作池中的一个任务
+type orderedJob struct {
+	run      func() // 在工作协程中执行
+	merge    func() // 在调用方协程中按提交顺序执行
+	done     chan struct{}
+	panicked interface{} // run 中发生的 panic，合并时在调用方协程中重新抛出
+}
+
+// newOrderedPool 创建 workers 个工作协程的工作池，workers 不大于1时返回 nil
+func newOrderedPool(workers int) *orderedPool {
+	if workers <= 1 {
+		return nil
+	}
+	pool := &orderedPool{
+		jobs:       make(chan *orderedJob, workers),
+		maxPending: workers * maxPendingBatches,
+	}
+	for i := 0; i < workers; i++ {
+		pool.workers.Add(1)
+		go func() {
+			defer pool.workers.Done()
+			// 任务读取的可能是内存映射的文件，文件被截断时的内存访问错误转换为 panic，由调用方协程按文件错误处理
+			debug.SetPanicOnFault(true)
+			for job := range pool.jobs {
+				pool.execute(job)
+			}
+		}()
+	}
+	return pool
+}
+
+// execute 执行任务的匹配部分，panic 记录到任务中由调用方协程重新抛出
+func (p *orderedPool) execute(job *orderedJob) {
+	defer close(job.done)
+	defer func() {
+		if r := recover(); r != nil {
+			job.panicked = r
+		}
+	}()
+	job.run()
+}
+
+// submit 提交任务，并合并已完成的排在最前的任务；积压的任务过多时等待最早的任务完成
+func (p *orderedPool) submit(run, merge func()) {
+	if p == nil {
+		run()
+		merge()
+		return
+	}
+
+	job := &orderedJob{run: run, merge: merge, done: make(chan struct{})}
+	p.pending = append(p.pending, job)
+	p.jobs <- job
+
+	for len(p.pending) > 0 {
+		head := p.pending[0]
+		if len(p.pending) <= p.maxPending {
+			select {
+			case <-head.done:
+			default:
+				return
+			}
+		} else {
+			<-head.done
+		}
+		p.mergeHead()
+	}
+}
+
+// mergeHead 合并最早提交的任务（调用前该任务已完成）
+func (p *orderedPool) mergeHead() {
+	head := p.pending[0]
+	p.pending[0] = nil
+	p.pending = p.pending[1:]
+	if head.panicked != nil {
+		if isMemoryFault(head.panicked) {
+			panic(head.panicked)
+		}
+		panic(fmt.Sprintf("文件内并发匹配时发生异常: %v", head.panicked))
+	}
+	head.merge()
+}
+
+// wait 等待所有任务完成并按提交顺序合并，之后可以继续提交任务
+func (p *orderedPool) wait() {
+	if p == nil {
+		return
+	}
+	for len(p.pending) > 0 {
+		<-p.pending[0].done
+		p.mergeHead()
+	}
+}
+
+// stop 停止工作协程并等待其退出，在调用方发生 panic 时同样需要调用以免协程泄漏
+// 任务读取的数据可能是内存映射的文件，返回前丢弃尚未开始的任务并等待执行中的任务完成，调用方之后才能释放映射
+func (p *orderedPool) stop() {
+	if p == nil || p.closed {
+		return
+	}
+	p.closed = true
+	for drained := false; !drained; {
+		select {
+		case job := <-p.jobs:
+			close(job.done)
+		default:
+			drained = true
+		}
+	}
+	close(p.jobs)
+	p.workers.Wait()
+	p.pending = nil
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestOrderedPoolStopWaitsForWorkers 工作协程中发生 panic 时，stop 返回前所有读取映射内存的任务都已结束
+func TestOrderedPoolStopWaitsForWorkers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dump.bin")
+	if err := os.WriteFile(path, make([]byte, 1<<16), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var started, finished int32
+	scan := func() {
+		data, release, err := mapFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer release()
+		pool := newOrderedPool(4)
+		defer pool.stop()
+
+		for i := 0; i < 32; i++ {
+			i := i
+			pool.submit(func() {
+				if i == 0 {
+					panic("malformed input")
+				}
+				atomic.AddInt32(&started, 1)
+				time.Sleep(5 * time.Millisecond)
+				_ = data[len(data)-1]
+				atomic.AddInt32(&finished, 1)
+			}, func() {})
+		}
+		pool.wait()
+	}
+
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("worker panic was not re-raised in the caller")
+			}
+		}()
+		scan()
+	}()
+
+	if s, f := atomic.LoadInt32(&started), atomic.LoadInt32(&finished); s != f {
+		t.Errorf("%d jobs still running after stop (started %d, finished %d)", s-f, s, f)
+	}
+}
+
+// TestOrderedPoolOrder 并发执行的任务按提交顺序合并
+func TestOrderedPoolOrder(t *testing.T) {
+	pool := newOrderedPool(4)
+	defer pool.stop()
+
+	var merged []int
+	for i := 0; i < 100; i++ {
+		i := i
+		pool.submit(func() {
+			time.Sleep(time.Duration(i%3) * time.Millisecond)
+		}, func() {
+			merged = append(merged, i)
+		})
+	}
+	pool.wait()
+
+	for i, v := range merged {
+		if v != i {
+			t.Fatalf("merged[%d] = %d, want %d", i, v, i)
+		}
+	}
+	if len(merged) != 100 {
+		t.Fatalf("merged %d jobs, want 100", len(merged))
+	}
+}
@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"archive/zip"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+const (
+	JAVA_CLASS_MAGIC = 0xCAFEBABE
+
+	// maxJarEntrySize JAR中单个类文件的最大读取大小
+	maxJarEntrySize = 16 * 1024 * 1024
+)
+
+// 常量池标签
+const (
+	constantUtf8               = 1
+	constantInteger            = 3
+	constantFloat              = 4
+	constantLong               = 5
+	constantDouble             = 6
+	constantClass              = 7
+	constantString             = 8
+	constantFieldref           = 9
+	constantMethodref          = 10
+	constantInterfaceMethodref = 11
+	constantNameAndType        = 12
+	constantMethodHandle       = 15
+	constantMethodType         = 16
+	constantDynamic            = 17
+	constantInvokeDynamic      = 18
+	constantModule             = 19
+	constantPackage            = 20
+)
+
+// JavaClassParser Java类文件解析器（解析常量池中的字符串）
+type JavaClassParser struct {
+	binaryParser *BinaryParser
+}
+
+// NewJavaClassParser 创建Java类文件解析器
+func NewJavaClassParser(binaryParser *BinaryParser) *JavaClassParser {
+	return &JavaClassParser{
+		binaryParser: binaryParser,
+	}
+}
+
+// Parse 解析.class文件
+func (p *JavaClassParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取Java类文件%s错误", filePath)
+		return nil
+	}
+
+	results, err := p.ParseClass(data, keywords, verbose)
+	if err != nil {
+		logger.Debugf("解析Java类文件%s失败: %v", filePath, err)
+	}
+	return results
+}
+
+// ParseClass 解析类文件内容，检查常量池中的 CONSTANT_Utf8 条目
+func (p *JavaClassParser) ParseClass(data []byte, keywords []string, verbose bool) ([]string, error) {
+	var matchingLines []string
+
+	className, constants, err := readConstantPool(data)
+	if err != nil {
+		return nil, err
+	}
+
+	for index := 1; index < len(constants); index++ {
+		str := constants[index]
+		if len(str) < 4 {
+			continue
+		}
+
+		// 规则匹配
+		matched := false
+		for _, result := range p.binaryParser.MatchString(str) {
+			lineOutput := formatJavaResult(className, index, result.RuleName, result.RiskLevel, result.MatchedValue, str)
+			matchingLines = append(matchingLines, lineOutput)
+			matched = true
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+		if matched {
+			continue
+		}
+
+		// 关键字匹配
+		if keyword, ok := p.binaryParser.matcher.find(str, keywords); ok {
+			lineOutput := formatJavaResult(className, index, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, str)
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+	}
+
+	return matchingLines, nil
+}
+
+// readConstantPool 读取常量池，返回类名和按索引排列的 Utf8 常量（非 Utf8 条目为空字符串）
+func readConstantPool(data []byte) (string, []string, error) {
+	if len(data) < 10 || binary.BigEndian.Uint32(data[0:4]) != JAVA_CLASS_MAGIC {
+		return "", nil, fmt.Errorf("不是有效的Java类文件")
+	}
+
+	count := int(binary.BigEndian.Uint16(data[8:10]))
+	constants := make([]string, count)
+	classRefs := make(map[int]int) // CONSTANT_Class 索引 -> 名称索引
+	pos := 10
+
+	for index := 1; index < count; index++ {
+		if pos >= len(data) {
+			return "", nil, fmt.Errorf("常量池被截断")
+		}
+		tag := data[pos]
+		pos++
+
+		size := 0
+		switch tag {
+		case constantUtf8:
+			if pos+2 > len(data) {
+				return "", nil, fmt.Errorf("常量池被截断")
+			}
+			length := int(binary.BigEndian.Uint16(data[pos : pos+2]))
+			pos += 2
+			if pos+length > len(data) {
+				return "", nil, fmt.Errorf("常量池被截断")
+			}
+			constants[index] = string(data[pos : pos+length])
+			size = length
+		case constantClass:
+			if pos+2 > len(data) {
+				return "", nil, fmt.Errorf("常量池被截断")
+			}
+			classRefs[index] = int(binary.BigEndian.Uint16(data[pos : pos+2]))
+			size = 2
+		case constantString, constantMethodType, constantModule, constantPackage:
+			size = 2
+		case constantMethodHandle:
+			size = 3
+		case constantInteger, constantFloat, constantFieldref, constantMethodref,
+			constantInterfaceMethodref, constantNameAndType, constantDynamic, constantInvokeDynamic:
+			size = 4
+		case constantLong, constantDouble:
+			// 8字节常量占用两个索引
+			size = 8
+			index++
+		default:
+			return "", nil, fmt.Errorf("未知的常量池标签: %d", tag)
+		}
+		pos += size
+	}
+
+	// access_flags 之后为 this_class
+	className := ""
+	if pos+4 <= len(data) {
+		thisClass := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if nameIndex, ok := classRefs[thisClass]; ok && nameIndex < len(constants) {
+			className = strings.ReplaceAll(constants[nameIndex], "/", ".")
+		}
+	}
+
+	return className, constants, nil
+}
+
+// JarParser JAR文件解析器，逐个解析其中的类文件
+type JarParser struct {
+	classParser *JavaClassParser
+	pool        EntryPool // 类文件并发扫描的工作池（为 nil 时依次扫描）
+}
+
+// NewJarParser 创建JAR解析器
+func NewJarParser(classParser *JavaClassParser) *JarParser {
+	return &JarParser{
+		classParser: classParser,
+	}
+}
+
+// Parse 解析.jar文件
+func (p *JarParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	var matchingLines []string
+
+	reader, err := zip.OpenReader(filePath)
+	if err != nil {
+		logger.Warnf("打开JAR文件%s错误", filePath)
+		return matchingLines
+	}
+	defer reader.Close()
+
+	var entries []*zip.File
+	for _, entry := range reader.File {
+		if strings.HasSuffix(entry.Name, ".class") && entry.UncompressedSize64 <= maxJarEntrySize {
+			entries = append(entries, entry)
+		}
+	}
+
+	return scanEntries(p.pool, filePath, entries, func(entry *zip.File) []string {
+		data, err := readZipEntry(entry)
+		if err != nil {
+			logger.Debugf("读取JAR条目%s失败: %v", entry.Name, err)
+			return nil
+		}
+
+		results, err := p.classParser.ParseClass(data, keywords, verbose)
+		if err != nil {
+			logger.Debugf("解析JAR条目%s失败: %v", entry.Name, err)
+			return nil
+		}
+		return results
+	})
+}
+
+// readZipEntry 读取压缩包条目内容
+func readZipEntry(entry *zip.File) ([]byte, error) {
+	rc, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(io.LimitReader(rc, maxJarEntrySize))
+}
+
+// formatJavaResult 格式化Java常量池扫描结果
+func formatJavaResult(className string, index int, ruleName, riskLevel, matchedValue, constant string) string {
+	return fmt.Sprintf("JAVA|%s|%d|%s|%s|%s|%s", className, index, ruleName, riskLevel, matchedValue, constant)
+}
@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"Findx/internal/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// k8sManifest Kubernetes 清单中与 Secret 相关的字段
+type k8sManifest struct {
+	Kind     string `yaml:"kind"`
+	Metadata struct {
+		Name      string `yaml:"name"`
+		Namespace string `yaml:"namespace"`
+	} `yaml:"metadata"`
+	Data       map[string]string `yaml:"data"`
+	StringData map[string]string `yaml:"stringData"`
+	Items      []k8sManifest     `yaml:"items"` // kind: List
+}
+
+// K8sSecretParser Kubernetes Secret 清单解析器，解码 data 中的 base64 值后扫描
+type K8sSecretParser struct {
+	binaryParser *BinaryParser
+}
+
+// NewK8sSecretParser 创建 Kubernetes Secret 解析器
+func NewK8sSecretParser(binaryParser *BinaryParser) *K8sSecretParser {
+	return &K8sSecretParser{
+		binaryParser: binaryParser,
+	}
+}
+
+// Parse 解析YAML/JSON清单文件，非 Secret 清单返回空结果
+func (p *K8sSecretParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取文件%s错误", filePath)
+		return nil
+	}
+
+	// 快速判断，避免对普通配置文件做完整解析
+	if !bytes.Contains(data, []byte("Secret")) {
+		return nil
+	}
+
+	var matchingLines []string
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var manifest k8sManifest
+		err := decoder.Decode(&manifest)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.Debugf("解析清单%s失败: %v", filePath, err)
+			break
+		}
+		matchingLines = append(matchingLines, p.checkManifest(&manifest, keywords)...)
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// checkManifest 检查单个清单（包括 List 中的条目）
+func (p *K8sSecretParser) checkManifest(manifest *k8sManifest, keywords []string) []string {
+	var results []string
+	for i := range manifest.Items {
+		results = append(results, p.checkManifest(&manifest.Items[i], keywords)...)
+	}
+
+	if manifest.Kind != "Secret" {
+		return results
+	}
+
+	name := manifest.Metadata.Name
+	if manifest.Metadata.Namespace != "" {
+		name = manifest.Metadata.Namespace + "/" + name
+	}
+
+	for _, key := range sortedKeys(manifest.Data) {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(manifest.Data[key]))
+		if err != nil {
+			logger.Debugf("Secret %s 的 %s 不是有效的base64", name, key)
+			continue
+		}
+		results = append(results, p.checkEntry(name+".data."+key, key, string(decoded), keywords)...)
+	}
+	for _, key := range sortedKeys(manifest.StringData) {
+		results = append(results, p.checkEntry(name+".stringData."+key, key, manifest.StringData[key], keywords)...)
+	}
+
+	return results
+}
+
+// checkEntry 检查单个 Secret 条目，以 "键=值" 的形式匹配规则和关键字
+func (p *K8sSecretParser) checkEntry(location, key, value string, keywords []string) []string {
+	var results []string
+	candidate := key + "=" + value
+
+	for _, result := range p.binaryParser.MatchString(candidate) {
+		results = append(results, formatK8sResult(location, result.RuleName, result.RiskLevel, "", result.MatchedValue, value))
+	}
+	if len(results) > 0 {
+		return results
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(candidate, keywords); ok {
+		return []string{formatK8sResult(location, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, value, value)}
+	}
+
+	return nil
+}
+
+// sortedKeys 获取排序后的键，保证结果顺序稳定
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatK8sResult 格式化 Kubernetes Secret 扫描结果
+func formatK8sResult(location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	return fmt.Sprintf("K8S|%s|%s|%s|%s|%s|%s", location, ruleName, riskLevel, keyword, matchedValue, content)
+}
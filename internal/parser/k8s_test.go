@@ -0,0 +1,111 @@
+package parser
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// b64 base64 编码 Secret 的 data 值
+func b64(s string) string {
+	return base64.StdEncoding.EncodeToString([]byte(s))
+}
+
+// k8sTestManifests 多文档清单：带命名空间的 Secret（data 和 stringData）、ConfigMap 和引用 Secret 的 Deployment
+var k8sTestManifests = `apiVersion: v1
+kind: Secret
+metadata:
+  name: db-credentials
+  namespace: prod
+type: Opaque
+data:
+  username: ` + b64("dbadmin") + `
+  password: ` + b64("Pr0d-Db-Pass!") + `
+  url: ` + b64("jdbc:postgresql://10.0.0.12:5432/app") + `
+  broken: not*base64
+stringData:
+  api_key: AKIA1234567890ABCDEFGH
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app-config
+data:
+  password: ` + b64("NotDecoded1") + `
+  SECRET_NAME: db-credentials
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          envFrom:
+            - secretRef:
+                name: db-credentials
+`
+
+// k8sTestList kubectl get secrets -o json 输出的 List
+var k8sTestList = `{
+  "apiVersion": "v1",
+  "kind": "List",
+  "items": [
+    {"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "tls"}, "data": {"token": "` + b64("secret token value") + `"}},
+    {"apiVersion": "v1", "kind": "ConfigMap", "metadata": {"name": "settings"}, "data": {"token": "plain"}}
+  ]
+}`
+
+func TestK8sSecretParser(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			file:    "manifests.yaml",
+			content: k8sTestManifests,
+			want: []string{
+				"K8S|prod/db-credentials.data.password|密码字段|critical||Pr0d-Db-Pass!|Pr0d-Db-Pass!",
+				"K8S|prod/db-credentials.data.url|JDBC连接URL|high||jdbc:postgresql://10.0.0.12:5432/app|jdbc:postgresql://10.0.0.12:5432/app",
+				"K8S|prod/db-credentials.data.username|用户名字段|high||dbadmin|dbadmin",
+				"K8S|prod/db-credentials.stringData.api_key|API密钥|critical||AKIA1234567890ABCDEFGH|AKIA1234567890ABCDEFGH",
+			},
+		},
+		{
+			file:    "secrets.json",
+			content: k8sTestList,
+			want:    []string{"K8S|tls.data.token|关键字匹配|medium|token|secret token value|secret token value"},
+		},
+		{
+			// 后续文档格式错误时保留之前文档的结果
+			file:    "truncated.yaml",
+			content: "kind: Secret\nmetadata:\n  name: ci\ndata:\n  token: " + b64("ci-token-1") + "\n---\nkind: [Secret\n",
+			want:    []string{"K8S|ci.data.token|关键字匹配|medium|token|ci-token-1|ci-token-1"},
+		},
+		{
+			// ConfigMap 的值是明文，由 YAML 解析器扫描，这里不解码
+			file:    "configmap.yaml",
+			content: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: Secret-names\ndata:\n  password: " + b64("NotDecoded1") + "\n",
+		},
+		{file: "values.yaml", content: "replicas: 2\npassword: plain\n"},
+	}
+
+	parser := NewK8sSecretParser(NewBinaryParser())
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, []string{"token"}, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
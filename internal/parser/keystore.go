@@ -0,0 +1,515 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"Findx/internal/logger"
+)
+
+// 密钥库结果的规则名称
+const (
+	KeystorePrivateKeyRuleName  = "密钥库私钥"
+	KeystoreCertificateRuleName = "密钥库证书"
+	KeystoreLockedRuleName      = "加密密钥库"
+)
+
+// 密钥库文件头
+const (
+	jksMagic   = 0xFEEDFEED
+	jceksMagic = 0xCECECECE
+)
+
+// jksDigestWhitener JKS/JCEKS 完整性摘要中口令之后附加的固定字符串
+const jksDigestWhitener = "Mighty Aphrodite"
+
+// DefaultKeystorePasswords 未指定或指定的口令不正确时尝试的常见默认口令
+var DefaultKeystorePasswords = []string{"changeit", "changeme", "password", "123456", "secret", ""}
+
+// keystoreExtensions 密钥库文件的扩展名
+var keystoreExtensions = []string{".jks", ".jceks", ".keystore", ".p12", ".pfx"}
+
+// IsKeystoreFile 按扩展名判断是否为 Java 密钥库或 PKCS#12 文件
+func IsKeystoreFile(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	for _, ext := range keystoreExtensions {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// keystoreEntry 密钥库中的一个条目
+type keystoreEntry struct {
+	Alias      string
+	PrivateKey bool     // 私钥条目（证书链中第一张为对应的证书）
+	Secret     bool     // JCEKS 中的对称密钥条目
+	Encrypted  bool     // 私钥是否加密保存（PKCS#12 中未加密的 keyBag 为 false）
+	Certs      [][]byte // DER 编码的证书
+}
+
+// keystore 解析后的密钥库
+type keystore struct {
+	Format   string
+	Count    int // 条目数（别名数）
+	Entries  []keystoreEntry
+	Password *string // 验证通过的口令，未知时为 nil
+	Partial  bool    // 口令未知时只能列出部分条目
+}
+
+// KeystoreParser Java 密钥库（JKS/JCEKS）和 PKCS#12（.p12/.pfx）解析器，列出其中的私钥和证书
+// 依次尝试 --keystore-password 指定的口令和常见默认口令，口令未知时仍报告文件和条目数
+type KeystoreParser struct {
+	password string // --keystore-password，为空时只尝试默认口令
+}
+
+// NewKeystoreParser 创建密钥库解析器
+func NewKeystoreParser(password string) *KeystoreParser {
+	return &KeystoreParser{password: password}
+}
+
+// candidates 获取依次尝试的口令
+func (p *KeystoreParser) candidates() []string {
+	if p.password == "" {
+		return DefaultKeystorePasswords
+	}
+	return append([]string{p.password}, DefaultKeystorePasswords...)
+}
+
+// Parse 解析密钥库文件，不是可识别的密钥库格式时返回 nil 由调用方按普通文件处理
+func (p *KeystoreParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取文件%s错误", filePath)
+		return nil
+	}
+
+	var ks *keystore
+	if len(data) >= 4 {
+		switch binary.BigEndian.Uint32(data) {
+		case jksMagic:
+			ks, err = p.parseJKS(data, "JKS")
+		case jceksMagic:
+			ks, err = p.parseJKS(data, "JCEKS")
+		default:
+			ks, err = p.parsePKCS12(data)
+		}
+	}
+	if ks == nil {
+		logger.Debugf("不是可识别的密钥库，按普通文件扫描: %s（%v）", filePath, err)
+		return nil
+	}
+	if err != nil {
+		logger.Debugf("密钥库%s解析不完整: %v", filePath, err)
+	}
+
+	matchingLines := p.results(ks)
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+	return matchingLines
+}
+
+// results 生成密钥库的扫描结果：私钥为严重，单独的证书为低危，口令未知时追加一条加密密钥库结果
+func (p *KeystoreParser) results(ks *keystore) []string {
+	var results []string
+
+	passwordNote := "口令: 未知"
+	if ks.Password != nil {
+		switch {
+		case p.password != "" && *ks.Password == p.password:
+			passwordNote = "口令: 已通过 --keystore-password 验证"
+		case *ks.Password == "":
+			passwordNote = "口令: 空口令"
+		default:
+			passwordNote = fmt.Sprintf("口令: 默认口令 %q", *ks.Password)
+		}
+	}
+
+	for _, entry := range ks.Entries {
+		location := "别名 " + entry.Alias
+		details := []string{"格式: " + ks.Format}
+		switch {
+		case entry.PrivateKey:
+			if len(entry.Certs) > 0 {
+				details = append(details, describeCertificate(entry.Certs[0]))
+				details = append(details, fmt.Sprintf("证书链: %d 张", len(entry.Certs)))
+			}
+			if !entry.Encrypted {
+				details = append(details, "私钥未加密")
+			}
+			details = append(details, passwordNote)
+			results = append(results, formatKeystoreResult(location, KeystorePrivateKeyRuleName, "critical", entry.Alias, strings.Join(details, "; ")))
+		case entry.Secret:
+			details = append(details, "对称密钥", passwordNote)
+			results = append(results, formatKeystoreResult(location, KeystorePrivateKeyRuleName, "critical", entry.Alias, strings.Join(details, "; ")))
+		default:
+			for _, cert := range entry.Certs {
+				results = append(results, formatKeystoreResult(location, KeystoreCertificateRuleName, "low", entry.Alias, strings.Join(append(details, describeCertificate(cert)), "; ")))
+			}
+		}
+	}
+
+	if ks.Password == nil {
+		aliases := make([]string, 0, len(ks.Entries))
+		for _, entry := range ks.Entries {
+			aliases = append(aliases, entry.Alias)
+		}
+		details := []string{
+			"格式: " + ks.Format,
+			"口令未知（已尝试 --keystore-password 和常见默认口令）",
+		}
+		if len(aliases) > 0 {
+			details = append(details, "别名: "+strings.Join(aliases, ", "))
+		}
+		if ks.Partial {
+			details = append(details, "其余条目已加密")
+		}
+		results = append(results, formatKeystoreResult("密钥库", KeystoreLockedRuleName, "high", fmt.Sprintf("%d 个条目", ks.Count), strings.Join(details, "; ")))
+	}
+
+	return results
+}
+
+// describeCertificate 获取证书的主体、颁发者和有效期
+func describeCertificate(der []byte) string {
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return "证书无法解析"
+	}
+	validity := "有效期至 " + cert.NotAfter.Format("2006-01-02")
+	if time.Now().After(cert.NotAfter) {
+		validity += "（已过期）"
+	}
+	return fmt.Sprintf("主体: %s; 颁发者: %s; %s", cert.Subject, cert.Issuer, validity)
+}
+
+// formatKeystoreResult 格式化密钥库扫描结果
+func formatKeystoreResult(location, ruleName, riskLevel, matchedValue, content string) string {
+	return fmt.Sprintf("KEYSTORE|%s|%s|%s||%s|%s", strings.ReplaceAll(location, "|", "/"), ruleName, riskLevel, matchedValue, content)
+}
+
+// jksReader 按 Java DataOutputStream 格式读取 JKS/JCEKS 内容
+type jksReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+// next 读取 n 个字节，超出数据范围时记录错误并返回 nil
+func (r *jksReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || n > len(r.data)-r.pos {
+		r.err = errors.New("数据不完整")
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+// uint32 读取大端序 32 位整数
+func (r *jksReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+// utf 读取以 2 字节长度开头的字符串（Java modified UTF-8，别名和证书类型通常为 ASCII）
+func (r *jksReader) utf() string {
+	b := r.next(2)
+	if b == nil {
+		return ""
+	}
+	return string(r.next(int(binary.BigEndian.Uint16(b))))
+}
+
+// parseJKS 解析 JKS/JCEKS 密钥库，条目结构不加密，口令只用于校验完整性摘要和解密私钥
+func (p *KeystoreParser) parseJKS(data []byte, format string) (*keystore, error) {
+	r := &jksReader{data: data}
+	r.uint32() // 文件头
+	version := r.uint32()
+	ks := &keystore{Format: format, Count: int(r.uint32())}
+	if r.err != nil || version < 1 || version > 2 {
+		return nil, fmt.Errorf("无效的%s文件头", format)
+	}
+
+	readCert := func() []byte {
+		if version == 2 {
+			r.utf() // 证书类型，如 X.509
+		}
+		return r.next(int(r.uint32()))
+	}
+
+	for i := 0; i < ks.Count && r.err == nil; i++ {
+		tag := r.uint32()
+		entry := keystoreEntry{Alias: r.utf()}
+		r.next(8) // 创建时间
+		switch tag {
+		case 1:
+			entry.PrivateKey = true
+			entry.Encrypted = true
+			r.next(int(r.uint32()))
+			chain := int(r.uint32())
+			for j := 0; j < chain && r.err == nil; j++ {
+				if cert := readCert(); cert != nil {
+					entry.Certs = append(entry.Certs, cert)
+				}
+			}
+		case 2:
+			if cert := readCert(); cert != nil {
+				entry.Certs = append(entry.Certs, cert)
+			}
+		case 3:
+			// JCEKS 的对称密钥条目是 Java 序列化对象，无法确定长度，之后的条目不再读取
+			entry.Secret = true
+			ks.Entries = append(ks.Entries, entry)
+			ks.Partial = i < ks.Count-1
+			r.err = errors.New("对称密钥条目之后的条目未读取")
+			continue
+		default:
+			return ks, fmt.Errorf("未知的条目类型 %d", tag)
+		}
+		if r.err == nil {
+			ks.Entries = append(ks.Entries, entry)
+		}
+	}
+
+	if len(data) >= sha1.Size {
+		body, digest := data[:len(data)-sha1.Size], data[len(data)-sha1.Size:]
+		for _, password := range p.candidates() {
+			if bytes.Equal(jksDigest(body, password), digest) {
+				password := password
+				ks.Password = &password
+				break
+			}
+		}
+	}
+	return ks, r.err
+}
+
+// jksDigest 计算 JKS/JCEKS 的完整性摘要: SHA1(口令的 UTF-16BE 编码 + "Mighty Aphrodite" + 内容)
+func jksDigest(body []byte, password string) []byte {
+	h := sha1.New()
+	for _, u := range utf16.Encode([]rune(password)) {
+		h.Write([]byte{byte(u >> 8), byte(u)})
+	}
+	h.Write([]byte(jksDigestWhitener))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// pkcs12Bag 解析出的 PKCS#12 容器
+type pkcs12Bag struct {
+	alias      string
+	localKeyID string
+	key        bool
+	encrypted  bool
+	cert       []byte
+}
+
+// parsePKCS12 解析 PKCS#12 文件：用口令校验 MAC，并解密加密的内容以列出私钥和证书
+// 口令未知时只能列出未加密部分中的容器（通常私钥以加密形式保存在未加密部分，证书在加密部分）
+func (p *KeystoreParser) parsePKCS12(data []byte) (*keystore, error) {
+	var pfx pfxPdu
+	if rest, err := asn1.Unmarshal(data, &pfx); err != nil || len(rest) > 0 || pfx.Version != 3 {
+		return nil, errors.New("不是有效的PKCS#12文件")
+	}
+	if !pfx.AuthSafe.ContentType.Equal(oidDataContentType) {
+		return nil, errors.New("不支持公钥保护的PKCS#12文件")
+	}
+	authSafeData, err := contentOctets(pfx.AuthSafe.Content)
+	if err != nil {
+		return nil, err
+	}
+	var authSafe []contentInfo
+	if _, err := asn1.Unmarshal(authSafeData, &authSafe); err != nil {
+		return nil, err
+	}
+
+	ks := &keystore{Format: "PKCS#12"}
+	candidates := p.candidates()
+	if len(pfx.MacData.MacSalt) > 0 {
+		for _, password := range candidates {
+			if verifyPKCS12Mac(&pfx.MacData, authSafeData, password) {
+				password := password
+				ks.Password = &password
+				candidates = []string{password}
+				break
+			}
+		}
+		if ks.Password == nil {
+			candidates = nil
+		}
+	}
+
+	var bags []pkcs12Bag
+	var firstErr error
+	for _, ci := range authSafe {
+		switch {
+		case ci.ContentType.Equal(oidDataContentType):
+			content, err := contentOctets(ci.Content)
+			if err == nil {
+				bags, err = appendSafeBags(bags, content)
+			}
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		case ci.ContentType.Equal(oidEncryptedDataContentType):
+			content, password, err := decryptSafeContents(ci, candidates)
+			if err != nil {
+				ks.Partial = true
+				if !errors.Is(err, errWrongPassword) && firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			// 没有 MAC 的文件以能否解密确定口令
+			if ks.Password == nil {
+				ks.Password = &password
+				candidates = []string{password}
+			}
+			if bags, err = appendSafeBags(bags, content); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	ks.Entries = groupPKCS12Bags(bags)
+	ks.Count = len(ks.Entries)
+	return ks, firstErr
+}
+
+// errWrongPassword 所有候选口令都无法解密
+var errWrongPassword = errors.New("口令不正确")
+
+// decryptSafeContents 依次用候选口令解密加密的 SafeContents，返回明文和使用的口令
+func decryptSafeContents(ci contentInfo, candidates []string) ([]byte, string, error) {
+	var ed encryptedData
+	if _, err := asn1.Unmarshal(ci.Content.Bytes, &ed); err != nil {
+		return nil, "", err
+	}
+	info := ed.EncryptedContentInfo
+	for _, password := range candidates {
+		plaintext, err := decryptPKCS12(info.ContentEncryptionAlgorithm, info.EncryptedContent, password)
+		if errors.Is(err, errUnsupportedAlgorithm) {
+			return nil, "", err
+		}
+		if err != nil {
+			continue
+		}
+		// 填充偶然正确时内容不是有效的 ASN.1 结构
+		var probe []asn1.RawValue
+		if _, err := asn1.Unmarshal(plaintext, &probe); err == nil {
+			return plaintext, password, nil
+		}
+	}
+	return nil, "", errWrongPassword
+}
+
+// appendSafeBags 解析 SafeContents 中的私钥和证书容器
+func appendSafeBags(bags []pkcs12Bag, content []byte) ([]pkcs12Bag, error) {
+	var safeBags []safeBag
+	if _, err := asn1.Unmarshal(content, &safeBags); err != nil {
+		return bags, err
+	}
+
+	for _, sb := range safeBags {
+		bag := pkcs12Bag{}
+		for _, attr := range sb.Attributes {
+			var value asn1.RawValue
+			if _, err := asn1.Unmarshal(attr.Value.Bytes, &value); err != nil {
+				continue
+			}
+			switch {
+			case attr.ID.Equal(oidFriendlyName) && value.Tag == asn1.TagBMPString:
+				bag.alias = decodeBMPString(value.Bytes)
+			case attr.ID.Equal(oidLocalKeyID):
+				bag.localKeyID = hex.EncodeToString(value.Bytes)
+			}
+		}
+
+		switch {
+		case sb.ID.Equal(oidKeyBag):
+			bag.key = true
+		case sb.ID.Equal(oidPKCS8ShroudedKeyBag):
+			bag.key = true
+			bag.encrypted = true
+		case sb.ID.Equal(oidCertBag):
+			var cb certBag
+			if _, err := asn1.Unmarshal(sb.Value.Bytes, &cb); err != nil || !cb.ID.Equal(oidX509Certificate) {
+				continue
+			}
+			bag.cert = cb.Data
+		case sb.ID.Equal(oidSecretBag):
+			bag.key = true
+			bag.encrypted = true
+		default:
+			continue
+		}
+		bags = append(bags, bag)
+	}
+	return bags, nil
+}
+
+// groupPKCS12Bags 将私钥与 localKeyId 或别名相同的证书归为一个条目，其余证书单独成为条目
+func groupPKCS12Bags(bags []pkcs12Bag) []keystoreEntry {
+	var entries []keystoreEntry
+	used := make([]bool, len(bags))
+	sameEntry := func(a, b pkcs12Bag) bool {
+		if a.localKeyID != "" && b.localKeyID != "" {
+			return a.localKeyID == b.localKeyID
+		}
+		return a.alias != "" && a.alias == b.alias
+	}
+
+	for i, key := range bags {
+		if !key.key {
+			continue
+		}
+		entry := keystoreEntry{Alias: key.alias, PrivateKey: true, Encrypted: key.encrypted}
+		for j, cert := range bags {
+			if cert.cert != nil && !used[j] && sameEntry(key, cert) {
+				entry.Certs = append(entry.Certs, cert.cert)
+				used[j] = true
+				if entry.Alias == "" {
+					entry.Alias = cert.alias
+				}
+			}
+		}
+		used[i] = true
+		entries = append(entries, entry)
+	}
+	for i, bag := range bags {
+		if !used[i] && bag.cert != nil {
+			entries = append(entries, keystoreEntry{Alias: bag.alias, Certs: [][]byte{bag.cert}})
+		}
+	}
+
+	for i := range entries {
+		if entries[i].Alias == "" {
+			entries[i].Alias = fmt.Sprintf("#%d", i+1)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].PrivateKey && !entries[j].PrivateKey
+	})
+	return entries
+}
@@ -0,0 +1,294 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+// testKeystorePassword 用例中的非默认口令
+const testKeystorePassword = "S3cret!Store"
+
+// testCertificate 生成自签名证书（DER 编码）
+func testCertificate(t *testing.T, commonName string) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der
+}
+
+// buildJKS 构造包含一个私钥条目（alias server）和一个受信任证书条目（alias ca）的 JKS 文件
+func buildJKS(password string, serverCert, caCert []byte) []byte {
+	var buf bytes.Buffer
+	writeUint32 := func(v uint32) { binary.Write(&buf, binary.BigEndian, v) }
+	writeUTF := func(s string) {
+		binary.Write(&buf, binary.BigEndian, uint16(len(s)))
+		buf.WriteString(s)
+	}
+	writeCert := func(der []byte) {
+		writeUTF("X.509")
+		writeUint32(uint32(len(der)))
+		buf.Write(der)
+	}
+
+	writeUint32(jksMagic)
+	writeUint32(2)
+	writeUint32(2)
+
+	writeUint32(1)
+	writeUTF("server")
+	buf.Write(make([]byte, 8))
+	encryptedKey := bytes.Repeat([]byte{0x5A}, 48) // 加密的私钥，解析时不解密
+	writeUint32(uint32(len(encryptedKey)))
+	buf.Write(encryptedKey)
+	writeUint32(1)
+	writeCert(serverCert)
+
+	writeUint32(2)
+	writeUTF("ca")
+	buf.Write(make([]byte, 8))
+	writeCert(caCert)
+
+	buf.Write(jksDigest(buf.Bytes(), password))
+	return buf.Bytes()
+}
+
+// explicitContent 构造 [0] 显式标签的内容
+func explicitContent(inner []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner}
+}
+
+// mustMarshal 编码 ASN.1 结构，失败时 panic（仅用于构造用例）
+func mustMarshal(v interface{}) []byte {
+	der, err := asn1.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return der
+}
+
+// dataContentInfo 构造 data 类型的 ContentInfo
+func dataContentInfo(content []byte) contentInfo {
+	return contentInfo{ContentType: oidDataContentType, Content: explicitContent(mustMarshal(content))}
+}
+
+// bagAttributes 构造 localKeyId 和 friendlyName 属性
+func bagAttributes(alias string) []pkcs12Attribute {
+	var name []byte
+	for _, u := range utf16.Encode([]rune(alias)) {
+		name = append(name, byte(u>>8), byte(u))
+	}
+	set := func(v asn1.RawValue) asn1.RawValue {
+		return asn1.RawValue{Tag: asn1.TagSet, IsCompound: true, Bytes: mustMarshal(v)}
+	}
+	return []pkcs12Attribute{
+		{ID: oidLocalKeyID, Value: set(asn1.RawValue{Tag: asn1.TagOctetString, Bytes: []byte{1}})},
+		{ID: oidFriendlyName, Value: set(asn1.RawValue{Tag: asn1.TagBMPString, Bytes: name})},
+	}
+}
+
+// encryptPBES2 用 PBES2（PBKDF2-HMAC-SHA256 + AES-256-CBC）加密 SafeContents，与 OpenSSL 3 的默认算法相同
+func encryptPBES2(plaintext []byte, password string) contentInfo {
+	salt := bytes.Repeat([]byte{0x11}, 8)
+	iv := bytes.Repeat([]byte{0x22}, aes.BlockSize)
+	kdf := pbkdf2Params{
+		Salt:       salt,
+		Iterations: 2048,
+		PRF:        pkix.AlgorithmIdentifier{Algorithm: oidHMACWithSHA256, Parameters: asn1.NullRawValue},
+	}
+	params := pbes2Params{
+		KeyDerivationFunc: pkix.AlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: mustMarshal(kdf)}},
+		EncryptionScheme:  pkix.AlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: mustMarshal(iv)}},
+	}
+
+	pad := aes.BlockSize - len(plaintext)%aes.BlockSize
+	padded := append(append([]byte(nil), plaintext...), bytes.Repeat([]byte{byte(pad)}, pad)...)
+	block, _ := aes.NewCipher(pbkdf2Key(sha256.New, []byte(password), salt, kdf.Iterations, 32))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+
+	ed := encryptedData{
+		EncryptedContentInfo: encryptedContentInfo{
+			ContentType:                oidDataContentType,
+			ContentEncryptionAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: mustMarshal(params)}},
+			EncryptedContent:           padded,
+		},
+	}
+	return contentInfo{ContentType: oidEncryptedDataContentType, Content: explicitContent(mustMarshal(ed))}
+}
+
+// buildPKCS12 构造与 openssl pkcs12 -export 结构相同的 PKCS#12 文件：
+// 加密的私钥在未加密部分，证书在口令加密的部分，MAC 使用 SHA-256
+func buildPKCS12(password string, cert []byte) []byte {
+	// EncryptedPrivateKeyInfo，解析时不解密私钥，密文不需要有效
+	shroudedKey := mustMarshal(struct {
+		Algorithm     pkix.AlgorithmIdentifier
+		EncryptedData []byte
+	}{
+		Algorithm:     pkix.AlgorithmIdentifier{Algorithm: oidPBEWithSHAAnd3KeyTripleDESCBC, Parameters: asn1.RawValue{FullBytes: mustMarshal(pbeParams{Salt: bytes.Repeat([]byte{0x44}, 8), Iterations: 2048})}},
+		EncryptedData: bytes.Repeat([]byte{0x5A}, 48),
+	})
+	keyBags := mustMarshal([]safeBag{{
+		ID:         oidPKCS8ShroudedKeyBag,
+		Value:      explicitContent(shroudedKey),
+		Attributes: bagAttributes("server"),
+	}})
+	certBags := mustMarshal([]safeBag{{
+		ID:         oidCertBag,
+		Value:      explicitContent(mustMarshal(certBag{ID: oidX509Certificate, Data: cert})),
+		Attributes: bagAttributes("server"),
+	}})
+
+	authSafe := mustMarshal([]contentInfo{encryptPBES2(certBags, password), dataContentInfo(keyBags)})
+
+	salt := bytes.Repeat([]byte{0x33}, 8)
+	mac := hmac.New(sha256.New, pkcs12KDF(sha256.New, salt, bmpPassword(password), 2048, 3, sha256.Size))
+	mac.Write(authSafe)
+
+	return mustMarshal(pfxPdu{
+		Version:  3,
+		AuthSafe: dataContentInfo(authSafe),
+		MacData: macData{
+			Mac:        digestInfo{Algorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue}, Digest: mac.Sum(nil)},
+			MacSalt:    salt,
+			Iterations: 2048,
+		},
+	})
+}
+
+func TestKeystoreParser(t *testing.T) {
+	serverCert := testCertificate(t, "server.corp.local")
+	caCert := testCertificate(t, "Corp Root CA")
+	const (
+		serverSubject = "主体: CN=server.corp.local; 颁发者: CN=server.corp.local; 有效期至 2099-01-01"
+		caSubject     = "主体: CN=Corp Root CA; 颁发者: CN=Corp Root CA; 有效期至 2099-01-01"
+	)
+
+	tests := []struct {
+		name     string
+		file     string
+		data     []byte
+		password string // --keystore-password
+		want     []string
+	}{
+		{
+			name: "JKS empty password",
+			file: "empty.jks",
+			data: buildJKS("", serverCert, caCert),
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: JKS; " + serverSubject + "; 证书链: 1 张; 口令: 空口令",
+				"KEYSTORE|别名 ca|密钥库证书|low||ca|格式: JKS; " + caSubject,
+			},
+		},
+		{
+			name: "JKS default password",
+			file: "default.keystore",
+			data: buildJKS("changeit", serverCert, caCert),
+			want: []string{
+				`KEYSTORE|别名 server|密钥库私钥|critical||server|格式: JKS; ` + serverSubject + `; 证书链: 1 张; 口令: 默认口令 "changeit"`,
+				"KEYSTORE|别名 ca|密钥库证书|low||ca|格式: JKS; " + caSubject,
+			},
+		},
+		{
+			name:     "JKS correct password",
+			file:     "correct.jks",
+			data:     buildJKS(testKeystorePassword, serverCert, caCert),
+			password: testKeystorePassword,
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: JKS; " + serverSubject + "; 证书链: 1 张; 口令: 已通过 --keystore-password 验证",
+				"KEYSTORE|别名 ca|密钥库证书|low||ca|格式: JKS; " + caSubject,
+			},
+		},
+		{
+			// JKS 的条目结构不加密，口令不正确时仍列出条目
+			name:     "JKS wrong password",
+			file:     "wrong.jks",
+			data:     buildJKS(testKeystorePassword, serverCert, caCert),
+			password: "not-the-password",
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: JKS; " + serverSubject + "; 证书链: 1 张; 口令: 未知",
+				"KEYSTORE|别名 ca|密钥库证书|low||ca|格式: JKS; " + caSubject,
+				"KEYSTORE|密钥库|加密密钥库|high||2 个条目|格式: JKS; 口令未知（已尝试 --keystore-password 和常见默认口令）; 别名: server, ca",
+			},
+		},
+		{
+			name: "PKCS#12 empty password",
+			file: "empty.p12",
+			data: buildPKCS12("", serverCert),
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: PKCS#12; " + serverSubject + "; 证书链: 1 张; 口令: 空口令",
+			},
+		},
+		{
+			name:     "PKCS#12 correct password",
+			file:     "correct.pfx",
+			data:     buildPKCS12(testKeystorePassword, serverCert),
+			password: testKeystorePassword,
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: PKCS#12; " + serverSubject + "; 证书链: 1 张; 口令: 已通过 --keystore-password 验证",
+			},
+		},
+		{
+			// 证书在加密部分，口令不正确时只能列出私钥容器
+			name:     "PKCS#12 wrong password",
+			file:     "wrong.p12",
+			data:     buildPKCS12(testKeystorePassword, serverCert),
+			password: "not-the-password",
+			want: []string{
+				"KEYSTORE|别名 server|密钥库私钥|critical||server|格式: PKCS#12; 口令: 未知",
+				"KEYSTORE|密钥库|加密密钥库|high||1 个条目|格式: PKCS#12; 口令未知（已尝试 --keystore-password 和常见默认口令）; 别名: server; 其余条目已加密",
+			},
+		},
+		{
+			// 扩展名匹配但不是密钥库，由调用方按普通文件扫描
+			name: "not a keystore",
+			file: "notes.p12",
+			data: []byte("password=Hunter2024\n"),
+			want: nil,
+		},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if !IsKeystoreFile(path) {
+				t.Fatalf("IsKeystoreFile(%q) = false", tt.file)
+			}
+			got := NewKeystoreParser(tt.password).Parse(path, nil, false)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
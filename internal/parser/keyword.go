@@ -0,0 +1,69 @@
+package parser
+
+import "strings"
+
+// keywordMatcher 关键字匹配器，为 nil 时按原样区分大小写匹配
+// 忽略大小写时文本和关键字都转为小写，并将全角字符转为半角后比较，报告中仍保留原文
+type keywordMatcher struct {
+	folded map[string]string // 原关键字 -> 规范化后的关键字（预先计算）
+}
+
+// newKeywordMatcher 创建忽略大小写的关键字匹配器并预先规范化关键字
+func newKeywordMatcher(keywords []string) *keywordMatcher {
+	m := &keywordMatcher{folded: make(map[string]string, len(keywords))}
+	for _, keyword := range keywords {
+		m.folded[keyword] = foldKeyword(keyword)
+	}
+	return m
+}
+
+// find 返回文本中包含的第一个关键字（原样返回）
+func (m *keywordMatcher) find(text string, keywords []string) (string, bool) {
+	if m == nil {
+		for _, keyword := range keywords {
+			if strings.Contains(text, keyword) {
+				return keyword, true
+			}
+		}
+		return "", false
+	}
+
+	normalized := foldKeyword(text)
+	for _, keyword := range keywords {
+		folded, ok := m.folded[keyword]
+		if !ok {
+			folded = foldKeyword(keyword)
+		}
+		if strings.Contains(normalized, folded) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// foldKeyword 转为小写，并将全角 ASCII 字符和全角空格转为半角
+func foldKeyword(s string) string {
+	return strings.ToLower(strings.Map(toHalfWidth, s))
+}
+
+// toHalfWidth 全角字符转半角，其他字符不变
+func toHalfWidth(r rune) rune {
+	switch {
+	case r == '　':
+		return ' '
+	case r >= '！' && r <= '～':
+		return r - 0xFEE0
+	}
+	return r
+}
+
+// KeywordFinder 返回查找文本中第一个关键字的函数，ignoreCase 为 true 时与 --keyword-ci 的匹配方式相同
+func KeywordFinder(keywords []string, ignoreCase bool) func(text string) (string, bool) {
+	var m *keywordMatcher
+	if ignoreCase {
+		m = newKeywordMatcher(keywords)
+	}
+	return func(text string) (string, bool) {
+		return m.find(text, keywords)
+	}
+}
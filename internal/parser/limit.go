@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ruleLimiter 限制单个文件中每条规则的结果数，nil 表示不限制
+type ruleLimiter struct {
+	max     int
+	counts  map[string]int
+	dropped map[string]int
+	risks   map[string]string
+}
+
+// newRuleLimiter 创建规则结果数限制器，max 不大于0时返回 nil
+func newRuleLimiter(max int) *ruleLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &ruleLimiter{
+		max:     max,
+		counts:  make(map[string]int),
+		dropped: make(map[string]int),
+		risks:   make(map[string]string),
+	}
+}
+
+// filter 过滤超出上限的结果，Base64编码的结果与原规则合并计数
+func (l *ruleLimiter) filter(results []BinaryMatchResult) []BinaryMatchResult {
+	if l == nil {
+		return results
+	}
+
+	kept := results[:0]
+	for _, result := range results {
+		name := strings.TrimSuffix(result.RuleName, " (Base64编码)")
+		if l.counts[name] >= l.max {
+			l.dropped[name]++
+			l.risks[name] = result.RiskLevel
+			continue
+		}
+		l.counts[name]++
+		kept = append(kept, result)
+	}
+	return kept
+}
+
+// droppedCount 返回已省略的结果总数
+func (l *ruleLimiter) droppedCount() int {
+	if l == nil {
+		return 0
+	}
+	total := 0
+	for _, n := range l.dropped {
+		total += n
+	}
+	return total
+}
+
+// cappedResults 为达到上限的规则生成提示结果
+func (l *ruleLimiter) cappedResults() []BinaryMatchResult {
+	if l == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(l.dropped))
+	for name := range l.dropped {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []BinaryMatchResult
+	for _, name := range names {
+		results = append(results, BinaryMatchResult{
+			RuleName:     name,
+			RuleDesc:     "规则结果数达到上限",
+			RiskLevel:    l.risks[name],
+			MatchedValue: fmt.Sprintf("已达到上限 %d 条，另有 %d 条结果被省略", l.max, l.dropped[name]),
+			Offset:       -1,
+			Context:      "使用 --max-per-rule 调整上限",
+		})
+	}
+	return results
+}
@@ -0,0 +1,63 @@
+package parser
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// MagicRoute 自定义文件头签名（--magic），文件以 Magic 开头时按 Type 解析（raw 或 text）
+type MagicRoute struct {
+	Magic []byte
+	Type  string
+}
+
+// magicRouter 读取文件头并匹配自定义签名，签名较长的优先
+type magicRouter struct {
+	routes  []MagicRoute
+	maxSize int
+}
+
+// newMagicRouter 创建文件头匹配器，没有签名时返回 nil（不读取文件头）
+func newMagicRouter(routes []MagicRoute) *magicRouter {
+	if len(routes) == 0 {
+		return nil
+	}
+
+	r := &magicRouter{}
+	for _, route := range routes {
+		// 按长度降序插入，较长的签名先匹配
+		i := 0
+		for i < len(r.routes) && len(r.routes[i].Magic) >= len(route.Magic) {
+			i++
+		}
+		r.routes = append(r.routes[:i], append([]MagicRoute{route}, r.routes[i:]...)...)
+		if len(route.Magic) > r.maxSize {
+			r.maxSize = len(route.Magic)
+		}
+	}
+	return r
+}
+
+// match 返回文件头匹配的解析方式，未匹配或读取失败时返回空字符串
+func (r *magicRouter) match(filePath string) string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	head := make([]byte, r.maxSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	head = head[:n]
+
+	for _, route := range r.routes {
+		if bytes.HasPrefix(head, route.Magic) {
+			return route.Type
+		}
+	}
+	return ""
+}
@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// scanMappedFile 映射文件并在映射的内容上执行 scan，返回前释放映射
+// 文件在扫描过程中被截断（如 --raw-scan、--dual-scan 扫描的日志被轮转）时读取映射会触发 SIGBUS，
+// 这里将其转换为该文件的错误，不会终止整个扫描；其他 panic 原样抛出
+func scanMappedFile(filePath string, scan func(data []byte) []string) (results []string, err error) {
+	data, release, err := mapFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			if !isMemoryFault(r) {
+				panic(r)
+			}
+			results, err = nil, fmt.Errorf("读取映射的文件内容时发生内存访问错误，文件可能在扫描过程中被截断: %v", r)
+		}
+	}()
+	return scan(data), nil
+}
+
+// isMemoryFault 判断 panic 是否为 SetPanicOnFault 开启后由内存访问错误转换而来
+func isMemoryFault(r interface{}) bool {
+	_, ok := r.(interface{ Addr() uintptr })
+	return ok
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package parser
+
+// mapFile 当前平台不支持内存映射，直接读取文件内容
+func mapFile(filePath string) ([]byte, func(), error) {
+	return readFile(filePath)
+}
@@ -0,0 +1,71 @@
+//go:build unix
+
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// truncatedMapping 创建 size 字节的文件，在映射后截断为空，返回映射内容的读取结果
+func truncatedMapping(t *testing.T, read func(data []byte) []string) ([]string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte(strings.Repeat("password=rotated\n", 4096)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return scanMappedFile(path, func(data []byte) []string {
+		if err := os.Truncate(path, 0); err != nil {
+			t.Fatal(err)
+		}
+		return read(data)
+	})
+}
+
+// TestScanMappedFileTruncated 映射后被截断的文件读取时触发的 SIGBUS 转换为该文件的错误
+func TestScanMappedFileTruncated(t *testing.T) {
+	results, err := truncatedMapping(t, func(data []byte) []string {
+		return []string{string(data[len(data)-1])}
+	})
+	if err == nil {
+		t.Fatalf("got results %q, want a memory fault error", results)
+	}
+}
+
+// TestScanMappedFileTruncatedInWorker 文件内并发匹配的工作协程中发生的内存访问错误同样转换为错误
+func TestScanMappedFileTruncatedInWorker(t *testing.T) {
+	_, err := truncatedMapping(t, func(data []byte) []string {
+		pool := newOrderedPool(4)
+		defer pool.stop()
+		tail := make([]byte, 8)
+		for i := range tail {
+			i, offset := i, len(data)-1-i*4096
+			pool.submit(func() { tail[i] = data[offset] }, func() {})
+		}
+		pool.wait()
+		return []string{string(tail)}
+	})
+	if err == nil {
+		t.Fatal("want a memory fault error")
+	}
+}
+
+// TestScanMappedFileOtherPanic 与映射无关的 panic 不被转换
+func TestScanMappedFileOtherPanic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.bin")
+	if err := os.WriteFile(path, []byte("MZ"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("want the original panic")
+		}
+	}()
+	scanMappedFile(path, func(data []byte) []string {
+		var m map[string]int
+		m["x"]++
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+//go:build unix
+
+package parser
+
+import (
+	"os"
+	"syscall"
+)
+
+// mapFile 以只读方式将文件映射到内存，返回映射的字节和释放函数
+// 映射失败时回退到 os.ReadFile
+func mapFile(filePath string) ([]byte, func(), error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 空文件无法映射
+	size := info.Size()
+	if size == 0 || int64(int(size)) != size {
+		return readFile(filePath)
+	}
+
+	data, err := syscall.Mmap(int(file.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return readFile(filePath)
+	}
+
+	return data, func() { syscall.Munmap(data) }, nil
+}
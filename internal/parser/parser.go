@@ -1,8 +1,12 @@
 package parser
 
 import (
+	"context"
 	"os"
 	"strings"
+	"time"
+
+	"Findx/internal/logger"
 )
 
 // Parser 文件解析器接口
@@ -12,7 +16,28 @@ type Parser interface {
 
 // ParserConfig 解析器配置
 type ParserConfig struct {
-	ContextLength int
+	ContextLength    int
+	OnlyRules        []string          // 仅启用的规则名称（为空表示全部启用）
+	SkipRules        []string          // 禁用的规则名称
+	NoValidateRules  []string          // 不校验匹配值的规则名称
+	RawFallback      bool              // 不是有效PE的二进制文件按原始字节扫描（默认跳过）
+	AllMatches       bool              // 保留同一行命中的所有规则结果
+	DualScan         bool              // 文本和二进制文件同时使用文本和二进制两种方式扫描
+	RawScan          bool              // 所有文件按原始字节扫描，不校验PE格式
+	MaxPerRule       int               // 二进制扫描中每个文件单条规则的最大结果数
+	MaxStrings       int               // 二进制扫描中每个文件最多检查的字符串数（0表示不限制）
+	MergeFragments   bool              // 二进制扫描中合并相邻的字符串片段后再应用规则
+	IntraFileWorkers int               // 二进制扫描中单个文件内并发匹配的协程数（不大于1时依次匹配）
+	TextThreshold    float64           // Base64解码内容视为文本的可打印字符最低比例（0表示使用默认值）
+	KeywordCI        bool              // 关键字忽略大小写，并将全角字符按半角比较
+	Keywords         []string          // 忽略大小写时预先规范化的关键字
+	RuleTimeout      time.Duration     // 规则匹配单个字符串的时间上限（0表示不限制）
+	ValueMaxLen      int               // 匹配值的最大长度（0表示不截断）
+	MagicRoutes      []MagicRoute      // 自定义文件头签名，匹配时优先于扩展名选择解析方式
+	KeywordRisks     map[string]string // 关键字匹配结果的风险等级（未指定的关键字为 medium）
+	SecretWords      []string          // 高熵赋值规则的敏感变量名关键词（为空时不启用该规则）
+	EntropyThreshold float64           // 高熵赋值规则的信息熵阈值（0表示使用默认值）
+	KeystorePassword string            // 密钥库口令（为空时只尝试常见默认口令）
 }
 
 // FileParser 文件解析器管理器
@@ -22,43 +47,316 @@ type FileParser struct {
 	excelParser   *ExcelParser
 	csvParser     *CSVParser
 	binaryParser  *BinaryParser
+	classParser   *JavaClassParser
+	jarParser     *JarParser
+	xmlParser     *XmlParser
+	yamlParser    *YamlParser
+	k8sParser     *K8sSecretParser
+	pcapParser    *PcapParser
+	harParser     *HarParser
+	imageParser   *ImageMetadataParser
+	tfParser      *TerraformParser
+	ksParser      *KeystoreParser
+	certParser    *CertParser
+	credParser    *CredentialStoreParser
+	historyParser *HistoryParser
+	scriptParser  *ScriptParser
+	stringDumper  *StringDumper
+	entryPool     EntryPool // 压缩包条目并发扫描的工作池（为 nil 时依次扫描）
 	contextLength int
+	dualScan      bool
+	rawScan       bool
+	rawFallback   bool         // 不是有效PE的二进制文件按原始字节扫描
+	magic         *magicRouter // 自定义文件头签名（为 nil 时不读取文件头）
 }
 
+// maxDualScanSize 双重扫描的文件大小上限，超出时只使用常规解析器
+const maxDualScanSize = 32 * 1024 * 1024
+
 // NewFileParser 创建文件解析器管理器
-func NewFileParser(contextLength int) *FileParser {
+func NewFileParser(cfg ParserConfig) *FileParser {
+	binaryParser := NewBinaryParser()
+	binaryParser.FilterRules(cfg.OnlyRules, cfg.SkipRules)
+	binaryParser.rules = skipValidation(binaryParser.rules, cfg.NoValidateRules)
+	binaryParser.allMatches = cfg.AllMatches
+	binaryParser.maxPerRule = cfg.MaxPerRule
+	binaryParser.maxStrings = cfg.MaxStrings
+	binaryParser.mergeFragments = cfg.MergeFragments
+	binaryParser.concurrency = cfg.IntraFileWorkers
+	binaryParser.valueMaxLen = cfg.ValueMaxLen
+	binaryParser.keywordRisks = cfg.KeywordRisks
+	if cfg.TextThreshold > 0 {
+		binaryParser.textThreshold = cfg.TextThreshold
+	}
+	classParser := NewJavaClassParser(binaryParser)
+	textParser := NewTextParser()
+	textParser.detectJWT = len(filterRules([]DetectionRule{{Name: "JWT令牌"}}, cfg.OnlyRules, cfg.SkipRules)) > 0
+	textParser.codeRules = filterRules(textParser.codeRules, cfg.OnlyRules, cfg.SkipRules)
+	textParser.entropyRules = filterRules(newEntropyRules(cfg.SecretWords), cfg.OnlyRules, cfg.SkipRules)
+	textParser.entropyWords = entropyWords(cfg.SecretWords)
+	textParser.entropyThreshold = DefaultEntropyThreshold
+	if cfg.EntropyThreshold > 0 {
+		textParser.entropyThreshold = cfg.EntropyThreshold
+	}
+	historyParser := NewHistoryParser()
+	historyParser.FilterRules(cfg.OnlyRules, cfg.SkipRules)
+	scriptParser := NewScriptParser()
+	scriptParser.FilterRules(cfg.OnlyRules, cfg.SkipRules)
+	if cfg.RuleTimeout > 0 {
+		binaryParser.rules = withTimeout(binaryParser.rules, cfg.RuleTimeout)
+		historyParser.rules = withTimeout(historyParser.rules, cfg.RuleTimeout)
+		scriptParser.rules = withTimeout(scriptParser.rules, cfg.RuleTimeout)
+		textParser.codeRules = withTimeout(textParser.codeRules, cfg.RuleTimeout)
+		textParser.entropyRules = withTimeout(textParser.entropyRules, cfg.RuleTimeout)
+	}
+	wordParser := NewWordParser()
+	excelParser := NewExcelParser()
+	csvParser := NewCSVParser()
+
+	// 忽略大小写时所有解析器共用同一个匹配器，Java、XML、YAML、K8s、抓包解析器通过二进制解析器使用
+	if cfg.KeywordCI {
+		matcher := newKeywordMatcher(cfg.Keywords)
+		binaryParser.matcher = matcher
+		textParser.matcher = matcher
+		historyParser.matcher = matcher
+		scriptParser.matcher = matcher
+		wordParser.matcher = matcher
+		excelParser.matcher = matcher
+		csvParser.matcher = matcher
+	}
+
 	return &FileParser{
-		textParser:    NewTextParser(),
-		wordParser:    NewWordParser(),
-		excelParser:   NewExcelParser(),
-		csvParser:     NewCSVParser(),
-		binaryParser:  NewBinaryParser(),
-		contextLength: contextLength,
+		textParser:    textParser,
+		wordParser:    wordParser,
+		excelParser:   excelParser,
+		csvParser:     csvParser,
+		binaryParser:  binaryParser,
+		classParser:   classParser,
+		jarParser:     NewJarParser(classParser),
+		xmlParser:     NewXmlParser(binaryParser, textParser),
+		yamlParser:    NewYamlParser(binaryParser, textParser),
+		k8sParser:     NewK8sSecretParser(binaryParser),
+		pcapParser:    NewPcapParser(binaryParser),
+		harParser:     NewHarParser(binaryParser, textParser),
+		imageParser:   NewImageMetadataParser(binaryParser),
+		tfParser:      NewTerraformParser(binaryParser, textParser),
+		ksParser:      NewKeystoreParser(cfg.KeystorePassword),
+		certParser:    NewCertParser(),
+		credParser:    NewCredentialStoreParser(),
+		historyParser: historyParser,
+		scriptParser:  scriptParser,
+		contextLength: cfg.ContextLength,
+		dualScan:      cfg.DualScan,
+		rawScan:       cfg.RawScan,
+		rawFallback:   cfg.RawFallback,
+		magic:         newMagicRouter(cfg.MagicRoutes),
 	}
 }
 
+// SetStringDumper 设置二进制字符串转储（--dump-strings），为 nil 时不转储
+func (fp *FileParser) SetStringDumper(dumper *StringDumper) {
+	fp.stringDumper = dumper
+}
+
+// SetEntryPool 设置压缩包条目的工作池，JAR 中的类文件和 Office 文档中的嵌入对象在工作池中与其他文件并发扫描
+func (fp *FileParser) SetEntryPool(pool EntryPool) {
+	fp.entryPool = pool
+	fp.jarParser.pool = pool
+}
+
 // Parse 根据文件类型选择合适的解析器
 func (fp *FileParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	return fp.ParseContext(context.Background(), filePath, keywords, verbose)
+}
+
+// ParseContext 根据文件类型选择合适的解析器，ctx 取消时中止二进制扫描
+func (fp *FileParser) ParseContext(ctx context.Context, filePath string, keywords []string, verbose bool) []string {
+	results := fp.parse(ctx, filePath, keywords, verbose)
+	if fp.dualScan {
+		results = fp.dualScanFile(ctx, filePath, keywords, verbose, results)
+	}
+	return results
+}
+
+// parse 按扩展名选择单个解析器
+func (fp *FileParser) parse(ctx context.Context, filePath string, keywords []string, verbose bool) []string {
+	// 原始字节扫描：不区分文件格式
+	if fp.rawScan {
+		return fp.parseRawFile(ctx, filePath, keywords, verbose)
+	}
+
+	// 自定义文件头签名优先于扩展名
+	if fp.magic != nil {
+		switch fp.magic.match(filePath) {
+		case "raw":
+			return fp.parseRawFile(ctx, filePath, keywords, verbose)
+		case "text":
+			return fp.textParser.Parse(filePath, keywords, verbose)
+		}
+	}
+
 	// 检查是否为二进制文件（DLL/EXE）
 	if isBinaryFile(filePath) {
-		return fp.parseBinaryFile(filePath, keywords, verbose)
+		return fp.parseBinaryFile(ctx, filePath, keywords, verbose)
+	}
+
+	// 命令历史文件额外匹配命令行中的凭据参数
+	if IsHistoryFile(filePath) {
+		return fp.historyParser.Parse(filePath, keywords, verbose)
+	}
+
+	// PowerShell 和批处理脚本额外解码编码命令
+	if IsScriptFile(filePath) {
+		return fp.scriptParser.Parse(filePath, keywords, verbose)
 	}
 
 	// 文档文件
 	switch {
 	case strings.HasSuffix(filePath, ".docx"):
-		return fp.wordParser.Parse(filePath, keywords, verbose)
+		results := fp.wordParser.Parse(filePath, keywords, verbose)
+		return append(results, fp.parseEmbedded(ctx, filePath, keywords, verbose)...)
 	case strings.HasSuffix(filePath, ".xlsx"):
-		return fp.excelParser.ParseXLSX(filePath, keywords, verbose)
+		results := fp.excelParser.ParseXLSX(filePath, keywords, verbose)
+		return append(results, fp.parseEmbedded(ctx, filePath, keywords, verbose)...)
+	case strings.HasSuffix(filePath, ".pptx"):
+		// 只解析嵌入对象和媒体文件，不解析幻灯片文字
+		return fp.parseEmbedded(ctx, filePath, keywords, verbose)
 	case strings.HasSuffix(filePath, ".xls"):
 		return fp.excelParser.ParseXLS(filePath, keywords, verbose)
 	case strings.HasSuffix(filePath, ".csv"):
 		return fp.csvParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".class"):
+		return fp.classParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".jar"):
+		return fp.jarParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".pcap"), strings.HasSuffix(filePath, ".pcapng"):
+		return fp.pcapParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".har"):
+		return fp.harParser.Parse(filePath, keywords, verbose)
+	case IsTerraformFile(filePath):
+		return fp.tfParser.Parse(filePath, keywords, verbose)
+	case IsImageFile(filePath):
+		// 只扫描 EXIF、XMP 和 PNG 文本块等元数据（--scan-images）
+		return fp.imageParser.Parse(filePath, keywords, verbose)
+	case IsKeystoreFile(filePath):
+		// 列出 JKS/JCEKS/PKCS#12 密钥库中的私钥和证书，无法识别的格式按普通文件扫描
+		if results := fp.ksParser.Parse(filePath, keywords, verbose); results != nil {
+			return results
+		}
+		return fp.textParser.Parse(filePath, keywords, verbose)
+	case IsCertificateFile(filePath):
+		// 列出 PEM/DER 证书的有效期和附带的私钥，没有证书和私钥时按普通文件扫描
+		if results := fp.certParser.Parse(filePath, keywords, verbose); results != nil {
+			return results
+		}
+		return fp.textParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".gz"):
+		return fp.textParser.ParseGzip(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".xml"), strings.HasSuffix(filePath, ".config"):
+		return fp.xmlParser.Parse(filePath, keywords, verbose)
+	case strings.HasSuffix(filePath, ".yaml"), strings.HasSuffix(filePath, ".yml"):
+		// 按键路径扫描（展开锚点/别名），并识别 Kubernetes Secret 清单
+		results := fp.yamlParser.Parse(filePath, keywords, verbose)
+		return append(results, fp.k8sParser.Parse(filePath, keywords, verbose)...)
+	case strings.HasSuffix(filePath, ".json"):
+		// Firefox 保存的登录信息
+		if IsFirefoxLogins(filePath) {
+			if results := fp.credParser.Parse(filePath, keywords, verbose); results != nil {
+				return results
+			}
+		}
+		// 文本扫描之外，识别 Kubernetes Secret 清单并解码其中的值
+		results := fp.textParser.Parse(filePath, keywords, verbose)
+		return append(results, fp.k8sParser.Parse(filePath, keywords, verbose)...)
 	default:
+		// 按文件结构识别钥匙串和浏览器 Login Data、Cookies 等凭据存储
+		if IsCredentialStore(filePath) {
+			if results := fp.credParser.Parse(filePath, keywords, verbose); results != nil {
+				return results
+			}
+		}
 		return fp.textParser.Parse(filePath, keywords, verbose)
 	}
 }
 
+// dualScanFile 对二进制文件追加文本扫描、对纯文本文件追加二进制扫描，合并后去除重复结果
+// 文本结果保留行号，二进制结果保留偏移量；文档、压缩包等结构化格式不参与
+func (fp *FileParser) dualScanFile(ctx context.Context, filePath string, keywords []string, verbose bool, results []string) []string {
+	binaryFile := isBinaryFile(filePath)
+	if !binaryFile && !usesTextParser(filePath) {
+		return results
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil || info.Size() > maxDualScanSize {
+		logger.Debugf("文件超过双重扫描大小上限，跳过: %s", filePath)
+		return results
+	}
+
+	var extra []string
+	if binaryFile {
+		extra = fp.textParser.Parse(filePath, keywords, verbose)
+	} else {
+		extra, err = scanMappedFile(filePath, func(data []byte) []string {
+			return fp.binaryParser.scanBytes(ctx, filePath, data, keywords, verbose, fp.contextLength)
+		})
+		if err != nil {
+			logger.Warnf("双重扫描读取文件失败: %s: %v", filePath, err)
+			return results
+		}
+	}
+
+	return mergeDualResults(results, extra)
+}
+
+// usesTextParser 判断文件是否只由文本解析器处理
+func usesTextParser(filePath string) bool {
+	for _, ext := range []string{".docx", ".xlsx", ".pptx", ".xls", ".csv", ".class", ".jar", ".pcap", ".pcapng", ".har", ".xml", ".config", ".yaml", ".yml", ".gz"} {
+		if strings.HasSuffix(filePath, ext) {
+			return false
+		}
+	}
+	return !IsImageFile(filePath) && !IsTerraformFile(filePath) && !IsKeystoreFile(filePath) && !IsCertificateFile(filePath)
+}
+
+// mergeDualResults 合并两种扫描方式的结果
+// 二进制方式的关键字结果与文本方式命中的同一行内容相同时视为重复
+func mergeDualResults(results, extra []string) []string {
+	seen := make(map[string]bool)
+	textLines := make(map[string]bool)
+	for _, raw := range append(append([]string{}, results...), extra...) {
+		if parts := strings.SplitN(raw, "|", 4); len(parts) == 4 && parts[0] == "TEXT" {
+			textLines[strings.TrimSpace(parts[3])] = true
+		}
+	}
+
+	var merged []string
+	for _, raw := range append(results, extra...) {
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+
+		// BINARY|关键字|关键字匹配|medium|<字符串>|0x..|...
+		if parts := strings.SplitN(raw, "|", 6); len(parts) == 6 && parts[0] == "BINARY" && parts[1] == "关键字" {
+			if textLines[strings.TrimSpace(parts[4])] {
+				continue
+			}
+		}
+		merged = append(merged, raw)
+	}
+	return merged
+}
+
+// readFile 读取整个文件内容，用于无法内存映射的情况
+func readFile(filePath string) ([]byte, func(), error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, func() {}, nil
+}
+
 // isBinaryFile 判断是否为二进制文件
 func isBinaryFile(filePath string) bool {
 	ext := strings.ToLower(filePath)
@@ -73,17 +371,47 @@ func isBinaryFile(filePath string) bool {
 	return false
 }
 
-// parseBinaryFile 解析二进制文件
-func (fp *FileParser) parseBinaryFile(filePath string, keywords []string, verbose bool) []string {
-	// 读取文件内容
-	data, err := os.ReadFile(filePath)
-	if err != nil {
-		if verbose {
-			println("[-] 读取二进制文件失败:", filePath)
+// parseRawFile 将文件作为原始字节扫描（内存转储、core 文件等），不校验文件格式，结果报告偏移
+func (fp *FileParser) parseRawFile(ctx context.Context, filePath string, keywords []string, verbose bool) []string {
+	results, err := scanMappedFile(filePath, func(data []byte) []string {
+		logger.Debugf("原始字节扫描: %s (%.2f MB)", filePath, float64(len(data))/1024/1024)
+		if fp.stringDumper != nil {
+			fp.stringDumper.Dump(filePath, data, "")
 		}
+		return fp.binaryParser.scanBytes(ctx, filePath, data, keywords, verbose, fp.contextLength)
+	})
+	if err != nil {
+		logger.Warnf("读取文件失败: %s: %v", filePath, err)
 		return nil
 	}
+	return results
+}
 
-	// 使用二进制解析器（带关键字和上下文长度）
-	return fp.binaryParser.ParseWithKeywords(filePath, data, keywords, verbose, fp.contextLength)
+// parseBinaryFile 解析二进制文件
+func (fp *FileParser) parseBinaryFile(ctx context.Context, filePath string, keywords []string, verbose bool) []string {
+	// 映射文件内容，避免每个工作协程在堆上复制整个文件
+	results, err := scanMappedFile(filePath, func(data []byte) []string {
+		validPE := len(data) >= 64 && isValidPEFile(data)
+		if fp.stringDumper != nil {
+			note := ""
+			if !validPE && !fp.rawFallback {
+				note = "不是有效的PE文件，二进制扫描时跳过"
+			}
+			fp.stringDumper.Dump(filePath, data, note)
+		}
+
+		// 不是有效PE的文件（ELF、损坏的PE、原始数据等）按原始字节扫描（--binary-fallback raw）
+		if !validPE && fp.rawFallback {
+			logger.Debugf("不是有效的PE文件，按原始字节扫描: %s", filePath)
+			return fp.binaryParser.scanBytes(ctx, filePath, data, keywords, verbose, fp.contextLength)
+		}
+
+		// 使用二进制解析器（带关键字和上下文长度）
+		return fp.binaryParser.ParseWithKeywordsContext(ctx, filePath, data, keywords, verbose, fp.contextLength)
+	})
+	if err != nil {
+		logger.Warnf("读取二进制文件失败: %s: %v", filePath, err)
+		return nil
+	}
+	return results
 }
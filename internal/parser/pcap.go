@@ -0,0 +1,435 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sort"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+const (
+	// maxPcapStreamSize 单个流最多重组的负载大小，凭据通常出现在流的开头
+	maxPcapStreamSize = 1024 * 1024
+	// maxPcapPacketSize 单个数据包的最大长度，超出视为文件损坏
+	maxPcapPacketSize = 256 * 1024
+)
+
+// 链路层类型
+const (
+	linkTypeNull     = 0
+	linkTypeEthernet = 1
+	linkTypeRaw      = 101
+	linkTypeLinuxSLL = 113
+	linkTypeIPv4     = 228
+	linkTypeIPv6     = 229
+)
+
+// pcapng 块类型
+const (
+	pcapngSectionHeader   = 0x0A0D0D0A
+	pcapngInterfaceDesc   = 0x00000001
+	pcapngSimplePacket    = 0x00000003
+	pcapngEnhancedPacket  = 0x00000006
+	pcapngByteOrderMagic  = 0x1A2B3C4D
+	pcapMagicMicroseconds = 0xA1B2C3D4
+	pcapMagicNanoseconds  = 0xA1B23C4D
+)
+
+// pcapSegment TCP/UDP 负载片段
+type pcapSegment struct {
+	seq     uint32
+	payload []byte
+}
+
+// pcapFlow 单向的传输层流
+type pcapFlow struct {
+	key      string // 五元组描述，如 "TCP 10.0.0.1:51234 -> 10.0.0.2:80"
+	tcp      bool
+	segments []pcapSegment
+	size     int
+}
+
+// PcapParser 网络抓包文件解析器，重组TCP流后扫描应用层负载
+type PcapParser struct {
+	binaryParser *BinaryParser
+}
+
+// NewPcapParser 创建抓包文件解析器
+func NewPcapParser(binaryParser *BinaryParser) *PcapParser {
+	return &PcapParser{
+		binaryParser: binaryParser,
+	}
+}
+
+// Parse 解析 .pcap/.pcapng 文件
+func (p *PcapParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("打开抓包文件%s错误", filePath)
+		return nil
+	}
+	defer file.Close()
+
+	flows := make(map[string]*pcapFlow)
+	var order []string
+	handle := func(linkType int, data []byte) {
+		flow, seq, payload := decodePacket(linkType, data)
+		if flow == nil || len(payload) == 0 {
+			return
+		}
+		existing, ok := flows[flow.key]
+		if !ok {
+			existing = flow
+			flows[flow.key] = flow
+			order = append(order, flow.key)
+		}
+		if existing.size >= maxPcapStreamSize {
+			return
+		}
+		existing.segments = append(existing.segments, pcapSegment{seq: seq, payload: append([]byte(nil), payload...)})
+		existing.size += len(payload)
+	}
+
+	reader := bufio.NewReader(file)
+	magic, err := reader.Peek(4)
+	if err != nil {
+		logger.Debugf("抓包文件%s过短", filePath)
+		return nil
+	}
+	if binary.LittleEndian.Uint32(magic) == pcapngSectionHeader {
+		err = readPcapng(reader, handle)
+	} else {
+		err = readPcap(reader, handle)
+	}
+	if err != nil {
+		logger.Debugf("解析抓包文件%s失败: %v", filePath, err)
+	}
+
+	var matchingLines []string
+	for _, key := range order {
+		stream := flows[key].reassemble()
+		matchingLines = append(matchingLines, p.checkStream(key, stream, keywords)...)
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// readPcap 读取经典 pcap 格式
+func readPcap(r io.Reader, handle func(linkType int, data []byte)) error {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+
+	var order binary.ByteOrder
+	switch {
+	case binary.LittleEndian.Uint32(header) == pcapMagicMicroseconds, binary.LittleEndian.Uint32(header) == pcapMagicNanoseconds:
+		order = binary.LittleEndian
+	case binary.BigEndian.Uint32(header) == pcapMagicMicroseconds, binary.BigEndian.Uint32(header) == pcapMagicNanoseconds:
+		order = binary.BigEndian
+	default:
+		return fmt.Errorf("不是有效的pcap文件")
+	}
+	linkType := int(order.Uint32(header[20:24]) & 0xFFFF)
+
+	record := make([]byte, 16)
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		capLen := order.Uint32(record[8:12])
+		if capLen > maxPcapPacketSize {
+			return fmt.Errorf("数据包长度异常: %d", capLen)
+		}
+		data := make([]byte, capLen)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return err
+		}
+		handle(linkType, data)
+	}
+}
+
+// readPcapng 读取 pcapng 格式（支持增强型和简单数据包块）
+func readPcapng(r io.Reader, handle func(linkType int, data []byte)) error {
+	var order binary.ByteOrder = binary.LittleEndian
+	var linkTypes []int
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		blockType := order.Uint32(header[0:4])
+		if blockType == pcapngSectionHeader {
+			// 字节序由块体开头的魔数决定，需要在读取长度之前确定
+			var magic [4]byte
+			if _, err := io.ReadFull(r, magic[:]); err != nil {
+				return err
+			}
+			if binary.BigEndian.Uint32(magic[:]) == pcapngByteOrderMagic {
+				order = binary.BigEndian
+			} else {
+				order = binary.LittleEndian
+			}
+			linkTypes = nil
+			blockLen := order.Uint32(header[4:8])
+			if blockLen < 16 || blockLen > maxPcapPacketSize {
+				return fmt.Errorf("块长度异常: %d", blockLen)
+			}
+			if _, err := io.CopyN(io.Discard, r, int64(blockLen)-12); err != nil {
+				return err
+			}
+			continue
+		}
+
+		blockLen := order.Uint32(header[4:8])
+		if blockLen < 12 || blockLen > maxPcapPacketSize {
+			return fmt.Errorf("块长度异常: %d", blockLen)
+		}
+		body := make([]byte, blockLen-8)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return err
+		}
+		body = body[:len(body)-4] // 去掉尾部的块长度
+
+		switch blockType {
+		case pcapngInterfaceDesc:
+			if len(body) >= 2 {
+				linkTypes = append(linkTypes, int(order.Uint16(body[0:2])))
+			}
+		case pcapngEnhancedPacket:
+			if len(body) < 20 {
+				continue
+			}
+			iface := int(order.Uint32(body[0:4]))
+			capLen := int(order.Uint32(body[12:16]))
+			if iface >= len(linkTypes) || 20+capLen > len(body) {
+				continue
+			}
+			handle(linkTypes[iface], body[20:20+capLen])
+		case pcapngSimplePacket:
+			if len(body) < 4 || len(linkTypes) == 0 {
+				continue
+			}
+			handle(linkTypes[0], body[4:])
+		}
+	}
+}
+
+// decodePacket 解析链路层、网络层和传输层头部，返回所属的流、TCP序号和负载
+func decodePacket(linkType int, data []byte) (*pcapFlow, uint32, []byte) {
+	var etherType uint16
+	switch linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return nil, 0, nil
+		}
+		etherType = binary.BigEndian.Uint16(data[12:14])
+		data = data[14:]
+		// 802.1Q VLAN 标签
+		for etherType == 0x8100 && len(data) >= 4 {
+			etherType = binary.BigEndian.Uint16(data[2:4])
+			data = data[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil, 0, nil
+		}
+		etherType = binary.BigEndian.Uint16(data[14:16])
+		data = data[16:]
+	case linkTypeNull:
+		if len(data) < 4 {
+			return nil, 0, nil
+		}
+		data = data[4:]
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+	default:
+		return nil, 0, nil
+	}
+
+	if len(data) == 0 {
+		return nil, 0, nil
+	}
+	if etherType == 0 {
+		// 无链路层类型信息时根据IP版本判断
+		switch data[0] >> 4 {
+		case 4:
+			etherType = 0x0800
+		case 6:
+			etherType = 0x86DD
+		}
+	}
+
+	var srcIP, dstIP net.IP
+	var protocol byte
+	switch etherType {
+	case 0x0800:
+		if len(data) < 20 {
+			return nil, 0, nil
+		}
+		headerLen := int(data[0]&0x0F) * 4
+		totalLen := int(binary.BigEndian.Uint16(data[2:4]))
+		if headerLen < 20 || len(data) < headerLen {
+			return nil, 0, nil
+		}
+		if totalLen >= headerLen && totalLen < len(data) {
+			data = data[:totalLen] // 去掉以太网填充
+		}
+		protocol = data[9]
+		srcIP, dstIP = net.IP(data[12:16]), net.IP(data[16:20])
+		data = data[headerLen:]
+	case 0x86DD:
+		if len(data) < 40 {
+			return nil, 0, nil
+		}
+		payloadLen := int(binary.BigEndian.Uint16(data[4:6]))
+		protocol = data[6]
+		srcIP, dstIP = net.IP(data[8:24]), net.IP(data[24:40])
+		data = data[40:]
+		if payloadLen < len(data) {
+			data = data[:payloadLen]
+		}
+	default:
+		return nil, 0, nil
+	}
+
+	switch protocol {
+	case 6: // TCP
+		if len(data) < 20 {
+			return nil, 0, nil
+		}
+		srcPort := binary.BigEndian.Uint16(data[0:2])
+		dstPort := binary.BigEndian.Uint16(data[2:4])
+		seq := binary.BigEndian.Uint32(data[4:8])
+		headerLen := int(data[12]>>4) * 4
+		if headerLen < 20 || len(data) < headerLen {
+			return nil, 0, nil
+		}
+		key := fmt.Sprintf("TCP %s -> %s", net.JoinHostPort(srcIP.String(), fmt.Sprint(srcPort)), net.JoinHostPort(dstIP.String(), fmt.Sprint(dstPort)))
+		return &pcapFlow{key: key, tcp: true}, seq, data[headerLen:]
+	case 17: // UDP
+		if len(data) < 8 {
+			return nil, 0, nil
+		}
+		srcPort := binary.BigEndian.Uint16(data[0:2])
+		dstPort := binary.BigEndian.Uint16(data[2:4])
+		key := fmt.Sprintf("UDP %s -> %s", net.JoinHostPort(srcIP.String(), fmt.Sprint(srcPort)), net.JoinHostPort(dstIP.String(), fmt.Sprint(dstPort)))
+		return &pcapFlow{key: key}, 0, data[8:]
+	}
+
+	return nil, 0, nil
+}
+
+// reassemble 重组流的负载：TCP按序号排序并去除重传的重叠部分，UDP按到达顺序拼接
+func (f *pcapFlow) reassemble() []byte {
+	if !f.tcp {
+		var buf bytes.Buffer
+		for _, segment := range f.segments {
+			buf.Write(segment.payload)
+			buf.WriteByte('\n')
+		}
+		return buf.Bytes()
+	}
+
+	// 以首个片段为基准计算相对序号，处理序号回绕
+	base := f.segments[0].seq
+	for _, segment := range f.segments {
+		if int32(segment.seq-base) < 0 {
+			base = segment.seq
+		}
+	}
+	sort.SliceStable(f.segments, func(i, j int) bool {
+		return f.segments[i].seq-base < f.segments[j].seq-base
+	})
+
+	var buf bytes.Buffer
+	next := uint32(0)
+	for _, segment := range f.segments {
+		offset := segment.seq - base
+		end := offset + uint32(len(segment.payload))
+		if end <= next {
+			continue // 重传
+		}
+		payload := segment.payload
+		if offset < next {
+			payload = payload[next-offset:]
+		}
+		buf.Write(payload)
+		next = end
+	}
+	return buf.Bytes()
+}
+
+// checkStream 按行扫描流负载
+func (p *PcapParser) checkStream(flowKey string, stream []byte, keywords []string) []string {
+	var results []string
+
+	for _, line := range strings.Split(string(stream), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(line) < 4 {
+			continue
+		}
+
+		// HTTP Basic 认证：解码后报告用户名和密码
+		if credentials, ok := decodeBasicAuth(line); ok {
+			results = append(results, formatPcapResult(flowKey, "HTTP Basic认证", "critical", "", credentials, line))
+			continue
+		}
+
+		if matches := p.binaryParser.MatchString(line); len(matches) > 0 {
+			for _, result := range matches {
+				results = append(results, formatPcapResult(flowKey, result.RuleName, result.RiskLevel, "", result.MatchedValue, line))
+			}
+			continue
+		}
+
+		if keyword, ok := p.binaryParser.matcher.find(line, keywords); ok {
+			results = append(results, formatPcapResult(flowKey, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, strings.TrimSpace(line), line))
+		}
+	}
+
+	return results
+}
+
+// decodeBasicAuth 解码 HTTP Authorization: Basic 头
+func decodeBasicAuth(line string) (string, bool) {
+	name, value, ok := strings.Cut(line, ":")
+	if !ok || !strings.EqualFold(strings.TrimSpace(name), "Authorization") {
+		return "", false
+	}
+	scheme, encoded, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(scheme, "Basic") {
+		return "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || !strings.Contains(string(decoded), ":") {
+		return "", false
+	}
+	return string(decoded), true
+}
+
+// formatPcapResult 格式化抓包文件扫描结果
+func formatPcapResult(flowKey, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	return fmt.Sprintf("PCAP|%s|%s|%s|%s|%s|%s", flowKey, ruleName, riskLevel, keyword, matchedValue, content)
+}
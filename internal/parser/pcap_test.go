@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// testPacket 抓包用例中的一个TCP数据包（10.0.0.1:51234 -> 10.0.0.2:80）
+type testPacket struct {
+	seq     uint32
+	payload string
+}
+
+// ethernetTCPPacket 构造以太网/IPv4/TCP数据包
+func ethernetTCPPacket(pkt testPacket) []byte {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, 12)) // 目的和源MAC地址
+	binary.Write(&buf, binary.BigEndian, uint16(0x0800))
+
+	ip := make([]byte, 20)
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:4], uint16(20+20+len(pkt.payload)))
+	ip[8] = 64
+	ip[9] = 6
+	copy(ip[12:16], []byte{10, 0, 0, 1})
+	copy(ip[16:20], []byte{10, 0, 0, 2})
+	buf.Write(ip)
+
+	tcp := make([]byte, 20)
+	binary.BigEndian.PutUint16(tcp[0:2], 51234)
+	binary.BigEndian.PutUint16(tcp[2:4], 80)
+	binary.BigEndian.PutUint32(tcp[4:8], pkt.seq)
+	tcp[12] = 5 << 4
+	tcp[13] = 0x18 // PSH|ACK
+	buf.Write(tcp)
+
+	buf.WriteString(pkt.payload)
+	return buf.Bytes()
+}
+
+// classicPcap 构造经典 pcap 文件（以太网链路类型）
+func classicPcap(order binary.ByteOrder, packets ...testPacket) []byte {
+	var buf bytes.Buffer
+	header := make([]byte, 24)
+	order.PutUint32(header[0:4], pcapMagicMicroseconds)
+	order.PutUint16(header[4:6], 2)
+	order.PutUint16(header[6:8], 4)
+	order.PutUint32(header[16:20], 65535)
+	order.PutUint32(header[20:24], linkTypeEthernet)
+	buf.Write(header)
+
+	for _, pkt := range packets {
+		data := ethernetTCPPacket(pkt)
+		record := make([]byte, 16)
+		order.PutUint32(record[8:12], uint32(len(data)))
+		order.PutUint32(record[12:16], uint32(len(data)))
+		buf.Write(record)
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+// pcapngBlock 构造 pcapng 块，块体按4字节对齐
+func pcapngBlock(blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	length := uint32(12 + len(body))
+	block := make([]byte, 8, length)
+	binary.LittleEndian.PutUint32(block[0:4], blockType)
+	binary.LittleEndian.PutUint32(block[4:8], length)
+	block = append(block, body...)
+	return binary.LittleEndian.AppendUint32(block, length)
+}
+
+// pcapngCapture 构造包含一个以太网接口的 pcapng 文件（小端序）
+func pcapngCapture(packets ...testPacket) []byte {
+	var buf bytes.Buffer
+
+	shb := make([]byte, 16)
+	binary.LittleEndian.PutUint32(shb[0:4], pcapngByteOrderMagic)
+	binary.LittleEndian.PutUint16(shb[4:6], 1)
+	binary.LittleEndian.PutUint64(shb[8:16], ^uint64(0)) // 段长度未知
+	buf.Write(pcapngBlock(pcapngSectionHeader, shb))
+
+	idb := make([]byte, 8)
+	binary.LittleEndian.PutUint16(idb[0:2], linkTypeEthernet)
+	binary.LittleEndian.PutUint32(idb[4:8], 65535)
+	buf.Write(pcapngBlock(pcapngInterfaceDesc, idb))
+
+	for _, pkt := range packets {
+		data := ethernetTCPPacket(pkt)
+		epb := make([]byte, 20, 20+len(data))
+		binary.LittleEndian.PutUint32(epb[12:16], uint32(len(data)))
+		binary.LittleEndian.PutUint32(epb[16:20], uint32(len(data)))
+		buf.Write(pcapngBlock(pcapngEnhancedPacket, append(epb, data...)))
+	}
+	return buf.Bytes()
+}
+
+const pcapTestFlow = "TCP 10.0.0.1:51234 -> 10.0.0.2:80"
+
+var pcapBasicAuth = "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("admin:S3cretPass"))
+
+// pcapTests 抓包文件解析用例
+var pcapTests = []struct {
+	name    string
+	capture []byte
+	want    []string
+}{
+	{
+		name: "classic pcap",
+		capture: classicPcap(binary.LittleEndian,
+			testPacket{1000, "POST /login HTTP/1.1\r\nHost: app.local\r\n"},
+			testPacket{1039, pcapBasicAuth + "\r\n\r\n"},
+		),
+		want: []string{
+			"PCAP|" + pcapTestFlow + "|HTTP Basic认证|critical||admin:S3cretPass|" + pcapBasicAuth,
+		},
+	},
+	{
+		name: "classic pcap big endian, segments out of order",
+		capture: classicPcap(binary.BigEndian,
+			testPacket{1009, "Hunter2024\r\n"},
+			testPacket{1000, "password="},
+		),
+		want: []string{
+			"PCAP|" + pcapTestFlow + "|密码字段|critical||Hunter2024|password=Hunter2024",
+		},
+	},
+	{
+		name: "pcapng",
+		capture: pcapngCapture(
+			testPacket{1000, "POST /login HTTP/1.1\r\n"},
+			testPacket{1022, "password=Hunter2024\r\n"},
+		),
+		want: []string{
+			"PCAP|" + pcapTestFlow + "|密码字段|critical||Hunter2024|password=Hunter2024",
+		},
+	},
+	{
+		// 最后一个记录被截断：之前的数据包仍然报告
+		name: "classic pcap truncated record",
+		capture: func() []byte {
+			capture := classicPcap(binary.LittleEndian,
+				testPacket{1000, "password=Hunter2024\r\n"},
+				testPacket{1021, "api_key=AKIA1234567890ABCDEFGH\r\n"},
+			)
+			return capture[:len(capture)-10]
+		}(),
+		want: []string{
+			"PCAP|" + pcapTestFlow + "|密码字段|critical||Hunter2024|password=Hunter2024",
+		},
+	},
+	{
+		name:    "not a capture",
+		capture: []byte("password=Hunter2024\n"),
+		want:    nil,
+	},
+}
+
+func TestPcapParser(t *testing.T) {
+	parser := NewPcapParser(NewBinaryParser())
+	dir := t.TempDir()
+	for i, tt := range pcapTests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".pcap")
+			if err := os.WriteFile(path, tt.capture, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, nil, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results = %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package parser
+
+import (
+	"debug/pe"
+	"sort"
+)
+
+// PESection PE 节区在文件中的范围
+type PESection struct {
+	Name   string
+	Offset int64
+	Size   int64
+}
+
+// PESections 读取 PE 文件的节表（按文件偏移排序），不是有效的 PE 文件时返回 nil
+func PESections(filePath string) []PESection {
+	file, err := pe.Open(filePath)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var sections []PESection
+	for _, section := range file.Sections {
+		if section.Size == 0 {
+			continue
+		}
+		sections = append(sections, PESection{
+			Name:   section.Name,
+			Offset: int64(section.Offset),
+			Size:   int64(section.Size),
+		})
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i].Offset < sections[j].Offset })
+	return sections
+}
+
+// SectionAt 获取文件偏移所在的节区名称，位于文件头、附加数据等节区之外时返回空
+func SectionAt(sections []PESection, offset int64) string {
+	i := sort.Search(len(sections), func(i int) bool { return sections[i].Offset > offset }) - 1
+	if i < 0 || offset >= sections[i].Offset+sections[i].Size {
+		return ""
+	}
+	return sections[i].Name
+}
@@ -0,0 +1,379 @@
+package parser
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"unicode/utf16"
+)
+
+// PKCS#12 中使用的对象标识符
+var (
+	oidDataContentType          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidEncryptedDataContentType = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 6}
+
+	oidKeyBag              = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 1}
+	oidPKCS8ShroudedKeyBag = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 2}
+	oidCertBag             = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 3}
+	oidSecretBag           = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 10, 1, 5}
+	oidX509Certificate     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 22, 1}
+
+	oidFriendlyName = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 20}
+	oidLocalKeyID   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 21}
+
+	oidPBEWithSHAAnd3KeyTripleDESCBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 3}
+	oidPBEWithSHAAnd128BitRC2CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 5}
+	oidPBEWithSHAAnd40BitRC2CBC      = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 12, 1, 6}
+	oidPBES2                         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2                        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC     = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+)
+
+// errUnsupportedAlgorithm 不支持的加密或摘要算法
+var errUnsupportedAlgorithm = errors.New("不支持的算法")
+
+// pfxPdu PKCS#12 文件的顶层结构（RFC 7292）
+type pfxPdu struct {
+	Version  int
+	AuthSafe contentInfo
+	MacData  macData `asn1:"optional"`
+}
+
+// contentInfo PKCS#7 ContentInfo
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"tag:0,explicit,optional"`
+}
+
+// macData 完整性校验的 MAC
+type macData struct {
+	Mac        digestInfo
+	MacSalt    []byte
+	Iterations int `asn1:"optional,default:1"`
+}
+
+// digestInfo 摘要算法和摘要值
+type digestInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	Digest    []byte
+}
+
+// encryptedData 口令加密的内容
+type encryptedData struct {
+	Version              int
+	EncryptedContentInfo encryptedContentInfo
+}
+
+// encryptedContentInfo 加密算法和密文
+type encryptedContentInfo struct {
+	ContentType                asn1.ObjectIdentifier
+	ContentEncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedContent           []byte `asn1:"tag:0,optional"`
+}
+
+// safeBag 保存私钥、证书等的容器
+type safeBag struct {
+	ID         asn1.ObjectIdentifier
+	Value      asn1.RawValue     `asn1:"tag:0,explicit"`
+	Attributes []pkcs12Attribute `asn1:"set,optional"`
+}
+
+// pkcs12Attribute 容器的属性（friendlyName、localKeyId 等）
+type pkcs12Attribute struct {
+	ID    asn1.ObjectIdentifier
+	Value asn1.RawValue `asn1:"set"`
+}
+
+// certBag 证书容器
+type certBag struct {
+	ID   asn1.ObjectIdentifier
+	Data []byte `asn1:"tag:0,explicit"`
+}
+
+// pbeParams PKCS#12 口令加密算法的参数
+type pbeParams struct {
+	Salt       []byte
+	Iterations int
+}
+
+// pbes2Params PBES2 的密钥派生和加密算法
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params PBKDF2 的参数
+type pbkdf2Params struct {
+	Salt       []byte
+	Iterations int
+	KeyLength  int                      `asn1:"optional"`
+	PRF        pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// contentOctets 获取 ContentInfo 中 [0] 标签内的 OCTET STRING 内容
+// encoding/asn1 解析显式标签的 RawValue 时保留外层的 [0] 标签，内容在 Bytes 中
+func contentOctets(raw asn1.RawValue) ([]byte, error) {
+	var octets []byte
+	if _, err := asn1.Unmarshal(raw.Bytes, &octets); err != nil {
+		return nil, err
+	}
+	return octets, nil
+}
+
+// bmpPassword 将口令编码为 PKCS#12 使用的 BMPString（UTF-16BE，以两个零字节结尾），空口令编码为空
+func bmpPassword(password string) []byte {
+	if password == "" {
+		return nil
+	}
+	units := utf16.Encode([]rune(password))
+	out := make([]byte, 0, len(units)*2+2)
+	for _, u := range units {
+		out = append(out, byte(u>>8), byte(u))
+	}
+	return append(out, 0, 0)
+}
+
+// decodeBMPString 解码 BMPString（UTF-16BE）
+func decodeBMPString(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
+
+// hashByOID 按摘要或 HMAC 算法的对象标识符选择哈希函数
+func hashByOID(oid asn1.ObjectIdentifier) (func() hash.Hash, error) {
+	switch {
+	case oid.Equal(oidSHA1), oid.Equal(oidHMACWithSHA1):
+		return sha1.New, nil
+	case oid.Equal(oidSHA256), oid.Equal(oidHMACWithSHA256):
+		return sha256.New, nil
+	case oid.Equal(oidSHA512), oid.Equal(oidHMACWithSHA512):
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, oid)
+}
+
+// verifyPKCS12Mac 用口令校验 PKCS#12 的 MAC，口令正确时返回 true
+// 空口令在不同实现中编码为空或两个零字节，两种都尝试
+func verifyPKCS12Mac(md *macData, message []byte, password string) bool {
+	newHash, err := hashByOID(md.Mac.Algorithm.Algorithm)
+	if err != nil {
+		return false
+	}
+	encodings := [][]byte{bmpPassword(password)}
+	if password == "" {
+		encodings = append(encodings, []byte{0, 0})
+	}
+	for _, encoded := range encodings {
+		key := pkcs12KDF(newHash, md.MacSalt, encoded, md.Iterations, 3, newHash().Size())
+		mac := hmac.New(newHash, key)
+		mac.Write(message)
+		if hmac.Equal(mac.Sum(nil), md.Mac.Digest) {
+			return true
+		}
+	}
+	return false
+}
+
+// pkcs12KDF PKCS#12 的口令密钥派生函数（RFC 7292 附录 B.2），id 为 1 时派生密钥、2 时派生 IV、3 时派生 MAC 密钥
+func pkcs12KDF(newHash func() hash.Hash, salt, password []byte, iterations int, id byte, size int) []byte {
+	h := newHash()
+	u := h.Size()
+	v := h.BlockSize()
+
+	fill := func(b []byte) []byte {
+		if len(b) == 0 {
+			return nil
+		}
+		out := make([]byte, v*((len(b)+v-1)/v))
+		for i := range out {
+			out[i] = b[i%len(b)]
+		}
+		return out
+	}
+
+	diversifier := bytes.Repeat([]byte{id}, v)
+	input := append(fill(salt), fill(password)...)
+	var derived []byte
+	for len(derived) < size {
+		h.Reset()
+		h.Write(diversifier)
+		h.Write(input)
+		block := h.Sum(nil)
+		for i := 1; i < iterations; i++ {
+			h.Reset()
+			h.Write(block)
+			block = h.Sum(nil)
+		}
+		derived = append(derived, block...)
+
+		// I_j = (I_j + B + 1) mod 2^(8v)
+		b := make([]byte, v)
+		for i := range b {
+			b[i] = block[i%u]
+		}
+		for j := 0; j+v <= len(input); j += v {
+			carry := 1
+			for k := v - 1; k >= 0; k-- {
+				sum := int(input[j+k]) + int(b[k]) + carry
+				input[j+k] = byte(sum)
+				carry = sum >> 8
+			}
+		}
+	}
+	return derived[:size]
+}
+
+// pbkdf2Key PBKDF2 密钥派生（RFC 8018）
+func pbkdf2Key(newHash func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(newHash, password)
+	hashLen := prf.Size()
+	var derived []byte
+	var counter [4]byte
+	for block := uint32(1); len(derived) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], block)
+		prf.Write(counter[:])
+		u := prf.Sum(nil)
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for x := 0; x < hashLen; x++ {
+				t[x] ^= u[x]
+			}
+		}
+		derived = append(derived, t...)
+	}
+	return derived[:keyLen]
+}
+
+// decryptPKCS12 用口令解密 PKCS#12 中的加密内容，支持 PKCS#12 PBE（3DES、RC2）和 PBES2（PBKDF2 + AES/3DES）
+func decryptPKCS12(algorithm pkix.AlgorithmIdentifier, ciphertext []byte, password string) ([]byte, error) {
+	var block cipher.Block
+	var iv []byte
+
+	switch oid := algorithm.Algorithm; {
+	case oid.Equal(oidPBEWithSHAAnd3KeyTripleDESCBC), oid.Equal(oidPBEWithSHAAnd128BitRC2CBC), oid.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+		var params pbeParams
+		if _, err := asn1.Unmarshal(algorithm.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		pass := bmpPassword(password)
+		keyLen := 24
+		switch {
+		case oid.Equal(oidPBEWithSHAAnd128BitRC2CBC):
+			keyLen = 16
+		case oid.Equal(oidPBEWithSHAAnd40BitRC2CBC):
+			keyLen = 5
+		}
+		key := pkcs12KDF(sha1.New, params.Salt, pass, params.Iterations, 1, keyLen)
+		iv = pkcs12KDF(sha1.New, params.Salt, pass, params.Iterations, 2, 8)
+		var err error
+		if keyLen == 24 {
+			block, err = des.NewTripleDESCipher(key)
+		} else {
+			block, err = newRC2Cipher(key, keyLen*8)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	case oid.Equal(oidPBES2):
+		var params pbes2Params
+		if _, err := asn1.Unmarshal(algorithm.Parameters.FullBytes, &params); err != nil {
+			return nil, err
+		}
+		if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+			return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, params.KeyDerivationFunc.Algorithm)
+		}
+		var kdf pbkdf2Params
+		if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdf); err != nil {
+			return nil, err
+		}
+		newHash := sha1.New
+		if len(kdf.PRF.Algorithm) > 0 {
+			var err error
+			if newHash, err = hashByOID(kdf.PRF.Algorithm); err != nil {
+				return nil, err
+			}
+		}
+
+		scheme := params.EncryptionScheme.Algorithm
+		keyLen := 0
+		switch {
+		case scheme.Equal(oidAES128CBC):
+			keyLen = 16
+		case scheme.Equal(oidAES192CBC):
+			keyLen = 24
+		case scheme.Equal(oidAES256CBC), scheme.Equal(oidDESEDE3CBC):
+			keyLen = 32
+			if scheme.Equal(oidDESEDE3CBC) {
+				keyLen = 24
+			}
+		default:
+			return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, scheme)
+		}
+		if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+			return nil, err
+		}
+		key := pbkdf2Key(newHash, []byte(password), kdf.Salt, kdf.Iterations, keyLen)
+		var err error
+		if scheme.Equal(oidDESEDE3CBC) {
+			block, err = des.NewTripleDESCipher(key)
+		} else {
+			block, err = aes.NewCipher(key)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedAlgorithm, algorithm.Algorithm)
+	}
+
+	size := block.BlockSize()
+	if len(ciphertext) == 0 || len(ciphertext)%size != 0 || len(iv) != size {
+		return nil, errors.New("密文长度无效")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// 去除 PKCS#7 填充，填充无效说明口令错误
+	pad := int(plaintext[len(plaintext)-1])
+	if pad == 0 || pad > size {
+		return nil, errors.New("填充无效")
+	}
+	for _, b := range plaintext[len(plaintext)-pad:] {
+		if int(b) != pad {
+			return nil, errors.New("填充无效")
+		}
+	}
+	return plaintext[:len(plaintext)-pad], nil
+}
@@ -0,0 +1,177 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// 脚本结果的来源名称
+const (
+	scriptSource  = "脚本"
+	encodedSource = "PowerShell编码命令"
+)
+
+// maxEncodedDepth 编码命令中再次嵌套编码命令时的最大解码层数
+const maxEncodedDepth = 3
+
+// scriptExtensions 由脚本解析器处理的扩展名
+var scriptExtensions = []string{".ps1", ".psm1", ".bat", ".cmd"}
+
+// encodedCommandPattern PowerShell -EncodedCommand 参数（可缩写为 -e、-ec、-enc 等）及其 Base64 值
+var encodedCommandPattern = regexp.MustCompile(`(?i)(?:^|\s)[-/](e[a-z]*)\s+["']?([A-Za-z0-9+/]{8,}={0,2})`)
+
+// IsScriptFile 判断是否为 PowerShell 或批处理脚本
+func IsScriptFile(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	for _, scriptExt := range scriptExtensions {
+		if ext == scriptExt {
+			return true
+		}
+	}
+	return false
+}
+
+// ScriptParser PowerShell 和批处理脚本解析器，解码 -EncodedCommand 后重新扫描
+type ScriptParser struct {
+	rules   []DetectionRule
+	matcher *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+}
+
+// NewScriptParser 创建脚本解析器，同时使用命令行凭据规则
+func NewScriptParser() *ScriptParser {
+	return &ScriptParser{rules: append(initScriptRules(), initHistoryRules()...)}
+}
+
+// FilterRules 按规则名称筛选脚本凭据规则
+func (p *ScriptParser) FilterRules(only, skip []string) {
+	p.rules = filterRules(p.rules, only, skip)
+}
+
+// initScriptRules 初始化脚本凭据规则，第一个非空捕获组为凭据值
+func initScriptRules() []DetectionRule {
+	return []DetectionRule{
+		{
+			Name:        "SecureString明文",
+			Pattern:     regexp.MustCompile(`(?i)ConvertTo-SecureString\s+(?:-String\s+)?("[^"]+"|'[^']+'|[^\s"'-]\S*)[^|;]*-AsPlainText`),
+			Description: "ConvertTo-SecureString -AsPlainText 使用的明文口令",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "SecureString密文",
+			Pattern:     regexp.MustCompile(`(?i)\b(01000000d08c9ddf0115d1118c7a00c04fc297eb[0-9a-f]{32,})`),
+			Description: "DPAPI 加密的 SecureString，可在同一用户下解密",
+			RiskLevel:   "high",
+			Tags:        []string{"shell", "password"},
+		},
+		{
+			Name:        "net use凭据",
+			Pattern:     regexp.MustCompile(`(?i)\bnet\s+use\b.*?(?:\s/u(?:ser)?:\S+\s+("[^"]+"|[^\s/"]\S*)|\s("[^"]+"|[^\s/\\"*]\S*)\s+/u(?:ser)?:)`),
+			Description: "net use 映射共享时传递的密码",
+			RiskLevel:   "critical",
+			Tags:        []string{"shell", "password"},
+		},
+	}
+}
+
+// Parse 解析脚本文件，规则未命中的行再按关键字匹配
+func (p *ScriptParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	var matchingLines []string
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("打开文件%s错误", filePath)
+		return matchingLines
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024) // 编码命令可能很长
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+
+		results := p.checkLine(lineNum, line, scriptSource, keywords, 0)
+		if len(results) == 0 {
+			if keyword, ok := p.matcher.find(line, keywords); ok {
+				results = append(results, formatTextResult(keyword, lineNum, line))
+			}
+		}
+
+		for _, lineOutput := range results {
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		logger.Warnf("读取文件错误%s: %v", filePath, err)
+	}
+
+	return matchingLines
+}
+
+// checkLine 对一行脚本应用凭据规则，并解码其中的编码命令后重新扫描
+// 编码命令中的结果使用原始行号，关键字结果的内容为解码后的命令
+func (p *ScriptParser) checkLine(lineNum int, line, source string, keywords []string, depth int) []string {
+	results := matchCommandRules(p.rules, source, lineNum, line)
+	if depth >= maxEncodedDepth {
+		return results
+	}
+
+	for _, match := range encodedCommandPattern.FindAllStringSubmatch(line, -1) {
+		if !isEncodedCommandFlag(match[1]) {
+			continue
+		}
+		command, ok := decodeEncodedCommand(match[2])
+		if !ok {
+			continue
+		}
+		flat := strings.Join(strings.Fields(strings.NewReplacer("\r\n", "; ", "\n", "; ").Replace(strings.TrimSpace(command))), " ")
+		results = append(results, formatLineResult(source, lineNum, "PowerShell编码命令", "medium", flat, line))
+
+		for _, decoded := range strings.Split(command, "\n") {
+			decoded = strings.TrimSpace(decoded)
+			if decoded == "" {
+				continue
+			}
+			nested := p.checkLine(lineNum, decoded, encodedSource, keywords, depth+1)
+			if len(nested) == 0 {
+				if keyword, ok := p.matcher.find(decoded, keywords); ok {
+					nested = append(nested, formatTextResult(keyword, lineNum, decoded))
+				}
+			}
+			results = append(results, nested...)
+		}
+	}
+	return results
+}
+
+// isEncodedCommandFlag 判断参数名是否为 -EncodedCommand 的合法缩写
+func isEncodedCommandFlag(flag string) bool {
+	flag = strings.ToLower(flag)
+	return flag == "ec" || strings.HasPrefix("encodedcommand", flag)
+}
+
+// decodeEncodedCommand 解码 -EncodedCommand 的值（Base64 编码的 UTF-16LE 文本）
+func decodeEncodedCommand(value string) (string, bool) {
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(data) < 2 || len(data)%2 != 0 {
+		return "", false
+	}
+
+	command := decodeUTF16(data, false)
+	if textRatio(command) < DefaultTextThreshold {
+		return "", false
+	}
+	return string(command), true
+}
@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodedCommand 按 PowerShell -EncodedCommand 的格式编码命令（UTF-16LE 后 Base64）
+func encodedCommand(command string) string {
+	var b []byte
+	for _, u := range utf16.Encode([]rune(command)) {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func TestScriptParser(t *testing.T) {
+	encoded := encodedCommand("$c = ConvertTo-SecureString 'Enc0ded!Pass' -AsPlainText -Force\nInvoke-Sql -token x")
+	nested := encodedCommand("powershell -enc " + encodedCommand("sshpass -p 'N3sted!' ssh ops@10.0.0.5"))
+
+	tests := []struct {
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			file: "deploy.ps1",
+			content: "$pw = ConvertTo-SecureString \"Plain-Pass1\" -AsPlainText -Force\n" +
+				"$blob = '01000000d08c9ddf0115d1118c7a00c04fc297eb010000001a2b3c4d5e6f70819203a4b5c6d7e8f9'\n" +
+				"powershell.exe -NoProfile -EncodedCommand " + encoded + "\n" +
+				"powershell -ec " + nested + "\n" +
+				"# rotate token monthly\n",
+			want: []string{
+				`LINE|脚本|1|SecureString明文|critical|Plain-Pass1|$pw = ConvertTo-SecureString "Plain-Pass1" -AsPlainText -Force`,
+				`LINE|脚本|2|SecureString密文|high|01000000d08c9ddf0115d1118c7a00c04fc297eb010000001a2b3c4d5e6f70819203a4b5c6d7e8f9|$blob = '01000000d08c9ddf0115d1118c7a00c04fc297eb010000001a2b3c4d5e6f70819203a4b5c6d7e8f9'`,
+				// 解码后的命令按原始行号报告，关键字结果的内容为解码后的命令
+				`LINE|脚本|3|PowerShell编码命令|medium|$c = ConvertTo-SecureString 'Enc0ded!Pass' -AsPlainText -Force; Invoke-Sql -token x|powershell.exe -NoProfile -EncodedCommand ` + encoded,
+				`LINE|PowerShell编码命令|3|SecureString明文|critical|Enc0ded!Pass|$c = ConvertTo-SecureString 'Enc0ded!Pass' -AsPlainText -Force`,
+				`TEXT|token|3|Invoke-Sql -token x`,
+				`LINE|脚本|4|PowerShell编码命令|medium|powershell -enc ` + encodedCommand("sshpass -p 'N3sted!' ssh ops@10.0.0.5") + `|powershell -ec ` + nested,
+				`LINE|PowerShell编码命令|4|PowerShell编码命令|medium|sshpass -p 'N3sted!' ssh ops@10.0.0.5|powershell -enc ` + encodedCommand("sshpass -p 'N3sted!' ssh ops@10.0.0.5"),
+				`LINE|PowerShell编码命令|4|sshpass密码|critical|N3sted!|sshpass -p 'N3sted!' ssh ops@10.0.0.5`,
+				`TEXT|token|5|# rotate token monthly`,
+			},
+		},
+		{
+			// 密码为 * 时由 net use 提示输入，不报告
+			file: "map-share.bat",
+			content: "@echo off\r\n" +
+				"net use Z: \\\\fs01\\share Sh4re-Pass /user:CORP\\svc_backup\r\n" +
+				"net use Y: \\\\fs02\\data /user:CORP\\ops \"Quoted Pass\"\r\n" +
+				"net use X: \\\\fs03\\pub * /user:guest\r\n" +
+				"set DB_PASSWORD=B4tch-Db\r\n" +
+				"mysqldump -u root -pDumpP4ss app > app.sql\r\n",
+			want: []string{
+				`LINE|脚本|2|net use凭据|critical|Sh4re-Pass|net use Z: \\fs01\share Sh4re-Pass /user:CORP\svc_backup`,
+				`LINE|脚本|3|net use凭据|critical|Quoted Pass|net use Y: \\fs02\data /user:CORP\ops "Quoted Pass"`,
+				`LINE|脚本|5|环境变量凭据|high|B4tch-Db|set DB_PASSWORD=B4tch-Db`,
+				`LINE|脚本|6|MySQL命令行密码|critical|DumpP4ss|mysqldump -u root -pDumpP4ss app > app.sql`,
+			},
+		},
+		{
+			// 无法解码的编码命令参数忽略
+			file:    "fetch.cmd",
+			content: "curl -s -u deploy:Curl-P4ss https://repo.corp.local/artifact.zip -o a.zip\n-Enc notbase64!!\n",
+			want:    []string{`LINE|脚本|1|curl认证|high|Curl-P4ss|curl -s -u deploy:Curl-P4ss https://repo.corp.local/artifact.zip -o a.zip`},
+		},
+	}
+
+	parser := NewScriptParser()
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if !IsScriptFile(path) {
+				t.Fatalf("IsScriptFile(%q) = false", tt.file)
+			}
+			if got := parser.Parse(path, []string{"token"}, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
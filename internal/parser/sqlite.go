@@ -0,0 +1,341 @@
+package parser
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// sqliteMagic SQLite 数据库文件头
+const sqliteMagic = "SQLite format 3\x00"
+
+// maxSQLiteDepth 表 B 树的最大遍历深度，防止损坏文件导致无限递归
+const maxSQLiteDepth = 32
+
+// sqliteDB 只读的最小 SQLite 解析器，仅支持遍历表 B 树（不支持 WAL 中未合并的数据）
+type sqliteDB struct {
+	data     []byte
+	pageSize int
+	usable   int // 每页可用字节数（页大小减去保留区）
+}
+
+// sqliteTable sqlite_master 中的表定义
+type sqliteTable struct {
+	Name     string
+	RootPage int
+	Columns  []string
+}
+
+// openSQLite 解析 SQLite 文件头
+func openSQLite(data []byte) (*sqliteDB, error) {
+	if len(data) < 100 || string(data[:16]) != sqliteMagic {
+		return nil, fmt.Errorf("不是SQLite数据库")
+	}
+
+	pageSize := int(binary.BigEndian.Uint16(data[16:18]))
+	if pageSize == 1 {
+		pageSize = 65536
+	}
+	if pageSize < 512 || pageSize&(pageSize-1) != 0 {
+		return nil, fmt.Errorf("无效的SQLite页大小: %d", pageSize)
+	}
+
+	return &sqliteDB{
+		data:     data,
+		pageSize: pageSize,
+		usable:   pageSize - int(data[20]),
+	}, nil
+}
+
+// page 获取页内容（页号从1开始）
+func (db *sqliteDB) page(n int) ([]byte, error) {
+	start := (n - 1) * db.pageSize
+	if n < 1 || start+db.pageSize > len(db.data) {
+		return nil, fmt.Errorf("SQLite页号越界: %d", n)
+	}
+	return db.data[start : start+db.pageSize], nil
+}
+
+// tables 读取 sqlite_master 中的所有表
+func (db *sqliteDB) tables() ([]sqliteTable, error) {
+	var tables []sqliteTable
+	err := db.forEachRow(1, func(values []interface{}) {
+		// type, name, tbl_name, rootpage, sql
+		if len(values) < 5 || values[0] != "table" {
+			return
+		}
+		name, _ := values[1].(string)
+		root, _ := values[3].(int64)
+		sql, _ := values[4].(string)
+		tables = append(tables, sqliteTable{
+			Name:     name,
+			RootPage: int(root),
+			Columns:  parseSQLiteColumns(sql),
+		})
+	})
+	return tables, err
+}
+
+// forEachRow 遍历表 B 树中的所有记录
+func (db *sqliteDB) forEachRow(root int, fn func(values []interface{})) error {
+	return db.walkTable(root, 0, make(map[int]bool), fn)
+}
+
+// walkTable 递归遍历表 B 树，visited 用于防止损坏文件中的循环引用
+func (db *sqliteDB) walkTable(pageNum, depth int, visited map[int]bool, fn func(values []interface{})) error {
+	if depth > maxSQLiteDepth || visited[pageNum] {
+		return fmt.Errorf("SQLite B树结构异常")
+	}
+	visited[pageNum] = true
+
+	page, err := db.page(pageNum)
+	if err != nil {
+		return err
+	}
+
+	hdr := 0
+	if pageNum == 1 {
+		hdr = 100
+	}
+	if hdr+8 > len(page) {
+		return fmt.Errorf("SQLite页头不完整")
+	}
+
+	pageType := page[hdr]
+	cellCount := int(binary.BigEndian.Uint16(page[hdr+3 : hdr+5]))
+
+	headerSize := 8
+	if pageType == 0x05 {
+		headerSize = 12
+	}
+	if hdr+headerSize+cellCount*2 > len(page) {
+		return fmt.Errorf("SQLite单元格指针越界")
+	}
+
+	for i := 0; i < cellCount; i++ {
+		ptr := hdr + headerSize + i*2
+		cell := int(binary.BigEndian.Uint16(page[ptr : ptr+2]))
+		if cell >= len(page) {
+			continue
+		}
+
+		switch pageType {
+		case 0x05: // 表内部页：左子页号 + rowid
+			if cell+4 > len(page) {
+				continue
+			}
+			child := int(binary.BigEndian.Uint32(page[cell : cell+4]))
+			if err := db.walkTable(child, depth+1, visited, fn); err != nil {
+				return err
+			}
+		case 0x0D: // 表叶子页：负载长度 + rowid + 负载
+			payload, err := db.cellPayload(page, cell)
+			if err != nil {
+				continue
+			}
+			if values, err := decodeSQLiteRecord(payload); err == nil {
+				fn(values)
+			}
+		default:
+			return fmt.Errorf("不支持的SQLite页类型: 0x%02X", pageType)
+		}
+	}
+
+	if pageType == 0x05 {
+		right := int(binary.BigEndian.Uint32(page[hdr+8 : hdr+12]))
+		return db.walkTable(right, depth+1, visited, fn)
+	}
+	return nil
+}
+
+// cellPayload 读取叶子单元格的完整负载，超出页内部分从溢出页链中拼接
+func (db *sqliteDB) cellPayload(page []byte, cell int) ([]byte, error) {
+	size, n := readVarint(page[cell:])
+	if n == 0 {
+		return nil, fmt.Errorf("无效的负载长度")
+	}
+	pos := cell + n
+	if _, n = readVarint(page[pos:]); n == 0 {
+		return nil, fmt.Errorf("无效的rowid")
+	}
+	pos += n
+
+	total := int(size)
+	if total < 0 || total > len(db.data) {
+		return nil, fmt.Errorf("负载长度越界")
+	}
+
+	// 计算页内保存的负载长度（SQLite 文件格式 2.3.1 节）
+	local := total
+	maxLocal := db.usable - 35
+	if total > maxLocal {
+		minLocal := (db.usable-12)*32/255 - 23
+		local = minLocal + (total-minLocal)%(db.usable-4)
+		if local > maxLocal {
+			local = minLocal
+		}
+	}
+	if pos+local > len(page) {
+		return nil, fmt.Errorf("负载越界")
+	}
+
+	payload := make([]byte, 0, total)
+	payload = append(payload, page[pos:pos+local]...)
+	if local == total {
+		return payload, nil
+	}
+
+	if pos+local+4 > len(page) {
+		return nil, fmt.Errorf("溢出页号越界")
+	}
+	next := int(binary.BigEndian.Uint32(page[pos+local : pos+local+4]))
+	for hops := 0; next != 0 && len(payload) < total; hops++ {
+		if hops > len(db.data)/db.pageSize {
+			return nil, fmt.Errorf("溢出页链异常")
+		}
+		overflow, err := db.page(next)
+		if err != nil {
+			return nil, err
+		}
+		chunk := overflow[4:db.usable]
+		if remaining := total - len(payload); len(chunk) > remaining {
+			chunk = chunk[:remaining]
+		}
+		payload = append(payload, chunk...)
+		next = int(binary.BigEndian.Uint32(overflow[:4]))
+	}
+	if len(payload) < total {
+		return nil, fmt.Errorf("溢出页数据不完整")
+	}
+	return payload, nil
+}
+
+// decodeSQLiteRecord 解码记录格式，返回 nil、int64、float64、string 或 []byte
+func decodeSQLiteRecord(payload []byte) ([]interface{}, error) {
+	headerSize, n := readVarint(payload)
+	if n == 0 || int(headerSize) > len(payload) || headerSize < uint64(n) {
+		return nil, fmt.Errorf("无效的记录头")
+	}
+
+	var types []uint64
+	for pos := n; pos < int(headerSize); {
+		t, n := readVarint(payload[pos:int(headerSize)])
+		if n == 0 {
+			return nil, fmt.Errorf("无效的字段类型")
+		}
+		types = append(types, t)
+		pos += n
+	}
+
+	values := make([]interface{}, 0, len(types))
+	body := payload[headerSize:]
+	for _, t := range types {
+		var size int
+		switch {
+		case t == 0, t == 8, t == 9:
+			size = 0
+		case t >= 1 && t <= 4:
+			size = int(t)
+		case t == 5:
+			size = 6
+		case t == 6, t == 7:
+			size = 8
+		case t >= 12:
+			size = int(t-12) / 2
+		default:
+			return nil, fmt.Errorf("无效的字段类型: %d", t)
+		}
+		if size > len(body) {
+			return nil, fmt.Errorf("字段越界")
+		}
+		raw := body[:size]
+		body = body[size:]
+
+		switch {
+		case t == 0:
+			values = append(values, nil)
+		case t == 8:
+			values = append(values, int64(0))
+		case t == 9:
+			values = append(values, int64(1))
+		case t == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(raw)))
+		case t <= 6:
+			// 大端有符号整数
+			var v int64
+			for _, b := range raw {
+				v = v<<8 | int64(b)
+			}
+			shift := uint(64 - 8*size)
+			values = append(values, v<<shift>>shift)
+		case t%2 == 0:
+			values = append(values, raw)
+		default:
+			values = append(values, string(raw))
+		}
+	}
+	return values, nil
+}
+
+// readVarint 读取 SQLite 变长整数，返回值和占用字节数（0 表示数据不足）
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return v<<8 | uint64(b[i]), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7F)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// parseSQLiteColumns 从 CREATE TABLE 语句中提取列名
+func parseSQLiteColumns(sql string) []string {
+	start := strings.Index(sql, "(")
+	end := strings.LastIndex(sql, ")")
+	if start < 0 || end <= start {
+		return nil
+	}
+
+	var columns []string
+	depth := 0
+	begin := start + 1
+	for i := start + 1; i <= end; i++ {
+		switch sql[i] {
+		case '(':
+			depth++
+			continue
+		case ')':
+			if i < end {
+				depth--
+				continue
+			}
+		case ',':
+			if depth > 0 {
+				continue
+			}
+		default:
+			continue
+		}
+
+		def := strings.TrimSpace(sql[begin:i])
+		begin = i + 1
+		fields := strings.Fields(def)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PRIMARY", "UNIQUE", "CHECK", "FOREIGN", "CONSTRAINT":
+			continue
+		}
+		columns = append(columns, strings.Trim(fields[0], "\"`[]'"))
+	}
+	return columns
+}
@@ -0,0 +1,403 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// TerraformSecretRuleName Terraform 状态和变量文件中敏感属性的规则名称
+const TerraformSecretRuleName = "Terraform敏感值"
+
+// terraformSecretKey 视为敏感的属性名和变量名
+var terraformSecretKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|private_?key|access_?key|api_?key|credential|connection_?string|sas_?key)`)
+
+// terraformPlainSuffixes 属性名以这些后缀结尾时只是引用或说明（如 kms_key_id、secret_arn），不视为敏感
+var terraformPlainSuffixes = []string{"_id", "_ids", "_arn", "_arns", "_name", "_type", "_version", "_policy", "_rotation", "_enabled", "_length"}
+
+// terraformAssignment tfvars 中的赋值行: 键 = 值，键可带引号
+var terraformAssignment = regexp.MustCompile(`^"?([\w.-]+)"?\s*[=:]\s*(.*)$`)
+
+// IsTerraformFile 判断是否为 Terraform 状态文件（.tfstate）或变量文件（.tfvars、.tfvars.json）
+func IsTerraformFile(filePath string) bool {
+	lower := strings.ToLower(filePath)
+	for _, suffix := range []string{".tfstate", ".tfstate.backup", ".tfvars", ".tfvars.json"} {
+		if strings.HasSuffix(lower, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTerraformSecretKey 判断属性名或变量名是否像密码、密钥等敏感字段
+func isTerraformSecretKey(name string) bool {
+	lower := strings.ToLower(name)
+	for _, suffix := range terraformPlainSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return false
+		}
+	}
+	return terraformSecretKey.MatchString(lower)
+}
+
+// TerraformParser Terraform 状态和变量文件解析器，遍历资源属性、输出和变量，报告资源地址和属性路径
+type TerraformParser struct {
+	binaryParser *BinaryParser
+	textParser   *TextParser
+}
+
+// NewTerraformParser 创建 Terraform 解析器
+func NewTerraformParser(binaryParser *BinaryParser, textParser *TextParser) *TerraformParser {
+	return &TerraformParser{
+		binaryParser: binaryParser,
+		textParser:   textParser,
+	}
+}
+
+// terraformState 状态文件中需要的字段（version 4，Terraform 0.12 及以后）
+type terraformState struct {
+	Version   int                        `json:"version"`
+	Outputs   map[string]terraformOutput `json:"outputs"`
+	Resources []terraformResource        `json:"resources"`
+}
+
+// terraformOutput 状态文件中的输出值
+type terraformOutput struct {
+	Value     interface{} `json:"value"`
+	Sensitive bool        `json:"sensitive"`
+}
+
+// terraformResource version 4 状态文件中的资源
+type terraformResource struct {
+	Module    string `json:"module"`
+	Mode      string `json:"mode"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Instances []struct {
+		IndexKey            interface{}            `json:"index_key"`
+		Attributes          map[string]interface{} `json:"attributes"`
+		SensitiveAttributes []json.RawMessage      `json:"sensitive_attributes"`
+	} `json:"instances"`
+}
+
+// Parse 解析 Terraform 文件，状态文件和 .tfvars.json 按 JSON 解析，.tfvars 按 HCL 赋值逐行解析
+func (p *TerraformParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取文件%s错误", filePath)
+		return nil
+	}
+
+	var matchingLines []string
+	lower := strings.ToLower(filePath)
+	switch {
+	case strings.HasSuffix(lower, ".tfvars"):
+		matchingLines = p.parseVars(data, keywords)
+	case strings.HasSuffix(lower, ".tfvars.json"):
+		var vars map[string]interface{}
+		if err := json.Unmarshal(data, &vars); err != nil {
+			logger.Debugf("解析变量文件%s失败，回退到文本扫描: %v", filePath, err)
+			return p.textParser.Parse(filePath, keywords, verbose)
+		}
+		for _, name := range sortedJSONKeys(vars) {
+			matchingLines = append(matchingLines, p.walk("var."+name, name, vars[name], false, keywords)...)
+		}
+	default:
+		var state terraformState
+		if err := json.Unmarshal(data, &state); err != nil {
+			logger.Debugf("解析状态文件%s失败，回退到文本扫描: %v", filePath, err)
+			return p.textParser.Parse(filePath, keywords, verbose)
+		}
+		// 更早版本的状态文件结构不同（modules 和扁平属性），按文本扫描
+		if state.Version < 4 {
+			return p.textParser.Parse(filePath, keywords, verbose)
+		}
+		matchingLines = p.checkState(&state, keywords)
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// checkState 检查状态文件中的输出和资源属性
+func (p *TerraformParser) checkState(state *terraformState, keywords []string) []string {
+	var results []string
+	names := make([]string, 0, len(state.Outputs))
+	for name := range state.Outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		output := state.Outputs[name]
+		results = append(results, p.walk("output."+name, name, output.Value, output.Sensitive, keywords)...)
+	}
+
+	for _, resource := range state.Resources {
+		address := resource.Type + "." + resource.Name
+		if resource.Mode == "data" {
+			address = "data." + address
+		}
+		if resource.Module != "" {
+			address = resource.Module + "." + address
+		}
+		for _, instance := range resource.Instances {
+			instanceAddress := address
+			switch key := instance.IndexKey.(type) {
+			case float64:
+				instanceAddress += "[" + strconv.FormatFloat(key, 'f', -1, 64) + "]"
+			case string:
+				instanceAddress += "[" + strconv.Quote(key) + "]"
+			}
+			sensitive := sensitiveAttributePaths(instance.SensitiveAttributes)
+			for _, name := range sortedJSONKeys(instance.Attributes) {
+				results = append(results, p.walkAttribute(instanceAddress, name, name, instance.Attributes[name], sensitive, keywords)...)
+			}
+		}
+	}
+
+	return results
+}
+
+// walkAttribute 遍历资源属性，path 为相对资源的属性路径，用于匹配 sensitive_attributes
+// 被标记为敏感的对象或列表中的所有值都按敏感值检查
+func (p *TerraformParser) walkAttribute(address, path, name string, value interface{}, sensitive map[string]bool, keywords []string) []string {
+	if sensitive[path] {
+		return p.walk(address+"."+path, name, value, true, keywords)
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var results []string
+		for _, key := range sortedJSONKeys(v) {
+			results = append(results, p.walkAttribute(address, path+"."+key, key, v[key], sensitive, keywords)...)
+		}
+		return results
+	case []interface{}:
+		var results []string
+		for i, item := range v {
+			results = append(results, p.walkAttribute(address, path+"["+strconv.Itoa(i)+"]", name, item, sensitive, keywords)...)
+		}
+		return results
+	default:
+		return p.walk(address+"."+path, name, value, false, keywords)
+	}
+}
+
+// walk 递归遍历 JSON 值，location 为完整地址，name 为最近的属性名
+// sensitive 为 true 时（输出或属性被 Terraform 标记为敏感）其中的所有字符串都按敏感值报告
+func (p *TerraformParser) walk(location, name string, value interface{}, sensitive bool, keywords []string) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		var results []string
+		for _, key := range sortedJSONKeys(v) {
+			results = append(results, p.walk(location+"."+key, key, v[key], sensitive, keywords)...)
+		}
+		return results
+	case []interface{}:
+		var results []string
+		for i, item := range v {
+			results = append(results, p.walk(location+"["+strconv.Itoa(i)+"]", name, item, sensitive, keywords)...)
+		}
+		return results
+	case string:
+		return p.checkValue(location, name, v, sensitive, keywords)
+	}
+	return nil
+}
+
+// checkValue 检查单个属性值: 先以 "属性名=值" 的形式匹配规则，未命中时敏感属性按 Terraform敏感值 报告，最后匹配关键字
+func (p *TerraformParser) checkValue(location, name, value string, sensitive bool, keywords []string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+
+	var results []string
+	candidate := name + "=" + value
+	for _, result := range p.binaryParser.MatchString(candidate) {
+		results = append(results, formatTerraformResult(location, result.RuleName, result.RiskLevel, "", result.MatchedValue, value))
+	}
+	if len(results) > 0 {
+		return results
+	}
+
+	if (sensitive || isTerraformSecretKey(name)) && !isPlaceholderValue(value) && !strings.HasPrefix(value, "var.") {
+		return []string{formatTerraformResult(location, TerraformSecretRuleName, "high", "", value, value)}
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(candidate, keywords); ok {
+		return []string{formatTerraformResult(location, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, value, value)}
+	}
+
+	return nil
+}
+
+// sensitiveAttributePaths 将 version 4 状态文件中的 sensitive_attributes 转换为属性路径集合（如 password、connection[0].password）
+// 每个路径为步骤列表，如 [{"type":"get_attr","value":"connection"},{"type":"index","value":0}]
+func sensitiveAttributePaths(raw []json.RawMessage) map[string]bool {
+	type step struct {
+		Type  string      `json:"type"`
+		Value interface{} `json:"value"`
+	}
+
+	paths := make(map[string]bool)
+	for _, item := range raw {
+		var steps []step
+		if err := json.Unmarshal(item, &steps); err != nil {
+			continue
+		}
+
+		var sb strings.Builder
+		for _, s := range steps {
+			switch value := s.Value.(type) {
+			case string:
+				if s.Type == "get_attr" {
+					if sb.Len() > 0 {
+						sb.WriteString(".")
+					}
+					sb.WriteString(value)
+				} else {
+					sb.WriteString("." + value)
+				}
+			case float64:
+				sb.WriteString("[" + strconv.FormatFloat(value, 'f', -1, 64) + "]")
+			}
+		}
+		if sb.Len() > 0 {
+			paths[sb.String()] = true
+		}
+	}
+	return paths
+}
+
+// parseVars 逐行解析 .tfvars 中的 HCL 赋值，支持嵌套的对象/映射、多行列表和 heredoc（<<EOT）
+// 不是赋值的行（如列表元素）按所在变量检查
+func (p *TerraformParser) parseVars(data []byte, keywords []string) []string {
+	var results []string
+	var blocks []string // 当前所在的对象或列表的键路径
+	var heredocEnd, heredocName string
+	var heredoc []string
+	inComment := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+
+		if heredocEnd != "" {
+			if line == heredocEnd {
+				results = append(results, p.checkValue(varLocation(blocks, heredocName), heredocName, strings.Join(heredoc, "\n"), false, keywords)...)
+				heredocEnd, heredoc = "", nil
+			} else {
+				heredoc = append(heredoc, raw)
+			}
+			continue
+		}
+
+		if inComment {
+			if _, after, ok := strings.Cut(line, "*/"); ok {
+				inComment = false
+				line = strings.TrimSpace(after)
+			} else {
+				continue
+			}
+		}
+		if strings.HasPrefix(line, "/*") {
+			if _, after, ok := strings.Cut(line, "*/"); ok {
+				line = strings.TrimSpace(after)
+			} else {
+				inComment = true
+				continue
+			}
+		}
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+
+		if line == "}" || line == "]" || line == "}," || line == "]," {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+
+		match := terraformAssignment.FindStringSubmatch(line)
+		if match == nil {
+			// 多行列表中的元素
+			if len(blocks) > 0 {
+				name := blocks[len(blocks)-1]
+				results = append(results, p.checkValue(varLocation(blocks, ""), name, trimHCLValue(line), false, keywords)...)
+			}
+			continue
+		}
+
+		name, value := match[1], strings.TrimSpace(match[2])
+		switch {
+		case value == "{" || value == "[":
+			blocks = append(blocks, name)
+		case strings.HasPrefix(value, "<<"):
+			heredocName = name
+			heredocEnd = strings.TrimPrefix(strings.TrimPrefix(value, "<<"), "-")
+		default:
+			results = append(results, p.checkValue(varLocation(blocks, name), name, trimHCLValue(value), false, keywords)...)
+		}
+	}
+
+	return results
+}
+
+// varLocation 生成变量的位置，如 var.db.password
+func varLocation(blocks []string, name string) string {
+	parts := append([]string{"var"}, blocks...)
+	if name != "" {
+		parts = append(parts, name)
+	}
+	return strings.Join(parts, ".")
+}
+
+// trimHCLValue 去掉值末尾的逗号、行尾注释和包围的引号
+func trimHCLValue(value string) string {
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), ","))
+	if strings.HasPrefix(value, `"`) {
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		if end := strings.Index(value[1:], `"`); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value
+}
+
+// sortedJSONKeys 获取 JSON 对象排序后的键，保证结果顺序稳定
+func sortedJSONKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatTerraformResult 格式化 Terraform 扫描结果，多行值中的换行替换为 ⏎
+func formatTerraformResult(location, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	flatten := strings.NewReplacer("\r\n", " ⏎ ", "\n", " ⏎ ").Replace
+	return fmt.Sprintf("TERRAFORM|%s|%s|%s|%s|%s|%s", strings.ReplaceAll(location, "|", "/"), ruleName, riskLevel, keyword, flatten(matchedValue), flatten(content))
+}
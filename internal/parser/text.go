@@ -2,17 +2,31 @@ package parser
 
 import (
 	"bufio"
+	"compress/gzip"
 	"fmt"
+	"io"
 	"os"
-	"strings"
+
+	"Findx/internal/logger"
 )
 
 // TextParser 文本文件解析器
-type TextParser struct{}
+type TextParser struct {
+	matcher   *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+	detectJWT bool            // 识别行中的JWT（报告时解码声明）
+	codeRules []DetectionRule // 源代码文件的硬编码凭据规则
+
+	entropyRules     []DetectionRule // 高熵赋值规则（未配置敏感变量名关键词时为空）
+	entropyWords     []string        // 高熵赋值规则的敏感变量名关键词（小写），用于正则匹配前的快速筛选
+	entropyThreshold float64         // 高熵赋值规则的信息熵阈值
+}
+
+// textSource 文本文件中规则匹配结果的来源名称
+const textSource = "文本文件"
 
 // NewTextParser 创建文本解析器
 func NewTextParser() *TextParser {
-	return &TextParser{}
+	return &TextParser{detectJWT: true, codeRules: initCodeRules()}
 }
 
 // Parse 解析文本文件内容
@@ -20,30 +34,83 @@ func (p *TextParser) Parse(filePath string, keywords []string, verbose bool) []s
 	var matchingLines []string
 	file, err := os.Open(filePath)
 	if err != nil {
-		fmt.Printf("[-] 打开文件%s错误\n", filePath)
+		logger.Warnf("打开文件%s错误", filePath)
 		return matchingLines
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
+	return p.parseReader(filePath, file, keywords, verbose)
+}
+
+// maxGzipTextSize 解压 .gz 文件时的最大读取量，防止压缩炸弹
+const maxGzipTextSize = 256 * 1024 * 1024
+
+// ParseGzip 解压并扫描 gzip 压缩的文本文件（如轮转后的日志）
+func (p *TextParser) ParseGzip(filePath string, keywords []string, verbose bool) []string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("打开文件%s错误", filePath)
+		return nil
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		logger.Warnf("解压文件失败%s: %v", filePath, err)
+		return nil
+	}
+	defer reader.Close()
+
+	return p.parseReader(filePath, io.LimitReader(reader, maxGzipTextSize), keywords, verbose)
+}
+
+// parseReader 逐行扫描文本内容，源代码文件额外按变量名识别硬编码凭据
+func (p *TextParser) parseReader(filePath string, r io.Reader, keywords []string, verbose bool) []string {
+	var matchingLines []string
+	code := IsCodeFile(filePath)
+	var pairs linePairs
+	scanner := bufio.NewScanner(r)
 	lineNum := 1
 	for scanner.Scan() {
 		line := scanner.Text()
-		for _, keyword := range keywords {
-			if strings.Contains(line, keyword) {
-				lineOutput := formatTextResult(keyword, lineNum, line)
-				matchingLines = append(matchingLines, lineOutput)
-				if verbose {
-					fmt.Println(lineOutput)
+		var results []string
+		if p.detectJWT {
+			for _, token := range jwtPattern.FindAllString(line, -1) {
+				results = append(results, formatLineResult(textSource, lineNum, "JWT令牌", "high", token, line))
+			}
+		}
+		if len(results) == 0 && code {
+			results = matchCodeRules(p.codeRules, lineNum, line)
+		}
+		if len(results) == 0 {
+			// 高熵赋值规则与关键字匹配同时报告，关键字结果保留其自身的风险等级
+			if len(p.entropyRules) > 0 && mayHaveSecretAssignment(line, p.entropyWords) {
+				source := textSource
+				if code {
+					source = codeSource
+				}
+				for i := 0; i < len(p.entropyRules) && len(results) == 0; i++ {
+					results = matchEntropyRule(&p.entropyRules[i], p.entropyThreshold, source, lineNum, line)
 				}
-				break // 找到一个匹配的字段即可
+			}
+			if keyword, ok := p.matcher.find(line, keywords); ok {
+				results = append(results, formatTextResult(keyword, lineNum, line))
+			}
+		}
+		if pair, ok := pairs.check(textSource, lineNum, line); ok {
+			results = append(results, pair)
+		}
+		for _, lineOutput := range results {
+			matchingLines = append(matchingLines, lineOutput)
+			if verbose {
+				fmt.Println(lineOutput)
 			}
 		}
 		lineNum++
 	}
 
 	if err := scanner.Err(); err != nil {
-		fmt.Printf("[-] 读取文件错误%s: %v\n", filePath, err)
+		logger.Warnf("读取文件错误%s: %v", filePath, err)
 	}
 
 	return matchingLines
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"bytes"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// DefaultTextThreshold 判断解码内容为文本时可打印字符的默认最低比例
+const DefaultTextThreshold = 0.7
+
+// textContent 判断数据是否为文本，返回 UTF-8 编码的文本内容
+// 依次识别 BOM、UTF-8（多字节字符按可打印计算）和 UTF-16（按零字节分布或常用字符范围判断字节序）
+func textContent(data []byte, threshold float64) ([]byte, bool) {
+	if len(data) == 0 {
+		return nil, false
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return decodeUTF16(data[2:], false), true
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return decodeUTF16(data[2:], true), true
+	}
+
+	if textRatio(data) > threshold {
+		return data, true
+	}
+
+	// UTF-16 中的 ASCII 字符包含大量零字节，中文等字符则集中在常用区段
+	for _, bigEndian := range []bool{false, true} {
+		if utf16Ratio(data, bigEndian) > threshold {
+			return decodeUTF16(data, bigEndian), true
+		}
+	}
+
+	return nil, false
+}
+
+// textRatio 计算可打印字符所占的字节比例
+// 合法的 UTF-8 多字节字符按其全部字节计入，末尾被截断的多字节序列不视为噪声
+func textRatio(data []byte) float64 {
+	printable := 0
+	for i := 0; i < len(data); {
+		b := data[i]
+		if b < utf8.RuneSelf {
+			if b >= 32 && b <= 126 || b == 10 || b == 13 || b == 9 {
+				printable++
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(data[i:])
+		if r == utf8.RuneError && size <= 1 {
+			if !utf8.FullRune(data[i:]) {
+				printable += len(data) - i
+				break
+			}
+			i++
+			continue
+		}
+		if unicode.IsPrint(r) {
+			printable += size
+		}
+		i += size
+	}
+	return float64(printable) / float64(len(data))
+}
+
+// utf16Ratio 按 UTF-16 解读数据时常用字符所占的比例
+// 只计入 ASCII 可打印字符、CJK 统一汉字、CJK 标点和全角字符，随机字节落入这些区段的概率约为三分之一
+func utf16Ratio(data []byte, bigEndian bool) float64 {
+	units := len(data) / 2
+	if units < 2 {
+		return 0
+	}
+
+	common := 0
+	for i := 0; i+1 < len(data); i += 2 {
+		u := utf16Unit(data[i:], bigEndian)
+		switch {
+		case u >= 32 && u <= 126, u == 10, u == 13, u == 9:
+			common++
+		case u >= 0x4E00 && u <= 0x9FFF, u >= 0x3000 && u <= 0x303F, u >= 0xFF00 && u <= 0xFFEF:
+			common++
+		}
+	}
+	return float64(common) / float64(units)
+}
+
+// utf16Unit 读取一个 UTF-16 码元
+func utf16Unit(b []byte, bigEndian bool) uint16 {
+	if bigEndian {
+		return uint16(b[0])<<8 | uint16(b[1])
+	}
+	return uint16(b[1])<<8 | uint16(b[0])
+}
+
+// decodeUTF16 将 UTF-16 数据转换为 UTF-8，末尾的奇数字节被忽略
+func decodeUTF16(data []byte, bigEndian bool) []byte {
+	units := make([]uint16, 0, len(data)/2)
+	for i := 0; i+1 < len(data); i += 2 {
+		units = append(units, utf16Unit(data[i:], bigEndian))
+	}
+	return []byte(string(utf16.Decode(units)))
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"time"
+
+	"Findx/internal/logger"
+)
+
+// withTimeout 为规则设置单个字符串上的匹配时间上限，timeout 不大于0时不限制
+func withTimeout(rules []DetectionRule, timeout time.Duration) []DetectionRule {
+	for i := range rules {
+		rules[i].timeout = timeout
+	}
+	return rules
+}
+
+// ruleDeadline 一组规则在单个字符串上匹配的截止时间（--rule-timeout），零值表示不限制
+// 在规则之间检查，不为每次匹配创建协程和定时器；正在执行的匹配无法中止，超时后跳过该字符串上剩余的规则
+type ruleDeadline struct {
+	at time.Time
+}
+
+// startDeadline 开始用 rules 匹配一个字符串，未设置超时时不读取时钟
+func startDeadline(rules []DetectionRule) ruleDeadline {
+	if len(rules) == 0 || rules[0].timeout <= 0 {
+		return ruleDeadline{}
+	}
+	return ruleDeadline{at: time.Now().Add(rules[0].timeout)}
+}
+
+// exceeded 在匹配 rule 之前检查是否已超过截止时间，超过时记录警告，调用方跳过该字符串上剩余的规则
+func (d ruleDeadline) exceeded(rule *DetectionRule, s string) bool {
+	if d.at.IsZero() || time.Now().Before(d.at) {
+		return false
+	}
+	logger.Warnf("规则匹配超时，已跳过 %s 及之后的规则（超过 %s，字符串长度 %d）", rule.Name, rule.timeout, len(s))
+	return true
+}
+
+// findAll 在字符串中查找规则的所有匹配
+func (r *DetectionRule) findAll(s string) [][]string {
+	return r.Pattern.FindAllStringSubmatch(s, -1)
+}
@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestRuleDeadline 截止时间在规则之间检查，未设置超时时不限制
+func TestRuleDeadline(t *testing.T) {
+	rules := []DetectionRule{{Name: "密码字段"}}
+	if d := startDeadline(rules); d.exceeded(&rules[0], "x") {
+		t.Error("deadline without a timeout expired")
+	}
+	if d := startDeadline(withTimeout(rules, time.Hour)); d.exceeded(&rules[0], "x") {
+		t.Error("deadline expired before the timeout")
+	}
+	if d := (ruleDeadline{at: time.Now().Add(-time.Millisecond)}); !d.exceeded(&rules[0], "x") {
+		t.Error("past deadline not reported as exceeded")
+	}
+}
+
+// TestRuleTimeoutSameMatches 时间上限足够时结果与不限制时相同，且匹配不启动额外的协程
+func TestRuleTimeoutSameMatches(t *testing.T) {
+	input := strings.Repeat("x", 4096) + ` password=Hunter2024 jdbc:mysql://db01:3306/app?user=root&password=Pa55word`
+	want := NewBinaryParser().MatchString(input)
+	if len(want) == 0 {
+		t.Fatal("fixture produced no matches")
+	}
+
+	p := NewBinaryParser()
+	p.rules = withTimeout(p.rules, time.Hour)
+	before := runtime.NumGoroutine()
+	got := p.MatchString(input)
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("goroutines grew from %d to %d", before, after)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+)
+
+// emailPlaceholderDomains 示例和保留域名，其中的邮箱地址不报告
+var emailPlaceholderDomains = []string{"example.com", "example.org", "example.net", "domain.com", "email.com", "test.com"}
+
+// emailFileExtensions 形如 logo@2x.png 的文件名的扩展名，不是邮箱地址的顶级域名
+var emailFileExtensions = []string{"png", "jpg", "jpeg", "gif", "svg", "webp", "ico", "js", "css", "map", "woff", "woff2", "ttf"}
+
+// isValidEmail 校验邮箱地址规则的匹配值，排除文件名（如 icon@2x.png）、示例域名和保留的顶级域名
+func isValidEmail(value string) bool {
+	local, domain, ok := strings.Cut(strings.ToLower(value), "@")
+	if !ok || local == "" || strings.HasPrefix(domain, ".") || strings.Contains(domain, "..") {
+		return false
+	}
+
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	for _, ext := range emailFileExtensions {
+		if tld == ext {
+			return false
+		}
+	}
+	switch tld {
+	case "test", "example", "invalid", "localhost", "local":
+		return false
+	}
+	for _, placeholder := range emailPlaceholderDomains {
+		if domain == placeholder || strings.HasSuffix(domain, "."+placeholder) {
+			return false
+		}
+	}
+	return true
+}
+
+// isValidIPAddress 校验IP地址规则的匹配值（可带端口）
+// 每段需在 0-255 之间且没有前导零（排除版本号、OID 等），端口需在 1-65535 之间
+func isValidIPAddress(value string) bool {
+	host, port, hasPort := strings.Cut(value, ":")
+	if hasPort {
+		n, err := strconv.Atoi(port)
+		if err != nil || n < 1 || n > 65535 {
+			return false
+		}
+	}
+
+	parts := strings.Split(host, ".")
+	if len(parts) != 4 {
+		return false
+	}
+	var octets [4]int
+	for i, part := range parts {
+		if len(part) > 1 && part[0] == '0' {
+			return false
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n > 255 {
+			return false
+		}
+		octets[i] = n
+	}
+
+	// 0.x 未指定地址、127.x 回环地址、224 以上的组播/保留地址（包括 255.255.255.0 等子网掩码）
+	return octets[0] != 0 && octets[0] != 127 && octets[0] < 224
+}
+
+// skipValidation 不校验指定规则的匹配值（--no-validate），直接报告正则匹配的结果
+func skipValidation(rules []DetectionRule, names []string) []DetectionRule {
+	if len(names) == 0 {
+		return rules
+	}
+
+	skip := make(map[string]bool)
+	for _, name := range names {
+		skip[name] = true
+	}
+	for i := range rules {
+		if skip[rules[i].Name] {
+			rules[i].noValidate = true
+		}
+	}
+	return rules
+}
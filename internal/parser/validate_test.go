@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ipHeavyLog IP地址密集的日志：只有两行包含真实的对端地址，其他为监听地址、回环、子网掩码、组播、版本号和越界的数字
+var ipHeavyLog = []string{
+	"2024-05-01 10:00:00 INFO server listening on 0.0.0.0:8080",
+	"2024-05-01 10:00:00 INFO health check 127.0.0.1:9000 ok",
+	"2024-05-01 10:00:01 INFO iface eth0 netmask 255.255.255.0 broadcast 255.255.255.255",
+	"2024-05-01 10:00:01 DEBUG ssdp announce to 239.255.255.250:1900",
+	"2024-05-01 10:00:02 INFO agent version 10.04.2.1 loaded",
+	"2024-05-01 10:00:02 WARN counter overflow 300.1.2.3 reset",
+	"2024-05-01 10:00:03 INFO accepted connection from 203.0.113.7:51234",
+	"2024-05-01 10:00:03 INFO upstream db 10.1.2.3:5432 connected",
+	"2024-05-01 10:00:04 DEBUG bad port 10.1.2.3:70000 ignored",
+}
+
+// ipValues 逐行匹配日志，返回IP地址规则的匹配值
+func ipValues(p *BinaryParser, lines []string) []string {
+	var values []string
+	for _, line := range lines {
+		for _, result := range p.MatchString(line) {
+			if result.RuleName == "IP地址和端口" {
+				values = append(values, result.MatchedValue)
+			}
+		}
+	}
+	return values
+}
+
+func TestIPHeavyLogValidated(t *testing.T) {
+	want := []string{"203.0.113.7:51234", "10.1.2.3:5432"}
+	if got := ipValues(NewBinaryParser(), ipHeavyLog); !reflect.DeepEqual(got, want) {
+		t.Errorf("IP values = %q, want %q", got, want)
+	}
+}
+
+func TestIPHeavyLogNoValidate(t *testing.T) {
+	p := NewBinaryParser()
+	p.rules = skipValidation(p.rules, []string{"IP地址和端口"})
+
+	got := ipValues(p, ipHeavyLog)
+	// 不校验时每行的地址都报告（70000 不是合法端口，正则只匹配到地址部分）
+	if len(got) < len(ipHeavyLog) {
+		t.Errorf("--no-validate reported %d values, want at least %d: %q", len(got), len(ipHeavyLog), got)
+	}
+	for _, noise := range []string{"0.0.0.0:8080", "127.0.0.1:9000", "255.255.255.0", "10.04.2.1"} {
+		found := false
+		for _, value := range got {
+			found = found || value == noise
+		}
+		if !found {
+			t.Errorf("--no-validate dropped %q", noise)
+		}
+	}
+}
+
+// TestIPHeavyLogKeepsOtherRules 校验只影响IP地址规则，同一日志中的凭据照常报告
+func TestIPHeavyLogKeepsOtherRules(t *testing.T) {
+	lines := append(append([]string(nil), ipHeavyLog...),
+		"2024-05-01 10:00:05 ERROR login failed password=Tr0ub4dor from 127.0.0.1")
+
+	var rules []string
+	for _, line := range lines {
+		for _, result := range NewBinaryParser().MatchString(line) {
+			rules = append(rules, result.RuleName+"="+result.MatchedValue)
+		}
+	}
+	joined := strings.Join(rules, "\n")
+	if !strings.Contains(joined, "密码字段=Tr0ub4dor") {
+		t.Errorf("password in an IP-heavy log line was not reported:\n%s", joined)
+	}
+	if strings.Contains(joined, "127.0.0.1") {
+		t.Errorf("loopback address was reported:\n%s", joined)
+	}
+}
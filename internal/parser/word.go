@@ -2,13 +2,16 @@ package parser
 
 import (
 	"fmt"
-	"strings"
+
+	"Findx/internal/logger"
 
 	"github.com/carmel/gooxml/document"
 )
 
 // WordParser Word文档解析器
-type WordParser struct{}
+type WordParser struct {
+	matcher *keywordMatcher // 关键字匹配方式（nil 表示区分大小写）
+}
 
 // NewWordParser 创建Word解析器
 func NewWordParser() *WordParser {
@@ -20,7 +23,7 @@ func (p *WordParser) Parse(filePath string, keywords []string, verbose bool) []s
 	var matchingLines []string
 	doc, err := document.Open(filePath)
 	if err != nil {
-		fmt.Printf("[-] 打开Word文件%s错误\n", filePath)
+		logger.Warnf("打开Word文件%s错误", filePath)
 		return matchingLines
 	}
 
@@ -28,14 +31,11 @@ func (p *WordParser) Parse(filePath string, keywords []string, verbose bool) []s
 	for _, para := range doc.Paragraphs() {
 		for _, run := range para.Runs() {
 			text := run.Text()
-			for _, keyword := range keywords {
-				if strings.Contains(text, keyword) {
-					lineOutput := formatWordResult(keyword, "段落", text)
-					matchingLines = append(matchingLines, lineOutput)
-					if verbose {
-						fmt.Println(lineOutput)
-					}
-					break
+			if keyword, ok := p.matcher.find(text, keywords); ok {
+				lineOutput := formatWordResult(keyword, "段落", text)
+				matchingLines = append(matchingLines, lineOutput)
+				if verbose {
+					fmt.Println(lineOutput)
 				}
 			}
 		}
@@ -48,14 +48,11 @@ func (p *WordParser) Parse(filePath string, keywords []string, verbose bool) []s
 				for _, para := range cell.Paragraphs() {
 					for _, run := range para.Runs() {
 						text := run.Text()
-						for _, keyword := range keywords {
-							if strings.Contains(text, keyword) {
-								lineOutput := formatWordResult(keyword, "表格", text)
-								matchingLines = append(matchingLines, lineOutput)
-								if verbose {
-									fmt.Println(lineOutput)
-								}
-								break
+						if keyword, ok := p.matcher.find(text, keywords); ok {
+							lineOutput := formatWordResult(keyword, "表格", text)
+							matchingLines = append(matchingLines, lineOutput)
+							if verbose {
+								fmt.Println(lineOutput)
 							}
 						}
 					}
@@ -0,0 +1,131 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// XmlParser XML配置文件解析器，报告匹配项所在的元素路径
+type XmlParser struct {
+	binaryParser *BinaryParser
+	textParser   *TextParser
+}
+
+// NewXmlParser 创建XML解析器
+func NewXmlParser(binaryParser *BinaryParser, textParser *TextParser) *XmlParser {
+	return &XmlParser{
+		binaryParser: binaryParser,
+		textParser:   textParser,
+	}
+}
+
+// Parse 解析XML文件，格式错误时回退到文本扫描
+func (p *XmlParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	file, err := os.Open(filePath)
+	if err != nil {
+		logger.Warnf("打开XML文件%s错误", filePath)
+		return nil
+	}
+	defer file.Close()
+
+	var matchingLines []string
+	var stack []string
+
+	decoder := xml.NewDecoder(file)
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			logger.Debugf("XML解析失败，回退到文本扫描: %s: %v", filePath, err)
+			return p.textParser.Parse(filePath, keywords, verbose)
+		}
+
+		line, _ := decoder.InputPos()
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			elementPath := "/" + strings.Join(stack, "/")
+			settingKey := xmlSettingKey(t.Attr)
+			for _, attr := range t.Attr {
+				nodePath := elementPath + "@" + attr.Name.Local
+				name := attr.Name.Local
+				// <add key="ApiKey" value="..."/> 之类的键值对，值以键名匹配
+				if settingKey != "" && name == "value" {
+					name = settingKey
+				}
+				matchingLines = append(matchingLines, p.checkNode(nodePath, line, name, attr.Value, keywords)...)
+			}
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		case xml.CharData:
+			text := strings.TrimSpace(string(t))
+			if text == "" || len(stack) == 0 {
+				continue
+			}
+			elementPath := "/" + strings.Join(stack, "/")
+			matchingLines = append(matchingLines, p.checkNode(elementPath, line, stack[len(stack)-1], text, keywords)...)
+		}
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// xmlSettingKey 返回同时带有 key 和 value 属性的元素（如 web.config appSettings 中的 add）的键名
+func xmlSettingKey(attrs []xml.Attr) string {
+	var key string
+	hasValue := false
+	for _, attr := range attrs {
+		switch attr.Name.Local {
+		case "key":
+			key = attr.Value
+		case "value":
+			hasValue = true
+		}
+	}
+	if !hasValue {
+		return ""
+	}
+	return key
+}
+
+// checkNode 检查单个属性值或文本节点
+// 以 "名称=值" 的形式匹配，使 password= 之类的关键字也能命中 <password> 元素和 password 属性
+func (p *XmlParser) checkNode(nodePath string, line int, name, value string, keywords []string) []string {
+	var results []string
+	candidate := name + "=" + value
+
+	for _, result := range p.binaryParser.MatchString(candidate) {
+		results = append(results, formatXMLResult(nodePath, line, result.RuleName, result.RiskLevel, "", result.MatchedValue, value))
+	}
+	if len(results) > 0 {
+		return results
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(candidate, keywords); ok {
+		return []string{formatXMLResult(nodePath, line, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, value, value)}
+	}
+
+	return nil
+}
+
+// formatXMLResult 格式化XML扫描结果
+func formatXMLResult(nodePath string, line int, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	return fmt.Sprintf("XML|%s|%d|%s|%s|%s|%s|%s", nodePath, line, ruleName, riskLevel, keyword, matchedValue, content)
+}
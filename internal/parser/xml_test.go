@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// xmlTestWebConfig .NET web.config：connectionStrings 中的连接字符串、appSettings 中的键值对（值以键名匹配）和 system.net 中的 SMTP 凭据属性
+const xmlTestWebConfig = `<?xml version="1.0" encoding="utf-8"?>
+<configuration>
+  <connectionStrings>
+    <add name="Main" connectionString="Server=db.internal;Database=app;User Id=sa;Password=Sql-Pr0d!;" providerName="System.Data.SqlClient" />
+  </connectionStrings>
+  <appSettings>
+    <add key="ApiKey" value="AKIA1234567890ABCDEFGH" />
+    <add key="PageSize" value="50" />
+  </appSettings>
+  <system.net>
+    <mailSettings>
+      <smtp from="noreply@corp.local">
+        <network host="smtp.corp.local" userName="mailer" password="M4il-Pass" />
+      </smtp>
+    </mailSettings>
+  </system.net>
+</configuration>
+`
+
+// xmlTestBeans Spring 配置：元素文本中的凭据、CDATA 和注释
+const xmlTestBeans = `<beans>
+  <!-- password=in-comment -->
+  <bean id="dataSource">
+    <property name="url">
+      <value>jdbc:mysql://10.0.0.12:3306/app</value>
+    </property>
+    <username>app_user</username>
+    <password><![CDATA[Cd4ta-Pass]]></password>
+    <description>token refresh job</description>
+  </bean>
+</beans>
+`
+
+func TestXmlParser(t *testing.T) {
+	tests := []struct {
+		file    string
+		content string
+		want    []string
+	}{
+		{
+			file:    "web.config",
+			content: xmlTestWebConfig,
+			want: []string{
+				"XML|/configuration/connectionStrings/add@connectionString|4|密码字段|critical||Sql-Pr0d!|Server=db.internal;Database=app;User Id=sa;Password=Sql-Pr0d!;",
+				"XML|/configuration/appSettings/add@value|7|API密钥|critical||AKIA1234567890ABCDEFGH|AKIA1234567890ABCDEFGH",
+				"XML|/configuration/system.net/mailSettings/smtp/network@userName|13|用户名字段|high||mailer|mailer",
+				"XML|/configuration/system.net/mailSettings/smtp/network@password|13|密码字段|critical||M4il-Pass|M4il-Pass",
+			},
+		},
+		{
+			file:    "beans.xml",
+			content: xmlTestBeans,
+			want: []string{
+				"XML|/beans/bean/property/value|5|JDBC连接URL|high||jdbc:mysql://10.0.0.12:3306/app|jdbc:mysql://10.0.0.12:3306/app",
+				"XML|/beans/bean/username|7|用户名字段|high||app_user|app_user",
+				"XML|/beans/bean/password|8|密码字段|critical||Cd4ta-Pass|Cd4ta-Pass",
+				"XML|/beans/bean/description|9|关键字匹配|medium|token|token refresh job|token refresh job",
+			},
+		},
+		{
+			// 格式错误时按文本扫描
+			file:    "broken.xml",
+			content: "<config>\n  <password>Br0ken</passwd>\n",
+			want:    []string{"TEXT|password|2|  <password>Br0ken</passwd>"},
+		},
+	}
+
+	parser := NewFileParser(ParserConfig{})
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, []string{"password", "token"}, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
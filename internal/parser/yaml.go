@@ -0,0 +1,154 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"Findx/internal/logger"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes 每个文档最多检查的节点数，防止别名嵌套展开导致的资源耗尽
+const maxYAMLNodes = 100000
+
+// YamlParser YAML 配置文件解析器，支持多文档、锚点/别名和块标量，报告文档序号和完整键路径
+type YamlParser struct {
+	binaryParser *BinaryParser
+	textParser   *TextParser
+}
+
+// NewYamlParser 创建YAML解析器
+func NewYamlParser(binaryParser *BinaryParser, textParser *TextParser) *YamlParser {
+	return &YamlParser{
+		binaryParser: binaryParser,
+		textParser:   textParser,
+	}
+}
+
+// yamlWalker 遍历单个 YAML 文档时的状态
+type yamlWalker struct {
+	parser   *YamlParser
+	document int
+	keywords []string
+	visited  int
+	results  []string
+}
+
+// Parse 解析YAML文件，格式错误（如含模板语法）时回退到文本扫描
+func (p *YamlParser) Parse(filePath string, keywords []string, verbose bool) []string {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		logger.Warnf("读取文件%s错误", filePath)
+		return nil
+	}
+
+	var matchingLines []string
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for document := 1; ; document++ {
+		var root yaml.Node
+		err := decoder.Decode(&root)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			logger.Debugf("YAML解析失败，回退到文本扫描: %s: %v", filePath, err)
+			return p.textParser.Parse(filePath, keywords, verbose)
+		}
+
+		walker := &yamlWalker{parser: p, document: document, keywords: keywords}
+		walker.walk(&root, "", "", 0)
+		matchingLines = append(matchingLines, walker.results...)
+	}
+
+	if verbose {
+		for _, lineOutput := range matchingLines {
+			fmt.Println(lineOutput)
+		}
+	}
+
+	return matchingLines
+}
+
+// walk 递归遍历节点，path 为键路径，name 为最近的键名
+// 通过别名引用的节点按引用处的键路径和行号报告，aliasLine 为引用处的行号
+func (w *yamlWalker) walk(node *yaml.Node, path, name string, aliasLine int) {
+	w.visited++
+	if node == nil || w.visited > maxYAMLNodes {
+		return
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			w.walk(child, path, name, aliasLine)
+		}
+	case yaml.AliasNode:
+		line := aliasLine
+		if line == 0 {
+			line = node.Line
+		}
+		w.walk(node.Alias, path, name, line)
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			// 合并键 <<: *anchor 的内容属于当前映射
+			if key.Value == "<<" && key.Tag == "!!merge" {
+				w.walk(value, path, name, aliasLine)
+				continue
+			}
+			childPath := key.Value
+			if path != "" {
+				childPath = path + "." + key.Value
+			}
+			w.walk(value, childPath, key.Value, aliasLine)
+		}
+	case yaml.SequenceNode:
+		for i, child := range node.Content {
+			w.walk(child, path+"["+strconv.Itoa(i)+"]", name, aliasLine)
+		}
+	case yaml.ScalarNode:
+		value := strings.TrimSpace(node.Value)
+		if value == "" || path == "" {
+			return
+		}
+		line := aliasLine
+		if line == 0 {
+			line = node.Line
+		}
+		w.results = append(w.results, w.parser.checkValue(w.document, path, line, name, value, w.keywords)...)
+	}
+}
+
+// checkValue 检查单个标量值，以 "键=值" 的形式匹配
+// 块标量（| 和 >）中的多行内容作为整体匹配
+func (p *YamlParser) checkValue(document int, keyPath string, line int, name, value string, keywords []string) []string {
+	var results []string
+	candidate := name + "=" + value
+
+	for _, result := range p.binaryParser.MatchString(candidate) {
+		results = append(results, formatYAMLResult(document, keyPath, line, result.RuleName, result.RiskLevel, "", result.MatchedValue, value))
+	}
+	if len(results) > 0 {
+		return results
+	}
+
+	if keyword, ok := p.binaryParser.matcher.find(candidate, keywords); ok {
+		return []string{formatYAMLResult(document, keyPath, line, "关键字匹配", p.binaryParser.keywordRisk(keyword), keyword, value, value)}
+	}
+
+	return nil
+}
+
+// formatYAMLResult 格式化YAML扫描结果: YAML|文档序号|键路径|行号|规则|风险|关键字|匹配值|内容
+// 块标量中的换行替换为 ⏎，保证每条结果占一行
+func formatYAMLResult(document int, keyPath string, line int, ruleName, riskLevel, keyword, matchedValue, content string) string {
+	flatten := strings.NewReplacer("\r\n", " ⏎ ", "\n", " ⏎ ").Replace
+	clean := func(s string) string { return strings.ReplaceAll(flatten(s), "|", "/") }
+	return fmt.Sprintf("YAML|%d|%s|%d|%s|%s|%s|%s|%s", document, clean(keyPath), line, ruleName, riskLevel, keyword, clean(matchedValue), flatten(content))
+}
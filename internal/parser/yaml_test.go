@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// yamlTestConfig 两个文档：嵌套键、锚点和合并键、列表和块标量；第二个文档中同名键位于不同路径
+const yamlTestConfig = `# 应用配置
+defaults: &db_defaults
+  username: app_user
+  password: Def4ult-Pass
+services:
+  billing:
+    database:
+      <<: *db_defaults
+      host: db.internal:5432
+    tokens:
+      - name: ci
+        value: token-ci-1
+    notes: |
+      rotate the password quarterly
+      owner: ops
+---
+services:
+  billing:
+    database:
+      password: "Pr0d-Only!"
+  reporting:
+    api_key: AKIA1234567890ABCDEFGH
+`
+
+func TestYamlParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "nested multi-document",
+			content: yamlTestConfig,
+			want: []string{
+				"YAML|1|defaults.username|3|用户名字段|high||app_user|app_user",
+				"YAML|1|defaults.password|4|密码字段|critical||Def4ult-Pass|Def4ult-Pass",
+				// 合并键引入的值按引用处的路径和行号报告
+				"YAML|1|services.billing.database.username|8|用户名字段|high||app_user|app_user",
+				"YAML|1|services.billing.database.password|8|密码字段|critical||Def4ult-Pass|Def4ult-Pass",
+				"YAML|1|services.billing.tokens[0].value|12|关键字匹配|medium|token|token-ci-1|token-ci-1",
+				"YAML|1|services.billing.notes|13|关键字匹配|medium|password|rotate the password quarterly ⏎ owner: ops|rotate the password quarterly ⏎ owner: ops",
+				"YAML|2|services.billing.database.password|20|密码字段|critical||Pr0d-Only!|Pr0d-Only!",
+				"YAML|2|services.reporting.api_key|22|API密钥|critical||AKIA1234567890ABCDEFGH|AKIA1234567890ABCDEFGH",
+			},
+		},
+		{
+			// 含模板语法时按文本扫描
+			name:    "templated",
+			content: "db:\n  password: {{ .Values.password }\n",
+			want:    []string{"TEXT|password|2|  password: {{ .Values.password }"},
+		},
+		{
+			name:    "empty documents",
+			content: "---\n---\n",
+			want:    nil,
+		},
+	}
+
+	parser := NewYamlParser(NewBinaryParser(), NewTextParser())
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if got := parser.Parse(path, []string{"password", "token"}, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("results =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,280 @@
+package risk
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// 内置风险等级，规则和解析器产生的结果使用这些等级
+const (
+	Critical = "critical"
+	High     = "high"
+	Medium   = "medium"
+	Low      = "low"
+)
+
+// builtinNames 内置风险等级，从高到低
+var builtinNames = []string{Critical, High, Medium, Low}
+
+// Level 风险等级定义
+type Level struct {
+	Name  string   `json:"name"`  // 等级名称（不区分大小写，如 P0）
+	Label string   `json:"label"` // 显示文本（为空时使用名称）
+	Icon  string   `json:"icon"`  // 图标（为空时使用对应内置等级的图标）
+	Color string   `json:"color"` // HTML 报告中的颜色（如 #ef4444，为空时使用对应内置等级的样式）
+	Maps  []string `json:"maps"`  // 归入该等级的内置等级（critical/high/medium/low）
+
+	band string // 对应的内置等级，用于报告样式和 syslog 严重程度
+}
+
+// Band 获取等级对应的内置等级
+func (l Level) Band() string {
+	return l.band
+}
+
+// builtinLevels 内置的风险等级体系
+var builtinLevels = []Level{
+	{Name: Critical, Label: "严重", Icon: "🔴", band: Critical},
+	{Name: High, Label: "高危", Icon: "🟠", band: High},
+	{Name: Medium, Label: "中危", Icon: "🟡", band: Medium},
+	{Name: Low, Label: "低危", Icon: "🟢", band: Low},
+}
+
+// 当前使用的风险等级体系
+var (
+	mu      sync.RWMutex
+	levels  = builtinLevels
+	aliases = map[string]string{} // 内置等级 -> 自定义等级名称
+	weights = map[string]int{}    // 风险评分中指定了权重的等级
+)
+
+// defaultWeights 内置风险等级在风险评分中的默认权重，自定义等级使用所对应内置等级的权重
+var defaultWeights = map[string]int{Critical: 10, High: 5, Medium: 2, Low: 1}
+
+// Configure 使用自定义的风险等级体系，levels 按风险从高到低排列
+// 每个内置等级必须且只能归入一个自定义等级，未指定图标和颜色的等级沿用所归入的（或按位置对应的）内置等级的图标和样式
+func Configure(defs []Level) error {
+	if len(defs) == 0 {
+		return fmt.Errorf("风险等级列表不能为空")
+	}
+
+	configured := make([]Level, len(defs))
+	names := make(map[string]bool)
+	mapped := make(map[string]string)
+	for i, def := range defs {
+		name := strings.ToLower(strings.TrimSpace(def.Name))
+		if name == "" {
+			return fmt.Errorf("第 %d 个风险等级缺少名称", i+1)
+		}
+		if names[name] {
+			return fmt.Errorf("风险等级重复: %s", def.Name)
+		}
+		names[name] = true
+
+		level := def
+		level.Name = name
+		if level.Label == "" {
+			level.Label = strings.TrimSpace(def.Name)
+		}
+		for _, m := range def.Maps {
+			m = strings.ToLower(strings.TrimSpace(m))
+			if !isBuiltin(m) {
+				return fmt.Errorf("风险等级 %s 归入的内置等级无效: %s（可选: %s）", def.Name, m, strings.Join(builtinNames, ", "))
+			}
+			if prev, ok := mapped[m]; ok {
+				return fmt.Errorf("内置风险等级 %s 同时归入了 %s 和 %s", m, prev, name)
+			}
+			mapped[m] = name
+			if level.band == "" {
+				level.band = m
+			}
+		}
+		if level.band == "" {
+			// 未归入内置等级的按在列表中的位置对应
+			level.band = builtinNames[i*len(builtinNames)/len(defs)]
+		}
+		if level.Icon == "" {
+			level.Icon = builtinLevels[indexOf(builtinNames, level.band)].Icon
+		}
+		configured[i] = level
+	}
+	for _, name := range builtinNames {
+		if _, ok := mapped[name]; !ok && !names[name] {
+			return fmt.Errorf("内置风险等级 %s 未归入任何自定义等级", name)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	levels = configured
+	aliases = mapped
+	return nil
+}
+
+// isBuiltin 判断是否为内置风险等级
+func isBuiltin(name string) bool {
+	return indexOf(builtinNames, name) >= 0
+}
+
+// indexOf 获取名称在列表中的位置，不存在时返回 -1
+func indexOf(names []string, name string) int {
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Levels 获取当前的风险等级，从高到低
+func Levels() []Level {
+	mu.RLock()
+	defer mu.RUnlock()
+	return append([]Level(nil), levels...)
+}
+
+// Names 获取当前的风险等级名称，从高到低
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, len(levels))
+	for i, level := range levels {
+		names[i] = level.Name
+	}
+	return names
+}
+
+// Normalize 将风险等级转换为当前体系中的名称（小写），内置等级转换为其归入的自定义等级
+func Normalize(level string) string {
+	level = strings.ToLower(level)
+	mu.RLock()
+	defer mu.RUnlock()
+	if alias, ok := aliases[level]; ok {
+		return alias
+	}
+	return level
+}
+
+// lookup 查找风险等级在当前体系中的位置，不存在时返回 -1
+func lookup(level string) (int, Level) {
+	name := Normalize(level)
+	mu.RLock()
+	defer mu.RUnlock()
+	for i, l := range levels {
+		if l.Name == name {
+			return i, l
+		}
+	}
+	return -1, Level{}
+}
+
+// IsValid 判断风险等级是否有效（当前体系中的等级或已归入的内置等级）
+func IsValid(level string) bool {
+	i, _ := lookup(level)
+	return i >= 0
+}
+
+// Rank 风险等级的优先级，数值越大风险越高，无效等级返回 0
+func Rank(level string) int {
+	i, _ := lookup(level)
+	if i < 0 {
+		return 0
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(levels) - i
+}
+
+// Lower 获取低一级的风险等级，已是最低或无效等级时原样返回
+func Lower(level string) string {
+	i, _ := lookup(level)
+	mu.RLock()
+	defer mu.RUnlock()
+	if i < 0 {
+		return level
+	}
+	if i == len(levels)-1 {
+		return levels[i].Name
+	}
+	return levels[i+1].Name
+}
+
+// Lowest 获取当前体系中最低的风险等级
+func Lowest() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return levels[len(levels)-1].Name
+}
+
+// Icon 获取风险等级的图标
+func Icon(level string) string {
+	if i, l := lookup(level); i >= 0 {
+		return l.Icon
+	}
+	return "⚪"
+}
+
+// Label 获取风险等级的显示文本
+func Label(level string) string {
+	if i, l := lookup(level); i >= 0 {
+		return l.Label
+	}
+	return "未知"
+}
+
+// Band 获取风险等级对应的内置等级，无效等级返回空字符串
+func Band(level string) string {
+	_, l := lookup(level)
+	return l.band
+}
+
+// Color 获取风险等级在 HTML 报告中的自定义颜色，未指定时返回空字符串
+func Color(level string) string {
+	_, l := lookup(level)
+	return l.Color
+}
+
+// ConfigureWeights 设置风险评分中各等级的权重（--severity-weights），未指定的等级使用默认权重
+// 等级名称可以是当前体系中的等级或已归入的内置等级，权重不能为负数
+func ConfigureWeights(defs map[string]int) error {
+	configured := make(map[string]int, len(defs))
+	for name, weight := range defs {
+		i, level := lookup(name)
+		if i < 0 {
+			return fmt.Errorf("无效的风险等级: %s（可选: %s）", name, strings.Join(Names(), ", "))
+		}
+		if weight < 0 {
+			return fmt.Errorf("风险等级 %s 的权重不能为负数: %d", name, weight)
+		}
+		configured[level.Name] = weight
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	weights = configured
+	return nil
+}
+
+// Weight 获取风险等级在评分中的权重，无效等级返回 0
+func Weight(level string) int {
+	i, l := lookup(level)
+	if i < 0 {
+		return 0
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if weight, ok := weights[l.Name]; ok {
+		return weight
+	}
+	return defaultWeights[l.band]
+}
+
+// Score 按各等级的权重计算风险评分，counts 为各风险等级的结果数
+func Score(counts map[string]int) int {
+	score := 0
+	for level, count := range counts {
+		score += Weight(level) * count
+	}
+	return score
+}
@@ -0,0 +1,53 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// benchmarkTree 创建结果密集的目录：files 个文件，每个文件 lines 行都命中关键字
+func benchmarkTree(b *testing.B, files, lines int) []string {
+	b.Helper()
+	dir := b.TempDir()
+	var paths []string
+	for i := 0; i < files; i++ {
+		var sb strings.Builder
+		for j := 0; j < lines; j++ {
+			fmt.Fprintf(&sb, "db%d.password=Secret%04d\n", j, i)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("app%03d.conf", i))
+		if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+			b.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// BenchmarkScanFiles 结果密集时的并发扫描和输出（100 个文件、共 3 万条结果，实时输出重定向到 /dev/null）
+// 工作协程格式化各自的结果，由唯一的输出协程按序号写入，输出时不再互相阻塞
+func BenchmarkScanFiles(b *testing.B) {
+	files := benchmarkTree(b, 100, 300)
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer devNull.Close()
+	stdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = stdout }()
+	for _, threads := range []int{1, 4, 8} {
+		b.Run("threads="+strconv.Itoa(threads), func(b *testing.B) {
+			cfg := testConfig(b, "-f", filepath.Dir(files[0]), "-k", "password=", "-n", strconv.Itoa(threads))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				NewScanner(cfg).scanFiles(files)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Findx/internal/logger"
+	"Findx/internal/output"
+	"Findx/internal/risk"
+)
+
+// maxBlameSummary 扫描结束时列出的存在时间最长的结果数
+const maxBlameSummary = 10
+
+// uncommittedCommit git blame 中尚未提交的修改对应的提交号
+const uncommittedCommit = "0000000000000000000000000000000000000000"
+
+// blamer 对 Git 仓库中文件的结果行运行 git blame（--blame），记录最后修改该行的提交
+// 每个目录是否位于 Git 仓库中只检查一次；不在仓库中、未跟踪的文件和未提交的行不标注
+type blamer struct {
+	mu    sync.Mutex
+	repos map[string]*blameRepo // 目录 -> 所在的 Git 仓库（不在仓库中时为 nil）
+	now   time.Time
+}
+
+// blameRepo 目录所在的 Git 仓库
+type blameRepo struct {
+	shallow map[string]bool // 浅克隆的边界提交（非浅克隆时为空）
+}
+
+// newBlamer 创建 git blame 标注器，未安装 git 时给出警告并返回 nil
+func newBlamer() *blamer {
+	if _, err := exec.LookPath("git"); err != nil {
+		logger.Warnf("未找到 git 命令，忽略 --blame")
+		return nil
+	}
+	return &blamer{repos: make(map[string]*blameRepo), now: time.Now()}
+}
+
+// repoOf 获取目录所在的 Git 仓库，不在 Git 工作区中时返回 nil
+func (b *blamer) repoOf(dir string) *blameRepo {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if repo, checked := b.repos[dir]; checked {
+		return repo
+	}
+	var repo *blameRepo
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree", "--git-path", "shallow").Output()
+	if fields := strings.Split(strings.TrimSpace(string(out)), "\n"); err == nil && len(fields) == 2 && fields[0] == "true" {
+		repo = &blameRepo{shallow: make(map[string]bool)}
+		shallowFile := fields[1]
+		if !filepath.IsAbs(shallowFile) {
+			shallowFile = filepath.Join(dir, shallowFile)
+		}
+		if data, err := os.ReadFile(shallowFile); err == nil {
+			for _, commit := range strings.Fields(string(data)) {
+				repo.shallow[commit] = true
+			}
+		}
+	}
+	b.repos[dir] = repo
+	return repo
+}
+
+// annotate 为按行号定位的文本结果标注最后修改该行的提交，嵌入对象中的结果不标注
+func (b *blamer) annotate(path string, findings []output.Finding) {
+	var lines []int
+	seen := make(map[int]bool)
+	for i := range findings {
+		f := &findings[i]
+		if commentCheckedKinds[f.Kind] && f.LineNumber > 0 && f.Embedded == "" && !seen[f.LineNumber] {
+			seen[f.LineNumber] = true
+			lines = append(lines, f.LineNumber)
+		}
+	}
+	if len(lines) == 0 {
+		return
+	}
+	repo := b.repoOf(filepath.Dir(path))
+	if repo == nil {
+		return
+	}
+	sort.Ints(lines)
+
+	blames, err := b.blameLines(path, lines, repo)
+	if err != nil {
+		logger.Debugf("git blame 失败，不标注提交信息: %s: %v", path, err)
+		return
+	}
+	for i := range findings {
+		f := &findings[i]
+		if seen[f.LineNumber] && f.Embedded == "" && commentCheckedKinds[f.Kind] {
+			f.Blame = blames[f.LineNumber]
+		}
+	}
+}
+
+// blameLines 对文件的指定行运行一次 git blame，返回行号到提交信息的映射（未提交的行不包含在内）
+func (b *blamer) blameLines(path string, lines []int, repo *blameRepo) (map[int]*output.BlameInfo, error) {
+	args := []string{"-C", filepath.Dir(path), "blame", "--line-porcelain", "--root"}
+	for _, line := range lines {
+		args = append(args, "-L", fmt.Sprintf("%d,%d", line, line))
+	}
+	args = append(args, "--", filepath.Base(path))
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseBlamePorcelain(out, b.now, repo.shallow), nil
+}
+
+// parseBlamePorcelain 解析 git blame --line-porcelain 的输出，shallow 中的提交标记为浅克隆边界
+// 每行以 "<提交号> <原行号> <行号>" 开头，随后是 author、author-time 等头部，以制表符开头的一行为行内容
+func parseBlamePorcelain(out []byte, now time.Time, shallow map[string]bool) map[int]*output.BlameInfo {
+	blames := make(map[int]*output.BlameInfo)
+	var commit, author string
+	var line int
+	var date time.Time
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := scanner.Text()
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			if commit != "" && commit != uncommittedCommit {
+				blames[line] = output.NewBlameInfo(commit, author, date, now, shallow[commit])
+			}
+			commit, author, line, date = "", "", 0, time.Time{}
+		case commit == "":
+			fields := strings.Fields(text)
+			if len(fields) >= 3 && len(fields[0]) == 40 {
+				commit = fields[0]
+				line, _ = strconv.Atoi(fields[2])
+			}
+		case strings.HasPrefix(text, "author "):
+			author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-time "):
+			if sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64); err == nil {
+				date = time.Unix(sec, 0)
+			}
+		}
+	}
+	return blames
+}
+
+// printBlameSummary 扫描结束后在控制台列出存在时间最长的结果（--blame），长期暴露的敏感信息应优先处理
+func (s *Scanner) printBlameSummary() {
+	type agedFinding struct {
+		path    string
+		finding *output.Finding
+	}
+	var aged []agedFinding
+	s.mu.Lock()
+	for path, results := range s.fileResults {
+		for i := range results {
+			if results[i].Blame != nil {
+				aged = append(aged, agedFinding{path, &results[i]})
+			}
+		}
+	}
+	s.mu.Unlock()
+	if len(aged) == 0 {
+		return
+	}
+
+	sort.Slice(aged, func(i, j int) bool {
+		a, b := aged[i].finding, aged[j].finding
+		if !a.Blame.Date.Equal(b.Blame.Date) {
+			return a.Blame.Date.Before(b.Blame.Date)
+		}
+		if aged[i].path != aged[j].path {
+			return aged[i].path < aged[j].path
+		}
+		return a.LineNumber < b.LineNumber
+	})
+	if len(aged) > maxBlameSummary {
+		aged = aged[:maxBlameSummary]
+	}
+
+	logger.Infof("⏳ 存在时间最长的结果:")
+	for _, item := range aged {
+		f := item.finding
+		logger.Detailf("    %s %s:%d %s — %s", risk.Icon(f.RiskLevel), item.path, f.LineNumber, f.RuleName, f.Blame)
+	}
+}
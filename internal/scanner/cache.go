@@ -0,0 +1,263 @@
+package scanner
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+	"Findx/internal/output"
+)
+
+// cacheFile 缓存文件的数据结构
+type cacheFile struct {
+	Fingerprint string                `json:"fingerprint"`
+	LastScan    int64                 `json:"last_scan,omitempty"` // 上次扫描的开始时间（UnixNano）
+	Entries     map[string]cacheEntry `json:"entries"`
+}
+
+// cacheEntry 单个文件的缓存记录
+type cacheEntry struct {
+	Size    int64    `json:"size"`
+	ModTime int64    `json:"mtime"`
+	Hash    string   `json:"sha256"`
+	Results []string `json:"results"` // 解析器输出的原始结果
+}
+
+// ScanCache 文件级扫描缓存，未变化的文件直接使用上次的解析结果
+// 缓存保存解析器的原始输出，风险等级覆盖、规则筛选和去重在每次运行时重新应用
+type ScanCache struct {
+	path        string
+	fingerprint string
+	lastScan    int64 // 上次扫描的开始时间，之后修改的文件不能只凭修改时间判断
+	started     int64 // 本次扫描的开始时间
+	previous    map[string]cacheEntry
+	current     map[string]cacheEntry
+	hits        int
+	mu          sync.Mutex
+}
+
+// LoadScanCache 加载扫描缓存，未启用缓存时返回 nil
+// 关键词或解析参数变化后旧缓存整体失效
+func LoadScanCache(cfg *config.Config) *ScanCache {
+	if cfg.CacheFile == "" {
+		return nil
+	}
+
+	c := &ScanCache{
+		path:        cfg.CacheFile,
+		fingerprint: cacheFingerprint(cfg),
+		started:     time.Now().UnixNano(),
+		previous:    make(map[string]cacheEntry),
+		current:     make(map[string]cacheEntry),
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warnf("读取缓存文件失败: %v", err)
+		}
+		return c
+	}
+
+	var cached cacheFile
+	if err := json.Unmarshal(data, &cached); err != nil {
+		logger.Warnf("缓存文件格式错误，将重新扫描: %v", err)
+		return c
+	}
+	if cached.Fingerprint != c.fingerprint {
+		logger.Infof("扫描参数已变化，缓存失效")
+		return c
+	}
+	if cached.Entries != nil {
+		c.previous = cached.Entries
+	}
+	c.lastScan = cached.LastScan
+
+	return c
+}
+
+// Lookup 查找文件的缓存结果，文件大小和修改时间一致时命中
+// 仅修改时间变化而内容相同（哈希一致）时同样命中；修改时间不早于上次扫描开始时间的文件可能在读取后又被修改，同样校验哈希
+func (c *ScanCache) Lookup(filePath string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	entry, ok := c.previous[filePath]
+	c.mu.Unlock()
+	if !ok || entry.Size != info.Size() {
+		return nil, false
+	}
+
+	if entry.ModTime != info.ModTime().UnixNano() || (c.lastScan > 0 && entry.ModTime >= c.lastScan) {
+		hash, err := hashFile(filePath)
+		if err != nil || hash != entry.Hash {
+			return nil, false
+		}
+		entry.ModTime = info.ModTime().UnixNano()
+	}
+
+	c.mu.Lock()
+	c.current[filePath] = entry
+	c.hits++
+	c.mu.Unlock()
+
+	return entry.Results, true
+}
+
+// Store 记录文件本次的解析结果
+func (c *ScanCache) Store(filePath string, results []string) {
+	if c == nil {
+		return
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return
+	}
+	hash, err := hashFile(filePath)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current[filePath] = cacheEntry{
+		Size:    info.Size(),
+		ModTime: info.ModTime().UnixNano(),
+		Hash:    hash,
+		Results: results,
+	}
+}
+
+// Hits 获取缓存命中的文件数
+func (c *ScanCache) Hits() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits
+}
+
+// LastScan 获取上次扫描的开始时间，没有记录时返回零值
+func (c *ScanCache) LastScan() time.Time {
+	if c == nil || c.lastScan == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, c.lastScan)
+}
+
+// Removed 获取上次扫描过、本次不再扫描（已删除或被排除）的文件数
+func (c *ScanCache) Removed() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for path := range c.previous {
+		if _, ok := c.current[path]; !ok {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Save 保存本次扫描的缓存，不再存在的文件随之移除
+// 先写入同目录下的临时文件再替换，写入中断时保留上次的缓存
+func (c *ScanCache) Save() error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := json.Marshal(cacheFile{
+		Fingerprint: c.fingerprint,
+		LastScan:    c.started,
+		Entries:     c.current,
+	})
+	if err != nil {
+		return fmt.Errorf("生成缓存失败: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("创建缓存目录失败: %w", err)
+	}
+	file, err := output.CreateReportFile(c.path, false)
+	if err != nil {
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+	if err := file.Commit(); err != nil {
+		return fmt.Errorf("写入缓存文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// cacheFingerprint 根据影响解析结果的参数计算缓存指纹
+func cacheFingerprint(cfg *config.Config) string {
+	_, _, version := config.GetAppInfo()
+	keywords := make([]string, 0, len(cfg.Keywords))
+	for _, keyword := range cfg.Keywords {
+		// 关键字的风险等级写入了解析结果，等级变化时缓存同样失效
+		if level, ok := cfg.KeywordRisks[keyword]; ok {
+			keyword += "\x02" + level
+		}
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	// 文件头签名决定文件的解析方式，按配置顺序参与指纹（同长度签名按顺序优先）
+	signatures := make([]string, 0, len(cfg.MagicSignatures))
+	for _, signature := range cfg.MagicSignatures {
+		signatures = append(signatures, fmt.Sprintf("%X=%s", signature.Magic, signature.Type))
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%d\x00%t\x00%t\x00%d\x00%d\x00%t\x00%g\x00%t\x00%s\x00%s\x00%s\x00%s\x00%g\x00%s\x00%s\x00%s\x00%t\x00%s\x00%s",
+		version, cfg.ContextLength, cfg.AllMatches, cfg.DualScan, cfg.MaxPerRule, cfg.MaxStrings, cfg.MergeFragments, cfg.TextThreshold, cfg.KeywordCI,
+		strings.Join(keywords, "\x01"),
+		strings.Join(cfg.OnlyRules, "\x01"),
+		strings.Join(cfg.SkipRules, "\x01"),
+		strings.Join(cfg.SecretWords, "\x01"), cfg.EntropyThreshold,
+		strings.Join(cfg.NoValidateRules, "\x01"), cfg.BinaryFallback, cfg.KeystorePassword, cfg.RawScan,
+		strings.Join(signatures, "\x01"), cfg.RuleTimeout)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashFile 计算文件内容的 SHA-256
+func hashFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
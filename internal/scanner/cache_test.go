@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Findx/internal/config"
+)
+
+// cachedAfterChange 用 base 配置保存一个文件的缓存，再用 change 修改后的配置重新加载，返回该文件是否命中缓存
+func cachedAfterChange(t *testing.T, change func(cfg *config.Config)) bool {
+	t.Helper()
+	dir := t.TempDir()
+	target := filepath.Join(dir, "app.conf")
+	if err := os.WriteFile(target, []byte("password=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{CacheFile: filepath.Join(dir, "cache.json"), Keywords: []string{"password="}}
+	cache := LoadScanCache(cfg)
+	cache.Store(target, []string{"TEXT|1|password=|password=hunter2"})
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	change(cfg)
+	_, ok := LoadScanCache(cfg).Lookup(target)
+	return ok
+}
+
+func TestScanCacheFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *config.Config)
+		cached bool
+	}{
+		{"unchanged", func(cfg *config.Config) {}, true},
+		{"keywords", func(cfg *config.Config) { cfg.Keywords = []string{"token="} }, false},
+		{"raw scan", func(cfg *config.Config) { cfg.RawScan = true }, false},
+		{"rule timeout", func(cfg *config.Config) { cfg.RuleTimeout = 50 * time.Millisecond }, false},
+		{"magic signatures", func(cfg *config.Config) {
+			cfg.MagicSignatures = []config.MagicSignature{{Magic: []byte("MZ"), Type: config.MagicTypeRaw}}
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cachedAfterChange(t, tt.change); got != tt.cached {
+				t.Errorf("cached = %v, want %v", got, tt.cached)
+			}
+		})
+	}
+}
+
+// TestScanCacheSaveReplaces 保存缓存时替换旧文件，不在缓存目录中留下临时文件
+func TestScanCacheSaveReplaces(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{CacheFile: filepath.Join(dir, "cache.json")}
+	if err := os.WriteFile(cfg.CacheFile, []byte("stale"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LoadScanCache(cfg).Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "cache.json" {
+		t.Errorf("cache dir contains %v, want only cache.json", entries)
+	}
+	if data, _ := os.ReadFile(cfg.CacheFile); string(data) == "stale" {
+		t.Error("cache file was not replaced")
+	}
+}
@@ -0,0 +1,33 @@
+package scanner
+
+import (
+	"Findx/internal/output"
+	"Findx/internal/parser"
+)
+
+// commentCheckedKinds 按行号定位、可以判断是否位于注释中的结果类别
+var commentCheckedKinds = map[string]bool{"TEXT": true, "LINE": true, "XML": true, "YAML": true}
+
+// commentChecker 判断文本结果是否位于代码注释中，首次需要时才读取文件
+type commentChecker struct {
+	path     string
+	comments *parser.CommentMap
+	loaded   bool
+}
+
+// newCommentChecker 创建文件的注释检查器
+func newCommentChecker(path string) *commentChecker {
+	return &commentChecker{path: path}
+}
+
+// inComment 判断结果的匹配位置是否位于注释中，嵌入对象中的结果不检查
+func (c *commentChecker) inComment(f *output.Finding) bool {
+	if !commentCheckedKinds[f.Kind] || f.LineNumber <= 0 || f.Embedded != "" {
+		return false
+	}
+	if !c.loaded {
+		c.comments = parser.LoadCommentMap(c.path)
+		c.loaded = true
+	}
+	return c.comments.InComment(f.LineNumber, f.MatchedValue, f.Keyword)
+}
@@ -0,0 +1,37 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"Findx/internal/logger"
+)
+
+// confirmScan 报告待扫描的文件数和总大小，--interactive 模式下询问是否继续
+// 标准输入不是终端或指定了 --yes 时不询问，脚本中的使用不受影响
+func (s *Scanner) confirmScan(totalFiles int) bool {
+	logger.Infof("待扫描文件: %d 个，总大小: %.2f MB", totalFiles, float64(s.walkStats.TotalBytes)/1024/1024)
+	if !s.config.Interactive || s.config.AssumeYes || !stdinIsTerminal() {
+		return true
+	}
+
+	fmt.Fprint(os.Stderr, "[?] 是否开始扫描？[y/N] ")
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes", "是":
+		return true
+	default:
+		return false
+	}
+}
+
+// stdinIsTerminal 判断标准输入是否为终端
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
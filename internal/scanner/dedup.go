@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"sync"
+
+	"Findx/internal/config"
+	"Findx/internal/output"
+)
+
+// Deduplicator 扫描结果去重器，跨文件共享已见记录
+type Deduplicator struct {
+	keyFunc func(path string, f *output.Finding) string
+	seen    map[string]bool
+	dropped int
+	mu      sync.Mutex
+}
+
+// NewDeduplicator 根据去重粒度创建去重器，未启用去重时返回 nil
+func NewDeduplicator(mode string) *Deduplicator {
+	var keyFunc func(path string, f *output.Finding) string
+
+	switch mode {
+	case config.DedupeByValue:
+		keyFunc = func(path string, f *output.Finding) string {
+			return f.NormalizedValue()
+		}
+	case config.DedupeByValueFile:
+		keyFunc = func(path string, f *output.Finding) string {
+			return path + "\x00" + f.NormalizedValue()
+		}
+	case config.DedupeByValueRule:
+		keyFunc = func(path string, f *output.Finding) string {
+			return f.RuleName + "\x00" + f.NormalizedValue()
+		}
+	default:
+		return nil
+	}
+
+	return &Deduplicator{
+		keyFunc: keyFunc,
+		seen:    make(map[string]bool),
+	}
+}
+
+// Filter 过滤已出现过的结果，返回首次出现的结果
+func (d *Deduplicator) Filter(path string, findings []output.Finding) []output.Finding {
+	if d == nil {
+		return findings
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	unique := findings[:0]
+	for i := range findings {
+		key := d.keyFunc(path, &findings[i])
+		if d.seen[key] {
+			d.dropped++
+			continue
+		}
+		d.seen[key] = true
+		unique = append(unique, findings[i])
+	}
+
+	return unique
+}
+
+// Dropped 获取被去重的结果数量
+func (d *Deduplicator) Dropped() int {
+	if d == nil {
+		return 0
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dropped
+}
@@ -0,0 +1,229 @@
+package scanner
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"Findx/internal/logger"
+	"Findx/internal/parser"
+)
+
+// dockerManifest docker save 导出包中的 manifest.json 条目
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+	Layers   []string `json:"Layers"`
+}
+
+// prepareDockerImage 导出镜像并将各层中符合条件的文件提取到临时目录
+// 返回待扫描文件列表和清理函数
+func (s *Scanner) prepareDockerImage() ([]string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "findx-docker-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	tarPath, err := exportDockerImage(s.config.DockerImage, tempDir)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	manifest, err := readDockerManifest(tarPath)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	logger.Infof("镜像共 %d 层", len(manifest.Layers))
+
+	var files []string
+	seen := make(map[string]bool) // 路径+内容哈希，跳过各层间未变化的文件
+	for i, layer := range manifest.Layers {
+		layerDir := filepath.Join(tempDir, fmt.Sprintf("layer%d", i))
+		layerFiles, err := s.extractLayer(tarPath, layer, layerDir, seen)
+		if err != nil {
+			logger.Errorf("读取镜像层%s失败: %v", layer, err)
+			continue
+		}
+		files = append(files, layerFiles...)
+	}
+
+	return files, cleanup, nil
+}
+
+// exportDockerImage 获取镜像的 docker save 导出包，参数为本地tar文件时直接使用
+func exportDockerImage(ref, tempDir string) (string, error) {
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		return ref, nil
+	}
+
+	tarPath := filepath.Join(tempDir, "image.tar")
+	logger.Infof("导出镜像: %s", ref)
+	if _, err := exec.Command("docker", "save", "-o", tarPath, ref).CombinedOutput(); err != nil {
+		// 本地不存在该镜像时尝试拉取
+		logger.Infof("拉取镜像: %s", ref)
+		if out, err := exec.Command("docker", "pull", ref).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("拉取镜像失败: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+		if out, err := exec.Command("docker", "save", "-o", tarPath, ref).CombinedOutput(); err != nil {
+			return "", fmt.Errorf("导出镜像失败: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return tarPath, nil
+}
+
+// readDockerManifest 读取导出包中的 manifest.json
+func readDockerManifest(tarPath string) (*dockerManifest, error) {
+	var manifests []dockerManifest
+	found, err := visitTarEntry(tarPath, "manifest.json", func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&manifests)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("读取镜像清单失败: %w", err)
+	}
+	if !found || len(manifests) == 0 {
+		return nil, fmt.Errorf("不是有效的 docker save 导出包: %s", tarPath)
+	}
+
+	return &manifests[0], nil
+}
+
+// extractLayer 提取镜像层中符合扫描条件的文件
+func (s *Scanner) extractLayer(tarPath, layer, layerDir string, seen map[string]bool) ([]string, error) {
+	var files []string
+	digest := layerDigest(layer)
+
+	found, err := visitTarEntry(tarPath, layer, func(r io.Reader) error {
+		layerReader, err := maybeGunzip(r)
+		if err != nil {
+			return err
+		}
+
+		tr := tar.NewReader(layerReader)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if header.Typeflag != tar.TypeReg {
+				continue
+			}
+
+			// 忽略 whiteout 文件和不安全的路径
+			name := path.Clean("/" + header.Name)
+			if strings.HasPrefix(path.Base(name), ".wh.") {
+				continue
+			}
+
+			if s.excludedLayerDir(name) || s.config.ShouldExcludeFile(name) || s.config.ShouldSkipBySize(header.Size) ||
+				!(s.config.IsFileTypeSupported(path.Base(name)) || s.config.IsSensitiveFile(name) || parser.IsHistoryFile(name)) {
+				continue
+			}
+
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+
+			sum := sha256.Sum256(data)
+			key := name + "\x00" + hex.EncodeToString(sum[:])
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			target := filepath.Join(layerDir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, 0644); err != nil {
+				return err
+			}
+
+			s.pathAliases[target] = digest + ":" + name
+			files = append(files, target)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("导出包中不存在该层")
+	}
+
+	return files, nil
+}
+
+// excludedLayerDir 判断镜像层中的文件是否位于排除的目录中，与遍历本地目录时一样逐级检查所在目录
+func (s *Scanner) excludedLayerDir(name string) bool {
+	for dir := path.Dir(name); dir != "/" && dir != "."; dir = path.Dir(dir) {
+		if s.config.ShouldExcludeDir(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// visitTarEntry 在tar包中查找指定条目并处理其内容
+func visitTarEntry(tarPath, name string, fn func(r io.Reader) error) (bool, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if path.Clean(header.Name) == path.Clean(name) {
+			return true, fn(tr)
+		}
+	}
+}
+
+// maybeGunzip 根据魔数判断镜像层是否经过gzip压缩
+func maybeGunzip(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// layerDigest 根据层路径生成简短的层标识
+func layerDigest(layer string) string {
+	// OCI格式: blobs/sha256/<hex>；旧格式: <id>/layer.tar
+	id := path.Base(layer)
+	if id == "layer.tar" {
+		id = path.Base(path.Dir(layer))
+	}
+	if len(id) > 12 {
+		id = id[:12]
+	}
+	return "sha256:" + id
+}
@@ -0,0 +1,66 @@
+package scanner
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// writeTar 按顺序写入 tar 包条目
+func writeTar(t *testing.T, w *tar.Writer, entries [][2]string) {
+	t.Helper()
+	for _, entry := range entries {
+		if err := w.WriteHeader(&tar.Header{Name: entry[0], Mode: 0644, Size: int64(len(entry[1])), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(entry[1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestDockerLayerExcludeDirs 镜像层中位于 -ed 排除目录中的文件不提取
+func TestDockerLayerExcludeDirs(t *testing.T) {
+	var layer bytes.Buffer
+	lw := tar.NewWriter(&layer)
+	writeTar(t, lw, [][2]string{
+		{"app/config.conf", "password=Tr0ub4dor"},
+		{"app/node_modules/pkg/config.conf", "password=Tr0ub4dor"},
+		{"usr/lib/vendor/settings.conf", "password=Tr0ub4dor"},
+		{"etc/app.conf", "password=Tr0ub4dor"},
+	})
+	lw.Close()
+
+	manifest, _ := json.Marshal([]dockerManifest{{Config: "config.json", Layers: []string{"abc123/layer.tar"}}})
+	image := filepath.Join(t.TempDir(), "image.tar")
+	var buf bytes.Buffer
+	iw := tar.NewWriter(&buf)
+	writeTar(t, iw, [][2]string{{"manifest.json", string(manifest)}, {"abc123/layer.tar", layer.String()}})
+	iw.Close()
+	if err := os.WriteFile(image, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(t, "--docker-image", image, "-k", "password=", "-ed", "node_modules,vendor")
+	s := NewScanner(cfg)
+	files, cleanup, err := s.prepareDockerImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	var got []string
+	for _, file := range files {
+		got = append(got, s.displayPath(file))
+	}
+	sort.Strings(got)
+	want := []string{"sha256:abc123:/app/config.conf", "sha256:abc123:/etc/app.conf"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extracted %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package scanner
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// 生成文件检测的参数
+const (
+	generatedSampleSize    = 64 * 1024 // 读取文件开头的字节数
+	generatedMarkerLines   = 10        // 在前几行中查找生成标记
+	minifiedMinSize        = 2 * 1024  // 小于该大小的内容不判断是否压缩
+	minifiedAvgLineLen     = 300       // 压缩内容的平均行长度下限
+	minifiedMaxWhitespace  = 0.08      // 压缩内容中空格和制表符的比例上限
+	minifiedMinPunctuation = 0.08      // 压缩内容中代码符号的比例下限
+)
+
+// generatedMarkers 前几行中表示文件由工具生成的标记
+var generatedMarkers = []*regexp.Regexp{
+	regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`), // Go
+	regexp.MustCompile(`@generated\b`),                         // Facebook、Thrift、Relay 等
+	regexp.MustCompile(`<auto-generated\b`),                    // C#、.NET
+	regexp.MustCompile(`(?i)\b(auto-?generated|generated by)\b.*\bdo not (edit|modify)\b`),
+	regexp.MustCompile(`(?i)\bdo not (edit|modify)\b.*\b(auto-?generated|generated by)\b`),
+}
+
+// sourceMapName 源码映射文件名（.js.map、.css.map 等）
+var sourceMapName = regexp.MustCompile(`(?i)\.(js|mjs|cjs|css|ts)\.map$`)
+
+// minifiedName 按命名约定为压缩后的文件（.min.js、.min.css 等）
+var minifiedName = regexp.MustCompile(`(?i)[.-]min\.(js|mjs|cjs|css)$`)
+
+// detectGenerated 按文件名和文件开头的内容判断是否为生成或压缩的文件（--skip-generated），返回原因，不是时返回空
+// 二进制内容（包含零字节）不判断
+func detectGenerated(path string) string {
+	switch {
+	case minifiedName.MatchString(path):
+		return "压缩文件名"
+	case sourceMapName.MatchString(path):
+		return "源码映射"
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+	sample := make([]byte, generatedSampleSize)
+	n, err := io.ReadFull(file, sample)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return ""
+	}
+	sample = sample[:n]
+	if bytes.IndexByte(sample, 0) >= 0 {
+		return ""
+	}
+
+	lines := strings.SplitN(string(sample), "\n", generatedMarkerLines+1)
+	if len(lines) > generatedMarkerLines {
+		lines = lines[:generatedMarkerLines]
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		for _, marker := range generatedMarkers {
+			if marker.MatchString(line) {
+				return "生成标记"
+			}
+		}
+	}
+
+	// 内容为源码映射（文件名不是 .map 的情况）
+	if head := bytes.TrimSpace(sample); bytes.HasPrefix(head, []byte(`{"version":3`)) && bytes.Contains(head, []byte(`"mappings"`)) {
+		return "源码映射"
+	}
+	if isMinified(sample) {
+		return "压缩内容"
+	}
+	return ""
+}
+
+// isMinified 判断内容是否为压缩后的代码：行很长（或没有换行）、空白很少、代码符号密集
+// 三个条件同时满足才判定，单独的长行（如 Base64 数据、长日志行）不会被误判
+func isMinified(sample []byte) bool {
+	if len(sample) < minifiedMinSize {
+		return false
+	}
+	newlines := bytes.Count(sample, []byte("\n"))
+	if len(sample)/(newlines+1) < minifiedAvgLineLen {
+		return false
+	}
+
+	var whitespace, punctuation int
+	for _, c := range sample {
+		switch c {
+		case ' ', '\t':
+			whitespace++
+		case '{', '}', '(', ')', '[', ']', ';', ',', '=', ':':
+			punctuation++
+		}
+	}
+	size := float64(len(sample))
+	return float64(whitespace)/size < minifiedMaxWhitespace && float64(punctuation)/size >= minifiedMinPunctuation
+}
@@ -0,0 +1,25 @@
+package scanner
+
+import (
+	"Findx/internal/logger"
+	"Findx/internal/output"
+)
+
+// matchKnownHash 计算文件的 SHA-256 并与已知哈希列表比较，未指定哈希列表时不计算
+func (s *Scanner) matchKnownHash(path string) (string, bool) {
+	if len(s.config.KnownHashes) == 0 {
+		return "", false
+	}
+
+	hash, err := hashFile(path)
+	if err != nil {
+		logger.Warnf("计算文件哈希失败: %s: %v", path, err)
+		return "", false
+	}
+
+	label, ok := s.config.KnownHashes[hash]
+	if !ok {
+		return "", false
+	}
+	return output.FormatHashMatchRaw(hash, label), true
+}
@@ -0,0 +1,122 @@
+package scanner
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+	"Findx/internal/output"
+	"Findx/internal/parser"
+	"Findx/internal/risk"
+)
+
+// writeManifest 写入扫描清单（--manifest），记录扫描范围、生效的配置和统计，不包含匹配值
+func (s *Scanner) writeManifest(start time.Time, elapsed time.Duration, scanned int) error {
+	name, _, version := config.GetAppInfo()
+	cfg := s.config
+
+	// 需要额外选项启用的规则只在指定了选项时列出
+	optional := map[string]bool{
+		output.WeakPasswordRuleName:      cfg.WeakPasswords,
+		output.HashMatchRuleName:         len(cfg.KnownHashes) > 0,
+		parser.EntropyAssignmentRuleName: len(cfg.SecretWords) > 0,
+	}
+	var rules []string
+	for _, rule := range parser.BuiltinRuleNames() {
+		if enabled, ok := optional[rule]; ok && !enabled {
+			continue
+		}
+		if cfg.RuleEnabled(rule) {
+			rules = append(rules, rule)
+		}
+	}
+
+	byExt := s.walkStats.ByExt
+	if byExt == nil {
+		byExt = make(map[string]int)
+	}
+	total, byRisk, _ := s.summarizeFindings()
+
+	s.mu.Lock()
+	parseErrors := append([]string(nil), s.panicked...)
+	s.mu.Unlock()
+	sort.Strings(parseErrors)
+	skipped := int(atomic.LoadInt64(&s.skipped))
+
+	manifest := &output.Manifest{
+		Tool:      name,
+		Version:   version,
+		StartTime: start.Format(time.RFC3339),
+		EndTime:   start.Add(elapsed).Format(time.RFC3339),
+		Duration:  elapsed.String(),
+		Target:    s.scanTarget(),
+		Config: output.ManifestConfig{
+			FileTypes:      cfg.FileTypes,
+			ExcludeExts:    cfg.ExcludeExts,
+			ExcludeDirs:    cfg.ExcludeDirs,
+			AutoExcludes:   cfg.AutoExcludes,
+			ExcludeFiles:   cfg.ExcludeFiles,
+			Keywords:       cfg.Keywords,
+			KeywordCI:      cfg.KeywordCI,
+			Rules:          rules,
+			OnlyRules:      cfg.OnlyRules,
+			SkipRules:      cfg.SkipRules,
+			Tags:           cfg.Tags,
+			TestPaths:      cfg.TestPaths,
+			MaxFileSize:    cfg.MaxFileSize,
+			MinFileSize:    cfg.MinFileSize,
+			SkipHidden:     cfg.SkipHidden,
+			SkipGenerated:  cfg.SkipGenerated,
+			IncludeGit:     cfg.IncludeGit,
+			Owner:          cfg.Owner,
+			Group:          cfg.Group,
+			BinaryMode:     cfg.BinaryMode,
+			RawScan:        cfg.RawScan,
+			DualScan:       cfg.DualScan,
+			BinaryFallback: cfg.BinaryFallback,
+			MaxFindings:    cfg.MaxFindings,
+			Seed:           cfg.Seed,
+			Incremental:    cfg.Incremental,
+			Threads:        cfg.ThreadCount,
+		},
+		Files: output.ManifestFiles{
+			Scanned:    scanned - skipped,
+			Cached:     s.cache.Hits(),
+			TotalBytes: s.walkStats.TotalBytes,
+			ByExt:      byExt,
+		},
+		Skipped: output.ManifestSkipped{
+			ExcludedDirs:  s.walkStats.SkippedDirs,
+			ExcludedFiles: s.walkStats.SkippedFiles,
+			TooLarge:      s.walkStats.SkippedSize,
+			TooSmall:      s.walkStats.SkippedSmall,
+			OwnerMismatch: s.walkStats.SkippedOwner,
+			Generated:     s.walkStats.SkippedGenerated,
+			MaxFindings:   skipped,
+			ParseErrors:   parseErrors,
+		},
+		Findings: output.ManifestFindings{
+			Total:    total,
+			ByRisk:   byRisk,
+			Score:    risk.Score(byRisk),
+			TestData: int(atomic.LoadInt64(&s.demoted)),
+		},
+		Incomplete: skipped > 0 || len(parseErrors) > 0,
+	}
+
+	return output.WriteManifest(cfg.Manifest, manifest)
+}
+
+// saveManifest 指定 --manifest 时写入扫描清单并输出保存位置
+func (s *Scanner) saveManifest(start time.Time, elapsed time.Duration, scanned int) {
+	if s.config.Manifest == "" {
+		return
+	}
+	if err := s.writeManifest(start, elapsed, scanned); err != nil {
+		logger.Errorf("写入扫描清单失败: %v", err)
+		return
+	}
+	logger.Infof("扫描清单保存至: %s", s.config.Manifest)
+}
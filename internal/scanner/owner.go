@@ -0,0 +1,78 @@
+package scanner
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+
+	"Findx/internal/logger"
+)
+
+// ownerFilter 按文件属主和属组筛选待扫描文件，-1 表示不限制
+type ownerFilter struct {
+	uid int
+	gid int
+}
+
+// newOwnerFilter 解析 --owner / --group 指定的用户和组（名称或数字ID）
+// 未指定时返回 nil；当前平台无法获取文件属主时给出警告并忽略筛选
+func newOwnerFilter(owner, group string) (*ownerFilter, error) {
+	if owner == "" && group == "" {
+		return nil, nil
+	}
+	if !ownershipSupported {
+		logger.Warnf("当前平台不支持按文件属主筛选，忽略 --owner/--group")
+		return nil, nil
+	}
+
+	filter := &ownerFilter{uid: -1, gid: -1}
+	if owner != "" {
+		uid, err := lookupID(owner, func(name string) (string, error) {
+			u, err := user.Lookup(name)
+			if err != nil {
+				return "", err
+			}
+			return u.Uid, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 --owner %s: %v", owner, err)
+		}
+		filter.uid = uid
+	}
+	if group != "" {
+		gid, err := lookupID(group, func(name string) (string, error) {
+			g, err := user.LookupGroup(name)
+			if err != nil {
+				return "", err
+			}
+			return g.Gid, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("无法解析 --group %s: %v", group, err)
+		}
+		filter.gid = gid
+	}
+	return filter, nil
+}
+
+// lookupID 数字直接作为ID，否则按名称查找
+func lookupID(value string, lookup func(string) (string, error)) (int, error) {
+	if id, err := strconv.Atoi(value); err == nil && id >= 0 {
+		return id, nil
+	}
+	id, err := lookup(value)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(id)
+}
+
+// match 判断文件属主和属组是否符合条件，无法获取属主的文件视为不符合
+func (f *ownerFilter) match(info os.FileInfo) bool {
+	uid, gid, ok := fileOwner(info)
+	if !ok {
+		return false
+	}
+	return (f.uid < 0 || uid == f.uid) && (f.gid < 0 || gid == f.gid)
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package scanner
+
+import "os"
+
+// ownershipSupported 当前平台是否能获取文件属主
+const ownershipSupported = false
+
+// fileOwner 当前平台无法获取文件属主
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	return 0, 0, false
+}
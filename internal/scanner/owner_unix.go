@@ -0,0 +1,20 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os"
+	"syscall"
+)
+
+// ownershipSupported 当前平台是否能获取文件属主
+const ownershipSupported = true
+
+// fileOwner 获取文件的属主UID和属组GID
+func fileOwner(info os.FileInfo) (uid, gid int, ok bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(stat.Uid), int(stat.Gid), true
+}
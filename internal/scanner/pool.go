@@ -0,0 +1,86 @@
+package scanner
+
+import "sync"
+
+// entryPool 将压缩包条目分派到扫描文件的同一个工作池中（--archive-fanout）
+// slots 为扫描器的并发槽位：发送占用、接收释放
+type entryPool struct {
+	slots    chan struct{}
+	memory   *memoryBudget
+	panicked func(archive, entry string, r interface{}) // 条目扫描时发生 panic 的处理
+}
+
+// newEntryPool 创建压缩包条目工作池，limit 为同时解压的条目总大小上限，panicked 记录发生 panic 的条目
+func newEntryPool(slots chan struct{}, limit int64, panicked func(archive, entry string, r interface{})) *entryPool {
+	return &entryPool{
+		slots:    slots,
+		memory:   newMemoryBudget(limit),
+		panicked: panicked,
+	}
+}
+
+// Scan 在工作池中并发扫描条目，返回时所有条目均已完成
+// 调用方是压缩包所在文件的工作协程，等待条目期间让出自己的槽位，否则多个压缩包同时分派时会互相等待槽位
+func (p *entryPool) Scan(archive string, names []string, sizes []int64, scan func(i int)) {
+	<-p.slots
+	defer func() { p.slots <- struct{}{} }()
+
+	var wg sync.WaitGroup
+	for i, size := range sizes {
+		// 先预留内存再占用槽位，等待内存时不占用槽位
+		reserved := p.memory.acquire(size)
+		p.slots <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer p.memory.release(reserved)
+			defer func() { <-p.slots }()
+			// 条目在单独的协程中扫描，工作协程的 recover 捕获不到，异常的条目跳过并与异常的文件一起报告
+			defer func() {
+				if r := recover(); r != nil {
+					p.panicked(archive, names[i], r)
+				}
+			}()
+			scan(i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// memoryBudget 限制同时解压的条目总大小
+type memoryBudget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+// newMemoryBudget 创建内存预算
+func newMemoryBudget(limit int64) *memoryBudget {
+	b := &memoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire 预留 size 字节，超出上限的条目按上限预留（独占全部预算），返回实际预留的大小
+func (b *memoryBudget) acquire(size int64) int64 {
+	if size > b.limit {
+		size = b.limit
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.used+size > b.limit {
+		b.cond.Wait()
+	}
+	b.used += size
+	return size
+}
+
+// release 归还预留的内存
+func (b *memoryBudget) release(size int64) {
+	b.mu.Lock()
+	b.used -= size
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
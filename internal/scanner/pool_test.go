@@ -0,0 +1,39 @@
+package scanner
+
+import (
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// TestEntryPoolRecordsPanics 压缩包中一个条目的解析器 panic 时，其他条目继续扫描，异常的条目与异常的文件一起报告
+func TestEntryPoolRecordsPanics(t *testing.T) {
+	dir := t.TempDir()
+	cfg := testConfig(t, "-f", dir, "-n", "2", "--archive-fanout", "--relative-paths")
+	s := NewScanner(cfg)
+
+	slots := make(chan struct{}, cfg.ThreadCount)
+	pool := newEntryPool(slots, cfg.ArchiveMemory, func(archive, entry string, r interface{}) {
+		s.recordPanic(s.displayPath(archive)+"!"+entry, r)
+	})
+
+	names := []string{"a/Good.class", "a/Malformed.class", "a/Other.class"}
+	var scanned int32
+	slots <- struct{}{} // 调用方是占用一个槽位的工作协程
+	pool.Scan(filepath.Join(dir, "lib.jar"), names, []int64{10, 10, 10}, func(i int) {
+		if names[i] == "a/Malformed.class" {
+			var constants []string
+			_ = constants[7] // 解析器按损坏的常量池索引访问
+		}
+		atomic.AddInt32(&scanned, 1)
+	})
+	<-slots
+
+	if scanned != 2 {
+		t.Errorf("scanned %d entries, want 2", scanned)
+	}
+	if want := []string{"lib.jar!a/Malformed.class"}; !reflect.DeepEqual(s.panicked, want) {
+		t.Errorf("panicked = %v, want %v", s.panicked, want)
+	}
+}
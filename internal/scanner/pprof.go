@@ -0,0 +1,49 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"Findx/internal/logger"
+)
+
+// pprofShutdownTimeout 扫描结束后等待正在进行的性能采集完成的时间
+const pprofShutdownTimeout = 5 * time.Second
+
+// startPprof 在 addr 上启动 net/http/pprof 诊断服务（--pprof），返回的函数关闭服务
+// 处理器注册在独立的 ServeMux 上，不影响 http.DefaultServeMux
+func startPprof(addr string) (func(), error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("启动pprof服务失败: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	server := &http.Server{Handler: mux}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("pprof服务异常退出: %v", err)
+		}
+	}()
+	logger.Infof("pprof诊断服务: http://%s/debug/pprof/", listener.Addr())
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), pprofShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(ctx); err != nil {
+			// 超时时强制断开仍在采集的连接
+			server.Close()
+		}
+	}, nil
+}
@@ -0,0 +1,32 @@
+package scanner
+
+import (
+	"strings"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+)
+
+// applyAutoExclude 识别扫描目录的项目类型，按类型自动排除依赖和构建目录并追加相关文件类型（--auto-exclude）
+func (s *Scanner) applyAutoExclude(root, display string) {
+	if !s.config.AutoExclude {
+		return
+	}
+
+	projects := config.DetectProjectTypes(root)
+	if len(projects) == 0 {
+		logger.Infof("未识别项目类型: %s", display)
+		return
+	}
+	for _, project := range projects {
+		dirs, types := s.config.ApplyProjectType(project)
+		msg := "检测到项目类型: " + project.Name + " (" + display + ")"
+		if len(dirs) > 0 {
+			msg += "，自动排除: " + strings.Join(dirs, ", ")
+		}
+		if len(types) > 0 {
+			msg += "，追加文件类型: " + strings.Join(types, ", ")
+		}
+		logger.Infof("%s", msg)
+	}
+}
@@ -0,0 +1,67 @@
+package scanner
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+)
+
+// prepareRemoteRepo 将远程 Git 仓库浅克隆到临时目录并遍历其中的文件
+// 报告中的路径显示为 仓库地址:仓库内路径，返回待扫描文件列表和清理函数
+func (s *Scanner) prepareRemoteRepo() ([]string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "findx-git-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	repoURL := config.RedactRepoURL(s.config.Directories[0])
+	logger.Infof("克隆仓库: %s", repoURL)
+	cloneDir := filepath.Join(tempDir, "repo")
+	if err := cloneRepo(s.config.Directories[0], cloneDir, s.config.GitToken); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	s.applyAutoExclude(cloneDir, repoURL)
+	files := s.searchFiles(cloneDir)
+	for _, file := range files {
+		rel, err := filepath.Rel(cloneDir, file)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		if s.config.RelativePaths {
+			s.pathAliases[file] = rel
+		} else {
+			s.pathAliases[file] = repoURL + ":" + rel
+		}
+	}
+
+	return files, cleanup, nil
+}
+
+// cloneRepo 浅克隆仓库，令牌通过环境变量中的 http.extraHeader 传递，不出现在命令行参数中
+func cloneRepo(repoURL, dir, token string) error {
+	cmd := exec.Command("git", "clone", "--depth", "1", "--quiet", "--", repoURL, dir)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if token != "" {
+		auth := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + token))
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: Basic "+auth,
+		)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("克隆仓库失败: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
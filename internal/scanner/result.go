@@ -3,6 +3,10 @@ package scanner
 import (
 	"fmt"
 	"strings"
+
+	"Findx/internal/logger"
+	"Findx/internal/output"
+	"Findx/internal/risk"
 )
 
 // ScanResult 统一的扫描结果结构
@@ -80,32 +84,12 @@ func (r *ScanResult) FormatSimple() string {
 
 // maskSensitiveValue 对敏感值进行脱敏处理
 func maskSensitiveValue(value string) string {
-	if len(value) <= 6 {
-		return strings.Repeat("*", len(value))
-	}
-	
-	// 保留前2个和后2个字符
-	prefix := value[:2]
-	suffix := value[len(value)-2:]
-	middle := strings.Repeat("*", len(value)-4)
-	
-	return prefix + middle + suffix
+	return output.MaskValue(value)
 }
 
 // GetRiskIcon 获取风险等级图标
 func GetRiskIcon(riskLevel string) string {
-	switch strings.ToLower(riskLevel) {
-	case "critical":
-		return "🔴"
-	case "high":
-		return "🟠"
-	case "medium":
-		return "🟡"
-	case "low":
-		return "🟢"
-	default:
-		return "⚪"
-	}
+	return risk.Icon(riskLevel)
 }
 
 // ResultCollection 结果集合
@@ -138,21 +122,13 @@ func (rc *ResultCollection) Count() int {
 func (rc *ResultCollection) GetStatistics() map[string]int {
 	stats := make(map[string]int)
 	stats["total"] = len(rc.Results)
-	stats["critical"] = 0
-	stats["high"] = 0
-	stats["medium"] = 0
-	stats["low"] = 0
+	for _, name := range risk.Names() {
+		stats[name] = 0
+	}
 	
 	for _, result := range rc.Results {
-		switch strings.ToLower(result.RiskLevel) {
-		case "critical":
-			stats["critical"]++
-		case "high":
-			stats["high"]++
-		case "medium":
-			stats["medium"]++
-		case "low":
-			stats["low"]++
+		if level := risk.Normalize(result.RiskLevel); risk.IsValid(level) {
+			stats[level]++
 		}
 	}
 	
@@ -162,8 +138,11 @@ func (rc *ResultCollection) GetStatistics() map[string]int {
 // PrintStatistics 打印统计信息
 func (rc *ResultCollection) PrintStatistics() {
 	stats := rc.GetStatistics()
-	fmt.Printf("\n[*] 📊 扫描统计:\n")
-	fmt.Printf("    总计: %d 个敏感信息\n", stats["total"])
-	fmt.Printf("    🔴 严重: %d | 🟠 高危: %d | 🟡 中危: %d | 🟢 低危: %d\n",
-		stats["critical"], stats["high"], stats["medium"], stats["low"])
+	logger.Infof("📊 扫描统计:")
+	logger.Detailf("    总计: %d 个敏感信息", stats["total"])
+	var parts []string
+	for _, level := range risk.Levels() {
+		parts = append(parts, fmt.Sprintf("%s %s: %d", level.Icon, level.Label, stats[level.Name]))
+	}
+	logger.Detailf("    %s", strings.Join(parts, " | "))
 }
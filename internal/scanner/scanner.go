@@ -1,16 +1,25 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"Findx/internal/config"
+	"Findx/internal/logger"
 	"Findx/internal/output"
 	"Findx/internal/parser"
+	"Findx/internal/risk"
+	"Findx/pkg/utils"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
 )
 
 // Scanner 文件扫描器
@@ -18,28 +27,202 @@ type Scanner struct {
 	config      *config.Config
 	fileParser  *parser.FileParser
 	writer      *output.Writer
-	fileResults map[string][]string // 收集每个文件的结果用于生成HTML
-	mu          sync.Mutex          // 保护 fileResults
+	splitWriter *output.SplitWriter         // 按风险等级拆分的输出（仅指定 --split-output 时创建）
+	dedup       *Deduplicator
+	cache       *ScanCache
+	owner       *ownerFilter                // 按文件属主/属组筛选（未指定时为 nil）
+	blamer      *blamer                     // 标注结果行的提交信息（仅指定 --blame 时创建）
+	sinks       []*output.RemoteSink        // 实时发送结果的远程收集端（syslog/webhook）
+	ruleTags    map[string][]string         // 内置规则名称 -> 分类标签
+	walkStats   WalkStats                   // 文件遍历统计
+	pathAliases map[string]string           // 临时文件路径 -> 报告中显示的路径
+	fileResults map[string][]output.Finding // 收集每个文件的结果用于生成HTML
+	cleanFiles  []string                    // 扫描后没有结果的文件（仅指定 --clean-list 时记录）
+	sourcePaths map[string]string           // 报告中显示的路径 -> 文件绝对路径（用于编辑器链接）
+	hashOnly    map[string]bool             // 只计算哈希、不解析内容的文件（文件类型不受支持）
+	fileRoots   map[string]string           // 文件路径 -> 所属扫描目录（仅指定多个扫描目录时记录）
+	counted     int64                       // 计入 --max-findings / --fail-on 的结果数
+	skipped     int64                       // 达到结果上限后未扫描的文件数
+	demoted     int64                       // 位于测试数据目录中、风险等级已降为最低等级的结果数
+	panicked    []string                    // 解析时发生 panic 的文件（已跳过）
+	mu          sync.Mutex          // 保护 fileResults 和 cleanFiles
 }
 
 // NewScanner 创建扫描器
 func NewScanner(cfg *config.Config) *Scanner {
 	return &Scanner{
 		config:      cfg,
-		fileParser:  parser.NewFileParser(cfg.ContextLength),
-		writer:      output.NewWriter(cfg.OutputFile),
-		fileResults: make(map[string][]string),
+		fileParser:  newFileParser(cfg),
+		writer:      newWriter(cfg, cfg.OutputFile),
+		splitWriter: newSplitWriter(cfg),
+		dedup:       NewDeduplicator(cfg.DedupeBy),
+		cache:       LoadScanCache(cfg),
+		fileResults: make(map[string][]output.Finding),
+		sourcePaths: make(map[string]string),
+		hashOnly:    make(map[string]bool),
+		fileRoots:   make(map[string]string),
+		walkStats:   WalkStats{ByExt: make(map[string]int)},
+		pathAliases: make(map[string]string),
+		ruleTags:    parser.RuleTags(),
 	}
 }
 
+// newWriter 根据配置创建写入 path 的文本结果写入器，按 --output-encoding 决定是否写入 BOM 和是否转码为 GBK
+func newWriter(cfg *config.Config, path string) *output.Writer {
+	bom := cfg.OutputEncoding == config.OutputEncodingUTF8BOM
+	var writer *output.Writer
+	if cfg.Atomic {
+		writer = output.NewAtomicWriter(path, bom)
+	} else {
+		writer = output.NewWriter(path, bom)
+	}
+	if cfg.OutputEncoding == config.OutputEncodingGBK {
+		writer.SetEncoding(simplifiedchinese.GBK)
+	}
+	return writer
+}
+
+// newSplitWriter 指定 --split-output 时创建按风险等级拆分的写入器，各等级文件的写入方式与合并输出相同
+func newSplitWriter(cfg *config.Config) *output.SplitWriter {
+	if !cfg.SplitOutput {
+		return nil
+	}
+	return output.NewSplitWriter(cfg.OutputFile, func(path string) *output.Writer {
+		return newWriter(cfg, path)
+	})
+}
+
+// newFileParser 根据配置创建文件解析器，并提示未知的规则名称
+func newFileParser(cfg *config.Config) *parser.FileParser {
+	known := make(map[string]bool)
+	for _, name := range parser.BuiltinRuleNames() {
+		known[name] = true
+	}
+	for _, name := range append(append(append([]string{}, cfg.OnlyRules...), cfg.SkipRules...), cfg.NoValidateRules...) {
+		if !known[name] {
+			logger.Warnf("未知的规则名称: %s（可用规则: %s）", name, strings.Join(parser.BuiltinRuleNames(), ", "))
+		}
+	}
+
+	return parser.NewFileParser(parser.ParserConfig{
+		ContextLength:    cfg.ContextLength,
+		OnlyRules:        cfg.OnlyRules,
+		SkipRules:        cfg.SkipRules,
+		NoValidateRules:  cfg.NoValidateRules,
+		AllMatches:       cfg.AllMatches,
+		DualScan:         cfg.DualScan,
+		RawScan:          cfg.RawScan,
+		RawFallback:      cfg.BinaryFallback == config.BinaryFallbackRaw,
+		MaxPerRule:       cfg.MaxPerRule,
+		MaxStrings:       cfg.MaxStrings,
+		MergeFragments:   cfg.MergeFragments,
+		IntraFileWorkers: cfg.IntraFileWorkers,
+		RuleTimeout:      cfg.RuleTimeout,
+		ValueMaxLen:      cfg.ValueMaxLen,
+		TextThreshold:    cfg.TextThreshold,
+		KeywordCI:        cfg.KeywordCI,
+		Keywords:         cfg.Keywords,
+		MagicRoutes:      magicRoutes(cfg.MagicSignatures),
+		KeywordRisks:     cfg.KeywordRisks,
+		SecretWords:      cfg.SecretWords,
+		EntropyThreshold: cfg.EntropyThreshold,
+		KeystorePassword: cfg.KeystorePassword,
+	})
+}
+
+// magicRoutes 将配置中的文件头签名转换为解析器的路由
+func magicRoutes(signatures []config.MagicSignature) []parser.MagicRoute {
+	routes := make([]parser.MagicRoute, 0, len(signatures))
+	for _, signature := range signatures {
+		routes = append(routes, parser.MagicRoute(signature))
+	}
+	return routes
+}
+
 // Run 执行扫描
 func (s *Scanner) Run() error {
 	start := time.Now()
 
+	if s.config.Pprof != "" {
+		stop, err := startPprof(s.config.Pprof)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	owner, err := newOwnerFilter(s.config.Owner, s.config.Group)
+	if err != nil {
+		return err
+	}
+	s.owner = owner
+
+	if s.config.Blame {
+		s.blamer = newBlamer()
+	}
+
+	sinks, err := openRemoteSinks(s.config)
+	if err != nil {
+		return err
+	}
+	s.sinks = sinks
+
+	if s.config.DumpStrings != "" {
+		dumper, err := parser.NewStringDumper(s.config.DumpStrings, s.displayPath)
+		if err != nil {
+			return err
+		}
+		s.fileParser.SetStringDumper(dumper)
+		defer func() {
+			if err := dumper.Close(); err != nil {
+				logger.Errorf("写入字符串转储失败: %v", err)
+				return
+			}
+			logger.Infof("字符串转储保存至: %s", s.config.DumpStrings)
+		}()
+	}
+
 	// 搜索文件
-	files := s.searchFiles()
+	var files []string
+	if s.config.StdinContent {
+		stdinFiles, cleanup, err := s.prepareStdin()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		files = stdinFiles
+	} else if s.config.DockerImage != "" {
+		imageFiles, cleanup, err := s.prepareDockerImage()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		files = imageFiles
+	} else if s.config.IsRemoteRepo() {
+		repoFiles, cleanup, err := s.prepareRemoteRepo()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		files = repoFiles
+	} else {
+		files = s.searchRoots()
+	}
+	
+	// 仅统计模式：输出汇总后直接结束，不解析文件
+	if s.config.CountOnly {
+		s.printCountReport(len(files), time.Since(start))
+		return nil
+	}
+	
 	if len(files) == 0 {
-		fmt.Println("[*] 未找到匹配的文件")
+		logger.Infof("未找到匹配的文件")
+		s.saveManifest(start, time.Since(start), 0)
+		return nil
+	}
+	
+	if !s.confirmScan(len(files)) {
+		logger.Infof("已取消扫描")
 		return nil
 	}
 
@@ -48,39 +231,167 @@ func (s *Scanner) Run() error {
 
 	// 输出统计信息
 	elapsed := time.Since(start)
-	fmt.Printf("[*] 🎉🎉🎉🎉🎉🎉扫描完成🎉🎉🎉🎉🎉🎉\n")
-	fmt.Printf("[*] 扫描文件总数: %d    总耗时: %s\n", len(files), elapsed)
-	fmt.Printf("[*] 详细结果保存至: %s\n", s.config.OutputFile)
+	logger.Infof("🎉🎉🎉🎉🎉🎉扫描完成🎉🎉🎉🎉🎉🎉")
+	logger.Infof("扫描文件总数: %d    总耗时: %s", len(files), elapsed)
+	if findings, byLevel, _ := s.summarizeFindings(); findings > 0 {
+		logger.Infof("风险评分: %d（%s）", risk.Score(byLevel), s.config.SeverityWeightsText())
+	}
+	if skipped := atomic.LoadInt64(&s.skipped); skipped > 0 {
+		logger.Warnf("结果数达到 --max-findings 上限 (%d)，提前结束扫描，跳过 %d 个文件，报告只包含部分结果", s.config.MaxFindings, skipped)
+	}
+	if len(s.panicked) > 0 {
+		sort.Strings(s.panicked)
+		logger.Warnf("%d 个文件或压缩包条目解析时发生异常，已跳过: %s", len(s.panicked), strings.Join(s.panicked, ", "))
+	}
+	if demoted := atomic.LoadInt64(&s.demoted); demoted > 0 {
+		logger.Infof("测试数据: %d 条结果位于测试数据目录中，风险等级已降为 %s", demoted, risk.Lowest())
+	}
+	if dropped := s.dedup.Dropped(); dropped > 0 {
+		logger.Infof("去重合并: %d 条重复结果 (%s)", dropped, s.config.DedupeBy)
+	}
+	if s.config.DirSummary {
+		s.printDirectorySummary()
+	}
+	if s.blamer != nil {
+		s.printBlameSummary()
+	}
+	if s.config.UnusedRules {
+		s.printUnusedRules()
+	}
+	if s.config.CleanList != "" {
+		if err := s.writeCleanList(); err != nil {
+			logger.Errorf("写入无结果文件列表失败: %v", err)
+		} else {
+			logger.Infof("无结果文件列表保存至: %s（%d 个文件）", s.config.CleanList, len(s.cleanFiles))
+		}
+	}
+	if s.cache != nil && s.config.Incremental {
+		if last := s.cache.LastScan(); last.IsZero() {
+			logger.Infof("增量扫描: 首次扫描，已记录 %d 个文件", len(files))
+		} else {
+			logger.Infof("增量扫描: 上次扫描于 %s，解析变化文件 %d 个，沿用 %d 个，移除已删除文件 %d 个",
+				last.Format("2006-01-02 15:04:05"), len(files)-s.cache.Hits(), s.cache.Hits(), s.cache.Removed())
+		}
+		if err := s.cache.Save(); err != nil {
+			logger.Errorf("%v", err)
+		}
+	} else if s.cache != nil {
+		logger.Infof("缓存命中: %d 个文件", s.cache.Hits())
+		if err := s.cache.Save(); err != nil {
+			logger.Errorf("%v", err)
+		}
+	}
+	s.saveManifest(start, elapsed, len(files))
+	
+	// 仅摘要模式：不生成包含具体结果的报告
+	if s.config.SummaryOnly {
+		if err := s.writeSummaryReport(len(files), elapsed); err != nil {
+			logger.Errorf("写入摘要失败: %v", err)
+		}
+		if err := s.writer.Commit(); err != nil {
+			logger.Errorf("%v", err)
+		}
+		logger.Infof("扫描摘要保存至: %s", s.config.OutputFile)
+		return nil
+	}
+	
+	// 所有工作协程已结束、结果已写入，汇总只在此处写入一次（原子写入模式下随结果一起替换输出文件）
+	if err := s.writeSummaryFooter(len(files), elapsed); err != nil {
+		logger.Errorf("写入汇总失败: %v", err)
+	}
+	if err := s.writer.Commit(); err != nil {
+		logger.Errorf("%v", err)
+	}
+	if s.splitWriter != nil {
+		if err := s.splitWriter.Commit(); err != nil {
+			logger.Errorf("%v", err)
+		}
+		s.logSplitOutput()
+	} else {
+		logger.Infof("详细结果保存至: %s", s.config.OutputFile)
+	}
 	
 	// 生成HTML报告
 	if err := s.generateHTMLReport(elapsed); err != nil {
-		fmt.Printf("[-] 生成HTML报告失败: %v\n", err)
+		logger.Errorf("生成HTML报告失败: %v", err)
 	} else {
-		fmt.Printf("[*] HTML报告保存至: %s\n", s.config.HTMLOutput)
+		logger.Infof("HTML报告保存至: %s", s.config.HTMLOutput)
+	}
+	
+	// 生成JSON报告
+	if s.config.JSONOutput != "" {
+		if err := s.generateJSONReport(elapsed); err != nil {
+			logger.Errorf("生成JSON报告失败: %v", err)
+		} else {
+			logger.Infof("JSON报告保存至: %s", s.config.JSONOutput)
+		}
+	}
+
+	// 生成CSV报告
+	if s.config.CSVOutput != "" {
+		generator := output.NewCSVReportGenerator(s.config.CSVBOM, s.config.CSVMask)
+		if err := generator.Generate(s.config.CSVOutput, s.fileResults); err != nil {
+			logger.Errorf("生成CSV报告失败: %v", err)
+		} else {
+			logger.Infof("CSV报告保存至: %s", s.config.CSVOutput)
+		}
 	}
 
 	return nil
 }
 
+// searchRoots 遍历所有扫描目录，指定多个目录时记录每个文件所属的目录
+func (s *Scanner) searchRoots() []string {
+	if len(s.config.Directories) == 1 {
+		s.applyAutoExclude(s.config.Directories[0], s.config.Directories[0])
+		return s.searchFiles(s.config.Directories[0])
+	}
+
+	// 先识别所有扫描目录的项目类型，自动排除的目录和追加的文件类型对所有目录生效
+	for _, root := range s.config.Directories {
+		s.applyAutoExclude(root, root)
+	}
+
+	var files []string
+	for _, root := range s.config.Directories {
+		for _, file := range s.searchFiles(root) {
+			// 嵌套的扫描目录中的文件只扫描一次，归属于最深的目录
+			if _, ok := s.fileRoots[file]; ok {
+				continue
+			}
+			s.fileRoots[file] = s.config.RootOf(file)
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
 // searchFiles 搜索目录中的文件
-func (s *Scanner) searchFiles() []string {
+func (s *Scanner) searchFiles(root string) []string {
 	var files []string
-	var skippedDirs int
-	var skippedFiles int
-	var skippedSize int
+	stats := &s.walkStats
 	
-	err := filepath.Walk(s.config.Directory, func(path string, info os.FileInfo, err error) error {
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 		
+		// 隐藏文件和 .git 目录（扫描根目录除外）
+		if path != root && s.config.ShouldSkipHidden(info.Name(), info.IsDir()) {
+			if info.IsDir() {
+				stats.SkippedDirs++
+				logger.Debugf("跳过目录: %s", path)
+				return filepath.SkipDir
+			}
+			stats.SkippedFiles++
+			return nil
+		}
+		
 		// 检查是否排除目录
 		if info.IsDir() {
 			if s.config.ShouldExcludeDir(path) {
-				skippedDirs++
-				if s.config.Verbose {
-					fmt.Printf("[*] 跳过目录: %s\n", path)
-				}
+				stats.SkippedDirs++
+				logger.Debugf("跳过目录: %s", path)
 				return filepath.SkipDir
 			}
 			return nil
@@ -88,176 +399,619 @@ func (s *Scanner) searchFiles() []string {
 		
 		// 检查是否排除文件
 		if s.config.ShouldExcludeFile(path) {
-			skippedFiles++
+			stats.SkippedFiles++
 			return nil
 		}
 		
 		// 检查文件大小
 		if s.config.ShouldSkipBySize(info.Size()) {
-			skippedSize++
-			if s.config.Verbose {
-				fmt.Printf("[*] 跳过大文件: %s (%.2f MB)\n", path, float64(info.Size())/1024/1024)
+			if info.Size() < s.config.MinFileSize {
+				stats.SkippedSmall++
+				logger.Debugf("跳过小文件: %s (%d 字节)", path, info.Size())
+				return nil
 			}
+			stats.SkippedSize++
+			logger.Debugf("跳过大文件: %s (%.2f MB)", path, float64(info.Size())/1024/1024)
 			return nil
 		}
 		
-		// 检查文件类型
-		if s.config.IsFileTypeSupported(info.Name()) {
+		// 检查文件属主
+		if s.owner != nil && !s.owner.match(info) {
+			stats.SkippedOwner++
+			return nil
+		}
+		
+		// 检查文件类型，已知的敏感文件和命令历史文件不受文件类型限制
+		if s.config.IsFileTypeSupported(info.Name()) || s.config.IsSensitiveFile(path) || parser.IsHistoryFile(path) {
+			// 跳过生成和压缩的文件，已知的敏感文件和命令历史文件总会扫描
+			if s.config.SkipGenerated && !s.config.IsSensitiveFile(path) && !parser.IsHistoryFile(path) {
+				if reason := detectGenerated(path); reason != "" {
+					stats.SkippedGenerated++
+					logger.Debugf("跳过生成文件: %s (%s)", path, reason)
+					return nil
+				}
+			}
+			files = append(files, path)
+			stats.TotalBytes += info.Size()
+			stats.ByExt[extensionOf(path)]++
+		} else if len(s.config.KnownHashes) > 0 {
+			// 指定了哈希列表时，其他文件也需要计算哈希
 			files = append(files, path)
+			s.hashOnly[path] = true
 		}
 		
 		return nil
 	})
 	
 	if err != nil {
-		fmt.Printf("[-] 扫描目录错误: %v\n", err)
+		logger.Errorf("扫描目录错误: %v", err)
 	}
 	
 	// 打印统计信息
-	if skippedDirs > 0 || skippedFiles > 0 || skippedSize > 0 {
-		fmt.Printf("[*] 跳过统计: 目录(%d) 文件(%d) 大文件(%d)\n", skippedDirs, skippedFiles, skippedSize)
+	if stats.SkippedDirs > 0 || stats.SkippedFiles > 0 || stats.SkippedSize > 0 || stats.SkippedSmall > 0 || stats.SkippedOwner > 0 || stats.SkippedGenerated > 0 {
+		logger.Infof("跳过统计: 目录(%d) 文件(%d) 大文件(%d) 小文件(%d) 属主不符(%d) 生成文件(%d)", stats.SkippedDirs, stats.SkippedFiles, stats.SkippedSize, stats.SkippedSmall, stats.SkippedOwner, stats.SkippedGenerated)
 	}
 	
 	return files
 }
 
+// parseFile 解析文件内容（关闭原始输出），解析器 panic 时记录文件并返回 false，不影响其他文件的扫描
+// 损坏的 Office、压缩包等文件可能触发第三方库的 panic
+func (s *Scanner) parseFile(ctx context.Context, path string) (results []string, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.recordPanic(s.displayPath(path), r)
+			results, ok = nil, false
+		}
+	}()
+	return s.fileParser.ParseContext(ctx, path, s.config.Keywords, false), true
+}
+
+// recordPanic 记录解析时发生 panic 的文件或压缩包条目（压缩包路径!条目名），扫描结束时统一报告
+// 在 recover 所在的延迟函数中调用，堆栈包含发生 panic 的位置
+func (s *Scanner) recordPanic(name string, r interface{}) {
+	logger.Errorf("解析文件时发生异常，已跳过: %s: %v", name, r)
+	logger.Debugf("异常堆栈:\n%s", debug.Stack())
+	s.mu.Lock()
+	s.panicked = append(s.panicked, name)
+	s.mu.Unlock()
+}
+
+// fileBlock 单个文件格式化后的输出块
+type fileBlock struct {
+	start    int                 // 块中第一个结果的序号
+	count    int                 // 块中的结果数
+	parts    []string            // 文件头和每个结果的格式化文本
+	path     string              // 报告中显示的文件路径
+	findings []output.Finding    // 块中的结果，发送到远程收集端
+	byLevel  map[string][]string // 按风险等级拆分的文件头和结果（仅 --split-output）
+}
+
 // scanFiles 并发扫描文件
+// 工作协程各自格式化结果后交给唯一的输出协程写入，输出时不再互相阻塞
 func (s *Scanner) scanFiles(files []string) {
 	var wg sync.WaitGroup
-	var mu sync.Mutex // 添加互斥锁保护输出
 	semaphore := make(chan struct{}, s.config.ThreadCount)
+	if s.config.ArchiveFanout {
+		s.fileParser.SetEntryPool(newEntryPool(semaphore, s.config.ArchiveMemory, func(archive, entry string, r interface{}) {
+			s.recordPanic(s.displayPath(archive)+"!"+entry, r)
+		}))
+	}
 	
 	formatter := output.NewResultFormatter()
-	resultIndex := 0
+	var resultIndex int64 // 已分配的结果序号
+
+	// 达到 --max-findings 上限时取消，未开始的文件直接跳过，正在进行的二进制扫描中止
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blocks := make(chan fileBlock, s.config.ThreadCount)
+	written := make(chan struct{})
+	go s.writeBlocks(blocks, written)
 
-	for _, filePath := range files {
+	// 指定 --seed 时按种子确定的顺序提交结果，结果与协程调度无关
+	var seq *fileSequencer
+	if s.config.Seed != 0 {
+		files = s.seededOrder(files)
+		seq = newFileSequencer(len(files))
+	}
+
+	for i, filePath := range files {
 		wg.Add(1)
-		go func(path string) {
+		go func(i int, path string) {
 			defer wg.Done()
+			defer seq.pass(i)
 			
-			// 获取信号量
+			// 获取信号量，等待提交顺序前释放，否则后面的文件占满槽位时前面的文件无法完成
 			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			holding := true
+			defer func() {
+				if holding {
+					<-semaphore
+				}
+			}()
 
-			// 解析文件内容
-			rawResults := s.fileParser.Parse(path, s.config.Keywords, false) // 关闭原始输出
+			if ctx.Err() != nil {
+				atomic.AddInt64(&s.skipped, 1)
+				return
+			}
+
+			// 解析文件内容，未变化的文件使用缓存结果
+			var rawResults []string
+			if !s.hashOnly[path] {
+				var cached bool
+				rawResults, cached = s.cache.Lookup(path)
+				if !cached {
+					var ok bool
+					rawResults, ok = s.parseFile(ctx, path)
+					// 中途取消或发生异常的扫描结果可能不完整，不写入缓存
+					if ok && ctx.Err() == nil {
+						s.cache.Store(path, rawResults)
+					}
+				}
+			}
+			if s.config.IsSensitiveFile(s.displayPath(path)) {
+				rawResults = append([]string{output.FormatSensitiveFileRaw(filepath.Base(path))}, rawResults...)
+			}
+			if raw, ok := s.matchKnownHash(path); ok {
+				rawResults = append([]string{raw}, rawResults...)
+			}
+			
+			// 分类结果并应用风险等级覆盖
+			findings := s.classifyResults(path, rawResults)
+			if s.config.JSONRawContext > 0 {
+				attachRawContext(path, findings, s.config.JSONRawContext)
+			}
+			annotateSections(path, findings)
+			if s.blamer != nil {
+				s.blamer.annotate(path, findings)
+			}
+			if root := s.fileRoots[path]; root != "" {
+				for i := range findings {
+					findings[i].Root = root
+				}
+			}
+			if seq != nil {
+				holding = false
+				<-semaphore
+				seq.wait(i)
+				// 之前的文件达到 --max-findings 上限时，已解析的结果同样丢弃
+				if ctx.Err() != nil {
+					atomic.AddInt64(&s.skipped, 1)
+					return
+				}
+			}
+			sourcePath := path
+			path = s.displayPath(path)
+			// 去重前判断，结果与其他文件重复的文件不视为无结果
+			if len(findings) == 0 && s.config.CleanList != "" && !s.hashOnly[sourcePath] {
+				s.mu.Lock()
+				s.cleanFiles = append(s.cleanFiles, path)
+				s.mu.Unlock()
+			}
+			if len(findings) == 0 && s.config.ShowClean {
+				logger.Detailf("[ok] %s", path)
+			}
+			findings = s.dedup.Filter(path, findings)
+			// 去重按完整的值比较，之后再统一截断，所有输出中的匹配值一致
+			if s.config.ValueMaxLen > 0 {
+				for i := range findings {
+					findings[i].MatchedValue = utils.TruncateString(findings[i].MatchedValue, s.config.ValueMaxLen)
+				}
+			}
+			if s.countFindings(findings) {
+				cancel()
+			}
+			
+			// 仅摘要模式只收集结果用于统计
+			if s.config.SummaryOnly {
+				if len(findings) > 0 {
+					s.mu.Lock()
+					s.fileResults[path] = findings
+					s.mu.Unlock()
+				}
+				return
+			}
 			
 			// 写入结果
-			if len(rawResults) > 0 {
-				// 使用互斥锁保护输出，确保同一文件的结果不被打断
-				mu.Lock()
-				defer mu.Unlock()
-				
+			if len(findings) > 0 {
 				// 收集结果用于HTML报告
 				s.mu.Lock()
-				s.fileResults[path] = rawResults
+				s.fileResults[path] = findings
+				s.recordSourcePath(path, sourcePath)
 				s.mu.Unlock()
 				
-				// 格式化文件头
-				header := formatter.FormatFileHeader(path, len(rawResults))
-				
-				// 如果启用了 verbose，先输出文件头到控制台
-				if s.config.Verbose {
-					fmt.Print(header)
+				// 预留连续的结果序号，格式化在工作协程中完成
+				start := int(atomic.AddInt64(&resultIndex, int64(len(findings)))) - len(findings) + 1
+				block := fileBlock{
+					start:    start,
+					count:    len(findings),
+					parts:    make([]string, 0, len(findings)+1),
+					path:     path,
+					findings: findings,
 				}
-				
-				// 格式化每个结果
-				var formattedResults []string
-				formattedResults = append(formattedResults, header)
-				
-				for _, raw := range rawResults {
-					resultIndex++
-					formatted := s.formatResult(formatter, resultIndex, raw)
-					formattedResults = append(formattedResults, formatted)
-					
-					// 如果启用了 verbose，输出格式化后的结果到控制台
-					if s.config.Verbose {
-						fmt.Print(formatted)
+				if s.config.OutputFormat == config.OutputFormatFlat {
+					for i := range findings {
+						block.parts = append(block.parts, formatter.FormatFlatResult(path, &findings[i]))
+					}
+					if s.splitWriter != nil {
+						block.byLevel = splitByLevel(formatter, path, findings, block.parts, false)
+					}
+				} else {
+					block.parts = append(block.parts, formatter.FormatFileHeader(path, len(findings)))
+					for i := range findings {
+						block.parts = append(block.parts, s.formatResult(formatter, start+i, &findings[i]))
+					}
+					if s.splitWriter != nil {
+						block.byLevel = splitByLevel(formatter, path, findings, block.parts[1:], true)
 					}
 				}
-				
-				if err := s.writer.WriteFormattedResults(formattedResults); err != nil {
-					fmt.Printf("[-] 写入结果失败: %v\n", err)
-				}
+				blocks <- block
 			}
-		}(filePath)
+		}(i, filePath)
 	}
 
 	wg.Wait()
+	close(blocks)
+	<-written
 }
 
-// formatResult 格式化单个结果
-func (s *Scanner) formatResult(formatter *output.ResultFormatter, index int, raw string) string {
-	parts := strings.Split(raw, "|")
-	if len(parts) < 2 {
-		return raw
+// logSplitOutput 输出按风险等级拆分的结果文件，没有结果时汇总写入合并的输出文件
+func (s *Scanner) logSplitOutput() {
+	levels := s.splitWriter.Levels(risk.Names())
+	if len(levels) == 0 {
+		if s.config.OutputFormat == config.OutputFormatFlat {
+			logger.Infof("未发现敏感信息，未生成结果文件")
+		} else {
+			logger.Infof("未发现敏感信息，汇总保存至: %s", s.config.OutputFile)
+		}
+		return
 	}
-	
-	switch parts[0] {
-	case "TEXT":
-		if len(parts) >= 4 {
-			keyword := parts[1]
-			lineNum := 0
-			fmt.Sscanf(parts[2], "%d", &lineNum)
-			content := parts[3]
-			return formatter.FormatTextResult(index, keyword, lineNum, content)
+	for _, level := range levels {
+		logger.Infof("%s 结果保存至: %s", level, output.SplitOutputPath(s.config.OutputFile, level))
+	}
+}
+
+// splitByLevel 将一个文件的格式化结果按风险等级分组（--split-output），results 与 findings 一一对应
+// header 为真时每组以只统计该组结果数的文件头开始
+func splitByLevel(formatter *output.ResultFormatter, path string, findings []output.Finding, results []string, header bool) map[string][]string {
+	groups := make(map[string][]string)
+	for i := range findings {
+		level := risk.Normalize(findings[i].RiskLevel)
+		if level == "" {
+			level = "unknown"
 		}
-	case "WORD":
-		if len(parts) >= 4 {
-			location := parts[1]
-			keyword := parts[2]
-			content := parts[3]
-			return formatter.FormatDocumentResult(index, "Word文档", location, keyword, content)
+		groups[level] = append(groups[level], results[i])
+	}
+	if header {
+		for level, parts := range groups {
+			groups[level] = append([]string{formatter.FormatFileHeader(path, len(parts))}, parts...)
 		}
-	case "EXCEL":
-		if len(parts) >= 4 {
-			fileType := parts[1]
-			keyword := parts[2]
-			content := parts[3]
-			return formatter.FormatDocumentResult(index, fmt.Sprintf("Excel文档 (%s)", fileType), "单元格", keyword, content)
+	}
+	return groups
+}
+
+// countFindings 累计计入 --max-findings 的结果数（指定 --fail-on 时只计不低于该等级的结果），达到上限时返回 true
+func (s *Scanner) countFindings(findings []output.Finding) bool {
+	n := 0
+	for i := range findings {
+		if s.config.FailOn == "" || config.RiskLevelRank(findings[i].RiskLevel) >= config.RiskLevelRank(s.config.FailOn) {
+			n++
 		}
-	case "CSV":
-		if len(parts) >= 3 {
-			keyword := parts[1]
-			content := parts[2]
-			return formatter.FormatDocumentResult(index, "CSV文件", "字段", keyword, content)
+	}
+	total := atomic.AddInt64(&s.counted, int64(n))
+	return s.config.MaxFindings > 0 && total >= int64(s.config.MaxFindings)
+}
+
+// FailOnCount 返回不低于 --fail-on 风险等级的结果数，未指定 --fail-on 时返回 0
+func (s *Scanner) FailOnCount() int {
+	if s.config.FailOn == "" {
+		return 0
+	}
+	return int(atomic.LoadInt64(&s.counted))
+}
+
+// writeBlocks 输出协程：按结果序号顺序写入各文件的输出块
+// 输出文件在写入期间保持打开并缓冲，--flush-each 时每个文件的结果写入后立即刷新
+// 序号在工作协程中预留，先完成的块会暂存到之前的块写入后再输出，保证序号连续
+func (s *Scanner) writeBlocks(blocks <-chan fileBlock, written chan<- struct{}) {
+	defer close(written)
+
+	next := 1
+	pending := make(map[int]fileBlock)
+	for block := range blocks {
+		pending[block.start] = block
+		for {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next += ready.count
+
+			// 如果启用了 verbose，同时输出到控制台
+			if s.config.Verbose {
+				fmt.Print(strings.Join(ready.parts, ""))
+			}
+			if s.splitWriter != nil {
+				for level, parts := range ready.byLevel {
+					if err := s.splitWriter.WriteBlock(level, parts, s.config.FlushEach); err != nil {
+						logger.Errorf("写入结果失败: %v", err)
+					}
+				}
+			} else if err := s.writer.WriteBlock(ready.parts, s.config.FlushEach); err != nil {
+				logger.Errorf("写入结果失败: %v", err)
+			}
+			for _, sink := range s.sinks {
+				sink.Send(ready.path, ready.findings)
+			}
 		}
-	case "BINARY":
-		if len(parts) >= 7 {
-			matchType := parts[1]
-			ruleName := parts[2]
-			riskLevel := parts[3]
-			matchedValue := parts[4]
-			offset := 0
-			fmt.Sscanf(parts[5], "0x%X", &offset)
-			context := parts[6]
-			return formatter.FormatBinaryResult(index, matchType, ruleName, riskLevel, matchedValue, offset, context)
+	}
+
+	// 所有结果写入后关闭输出文件，之后的摘要等内容另行打开写入
+	if err := s.writer.Close(); err != nil {
+		logger.Errorf("写入结果失败: %v", err)
+	}
+	if s.splitWriter != nil {
+		if err := s.splitWriter.Close(); err != nil {
+			logger.Errorf("写入结果失败: %v", err)
 		}
 	}
+	closeRemoteSinks(s.sinks)
+}
+
+// displayPath 获取文件在报告中显示的路径，按 --path-separator 统一分隔符
+// 文本、HTML、JSON 等所有输出和编辑器链接都使用该路径，避免 Windows 上 / 和 \ 混用
+func (s *Scanner) displayPath(path string) string {
+	if alias, ok := s.pathAliases[path]; ok {
+		return alias
+	}
+	if s.config.RelativePaths {
+		path = s.config.RelativePath(path)
+	}
+	if s.config.PathSeparator == config.PathSeparatorNative {
+		return filepath.FromSlash(path)
+	}
+	return filepath.ToSlash(path)
+}
+
+// recordSourcePath 记录报告路径对应的文件绝对路径，Docker 镜像中的临时文件不记录
+func (s *Scanner) recordSourcePath(display, path string) {
+	if s.config.EditorLinks == "" {
+		return
+	}
+	if _, ok := s.pathAliases[path]; ok {
+		return
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		s.sourcePaths[display] = abs
+	}
+}
+
+// classifyResults 将原始结果解析为结构化结果，标注位于代码注释中的结果，并应用风险等级覆盖
+// 测试数据目录（--test-paths）中的结果在最后统一降为最低风险等级
+func (s *Scanner) classifyResults(sourcePath string, rawResults []string) []output.Finding {
+	path := s.displayPath(sourcePath)
+	findings := make([]output.Finding, 0, len(rawResults))
+	now := time.Now()
+	comments := newCommentChecker(sourcePath)
+	for _, raw := range rawResults {
+		finding, err := output.ParseFinding(raw)
+		if err != nil {
+			logger.Warnf("%v", err)
+			continue
+		}
+		if !s.config.RuleEnabled(finding.RuleName) {
+			continue
+		}
+		output.AnnotateJWT(finding, now)
+		switch finding.Kind {
+		case "TEXT", "WORD", "EXCEL", "CSV":
+			// 文本和文档的关键字结果不带风险等级，按关键字指定的等级设置（其他解析器已在结果中写入）
+			finding.RiskLevel = s.config.KeywordRisk(finding.Keyword)
+		}
+		finding.RiskLevel = risk.Normalize(finding.RiskLevel)
+		if comments.inComment(finding) {
+			finding.MarkInComment()
+		}
+		finding.RiskLevel = s.config.ResolveRiskLevel(path, finding.RuleName, finding.RiskLevel)
+		if finding.Kind == "BINARY" && s.belowBinaryMinRisk(finding.RiskLevel) {
+			continue
+		}
+		findings = append(findings, *finding)
+	}
+	
+	// 弱口令检查
+	if s.config.WeakPasswords && s.config.RuleEnabled(output.WeakPasswordRuleName) {
+		for _, weak := range weakPasswordFindings(findings) {
+			weak.RiskLevel = s.config.ResolveRiskLevel(path, weak.RuleName, risk.Normalize(weak.RiskLevel))
+			findings = append(findings, weak)
+		}
+	}
+	
+	if s.config.IsTestPath(path) {
+		for i := range findings {
+			findings[i].MarkInTestPath()
+		}
+		atomic.AddInt64(&s.demoted, int64(len(findings)))
+	}
 	
-	return raw
+	for i := range findings {
+		findings[i].Tags = s.ruleTags[strings.TrimSuffix(findings[i].RuleName, " (Base64编码)")]
+	}
+	return findings
 }
 
+// belowBinaryMinRisk 判断二进制结果的风险等级是否低于 --binary-min-risk
+func (s *Scanner) belowBinaryMinRisk(riskLevel string) bool {
+	if s.config.BinaryMinRisk == "" {
+		return false
+	}
+	return config.RiskLevelRank(riskLevel) < config.RiskLevelRank(s.config.BinaryMinRisk)
+}
 
-// truncateForBox 截断字符串以适应框格
-func truncateForBox(s string, maxLen int) string {
-	if len(s) <= maxLen {
-		return s
+// formatResult 格式化单个结果
+func (s *Scanner) formatResult(formatter *output.ResultFormatter, index int, f *output.Finding) string {
+	if f.Embedded != "" {
+		inner := *f
+		inner.Embedded = ""
+		return formatter.FormatEmbedded(s.formatResult(formatter, index, &inner), f.Embedded)
+	}
+	if f.Blame != nil {
+		inner := *f
+		inner.Blame = nil
+		return formatter.FormatBlame(s.formatResult(formatter, index, &inner), f.Blame.String())
+	}
+	if f.InTestPath {
+		inner := *f
+		inner.InTestPath = false
+		return formatter.FormatInTestPath(s.formatResult(formatter, index, &inner))
+	}
+	if f.InComment {
+		inner := *f
+		inner.InComment = false
+		return formatter.FormatInComment(s.formatResult(formatter, index, &inner))
 	}
-	return "..." + s[len(s)-maxLen+3:]
+	if f.Claims != nil {
+		return formatter.FormatJWTResult(index, f.MatchType, f.RiskLevel, f.MatchedValue, f.LineNumber, f.Offset, f.Claims.String(), f.Context)
+	}
+	switch f.Kind {
+	case "TEXT":
+		return formatter.FormatTextResult(index, f.Keyword, f.RiskLevel, f.LineNumber, f.Context)
+	case "WORD":
+		return formatter.FormatDocumentResult(index, "Word文档", f.Location, f.Keyword, f.RiskLevel, f.Context)
+	case "EXCEL":
+		return formatter.FormatDocumentResult(index, fmt.Sprintf("Excel文档 (%s)", f.Location), "单元格", f.Keyword, f.RiskLevel, f.Context)
+	case "CSV":
+		return formatter.FormatDocumentResult(index, "CSV文件", "字段", f.Keyword, f.RiskLevel, f.Context)
+	case "PAIR":
+		return formatter.FormatCellPairResult(index, f.Location, f.LineNumber, f.Keyword, f.MatchedValue, f.RiskLevel)
+	case "JAVA":
+		return formatter.FormatJavaResult(index, f.Location, f.ConstIndex, f.RuleName, f.RiskLevel, f.MatchedValue, f.Context)
+	case "LINE":
+		return formatter.FormatLineResult(index, f.MatchType, f.LineNumber, f.RuleName, f.RiskLevel, f.DisplayValue(), f.Context)
+	case "K8S":
+		return formatter.FormatK8sSecretResult(index, f.Location, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "PCAP":
+		return formatter.FormatPcapResult(index, f.Location, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "HAR":
+		return formatter.FormatHarResult(index, f.Location, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "TERRAFORM":
+		return formatter.FormatTerraformResult(index, f.Location, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "KEYSTORE":
+		return formatter.FormatKeystoreResult(index, f.Location, f.RuleName, f.RiskLevel, f.MatchedValue, f.Context)
+	case "CERT":
+		return formatter.FormatCertResult(index, f.LineNumber, f.Location, f.RuleName, f.RiskLevel, f.MatchedValue, f.Context)
+	case "IMAGE":
+		return formatter.FormatImageResult(index, f.Location, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "WEAK":
+		return formatter.FormatWeakPasswordResult(index, f.MatchType, f.RiskLevel, f.MatchedValue, f.LineNumber, f.Context)
+	case "FILE":
+		return formatter.FormatSensitiveFileResult(index, f.MatchedValue, f.RiskLevel, f.Context)
+	case "XML":
+		return formatter.FormatXMLResult(index, f.Location, f.LineNumber, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "YAML":
+		return formatter.FormatYAMLResult(index, f.Document, f.Location, f.LineNumber, f.RuleName, f.RiskLevel, f.Keyword, f.MatchedValue, f.Context)
+	case "CRED":
+		return formatter.FormatCredentialResult(index, f.MatchType, f.RiskLevel, f.Location, f.Context, f.MatchedValue)
+	case "COOKIE":
+		return formatter.FormatCookieResult(index, f.MatchType, f.RiskLevel, f.Location, f.Context, f.MatchedValue)
+	case "HASH":
+		return formatter.FormatHashMatchResult(index, f.MatchedValue, f.RiskLevel, f.Context)
+	case "BINARY":
+		return formatter.FormatBinaryResult(index, f.MatchType, f.RuleName, f.RiskLevel, f.MatchedValue, f.Offset, f.Section, f.Context)
+	}
+	
+	return f.Context
 }
 
+
 // generateHTMLReport 生成HTML报告
 func (s *Scanner) generateHTMLReport(duration time.Duration) error {
 	// 创建HTML报告生成器
-	generator, err := output.NewHTMLReportGenerator()
+	generator, err := output.NewHTMLReportGenerator(!s.config.NoBOM)
 	if err != nil {
 		return err
 	}
 	
 	// 构建报告数据
-	report := output.BuildHTMLReport(s.config.Directory, duration, s.fileResults)
+	report := output.BuildHTMLReport(s.scanTarget(), duration, s.fileResults, s.config.HTMLSort)
+	report.Theme = s.config.HTMLTheme
+	if link, ok := output.GetEditorLink(s.config.EditorLinks); ok {
+		report.ApplyEditorLinks(link, func(path string) string {
+			return s.sourcePaths[path]
+		})
+	}
 	
 	// 使用配置中的HTML输出路径
 	return generator.Generate(s.config.HTMLOutput, report)
 }
+
+// generateJSONReport 生成JSON报告
+func (s *Scanner) generateJSONReport(duration time.Duration) error {
+	name, _, version := config.GetAppInfo()
+	report := output.BuildJSONReport(name, version, s.scanTarget(), duration, s.fileResults)
+	return output.WriteJSONReport(s.config.JSONOutput, report)
+}
+
+// scanTarget 获取扫描目标描述（目录或镜像）
+func (s *Scanner) scanTarget() string {
+	if s.config.StdinContent {
+		return stdinName
+	}
+	if s.config.DockerImage != "" {
+		return s.config.DockerImage
+	}
+	targets := make([]string, len(s.config.Directories))
+	for i, dir := range s.config.Directories {
+		targets[i] = config.RedactRepoURL(dir)
+	}
+	return strings.Join(targets, ", ")
+}
+
+// annotateSections 为 PE 文件中的二进制结果标注所在的节区（如 .rdata、.rsrc）
+func annotateSections(path string, findings []output.Finding) {
+	var sections []parser.PESection
+	loaded := false
+	for i := range findings {
+		f := &findings[i]
+		if f.Kind != "BINARY" || f.Offset < 0 || f.Embedded != "" {
+			continue
+		}
+		if !loaded {
+			sections = parser.PESections(path)
+			loaded = true
+		}
+		if len(sections) == 0 {
+			return
+		}
+		f.Section = parser.SectionAt(sections, int64(f.Offset))
+	}
+}
+
+// attachRawContext 为二进制结果附带匹配位置前后的原始字节
+func attachRawContext(path string, findings []output.Finding, n int) {
+	var file *os.File
+	for i := range findings {
+		f := &findings[i]
+		if f.Kind != "BINARY" || f.Offset < 0 || f.Embedded != "" {
+			continue
+		}
+
+		if file == nil {
+			var err error
+			if file, err = os.Open(path); err != nil {
+				return
+			}
+			defer file.Close()
+		}
+
+		start := f.Offset - n
+		if start < 0 {
+			start = 0
+		}
+		buf := make([]byte, f.Offset-start+len(f.MatchedValue)+n)
+		read, _ := file.ReadAt(buf, int64(start))
+		f.RawContext = buf[:read]
+	}
+}
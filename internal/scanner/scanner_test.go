@@ -0,0 +1,254 @@
+package scanner
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"Findx/internal/config"
+
+	"github.com/urfave/cli/v2"
+)
+
+// testConfig 按命令行参数解析并校验配置，结果写入临时目录
+func testConfig(t testing.TB, args ...string) *config.Config {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "res.txt")
+	var cfg *config.Config
+	app := &cli.App{
+		Flags: config.GetFlags(),
+		Action: func(c *cli.Context) error {
+			var err error
+			if cfg, err = config.ParseConfig(c); err != nil {
+				return err
+			}
+			return cfg.Validate()
+		},
+	}
+	if err := app.Run(append([]string{"findx", "-o", out}, args...)); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+// runScan 运行扫描并返回文本结果
+func runScan(t testing.TB, cfg *config.Config) string {
+	t.Helper()
+	if err := NewScanner(cfg).Run(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(cfg.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestSeedReproducible 指定 --seed 时去重保留的文件和 --max-findings 截止的文件与线程数无关
+func TestSeedReproducible(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 40; i++ {
+		content := fmt.Sprintf("password=shared-secret\nusername=user%02d\n", i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("app%02d.conf", i)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scan := func(threads, seed string) string {
+		cfg := testConfig(t, "-f", dir, "-k", "password=,username=", "-n", threads, "--seed", seed,
+			"--relative-paths", "--dedupe-by", "value", "--max-findings", "15", "--format", "flat")
+		return runScan(t, cfg)
+	}
+
+	want := scan("1", "7")
+	for i := 0; i < 5; i++ {
+		if got := scan("8", "7"); got != want {
+			t.Fatalf("run %d with 8 threads differs from 1 thread:\n%s\nwant:\n%s", i, got, want)
+		}
+	}
+	if other := scan("8", "8"); other == want {
+		t.Errorf("seeds 7 and 8 produced the same order")
+	}
+}
+
+// TestEntropyRuleKeepsKeywordRisk 同一行同时命中高熵赋值规则和带风险等级的关键字时两条结果都报告，关键字保留自己的等级
+func TestEntropyRuleKeepsKeywordRisk(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("db.password=Xk9fQ2mZ7pLw4RtB\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runScan(t, testConfig(t, "-f", dir, "-k", "password=:critical", "--relative-paths", "--format", "flat"))
+	for _, want := range []string{
+		"app.conf:1:high:高熵赋值:Xk************tB\n",
+		"app.conf:1:critical:关键字匹配:db.password=Xk9fQ2mZ7pLw4RtB\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+// TestEntropyRuleDedupesRawValue 高熵赋值结果按原始值去重，脱敏后相同的不同值不会合并
+func TestEntropyRuleDedupesRawValue(t *testing.T) {
+	dir := t.TempDir()
+	content := "api_token=Xk9fQ2mZ7pLw4RtB\napi_token=Xk9fQ2mZ8pLw4RtB\napi_token=Xk9fQ2mZ7pLw4RtB\n"
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runScan(t, testConfig(t, "-f", dir, "-k", "nomatch", "--relative-paths", "--format", "flat", "--dedupe-by", "value"))
+	if n := strings.Count(got, ":高熵赋值:"); n != 2 {
+		t.Errorf("got %d entropy findings, want 2:\n%s", n, got)
+	}
+}
+
+// corruptXLS 构造目录项名称长度为0的 OLE2 复合文档，xls 库读取目录项名称时越界 panic
+func corruptXLS() []byte {
+	data := make([]byte, 512+128*2)
+	binary.LittleEndian.PutUint32(data[0:], 0xE011CFD0)
+	binary.LittleEndian.PutUint32(data[4:], 0xE11AB1A1)
+	binary.LittleEndian.PutUint16(data[28:], 0xFFFE)     // 字节序
+	binary.LittleEndian.PutUint32(data[60:], 0xFFFFFFFE) // 短扇区分配表起始
+	binary.LittleEndian.PutUint32(data[68:], 0xFFFFFFFE) // 主扇区分配表起始
+	data[512+66] = 5                                     // 第一个目录项为 Root Entry，名称长度为0
+	return data
+}
+
+// TestCorruptFileRecorded 损坏的文件使解析器 panic 时跳过该文件并记录，同一次扫描中的其他文件照常报告
+func TestCorruptFileRecorded(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("password=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "corrupt.xls"), corruptXLS(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(t, "-f", dir, "-k", "password=", "-n", "2", "--relative-paths", "--format", "flat")
+	s := NewScanner(cfg)
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(cfg.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "app.conf:1:medium:关键字匹配:password=hunter2\n"; !strings.Contains(string(data), want) {
+		t.Errorf("missing %q in:\n%s", want, data)
+	}
+	if want := []string{"corrupt.xls"}; !reflect.DeepEqual(s.panicked, want) {
+		t.Errorf("panicked = %v, want %v", s.panicked, want)
+	}
+}
+
+// TestCorruptEmbeddedObjectRecorded 文档中损坏的嵌入对象在工作池中 panic 时按 文档!条目 记录，同一文档中的其他嵌入对象照常报告
+func TestCorruptEmbeddedObjectRecorded(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(`<?xml version="1.0"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`)},
+		{"word/embeddings/oleObject1.xls", corruptXLS()},
+		{"word/embeddings/notes.txt", []byte("password=hunter2\n")},
+	} {
+		w, err := zw.Create(part.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(part.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report.docx"), buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(t, "-f", dir, "-k", "password=", "-n", "2", "--archive-fanout", "--relative-paths", "--format", "flat")
+	s := NewScanner(cfg)
+	if err := s.Run(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(cfg.OutputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "report.docx!word/embeddings/notes.txt:1:medium:关键字匹配:password=hunter2\n"; !strings.Contains(string(data), want) {
+		t.Errorf("missing %q in:\n%s", want, data)
+	}
+	if want := []string{"report.docx!word/embeddings/oleObject1.xls"}; !reflect.DeepEqual(s.panicked, want) {
+		t.Errorf("panicked = %v, want %v", s.panicked, want)
+	}
+}
+
+// TestHistoryAndRotatedLogsSelected 命令历史总会被扫描，指定 .log 时包含轮转和压缩的日志
+func TestHistoryAndRotatedLogsSelected(t *testing.T) {
+	dir := t.TempDir()
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte("WARN login password=G2ip-Pass\n"))
+	zw.Close()
+	files := map[string][]byte{
+		".bash_history": []byte("mysql -u root -pR00t-Db app\n"),
+		"app.log.1":     []byte("WARN login password=R0tated\n"),
+		"app.log.2.gz":  compressed.Bytes(),
+		"notes.md":      []byte("password=Not-Scanned\n"),
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := runScan(t, testConfig(t, "-f", dir, "-t", ".log", "-k", "password=", "--relative-paths", "--format", "flat"))
+	for _, want := range []string{"R00t-Db", "R0tated", "G2ip-Pass"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Not-Scanned") {
+		t.Errorf("notes.md scanned without .md in file types:\n%s", got)
+	}
+}
+
+// TestCommentFindingsDemoted 位于注释中的结果风险等级降低一级，代码中的结果保持不变，不支持注释的文件类型不降级
+func TestCommentFindingsDemoted(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.conf":  "# password=Old-Pass1\npassword=Live-Pass1\n",
+		"main.go":   "package main\n\n/*\nconst dbPassword = \"Old-Pass2\"\n*/\nconst dbPassword = \"Live-Pass2\" // password= rotated\n",
+		"notes.txt": "# password=Not-A-Comment\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := runScan(t, testConfig(t, "-f", dir, "-t", ".conf,.go,.txt", "-k", "password=:high", "--relative-paths", "--format", "flat"))
+	for _, want := range []string{
+		"app.conf:1:medium:关键字匹配:# password=Old-Pass1\n",
+		"app.conf:2:high:关键字匹配:password=Live-Pass1\n",
+		"main.go:4:medium:硬编码密码:Old-Pass2\n",
+		// 同一行的行尾注释不影响代码中的匹配值
+		"main.go:6:high:硬编码密码:Live-Pass2\n",
+		"notes.txt:1:high:关键字匹配:# password=Not-A-Comment\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
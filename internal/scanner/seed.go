@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"path/filepath"
+	"sort"
+)
+
+// seededOrder 按 --seed 对待扫描文件排序：按种子和文件相对路径的哈希排序，哈希相同时按路径
+// 使用相对于扫描目录的 / 分隔路径，相同种子在不同机器和扫描位置得到相同的顺序
+func (s *Scanner) seededOrder(files []string) []string {
+	keys := make(map[string]uint64, len(files))
+	for _, file := range files {
+		keys[file] = seedHash(s.config.Seed, s.seedKey(file))
+	}
+	ordered := append([]string(nil), files...)
+	sort.Slice(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if keys[a] != keys[b] {
+			return keys[a] < keys[b]
+		}
+		return a < b
+	})
+	return ordered
+}
+
+// seedKey 文件参与排序的路径，Docker 镜像等临时文件使用其报告路径
+func (s *Scanner) seedKey(path string) string {
+	if alias, ok := s.pathAliases[path]; ok {
+		return alias
+	}
+	return filepath.ToSlash(s.config.RelativePath(path))
+}
+
+// seedHash 计算种子和路径的 FNV-1a 哈希
+func seedHash(seed int64, key string) uint64 {
+	h := fnv.New64a()
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], uint64(seed))
+	h.Write(buf[:])
+	h.Write([]byte(key))
+	return h.Sum64()
+}
+
+// fileSequencer 按顺序提交各文件的结果（--seed）：文件并发解析，去重、计数和输出按文件顺序依次进行
+// 为 nil 时各文件解析完成后立即提交
+type fileSequencer struct {
+	turns []chan struct{} // turns[i] 关闭表示轮到第 i 个文件
+}
+
+// newFileSequencer 创建 n 个文件的提交顺序
+func newFileSequencer(n int) *fileSequencer {
+	q := &fileSequencer{turns: make([]chan struct{}, n+1)}
+	for i := range q.turns {
+		q.turns[i] = make(chan struct{})
+	}
+	close(q.turns[0])
+	return q
+}
+
+// wait 等待之前的文件全部提交
+func (q *fileSequencer) wait(i int) {
+	if q == nil {
+		return
+	}
+	<-q.turns[i]
+}
+
+// pass 第 i 个文件提交完成（或被跳过），轮到下一个文件；未调用 wait 时同样先等待之前的文件
+func (q *fileSequencer) pass(i int) {
+	if q == nil {
+		return
+	}
+	<-q.turns[i]
+	close(q.turns[i+1])
+}
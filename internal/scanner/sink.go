@@ -0,0 +1,40 @@
+package scanner
+
+import (
+	"Findx/internal/config"
+	"Findx/internal/logger"
+	"Findx/internal/output"
+)
+
+// openRemoteSinks 根据 --syslog 和 --webhook 创建远程结果发送端
+func openRemoteSinks(cfg *config.Config) ([]*output.RemoteSink, error) {
+	var sinks []*output.RemoteSink
+	if cfg.Syslog != "" {
+		sink, err := output.NewSyslogSink(cfg.Syslog)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Webhook != "" {
+		sink, err := output.NewWebhookSink(cfg.Webhook)
+		if err != nil {
+			closeRemoteSinks(sinks)
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// closeRemoteSinks 发送剩余的结果并输出各发送端的统计
+func closeRemoteSinks(sinks []*output.RemoteSink) {
+	for _, sink := range sinks {
+		sent, dropped, err := sink.Close()
+		if dropped > 0 {
+			logger.Warnf("%s: 已发送 %d 条结果，重试后仍失败丢弃 %d 条: %v", sink.Name(), sent, dropped, err)
+		} else {
+			logger.Infof("%s: 已发送 %d 条结果", sink.Name(), sent)
+		}
+	}
+}
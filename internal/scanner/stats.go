@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+	"Findx/internal/output"
+	"Findx/internal/risk"
+)
+
+// WalkStats 文件遍历统计
+type WalkStats struct {
+	SkippedDirs      int            // 排除的目录数
+	SkippedFiles     int            // 排除的文件数
+	SkippedSize      int            // 因大小超限跳过的文件数
+	SkippedSmall     int            // 因小于 --min-size 跳过的文件数
+	SkippedOwner     int            // 因属主/属组不符跳过的文件数
+	SkippedGenerated int            // 因 --skip-generated 跳过的生成和压缩文件数
+	TotalBytes       int64          // 待扫描文件总字节数
+	ByExt            map[string]int // 按扩展名统计的待扫描文件数
+}
+
+// extensionOf 获取文件扩展名（小写），无扩展名时返回占位文本
+func extensionOf(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == "" {
+		return "(无扩展名)"
+	}
+	return ext
+}
+
+// maxSummaryRules 摘要中列出的规则数量上限
+const maxSummaryRules = 10
+
+// summarizeFindings 汇总所有结果的风险分布和命中最多的规则
+func (s *Scanner) summarizeFindings() (findings int, byLevel map[string]int, topRules []output.RuleCount) {
+	byLevel = make(map[string]int)
+	byRule := make(map[string]int)
+
+	s.mu.Lock()
+	for _, results := range s.fileResults {
+		findings += len(results)
+		for i := range results {
+			byLevel[strings.ToLower(results[i].RiskLevel)]++
+			byRule[results[i].RuleName]++
+		}
+	}
+	s.mu.Unlock()
+
+	for name, count := range byRule {
+		topRules = append(topRules, output.RuleCount{Name: name, Count: count})
+	}
+	sort.Slice(topRules, func(i, j int) bool {
+		if topRules[i].Count != topRules[j].Count {
+			return topRules[i].Count > topRules[j].Count
+		}
+		return topRules[i].Name < topRules[j].Name
+	})
+	if len(topRules) > maxSummaryRules {
+		topRules = topRules[:maxSummaryRules]
+	}
+
+	return findings, byLevel, topRules
+}
+
+// formatSummary 格式化扫描汇总（文件数、结果数、风险分布、耗时和命中最多的规则）
+func (s *Scanner) formatSummary(totalFiles int, elapsed time.Duration) string {
+	findings, byLevel, topRules := s.summarizeFindings()
+	formatter := output.NewResultFormatter()
+	return formatter.FormatSummary(totalFiles, findings, elapsed.String(), byLevel, topRules)
+}
+
+// writeSummaryReport 仅摘要模式：将汇总信息写入输出文件，不包含任何具体结果
+func (s *Scanner) writeSummaryReport(totalFiles int, elapsed time.Duration) error {
+	s.printSummary(totalFiles, elapsed)
+	return s.writer.WriteFormattedResults([]string{s.formatSummary(totalFiles, elapsed)})
+}
+
+// printSummary 在控制台输出扫描汇总（仅摘要模式），内容与写入输出文件的汇总相同
+func (s *Scanner) printSummary(totalFiles int, elapsed time.Duration) {
+	findings, byLevel, topRules := s.summarizeFindings()
+
+	logger.Infof("📊 扫描摘要:")
+	logger.Detailf("    扫描文件: %d 个", totalFiles)
+	logger.Detailf("    发现问题: %d 个", findings)
+	logger.Detailf("    风险评分: %d", risk.Score(byLevel))
+	logger.Detailf("    耗时: %s", elapsed)
+	if len(byLevel) > 0 {
+		logger.Detailf("    风险分布:")
+		for _, level := range risk.Levels() {
+			if byLevel[level.Name] > 0 {
+				logger.Detailf("      %s %s: %d", level.Icon, level.Label, byLevel[level.Name])
+			}
+		}
+	}
+	if len(topRules) > 0 {
+		logger.Detailf("    命中最多的规则:")
+		for _, rule := range topRules {
+			logger.Detailf("      %s: %d", rule.Name, rule.Count)
+		}
+	}
+}
+
+// writeSummaryFooter 所有结果写入后在输出文件末尾追加汇总，使输出文件不依赖控制台输出即可查看统计
+// 单行格式的输出供 grep 等工具逐行处理，不追加汇总；按风险等级拆分时追加到每个等级的文件
+func (s *Scanner) writeSummaryFooter(totalFiles int, elapsed time.Duration) error {
+	if s.config.OutputFormat == config.OutputFormatFlat {
+		return nil
+	}
+	summary := s.formatSummary(totalFiles, elapsed)
+	if s.splitWriter != nil && len(s.splitWriter.Levels(nil)) > 0 {
+		return s.splitWriter.WriteFormattedResults([]string{summary})
+	}
+	return s.writer.WriteFormattedResults([]string{summary})
+}
+
+// printDirectorySummary 在控制台按目录输出结果数、各风险等级的结果数和最高风险等级
+func (s *Scanner) printDirectorySummary() {
+	s.mu.Lock()
+	dirs := output.AggregateByDirectory(s.fileResults)
+	s.mu.Unlock()
+	if len(dirs) == 0 {
+		return
+	}
+
+	logger.Infof("📁 按目录汇总:")
+	for _, dir := range dirs {
+		var levels []string
+		for _, name := range risk.Names() {
+			if count := dir.ByLevel[name]; count > 0 {
+				levels = append(levels, fmt.Sprintf("%s %d", risk.Label(name), count))
+			}
+		}
+		logger.Detailf("    %s %s: %d 项 (%s)，%d 个文件", risk.Icon(dir.MaxRisk), dir.Path, dir.Count, strings.Join(levels, ", "), len(dir.Files))
+	}
+}
+
+// writeCleanList 将扫描后没有结果的文件按路径排序写入 --clean-list 指定的文件
+func (s *Scanner) writeCleanList() error {
+	sort.Strings(s.cleanFiles)
+
+	file, err := output.CreateReportFile(s.config.CleanList, false)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	for _, path := range s.cleanFiles {
+		w.WriteString(path)
+		w.WriteString("\n")
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return file.Commit()
+}
+
+// printCountReport 输出仅统计模式的汇总信息
+func (s *Scanner) printCountReport(totalFiles int, elapsed time.Duration) {
+	stats := s.walkStats
+
+	logger.Infof("📊 扫描范围统计:")
+	logger.Detailf("    待扫描文件: %d 个", totalFiles)
+	logger.Detailf("    总大小: %.2f MB", float64(stats.TotalBytes)/1024/1024)
+
+	if len(stats.ByExt) > 0 {
+		exts := make([]string, 0, len(stats.ByExt))
+		for ext := range stats.ByExt {
+			exts = append(exts, ext)
+		}
+		sort.Slice(exts, func(i, j int) bool {
+			if stats.ByExt[exts[i]] != stats.ByExt[exts[j]] {
+				return stats.ByExt[exts[i]] > stats.ByExt[exts[j]]
+			}
+			return exts[i] < exts[j]
+		})
+
+		logger.Detailf("    按扩展名:")
+		for _, ext := range exts {
+			logger.Detailf("      %-16s %d", ext, stats.ByExt[ext])
+		}
+	}
+
+	logger.Detailf("    跳过: 目录(%d) 文件(%d) 大文件(%d) 小文件(%d) 属主不符(%d) 生成文件(%d)", stats.SkippedDirs, stats.SkippedFiles, stats.SkippedSize, stats.SkippedSmall, stats.SkippedOwner, stats.SkippedGenerated)
+	logger.Infof("统计耗时: %s", elapsed)
+}
@@ -0,0 +1,47 @@
+package scanner
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"Findx/internal/logger"
+)
+
+// TestSummaryOnlyConsole 仅摘要模式的控制台汇总通过日志输出到 stderr，不写入 stdout
+func TestSummaryOnlyConsole(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("password=hunter2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdout.Close()
+	saved := os.Stdout
+	os.Stdout = stdout
+	defer func() { os.Stdout = saved }()
+	var logs bytes.Buffer
+	logger.SetOutput(&logs)
+	defer logger.SetOutput(os.Stderr)
+
+	report := runScan(t, testConfig(t, "-f", dir, "-k", "password=", "--summary-only"))
+
+	printed, err := os.ReadFile(stdout.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(printed), "发现问题") {
+		t.Errorf("summary written to stdout:\n%s", printed)
+	}
+	if !strings.Contains(logs.String(), "    发现问题: 1 个\n") {
+		t.Errorf("summary missing from the log:\n%s", logs.String())
+	}
+	if !strings.Contains(report, "发现问题: 1 个") {
+		t.Errorf("summary missing from the report:\n%s", report)
+	}
+}
@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"Findx/internal/config"
+	"Findx/internal/logger"
+)
+
+// stdinName 标准输入内容在报告中显示的文件名
+const stdinName = "(stdin)"
+
+// prepareStdin 将标准输入的全部内容写入临时文件，作为一个虚拟文件扫描
+// 临时文件的扩展名决定使用的解析器: 二进制模式为 .bin，否则取第一个文件类型（默认 .txt）
+func (s *Scanner) prepareStdin() ([]string, func(), error) {
+	tempDir, err := os.MkdirTemp("", "findx-stdin-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("创建临时目录失败: %w", err)
+	}
+	cleanup := func() { os.RemoveAll(tempDir) }
+
+	if stdinIsTerminal() {
+		logger.Infof("从标准输入读取内容，按 Ctrl-D 结束")
+	}
+
+	path := filepath.Join(tempDir, "stdin"+stdinExt(s.config))
+	file, err := os.Create(path)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	size, err := io.Copy(file, os.Stdin)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("读取标准输入失败: %w", err)
+	}
+	logger.Infof("标准输入: %d 字节", size)
+
+	s.pathAliases[path] = stdinName
+	return []string{path}, cleanup, nil
+}
+
+// stdinExt 获取标准输入内容使用的扩展名
+func stdinExt(cfg *config.Config) string {
+	if cfg.BinaryMode {
+		return ".bin"
+	}
+	if len(cfg.FileTypes) > 0 {
+		return cfg.FileTypes[0]
+	}
+	return ".txt"
+}
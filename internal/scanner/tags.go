@@ -0,0 +1,58 @@
+package scanner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"Findx/internal/config"
+	"Findx/internal/parser"
+)
+
+// ApplyRuleTags 将 --tags 指定的标签展开为规则名称并加入 --only-rules
+// 同时指定 --only-rules 时两者取并集；存在未知标签时返回错误
+func ApplyRuleTags(cfg *config.Config) error {
+	if len(cfg.Tags) == 0 {
+		return nil
+	}
+
+	ruleTags := parser.RuleTags()
+	known := make(map[string]bool)
+	for _, tags := range ruleTags {
+		for _, tag := range tags {
+			known[tag] = true
+		}
+	}
+	wanted := make(map[string]bool)
+	for _, tag := range cfg.Tags {
+		if !known[tag] {
+			return fmt.Errorf("未知的规则标签: %s（可用标签: %s）", tag, strings.Join(sortedKeys(known), ", "))
+		}
+		wanted[tag] = true
+	}
+
+	selected := make(map[string]bool)
+	for _, name := range cfg.OnlyRules {
+		selected[name] = true
+	}
+	for name, tags := range ruleTags {
+		for _, tag := range tags {
+			if wanted[tag] {
+				selected[name] = true
+				break
+			}
+		}
+	}
+	cfg.OnlyRules = sortedKeys(selected)
+	return nil
+}
+
+// sortedKeys 返回集合中排序后的元素
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"strings"
+
+	"Findx/internal/logger"
+	"Findx/internal/output"
+	"Findx/internal/parser"
+)
+
+// activeRuleNames 获取本次扫描中可能产生结果的内置规则名称（按 --only-rules/--skip-rules 启用，且所需的功能已开启）
+func (s *Scanner) activeRuleNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range parser.BuiltinRuleNames() {
+		if seen[name] || !s.config.RuleEnabled(name) {
+			continue
+		}
+		seen[name] = true
+		switch name {
+		case output.KeywordRuleName:
+			if len(s.config.Keywords) == 0 {
+				continue
+			}
+		case output.WeakPasswordRuleName:
+			if !s.config.WeakPasswords {
+				continue
+			}
+		case output.HashMatchRuleName:
+			if len(s.config.KnownHashes) == 0 {
+				continue
+			}
+		case output.SensitiveFileRuleName:
+			if len(s.config.SensitiveFiles) == 0 {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// countRuleHits 统计整个扫描中每个关键词和每条规则的结果数
+// 二进制等结果中不记录命中的关键字，按与扫描时相同的方式在匹配值中查找第一个关键字
+func (s *Scanner) countRuleHits() (keywordHits, ruleHits map[string]int) {
+	keywordHits = make(map[string]int)
+	ruleHits = make(map[string]int)
+	known := make(map[string]bool, len(s.config.Keywords))
+	for _, keyword := range s.config.Keywords {
+		known[keyword] = true
+	}
+	findKeyword := parser.KeywordFinder(s.config.Keywords, s.config.KeywordCI)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, results := range s.fileResults {
+		for i := range results {
+			f := &results[i]
+			ruleHits[strings.TrimSuffix(f.RuleName, " (Base64编码)")]++
+			if f.RuleName != output.KeywordRuleName {
+				continue
+			}
+			keyword := f.Keyword
+			if !known[keyword] {
+				keyword, _ = findKeyword(f.MatchedValue)
+			}
+			if keyword != "" {
+				keywordHits[keyword]++
+			}
+		}
+	}
+	return keywordHits, ruleHits
+}
+
+// printUnusedRules 扫描结束后在控制台列出没有产生任何结果的关键词和规则（--unused-rules），用于精简关键词和规则列表
+func (s *Scanner) printUnusedRules() {
+	keywordHits, ruleHits := s.countRuleHits()
+
+	var unusedKeywords []string
+	for _, keyword := range s.config.Keywords {
+		if keywordHits[keyword] == 0 {
+			unusedKeywords = append(unusedKeywords, keyword)
+		}
+	}
+	rules := s.activeRuleNames()
+	var unusedRules []string
+	for _, name := range rules {
+		if ruleHits[name] == 0 {
+			unusedRules = append(unusedRules, name)
+		}
+	}
+
+	if len(unusedKeywords) == 0 && len(unusedRules) == 0 {
+		logger.Infof("未命中的关键词和规则: 无（%d 个关键词和 %d 条规则均有结果）", len(s.config.Keywords), len(rules))
+		return
+	}
+	if len(unusedKeywords) > 0 {
+		logger.Infof("🧹 未命中的关键词 (%d/%d):", len(unusedKeywords), len(s.config.Keywords))
+		for _, keyword := range unusedKeywords {
+			logger.Detailf("    %s", keyword)
+		}
+	}
+	if len(unusedRules) > 0 {
+		logger.Infof("🧹 未命中的规则 (%d/%d):", len(unusedRules), len(rules))
+		for _, name := range unusedRules {
+			logger.Detailf("    %s", name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package scanner
+
+import (
+	"strings"
+
+	"Findx/internal/output"
+)
+
+// weakPasswords 常见弱口令字典（小写）
+var weakPasswords = map[string]bool{
+	"password": true, "passwd": true, "pass": true, "admin": true, "administrator": true,
+	"root": true, "toor": true, "123456": true, "12345678": true, "123456789": true,
+	"1234567890": true, "111111": true, "000000": true, "888888": true, "666666": true,
+	"qwerty": true, "qwe123": true, "abc123": true, "letmein": true, "welcome": true,
+	"changeme": true, "default": true, "guest": true, "test": true, "secret": true,
+	"iloveyou": true, "master": true, "oracle": true, "mysql": true, "postgres": true,
+	"sa": true, "system": true, "manager": true, "user": true, "login": true,
+}
+
+// leetReplacer 常见的字符替换（leetspeak）还原
+var leetReplacer = strings.NewReplacer(
+	"@", "a", "4", "a",
+	"0", "o",
+	"1", "i", "!", "i", "|", "i",
+	"3", "e",
+	"$", "s", "5", "s",
+	"7", "t",
+)
+
+// passwordHints 判断结果是否与口令相关的关键字
+var passwordHints = []string{"password", "passwd", "pwd", "pass", "secret", "密码", "口令"}
+
+// valueTerminators 从文本行中截取值时的结束字符
+const valueTerminators = " \t\"'`;,&<>)"
+
+// isWeakPassword 判断值是否为弱口令
+// 先直接查字典，再去掉末尾的数字和符号并还原字符替换后查字典（如 P@ssw0rd123 -> password）
+func isWeakPassword(value string) bool {
+	lower := strings.ToLower(value)
+	if weakPasswords[lower] {
+		return true
+	}
+
+	base := strings.TrimRight(lower, "0123456789!@#$%^&*._-")
+	if base == "" {
+		return false
+	}
+	return weakPasswords[leetReplacer.Replace(base)]
+}
+
+// isPasswordFinding 判断结果是否来自口令相关的规则或关键字
+func isPasswordFinding(f *output.Finding) bool {
+	source := strings.ToLower(f.RuleName + " " + f.Keyword)
+	for _, hint := range passwordHints {
+		if strings.Contains(source, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// candidateValue 获取结果中的口令值，关键字匹配的结果取关键字之后的内容
+func candidateValue(f *output.Finding) string {
+	switch f.Kind {
+	case "TEXT", "WORD", "EXCEL", "CSV":
+		idx := strings.Index(f.Context, f.Keyword)
+		if idx < 0 {
+			return ""
+		}
+		rest := strings.TrimLeft(f.Context[idx+len(f.Keyword):], " \t=:\"'")
+		if end := strings.IndexAny(rest, valueTerminators); end >= 0 {
+			rest = rest[:end]
+		}
+		return rest
+	case "FILE":
+		return ""
+	default:
+		return strings.TrimSpace(f.MatchedValue)
+	}
+}
+
+// weakPasswordFindings 检查口令相关的结果，为弱口令追加独立的结果
+func weakPasswordFindings(findings []output.Finding) []output.Finding {
+	var weak []output.Finding
+	for i := range findings {
+		f := &findings[i]
+		if !isPasswordFinding(f) {
+			continue
+		}
+
+		value := candidateValue(f)
+		if value == "" || !isWeakPassword(value) {
+			continue
+		}
+
+		source := f.RuleName
+		if f.Keyword != "" {
+			source += ": " + f.Keyword
+		}
+		w := output.Finding{
+			Kind:         "WEAK",
+			RuleName:     output.WeakPasswordRuleName,
+			RiskLevel:    output.WeakPasswordRiskLevel,
+			MatchType:    source,
+			Location:     f.Location,
+			MatchedValue: value,
+			LineNumber:   f.LineNumber,
+			Offset:       f.Offset,
+			Context:      f.Context,
+		}
+		if f.InComment {
+			w.MarkInComment()
+		}
+		weak = append(weak, w)
+	}
+	return weak
+}
@@ -0,0 +1,12 @@
+package utils
+
+// 上下文中匹配内容的标记，HTML报告中渲染为高亮
+const (
+	HighlightStart = "⟦"
+	HighlightEnd   = "⟧"
+)
+
+// Highlight 用标记包围匹配内容
+func Highlight(s string) string {
+	return HighlightStart + s + HighlightEnd
+}
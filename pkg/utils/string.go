@@ -1,15 +1,12 @@
 package utils
 
-import "strings"
+import "unicode/utf8"
 
-// TruncateString 截断字符串，如果超过 maxLength 则截断并添加省略号
+// TruncateString 截断字符串，超过 maxLength 个字符时保留前 maxLength 个字符并添加省略号
+// maxLength 不大于0时不截断，按字符计算长度，不会截断多字节字符
 func TruncateString(str string, maxLength int) string {
-	// 移除字符串中的所有空格
-	trimmedStr := strings.ReplaceAll(str, " ", "")
-
-	// 如果移除空格后的字符串长度超过 maxLength，则截断
-	if len(trimmedStr) > maxLength {
-		return str[:maxLength] + "..."
+	if maxLength <= 0 || utf8.RuneCountInString(str) <= maxLength {
+		return str
 	}
-	return str
+	return string([]rune(str)[:maxLength]) + "..."
 }